@@ -15,6 +15,7 @@ import (
 	"github.com/milosgajdos/orbnet/pkg/graph/api/http"
 	"github.com/milosgajdos/orbnet/pkg/graph/api/memory"
 	"github.com/milosgajdos/orbnet/pkg/graph/api/sqlite"
+	"github.com/milosgajdos/orbnet/pkg/graph/builder/stars"
 )
 
 const (
@@ -111,6 +112,8 @@ func initMemDBSvc(s *http.Server, dsn string) error {
 	if err != nil {
 		return fmt.Errorf("failed creating new DB: %v", err)
 	}
+	db.Schema = stars.Schema()
+
 	if err := db.Open(); err != nil {
 		return fmt.Errorf("failed opening DB: %v", err)
 	}
@@ -130,15 +133,22 @@ func initMemDBSvc(s *http.Server, dsn string) error {
 		return fmt.Errorf("failed creating graph service: %v", err)
 	}
 
+	ps, err := memory.NewPathService(db)
+	if err != nil {
+		return fmt.Errorf("failed creating path service: %v", err)
+	}
+
 	s.GraphService = gs
 	s.NodeService = ns
 	s.EdgeService = es
+	s.PathService = ps
 
 	return nil
 }
 
 func initSqliteSvc(s *http.Server, dsn string) error {
 	db := sqlite.NewDB(dsn)
+	db.Schema = stars.Schema()
 
 	if err := db.Open(); err != nil {
 		return fmt.Errorf("failed opening DB: %v", err)
@@ -159,9 +169,15 @@ func initSqliteSvc(s *http.Server, dsn string) error {
 		return fmt.Errorf("failed creating graph service: %v", err)
 	}
 
+	ps, err := sqlite.NewPathService(db)
+	if err != nil {
+		return fmt.Errorf("failed creating path service: %v", err)
+	}
+
 	s.GraphService = gs
 	s.NodeService = ns
 	s.EdgeService = es
+	s.PathService = ps
 
 	return nil
 }
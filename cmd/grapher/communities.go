@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/analytics"
+	"github.com/milosgajdos/orbnet/pkg/graph/builder/stars"
+)
+
+// runCommunities detects communities of the graph passed in as argument and writes their summary to stdout.
+// The argument is either a directory with GitHub stars dumps or jsonapi encoded graph.
+func runCommunities(args []string) error {
+	flags := flag.NewFlagSet(CliName+" communities", flag.ExitOnError)
+
+	var (
+		algo       = flags.String("algorithm", string(analytics.Louvain), "community detection algorithm (louvain, labelprop)")
+		seed       = flags.Int64("seed", analytics.DefaultSeed, "seed of community detection")
+		resolution = flags.Float64("resolution", analytics.DefaultResolution, "louvain modularity resolution")
+		top        = flags.Int("top", 5, "number of top topics and languages of each community")
+		format     = flags.String("format", "text", "summary format (text, json)")
+		builders   = flags.Int("builders", BuilderPool, "number of graph builders")
+	)
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("communities requires exactly one graph")
+	}
+
+	ctx, cancel := signalContext(context.Background())
+	defer cancel()
+
+	g, err := loadGraph(ctx, flags.Arg(0), *builders)
+	if err != nil {
+		return err
+	}
+
+	c, err := analytics.DetectCommunities(g, analytics.Algorithm(*algo),
+		analytics.WithSeed(*seed),
+		analytics.WithResolution(*resolution),
+	)
+	if err != nil {
+		return err
+	}
+
+	labels := []string{stars.TopicEntity.String(), stars.LangEntity.String()}
+
+	summaries, err := analytics.Summarize(g, c, *top, labels...)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		for _, s := range summaries {
+			fmt.Printf("community %d: %d nodes\n", s.ID, s.Size)
+			for _, label := range labels {
+				names := make([]string, 0, len(s.Top[label]))
+				for _, c := range s.Top[label] {
+					names = append(names, fmt.Sprintf("%s (%d)", c.Name, c.Count))
+				}
+				fmt.Printf("\t%s: %s\n", label, strings.Join(names, ", "))
+			}
+		}
+	case "json":
+		out, err := json.MarshalIndent(summaries, "", "\t")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", out)
+	default:
+		return fmt.Errorf("unsupported format: %q", *format)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/diff"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/dot"
+)
+
+// runDiff compares two graphs passed in as arguments and writes their diff to stdout.
+// Arguments are either directories with GitHub stars dumps or jsonapi encoded graphs.
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet(CliName+" diff", flag.ExitOnError)
+
+	var (
+		format   = flags.String("format", "text", "diff format (text, json, dot)")
+		ignore   = flags.String("ignore", "", "comma separated list of ignored attributes")
+		edgeUIDs = flags.Bool("edge-uids", false, "match edges by their UIDs")
+		builders = flags.Int("builders", BuilderPool, "number of graph builders")
+	)
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 2 {
+		return fmt.Errorf("diff requires exactly two graphs")
+	}
+
+	ctx, cancel := signalContext(context.Background())
+	defer cancel()
+
+	a, err := loadGraph(ctx, flags.Arg(0), *builders)
+	if err != nil {
+		return err
+	}
+
+	b, err := loadGraph(ctx, flags.Arg(1), *builders)
+	if err != nil {
+		return err
+	}
+
+	opts := []diff.Option{
+		diff.WithEdgeUIDs(*edgeUIDs),
+	}
+
+	if *ignore != "" {
+		opts = append(opts, diff.WithIgnoreAttrs(strings.Split(*ignore, ",")...))
+	}
+
+	d, err := diff.Compare(a, b, opts...)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		return d.WriteText(os.Stdout)
+	case "json":
+		out, err := json.MarshalIndent(d, "", "\t")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", out)
+	case "dot":
+		g, err := d.Graph()
+		if err != nil {
+			return err
+		}
+		m, err := dot.NewMarshaler("GitHub Stars Diff", "", "\t")
+		if err != nil {
+			return err
+		}
+		out, err := m.Marshal(g)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", out)
+	default:
+		return fmt.Errorf("unsupported format: %q", *format)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/builder/stars"
+	"github.com/milosgajdos/orbnet/pkg/graph/embeddings"
+)
+
+// runEmbed trains node embeddings of the graph passed in as argument.
+// The argument is either a directory with GitHub stars dumps or jsonapi encoded graph.
+// The embeddings are written in word2vec text format or stored in the node attributes
+// of the graph which is then written in jsonapi format.
+func runEmbed(args []string) error {
+	flags := flag.NewFlagSet(CliName+" embed", flag.ExitOnError)
+
+	var (
+		dims     = flags.Int("dimensions", embeddings.DefaultDimensions, "number of embedding dimensions")
+		walks    = flags.Int("walks", embeddings.DefaultWalks, "number of random walks started from each node")
+		length   = flags.Int("length", embeddings.DefaultWalkLength, "number of nodes of each random walk")
+		p        = flags.Float64("p", 1, "node2vec return parameter")
+		q        = flags.Float64("q", 1, "node2vec in-out parameter")
+		window   = flags.Int("window", embeddings.DefaultWindow, "skip-gram context window size")
+		negative = flags.Int("negative", embeddings.DefaultNegative, "number of skip-gram negative samples")
+		epochs   = flags.Int("epochs", embeddings.DefaultEpochs, "number of skip-gram training epochs")
+		rate     = flags.Float64("rate", embeddings.DefaultLearningRate, "initial skip-gram learning rate")
+		seed     = flags.Int64("seed", embeddings.DefaultSeed, "random seed")
+		attrs    = flags.Bool("attrs", false, "store embeddings in node attributes and write jsonapi graph")
+		out      = flags.String("out", "", "output file (default: stdout)")
+		builders = flags.Int("builders", BuilderPool, "number of graph builders")
+	)
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("embed requires exactly one graph")
+	}
+
+	ctx, cancel := signalContext(context.Background())
+	defer cancel()
+
+	g, err := loadGraph(ctx, flags.Arg(0), *builders)
+	if err != nil {
+		return err
+	}
+
+	e, err := embeddings.Train(g,
+		embeddings.WithDimensions(*dims),
+		embeddings.WithWalks(*walks),
+		embeddings.WithWalkLength(*length),
+		embeddings.WithP(*p),
+		embeddings.WithQ(*q),
+		embeddings.WithWindow(*window),
+		embeddings.WithNegative(*negative),
+		embeddings.WithEpochs(*epochs),
+		embeddings.WithLearningRate(*rate),
+		embeddings.WithSeed(*seed),
+	)
+	if err != nil {
+		return err
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if !*attrs {
+		return e.Write(w)
+	}
+
+	e.SetAttrs(g)
+
+	schema := stars.Schema()
+	if err := schema.ExtendNodeSchemas(embeddings.Schema()); err != nil {
+		return err
+	}
+
+	m, err := NewMarshaler("jsonapi", "GitHub Stars", "", "\t", schema)
+	if err != nil {
+		return err
+	}
+
+	data, err := m.Marshal(g)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/api/memory/marshal"
+	"github.com/milosgajdos/orbnet/pkg/graph/api/memory/marshal/json"
+	"github.com/milosgajdos/orbnet/pkg/graph/builder/stars"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/cytoscape"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/dot"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/gexf"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/graphml"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	"golang.org/x/sync/errgroup"
+)
+
+// signalContext returns a context which is canceled on SIGINT.
+func signalContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt)
+	go func() {
+		select {
+		case <-sigChan:
+			fmt.Println("shutting down: received SIGINT...")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(sigChan)
+		cancel()
+	}
+}
+
+// buildGraph builds a graph of GitHub stars read from input.
+// If input is empty string the stars are read from standard input.
+func buildGraph(ctx context.Context, input string, builders int, label string) (*memory.Graph, error) {
+	g, err := memory.NewGraph(memory.WithLabel(label))
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := stars.NewBuilder(g)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := NewFetcher(input)
+	if err != nil {
+		return nil, err
+	}
+
+	reposChan := make(chan interface{}, builders)
+
+	eg, ctx := errgroup.WithContext(ctx)
+
+	for i := 0; i < builders; i++ {
+		eg.Go(func() error {
+			return b.Build(ctx, reposChan)
+		})
+	}
+
+	eg.Go(func() error {
+		return f.Fetch(ctx, reposChan)
+	})
+
+	if err := eg.Wait(); err != nil {
+		if err != context.Canceled {
+			return nil, fmt.Errorf("encountered error: %v", err)
+		}
+	}
+
+	return g, nil
+}
+
+// loadGraph loads a graph from path.
+// If path is a directory the graph is built from the GitHub stars dumped in it.
+// Files with .graphml, .gexf, .dot, .gv and .cyjs extensions are considered to be
+// graphs encoded in GraphML, GEXF, DOT and CytoscapeJS formats, respectively.
+// Otherwise path is considered to be a graph encoded in jsonapi format.
+func loadGraph(ctx context.Context, path string, builders int) (*memory.Graph, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return buildGraph(ctx, path, builders, filepath.Base(filepath.Clean(path)))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		graphType func([]byte) (string, error)
+		u         graph.Unmarshaler
+	)
+
+	switch filepath.Ext(path) {
+	case ".graphml":
+		graphType = graphml.GraphType
+		u, err = graphml.NewUnmarshaler(graphml.WithSchema(stars.Schema()))
+	case ".gexf":
+		graphType = gexf.GraphType
+		u, err = gexf.NewUnmarshaler(gexf.WithSchema(stars.Schema()))
+	case ".dot", ".gv":
+		graphType = dot.GraphType
+		u, err = dot.NewUnmarshaler(dot.WithSchema(stars.Schema()))
+	case ".cyjs":
+		graphType = cytoscape.GraphType
+		u, err = cytoscape.NewUnmarshaler(cytoscape.WithSchema(stars.Schema()))
+	default:
+		graphType = json.GraphType
+		u, err = json.NewUnmarshaler(marshal.WithSchema(stars.Schema()))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	typ, err := graphType(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load graph %s: %v", path, err)
+	}
+
+	g, err := memory.NewGraph(memory.WithType(typ))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.Unmarshal(data, g); err != nil {
+		return nil, fmt.Errorf("failed to load graph %s: %v", path, err)
+	}
+
+	return g, nil
+}
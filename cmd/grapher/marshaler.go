@@ -2,31 +2,121 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/milosgajdos/orbnet/pkg/graph"
 	"github.com/milosgajdos/orbnet/pkg/graph/api/memory/marshal/json"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/cypher"
 	"github.com/milosgajdos/orbnet/pkg/graph/marshal/cytoscape"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/d2"
 	"github.com/milosgajdos/orbnet/pkg/graph/marshal/dot"
 	"github.com/milosgajdos/orbnet/pkg/graph/marshal/gexf"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/graphml"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/gremlin"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/html"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/mermaid"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/neptune"
 	"github.com/milosgajdos/orbnet/pkg/graph/marshal/networkx"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/parquet"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/rdf"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/render"
 	"github.com/milosgajdos/orbnet/pkg/graph/marshal/sigma"
 )
 
-func NewMarshaler(format string, name, prefix, indent string) (graph.Marshaler, error) {
+// binaryFormats are the formats whose output is not text.
+var binaryFormats = map[string]bool{
+	"png":     true,
+	"parquet": true,
+	"arrow":   true,
+}
+
+// dirWriter writes graph files into a directory.
+type dirWriter interface {
+	WriteDir(g graph.Graph, dir string) error
+}
+
+func NewMarshaler(format string, name, prefix, indent string, schema *attrs.Registry) (graph.Marshaler, error) {
 	switch format {
 	case "dot":
 		return dot.NewMarshaler(name, prefix, indent)
 	case "gexf":
-		return gexf.NewMarshaler(name, prefix, indent)
+		return gexf.NewMarshaler(name, prefix, indent, gexf.WithSchema(schema))
+	case "graphml":
+		return graphml.NewMarshaler(name, prefix, indent, graphml.WithSchema(schema))
 	case "cytoscape":
 		return cytoscape.NewMarshaler(name, prefix, indent)
 	case "sigma":
 		return sigma.NewMarshaler(name, prefix, indent)
 	case "networkx":
 		return networkx.NewMarshaler(name, prefix, indent)
+	case "neptune":
+		return neptune.NewMarshaler(neptune.WithDialect(neptune.Gremlin), neptune.WithSchema(schema))
+	case "neo4j":
+		return neptune.NewMarshaler(neptune.WithDialect(neptune.Neo4j), neptune.WithSchema(schema))
+	case "parquet", "arrow":
+		return parquet.NewMarshaler(parquet.WithFormat(parquet.Format(format)), parquet.WithSchema(schema))
+	case "cypher":
+		return cypher.NewMarshaler(cypher.WithSchema(schema))
+	case "gremlin":
+		return gremlin.NewMarshaler(gremlin.WithSchema(schema))
+	case "turtle", "ntriples", "jsonld":
+		return rdf.NewMarshaler(rdf.WithFormat(rdf.Format(format)), rdf.WithSchema(schema))
+	case "html":
+		return html.NewMarshaler(name)
+	case "svg", "png":
+		return render.NewMarshaler(render.WithFormat(render.Format(format)))
+	case "mermaid":
+		return mermaid.NewMarshaler()
+	case "d2":
+		return d2.NewMarshaler()
 	case "jsonapi":
 		return json.NewMarshaler(name, prefix, indent)
 	}
 
 	return nil, fmt.Errorf("unsupported format: %q", format)
 }
+
+// writeGraph marshals g encoded in format into the file at path or to stdout if path is empty.
+// Text written to stdout is terminated by a newline.
+func writeGraph(m graph.Marshaler, g graph.Graph, format, path string) error {
+	if path == "" {
+		if err := marshalTo(os.Stdout, m, g); err != nil {
+			return err
+		}
+		if binaryFormats[format] {
+			return nil
+		}
+		_, err := fmt.Println()
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := marshalTo(f, m, g); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// marshalTo marshals g into w.
+// The graph is streamed into w if m is graph.StreamMarshaler.
+func marshalTo(w io.Writer, m graph.Marshaler, g graph.Graph) error {
+	if sm, ok := m.(graph.StreamMarshaler); ok {
+		return sm.MarshalTo(w, g)
+	}
+
+	data, err := m.Marshal(g)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
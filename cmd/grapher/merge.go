@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/builder/stars"
+	"github.com/milosgajdos/orbnet/pkg/graph/merge"
+)
+
+// runMerge merges graphs passed in as arguments and marshals the result to stdout or -out file.
+// Arguments are either directories with GitHub stars dumps or jsonapi encoded graphs.
+func runMerge(args []string) error {
+	flags := flag.NewFlagSet(CliName+" merge", flag.ExitOnError)
+
+	var (
+		policy   = flags.String("policy", string(merge.LeftWins), "attribute conflict policy (left, right, newest)")
+		weights  = flags.String("weights", string(merge.Sum), "parallel edge weight aggregation (sum, max, mean)")
+		format   = flags.String("format", "jsonapi", "encoding format (dot, gexf, graphml, cytoscape, sigma, networkx, html, jsonapi, cypher, gremlin, turtle, ntriples, jsonld, svg, png, mermaid, d2)")
+		builders = flags.Int("builders", BuilderPool, "number of graph builders")
+		label    = flags.String("flabel", GraphLabel, "merged graph label")
+		out      = flags.String("out", "", "output file (default: stdout)")
+	)
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if flags.NArg() < 2 {
+		return fmt.Errorf("merge requires at least two graphs")
+	}
+
+	m, err := NewMarshaler(*format, "GitHub Stars", "", "\t", stars.Schema())
+	if err != nil {
+		return err
+	}
+
+	merger, err := merge.NewMerger(
+		merge.WithLabel(*label),
+		merge.WithPolicy(merge.Policy(*policy)),
+		merge.WithAggregation(merge.Aggregation(*weights)),
+	)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signalContext(context.Background())
+	defer cancel()
+
+	graphs := make([]graph.Graph, 0, flags.NArg())
+	for _, path := range flags.Args() {
+		g, err := loadGraph(ctx, path, *builders)
+		if err != nil {
+			return err
+		}
+		graphs = append(graphs, g)
+	}
+
+	g, err := merger.Merge(graphs...)
+	if err != nil {
+		return err
+	}
+
+	return writeGraph(m, g, *format, *out)
+}
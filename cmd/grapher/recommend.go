@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/builder/stars"
+	"github.com/milosgajdos/orbnet/pkg/graph/recommend"
+)
+
+// runRecommend recommends repos related to the seed nodes in the graph passed in as the first argument
+// and writes them to stdout. The graph is either a directory with GitHub stars dumps or jsonapi encoded graph.
+// The remaining arguments are UIDs or names of the seed repos or owners.
+func runRecommend(args []string) error {
+	flags := flag.NewFlagSet(CliName+" recommend", flag.ExitOnError)
+
+	var (
+		method   = flags.String("method", string(recommend.PageRank), "scoring method (pagerank, adamic-adar, common)")
+		label    = flags.String("label", stars.RepoEntity.String(), "label of recommended nodes")
+		via      = flags.String("via", "", "comma separated list of labels of the nodes linking recommended nodes (default: all)")
+		limit    = flags.Int("limit", recommend.DefaultLimit, "maximum number of recommendations")
+		paths    = flags.Int("paths", recommend.DefaultPaths, "maximum number of explanation paths of each recommendation")
+		format   = flags.String("format", "text", "recommendations format (text, json)")
+		builders = flags.Int("builders", BuilderPool, "number of graph builders")
+	)
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if flags.NArg() < 2 {
+		return fmt.Errorf("recommend requires a graph and at least one seed")
+	}
+
+	opts := []recommend.Option{
+		recommend.WithMethod(recommend.Method(*method)),
+		recommend.WithLabel(*label),
+		recommend.WithLimit(*limit),
+		recommend.WithPaths(*paths),
+	}
+
+	if *via != "" {
+		opts = append(opts, recommend.WithVia(strings.Split(*via, ",")...))
+	}
+
+	ctx, cancel := signalContext(context.Background())
+	defer cancel()
+
+	g, err := loadGraph(ctx, flags.Arg(0), *builders)
+	if err != nil {
+		return err
+	}
+
+	recs, err := recommend.Recommend(g, flags.Args()[1:], opts...)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		for i, r := range recs {
+			fmt.Printf("%d. %s (%.4f)\n", i+1, r.Name, r.Score)
+			for _, p := range r.Paths {
+				fmt.Printf("\t%s\n", p)
+			}
+		}
+	case "json":
+		out, err := json.MarshalIndent(recs, "", "\t")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", out)
+	default:
+		return fmt.Errorf("unsupported format: %q", *format)
+	}
+
+	return nil
+}
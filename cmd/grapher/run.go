@@ -4,13 +4,15 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"os"
-	"os/signal"
+	"strings"
 
 	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/analytics"
 	"github.com/milosgajdos/orbnet/pkg/graph/builder/stars"
-	"github.com/milosgajdos/orbnet/pkg/graph/memory"
-	"golang.org/x/sync/errgroup"
+	"github.com/milosgajdos/orbnet/pkg/graph/layout"
+	"github.com/milosgajdos/orbnet/pkg/graph/predict"
+	"github.com/milosgajdos/orbnet/pkg/graph/projection"
+	"github.com/milosgajdos/orbnet/pkg/graph/subgraph"
 )
 
 const (
@@ -22,88 +24,205 @@ const (
 	BuilderPool = 1
 )
 
+// commands maps grapher subcommands to their handlers.
+var commands = map[string]func(args []string) error{
+	"merge":       runMerge,
+	"diff":        runDiff,
+	"communities": runCommunities,
+	"recommend":   runRecommend,
+	"suggest":     runSuggest,
+	"stats":       runStats,
+	"embed":       runEmbed,
+	"similar":     runSimilar,
+}
+
 func run(args []string) error {
+	if len(args) > 1 && !strings.HasPrefix(args[1], "-") {
+		cmd, ok := commands[args[1]]
+		if !ok {
+			return fmt.Errorf("unknown command: %q", args[1])
+		}
+		return cmd(args[1:])
+	}
+
 	flags := flag.NewFlagSet(CliName, flag.ExitOnError)
 
 	var (
 		input    = flags.String("input", "", "input source (default: stdin)")
-		marshal  = flags.Bool("marshal", false, "marshal graph to stdout")
-		format   = flags.String("format", "dot", "encoding format (dot, gexf, cytoscape, sigma, networkx, jsonapi)")
+		marshal  = flags.Bool("marshal", false, "marshal graph to stdout or -out file")
+		format   = flags.String("format", "dot", "encoding format (dot, gexf, graphml, cytoscape, sigma, networkx, html, jsonapi, neptune, neo4j, parquet, arrow, cypher, gremlin, turtle, ntriples, jsonld, svg, png, mermaid, d2)")
+		outdir   = flags.String("outdir", "", "output directory of neptune and neo4j CSV files or parquet and arrow tables")
+		out      = flags.String("out", "", "output file (default: stdout)")
 		builders = flags.Int("builders", BuilderPool, "number of graph builders")
 		label    = flags.String("flabel", GraphLabel, "graph label")
+		labels   = flags.String("labels", "", "comma separated list of labels of extracted nodes")
+		where    = flags.String("where", "", "attribute predicate expression of extracted nodes")
+		ego      = flags.String("ego", "", "comma separated list of UIDs of extracted ego networks")
+		radius   = flags.Int("radius", 1, "radius of extracted ego networks")
+		top      = flags.Int("top", 0, "extract top N nodes by degree")
+		analyze  = flags.String("analyze", "", "comma separated list of exported centrality measures (pagerank, degree, betweenness, closeness, hits)")
+		algo     = flags.String("communities", "", "community detection algorithm of exported node communities (louvain, labelprop)")
+		seed     = flags.Int64("seed", analytics.DefaultSeed, "seed of community detection and layout")
+		project  = flags.String("project", "", "project graph onto nodes with the given label via their shared neighbours, e.g. Topic:Repo")
+		weight   = flags.String("weighting", string(projection.Count), "weighting of projected edges (count, jaccard, newman)")
+		suggest  = flags.Bool("suggest", false, "add SuggestedTopic edges of predicted topics of repos without topics")
+		lay      = flags.String("layout", "", "layout algorithm of exported node x and y positions (fr, forceatlas2)")
 	)
 
 	if err := flags.Parse(args[1:]); err != nil {
 		return err
 	}
 
+	schema := stars.Schema()
+
+	var (
+		target, via string
+		weighting   projection.Weighting
+	)
+	if *project != "" {
+		var err error
+		target, via, err = projection.Parse(*project)
+		if err != nil {
+			return err
+		}
+
+		weighting, err = projection.ParseWeighting(*weight)
+		if err != nil {
+			return err
+		}
+
+		if err := schema.SetEdgeSchema(via, projection.Schema()); err != nil {
+			return err
+		}
+	}
+
+	var measures []analytics.Measure
+	if *analyze != "" {
+		var err error
+		measures, err = analytics.ParseMeasures(*analyze)
+		if err != nil {
+			return err
+		}
+
+		if err := schema.ExtendNodeSchemas(analytics.Schema(measures...)); err != nil {
+			return err
+		}
+	}
+
+	if *algo != "" {
+		if err := schema.ExtendNodeSchemas(analytics.CommunitySchema()); err != nil {
+			return err
+		}
+	}
+
+	if *lay != "" {
+		if err := schema.ExtendNodeSchemas(layout.Schema()); err != nil {
+			return err
+		}
+	}
+
 	var m graph.Marshaler
 	if *marshal {
 		var err error
-		m, err = NewMarshaler(*format, "GitHub Stars", "", "\t")
+		m, err = NewMarshaler(*format, "GitHub Stars", "", "\t", schema)
 		if err != nil {
 			return err
 		}
+
+		_, ok := m.(dirWriter)
+		switch {
+		case ok && *outdir == "":
+			return fmt.Errorf("%s format requires -outdir", *format)
+		case ok && *out != "":
+			return fmt.Errorf("%s format does not support -out", *format)
+		case !ok && *outdir != "":
+			return fmt.Errorf("%s format does not support -outdir", *format)
+		}
 	}
 
-	ctx := context.Background()
-	ctx, cancel := context.WithCancel(ctx)
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt)
-	defer func() {
-		signal.Stop(sigChan)
-		cancel()
-	}()
-	go func() {
-		select {
-		case <-sigChan:
-			fmt.Println("shutting down: received SIGINT...")
-			cancel()
-		case <-ctx.Done():
+	var sopts []subgraph.Option
+	if *labels != "" {
+		sopts = append(sopts, subgraph.WithLabels(strings.Split(*labels, ",")...))
+	}
+
+	if *where != "" {
+		expr, err := subgraph.Compile(*where)
+		if err != nil {
+			return err
 		}
-	}()
+		sopts = append(sopts, subgraph.WithExpr(expr))
+	}
 
-	g, err := memory.NewGraph(memory.WithLabel(*label))
-	if err != nil {
-		return err
+	if *ego != "" {
+		sopts = append(sopts, subgraph.WithEgo(*radius, strings.Split(*ego, ",")...))
 	}
 
-	b, err := stars.NewBuilder(g)
-	if err != nil {
-		return err
+	if *top > 0 {
+		sopts = append(sopts, subgraph.WithTopN(*top))
 	}
 
-	f, err := NewFetcher(*input)
+	ctx, cancel := signalContext(context.Background())
+	defer cancel()
+
+	mg, err := buildGraph(ctx, *input, *builders, *label)
 	if err != nil {
 		return err
 	}
 
-	reposChan := make(chan interface{}, *builders)
+	if *suggest {
+		suggestions, err := predict.Topics(mg)
+		if err != nil {
+			return err
+		}
 
-	eg, ctx := errgroup.WithContext(ctx)
+		if err := predict.Materialise(mg, suggestions); err != nil {
+			return err
+		}
+	}
 
-	for i := 0; i < *builders; i++ {
-		eg.Go(func() error {
-			return b.Build(ctx, reposChan)
-		})
+	if *project != "" {
+		mg, err = projection.Project(mg, target, via,
+			projection.WithWeighting(weighting),
+			projection.WithLabel(*label))
+		if err != nil {
+			return err
+		}
 	}
 
-	eg.Go(func() error {
-		return f.Fetch(ctx, reposChan)
-	})
+	var g graph.Graph = mg
+	if len(sopts) > 0 {
+		g, err = subgraph.Extract(mg, sopts...)
+		if err != nil {
+			return err
+		}
+	}
 
-	if err := eg.Wait(); err != nil {
-		if err != context.Canceled {
-			return fmt.Errorf("encountered error: %v", err)
+	if len(measures) > 0 {
+		if _, err := analytics.Analyze(g, measures, analytics.WithAttrs()); err != nil {
+			return err
 		}
 	}
 
-	if *marshal {
-		out, err := m.Marshal(g)
-		if err != nil {
+	if *algo != "" {
+		if _, err := analytics.DetectCommunities(g, analytics.Algorithm(*algo),
+			analytics.WithSeed(*seed), analytics.WithAttrs()); err != nil {
 			return err
 		}
-		fmt.Printf("%s\n", out)
+	}
+
+	if *lay != "" {
+		if _, err := layout.Layout(g, layout.Algorithm(*lay),
+			layout.WithSeed(*seed), layout.WithAttrs()); err != nil {
+			return err
+		}
+	}
+
+	if *marshal {
+		if dw, ok := m.(dirWriter); ok {
+			return dw.WriteDir(g, *outdir)
+		}
+
+		return writeGraph(m, g, *format, *out)
 	}
 	return nil
 }
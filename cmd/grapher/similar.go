@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/embeddings"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+// runSimilar finds the nodes of the graph passed in as the first argument with the embeddings most similar
+// to the embedding of the node given by its UID or name in the second argument and writes them to stdout.
+// The graph is either a directory with GitHub stars dumps or jsonapi encoded graph.
+// The embeddings are read from a word2vec text file or from the node attributes of the graph.
+func runSimilar(args []string) error {
+	flags := flag.NewFlagSet(CliName+" similar", flag.ExitOnError)
+
+	var (
+		path     = flags.String("embeddings", "", "embeddings file (default: graph node attributes)")
+		k        = flags.Int("k", 10, "number of similar nodes")
+		label    = flags.String("label", "", "label of similar nodes (default: all)")
+		format   = flags.String("format", "text", "similar nodes format (text, json)")
+		builders = flags.Int("builders", BuilderPool, "number of graph builders")
+	)
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 2 {
+		return fmt.Errorf("similar requires a graph and a node")
+	}
+
+	ctx, cancel := signalContext(context.Background())
+	defer cancel()
+
+	g, err := loadGraph(ctx, flags.Arg(0), *builders)
+	if err != nil {
+		return err
+	}
+
+	var e *embeddings.Embeddings
+	if *path != "" {
+		f, err := os.Open(*path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if e, err = embeddings.Read(f); err != nil {
+			return err
+		}
+	} else {
+		if e, err = embeddings.FromAttrs(g); err != nil {
+			return err
+		}
+	}
+
+	n, err := findNode(g, flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	nn, err := e.Nearest(n.UID(), *k, func(uid string) bool {
+		node := g.NodeWithUID(uid)
+		return node != nil && (*label == "" || node.Label() == *label)
+	})
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		for i, s := range nn {
+			node := g.NodeWithUID(s.UID)
+			fmt.Printf("%d. %s [%s] (%.4f)\n", i+1, graph.NodeName(node), node.Label(), s.Similarity)
+		}
+	case "json":
+		out, err := json.MarshalIndent(nn, "", "\t")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", out)
+	default:
+		return fmt.Errorf("unsupported format: %q", *format)
+	}
+
+	return nil
+}
+
+// findNode returns the node with the given uid or the only node with the given name.
+func findNode(g *memory.Graph, uid string) (graph.Node, error) {
+	if n := g.NodeWithUID(uid); n != nil {
+		return n, nil
+	}
+
+	var found []graph.Node
+	nodes := g.Nodes()
+	for nodes.Next() {
+		if n, ok := nodes.Node().(graph.Node); ok && graph.NodeName(n) == uid {
+			found = append(found, n)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("node %s not found", uid)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("ambiguous node name: %s", uid)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/stats"
+)
+
+// runStats computes the statistics of the graph passed in as argument and writes them to stdout.
+// The argument is either a directory with GitHub stars dumps or jsonapi encoded graph.
+func runStats(args []string) error {
+	flags := flag.NewFlagSet(CliName+" stats", flag.ExitOnError)
+
+	var (
+		top      = flags.Int("top", stats.DefaultTop, "number of top nodes by degree of each label")
+		freqs    = flags.Int("frequencies", stats.DefaultFrequencies, "number of rows of language and topic frequency tables (0: all)")
+		format   = flags.String("format", "text", "statistics format (text, json, markdown)")
+		builders = flags.Int("builders", BuilderPool, "number of graph builders")
+	)
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("stats requires exactly one graph")
+	}
+
+	ctx, cancel := signalContext(context.Background())
+	defer cancel()
+
+	g, err := loadGraph(ctx, flags.Arg(0), *builders)
+	if err != nil {
+		return err
+	}
+
+	s, err := stats.Compute(g, stats.WithTop(*top), stats.WithFrequencies(*freqs))
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		return s.WriteText(os.Stdout)
+	case "markdown":
+		return s.WriteMarkdown(os.Stdout)
+	case "json":
+		out, err := json.MarshalIndent(s, "", "\t")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", out)
+	default:
+		return fmt.Errorf("unsupported format: %q", *format)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/predict"
+)
+
+// runSuggest suggests likely topics of the repos in the graph passed in as the only argument
+// and writes them to stdout. The graph is either a directory with GitHub stars dumps or jsonapi encoded graph.
+func runSuggest(args []string) error {
+	flags := flag.NewFlagSet(CliName+" suggest", flag.ExitOnError)
+
+	var (
+		maxTopics  = flags.Int("max-topics", 0, "maximum number of topics of the repos which get suggestions")
+		limit      = flags.Int("limit", predict.DefaultLimit, "maximum number of suggested topics of each repo")
+		confidence = flags.Float64("min-confidence", predict.DefaultMinConfidence, "minimum confidence of suggested topics")
+		keywords   = flags.Float64("keywords", predict.DefaultKeywordConfidence, "confidence of topics matched by keywords (0 disables keyword matching)")
+		via        = flags.String("via", "", "comma separated list of labels of the nodes linking similar repos (default: Owner,Lang)")
+		format     = flags.String("format", "text", "suggestions format (text, json)")
+		builders   = flags.Int("builders", BuilderPool, "number of graph builders")
+	)
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("suggest requires exactly one graph")
+	}
+
+	opts := []predict.Option{
+		predict.WithMaxTopics(*maxTopics),
+		predict.WithLimit(*limit),
+		predict.WithMinConfidence(*confidence),
+		predict.WithKeywordConfidence(*keywords),
+	}
+
+	if *via != "" {
+		opts = append(opts, predict.WithVia(strings.Split(*via, ",")...))
+	}
+
+	ctx, cancel := signalContext(context.Background())
+	defer cancel()
+
+	g, err := loadGraph(ctx, flags.Arg(0), *builders)
+	if err != nil {
+		return err
+	}
+
+	suggestions, err := predict.Topics(g, opts...)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		var repo string
+		for _, s := range suggestions {
+			if s.RepoUID != repo {
+				fmt.Printf("%s\n", s.Repo)
+				repo = s.RepoUID
+			}
+			fmt.Printf("\t%s (%.4f): %s\n", s.Topic, s.Confidence, strings.Join(s.Reasons, ", "))
+		}
+	case "json":
+		out, err := json.MarshalIndent(suggestions, "", "\t")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", out)
+	default:
+		return fmt.Errorf("unsupported format: %q", *format)
+	}
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package analytics
+
+import (
+	"strings"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	gonum "gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/network"
+)
+
+// Measure is a centrality measure.
+type Measure string
+
+const (
+	// PageRank is edge weighted PageRank.
+	PageRank Measure = "pagerank"
+	// Degree is node degree.
+	Degree Measure = "degree"
+	// Betweenness is betweenness centrality.
+	Betweenness Measure = "betweenness"
+	// Closeness is closeness centrality.
+	Closeness Measure = "closeness"
+	// HITS are HITS hub and authority scores.
+	HITS Measure = "hits"
+)
+
+const (
+	// HubAttr is the name of HITS hub score attribute.
+	HubAttr = "hub"
+	// AuthorityAttr is the name of HITS authority score attribute.
+	AuthorityAttr = "authority"
+)
+
+// Measures returns all the supported centrality measures.
+func Measures() []Measure {
+	return []Measure{PageRank, Degree, Betweenness, Closeness, HITS}
+}
+
+// ParseMeasures parses comma separated list of centrality measures.
+func ParseMeasures(s string) ([]Measure, error) {
+	var measures []Measure
+	for _, m := range strings.Split(s, ",") {
+		measure := Measure(strings.TrimSpace(m))
+		if !measure.IsValid() {
+			return nil, graph.Errorf(graph.EINVALID, "unsupported measure: %q", m)
+		}
+		measures = append(measures, measure)
+	}
+	return measures, nil
+}
+
+// IsValid returns true if m is a supported centrality measure.
+func (m Measure) IsValid() bool {
+	for _, measure := range Measures() {
+		if m == measure {
+			return true
+		}
+	}
+	return false
+}
+
+// Attrs returns the names of the node attributes the scores of m are stored in.
+func (m Measure) Attrs() []string {
+	if m == HITS {
+		return []string{HubAttr, AuthorityAttr}
+	}
+	return []string{string(m)}
+}
+
+// Scores are node scores keyed by node IDs.
+type Scores map[int64]float64
+
+// PageRankScores returns edge weighted PageRank scores of the nodes in g.
+// Undirected edges are treated as a pair of directed edges.
+func PageRankScores(g graph.Graph, opts ...Option) (Scores, error) {
+	aopts, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return network.PageRankSparse(directed(g), aopts.Damping, aopts.Tolerance), nil
+}
+
+// DegreeScores returns degree of the nodes in g.
+// The degree of a directed graph node is the sum of its in and out degree.
+// Parallel edges are counted once.
+func DegreeScores(g graph.Graph) (Scores, error) {
+	dg, isDirected := g.(gonum.Directed)
+
+	s := make(Scores)
+	nodes := g.Nodes()
+	for nodes.Next() {
+		id := nodes.Node().ID()
+		d := g.From(id).Len()
+		if isDirected && graph.IsDirected(g.Type()) {
+			d += dg.To(id).Len()
+		}
+		s[id] = float64(d)
+	}
+
+	return s, nil
+}
+
+// BetweennessScores returns betweenness centrality of the nodes in g.
+// Edge weights are ignored.
+func BetweennessScores(g graph.Graph) (Scores, error) {
+	s := Scores(network.Betweenness(g))
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		if id := nodes.Node().ID(); !hasScore(s, id) {
+			s[id] = 0
+		}
+	}
+
+	return s, nil
+}
+
+// ClosenessScores returns closeness centrality of the nodes in g.
+// Edge weights are ignored and the incoming paths are used for directed graphs.
+// The closeness of nodes which are reachable from only a part of the graph
+// is scaled by the size of that part as proposed by Wasserman and Faust:
+//
+//	C(v) = (r-1)/(n-1) * (r-1)/\sum_u d(u,v)
+//
+// where r is the number of nodes v is reachable from, including v.
+func ClosenessScores(g graph.Graph) (Scores, error) {
+	dg := directed(g)
+	n := g.Nodes().Len()
+
+	s := make(Scores, n)
+	nodes := g.Nodes()
+	for nodes.Next() {
+		id := nodes.Node().ID()
+
+		dist := map[int64]int{id: 0}
+		queue := []int64{id}
+		sum := 0
+
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+
+			to := dg.To(u)
+			for to.Next() {
+				v := to.Node().ID()
+				if _, ok := dist[v]; ok {
+					continue
+				}
+				dist[v] = dist[u] + 1
+				sum += dist[v]
+				queue = append(queue, v)
+			}
+		}
+
+		if sum == 0 || n < 2 {
+			s[id] = 0
+			continue
+		}
+
+		r := float64(len(dist) - 1)
+		s[id] = (r / float64(n-1)) * (r / float64(sum))
+	}
+
+	return s, nil
+}
+
+// HITSScores returns HITS hub and authority scores of the nodes in g.
+// Undirected edges are treated as a pair of directed edges.
+func HITSScores(g graph.Graph, opts ...Option) (hubs, authorities Scores, err error) {
+	aopts, err := newOptions(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hubs, authorities = make(Scores), make(Scores)
+	for id, ha := range network.HITS(directed(g), aopts.Tolerance) {
+		hubs[id] = ha.Hub
+		authorities[id] = ha.Authority
+	}
+
+	return hubs, authorities, nil
+}
+
+// Analyze computes the given centrality measures of the nodes in g.
+// It returns the scores keyed by the names of their attributes.
+// If Attrs option is set the scores are written back to node attributes.
+func Analyze(g graph.Graph, measures []Measure, opts ...Option) (map[string]Scores, error) {
+	aopts, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]Scores)
+	for _, m := range measures {
+		var s Scores
+		switch m {
+		case PageRank:
+			s, err = PageRankScores(g, opts...)
+		case Degree:
+			s, err = DegreeScores(g)
+		case Betweenness:
+			s, err = BetweennessScores(g)
+		case Closeness:
+			s, err = ClosenessScores(g)
+		case HITS:
+			res[HubAttr], res[AuthorityAttr], err = HITSScores(g, opts...)
+		default:
+			return nil, graph.Errorf(graph.EINVALID, "unsupported measure: %q", m)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if s != nil {
+			res[string(m)] = s
+		}
+	}
+
+	if aopts.Attrs {
+		for name, s := range res {
+			if err := SetAttrs(g, name, s); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// SetAttrs sets node attribute with the given name to the node scores.
+func SetAttrs(g graph.Graph, name string, s Scores) error {
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n, ok := nodes.Node().(graph.Node)
+		if !ok {
+			return graph.Errorf(graph.EINVALID, "invalid node in graph %s", g.UID())
+		}
+
+		a := n.Attrs()
+		if a == nil {
+			return graph.Errorf(graph.EINVALID, "node %s has no attributes", n.UID())
+		}
+		a[name] = s[n.ID()]
+	}
+
+	return nil
+}
+
+// Schema returns the attribute schema of the scores of the given measures.
+func Schema(measures ...Measure) attrs.Schema {
+	s := make(attrs.Schema)
+	for _, m := range measures {
+		for _, name := range m.Attrs() {
+			s[name] = attrs.Float
+		}
+	}
+	return s
+}
+
+// hasScore returns true if s contains the score of node id.
+func hasScore(s Scores, id int64) bool {
+	_, ok := s[id]
+	return ok
+}
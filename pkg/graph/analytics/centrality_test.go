@@ -0,0 +1,191 @@
+package analytics
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+const tol = 1e-4
+
+// MustGraph returns the graph of the given type with the following edges:
+//
+//	a -> b, d -> b, b -> c
+func MustGraph(t *testing.T, typ string) (*memory.Graph, map[string]int64) {
+	t.Helper()
+
+	g, err := memory.NewGraph(memory.WithType(typ))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	nodes := make(map[string]*memory.Node)
+	ids := make(map[string]int64)
+	for _, uid := range []string{"a", "b", "c", "d"} {
+		n, err := memory.NewNode(g.NewNode().ID(), memory.WithUID(uid))
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(n)
+		nodes[uid] = n
+		ids[uid] = n.ID()
+	}
+
+	for _, te := range [][2]string{{"a", "b"}, {"d", "b"}, {"b", "c"}} {
+		e, err := memory.NewEdge(nodes[te[0]], nodes[te[1]])
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(e)
+	}
+
+	return g, ids
+}
+
+func assertScores(t *testing.T, ids map[string]int64, s Scores, exp map[string]float64) {
+	t.Helper()
+
+	if len(s) != len(ids) {
+		t.Fatalf("expected %d scores, got: %d", len(ids), len(s))
+	}
+
+	for uid, want := range exp {
+		if got := s[ids[uid]]; math.Abs(got-want) > tol {
+			t.Errorf("node %s: expected score: %f, got: %f", uid, want, got)
+		}
+	}
+}
+
+func TestDegreeScores(t *testing.T) {
+	for typ, exp := range map[string]map[string]float64{
+		graph.WeightedDirected:   {"a": 1, "b": 3, "c": 1, "d": 1},
+		graph.WeightedUndirected: {"a": 1, "b": 3, "c": 1, "d": 1},
+	} {
+		g, ids := MustGraph(t, typ)
+
+		s, err := DegreeScores(g)
+		if err != nil {
+			t.Fatalf("failed to compute degree: %v", err)
+		}
+		assertScores(t, ids, s, exp)
+	}
+}
+
+func TestBetweennessScores(t *testing.T) {
+	for typ, exp := range map[string]map[string]float64{
+		graph.WeightedDirected: {"a": 0, "b": 2, "c": 0, "d": 0},
+		// undirected paths are counted in both directions
+		graph.WeightedUndirected: {"a": 0, "b": 6, "c": 0, "d": 0},
+	} {
+		g, ids := MustGraph(t, typ)
+
+		s, err := BetweennessScores(g)
+		if err != nil {
+			t.Fatalf("failed to compute betweenness: %v", err)
+		}
+		assertScores(t, ids, s, exp)
+	}
+}
+
+func TestClosenessScores(t *testing.T) {
+	for typ, exp := range map[string]map[string]float64{
+		graph.WeightedDirected:   {"a": 0, "b": 2.0 / 3.0, "c": 0.6, "d": 0},
+		graph.WeightedUndirected: {"a": 0.6, "b": 1, "c": 0.6, "d": 0.6},
+	} {
+		g, ids := MustGraph(t, typ)
+
+		s, err := ClosenessScores(g)
+		if err != nil {
+			t.Fatalf("failed to compute closeness: %v", err)
+		}
+		assertScores(t, ids, s, exp)
+	}
+}
+
+func TestPageRankScores(t *testing.T) {
+	g, ids := MustGraph(t, graph.WeightedDirected)
+
+	s, err := PageRankScores(g)
+	if err != nil {
+		t.Fatalf("failed to compute pagerank: %v", err)
+	}
+
+	var sum float64
+	for _, v := range s {
+		sum += v
+	}
+	if math.Abs(sum-1) > tol {
+		t.Errorf("expected scores to sum to 1, got: %f", sum)
+	}
+
+	if s[ids["c"]] <= s[ids["b"]] || s[ids["b"]] <= s[ids["a"]] {
+		t.Errorf("unexpected pagerank order: %v", s)
+	}
+
+	if _, err := PageRankScores(g, WithDamping(1)); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+
+	if _, err := PageRankScores(g, WithTolerance(0)); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
+
+func TestHITSScores(t *testing.T) {
+	g, ids := MustGraph(t, graph.WeightedDirected)
+
+	hubs, authorities, err := HITSScores(g)
+	if err != nil {
+		t.Fatalf("failed to compute hits: %v", err)
+	}
+
+	if math.Abs(hubs[ids["a"]]-hubs[ids["d"]]) > tol || hubs[ids["a"]] <= hubs[ids["b"]] {
+		t.Errorf("unexpected hub scores: %v", hubs)
+	}
+
+	if authorities[ids["b"]] <= authorities[ids["c"]] || authorities[ids["a"]] != 0 {
+		t.Errorf("unexpected authority scores: %v", authorities)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	g, ids := MustGraph(t, graph.WeightedDirected)
+
+	measures, err := ParseMeasures("degree, hits")
+	if err != nil {
+		t.Fatalf("failed to parse measures: %v", err)
+	}
+
+	res, err := Analyze(g, measures, WithAttrs())
+	if err != nil {
+		t.Fatalf("failed to analyze graph: %v", err)
+	}
+
+	for _, name := range []string{"degree", HubAttr, AuthorityAttr} {
+		if _, ok := res[name]; !ok {
+			t.Fatalf("missing %s scores", name)
+		}
+	}
+
+	n := g.Node(ids["b"]).(graph.Node)
+	if d := n.Attrs()["degree"]; d != 3.0 {
+		t.Errorf("expected degree attribute: %v, got: %v", 3.0, d)
+	}
+
+	if _, ok := n.Attrs()[AuthorityAttr].(float64); !ok {
+		t.Errorf("expected authority attribute, got: %v", n.Attrs())
+	}
+
+	exp := attrs.Schema{"degree": attrs.Float, HubAttr: attrs.Float, AuthorityAttr: attrs.Float}
+	if s := Schema(measures...); !reflect.DeepEqual(s, exp) {
+		t.Errorf("expected schema: %v, got: %v", exp, s)
+	}
+
+	if _, err := ParseMeasures("pagerank,foo"); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
@@ -0,0 +1,317 @@
+package analytics
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	exprand "golang.org/x/exp/rand"
+	gonum "gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/community"
+)
+
+// Algorithm is a community detection algorithm.
+type Algorithm string
+
+const (
+	// Louvain is Louvain modularity maximization.
+	Louvain Algorithm = "louvain"
+	// LabelPropagation is label propagation.
+	LabelPropagation Algorithm = "labelprop"
+)
+
+const (
+	// CommunityAttr is the name of node community attribute.
+	CommunityAttr = "community"
+	// maxIterations limits the number of label propagation iterations.
+	maxIterations = 100
+)
+
+// Communities maps node IDs to the IDs of their communities.
+// Community IDs are ordered by community size, the largest community has ID 0.
+type Communities map[int64]int
+
+// Members returns IDs of the community members ordered by the community IDs.
+func (c Communities) Members() [][]int64 {
+	var members [][]int64
+	for id, cid := range c {
+		for len(members) <= cid {
+			members = append(members, nil)
+		}
+		members[cid] = append(members[cid], id)
+	}
+
+	for _, m := range members {
+		sort.Slice(m, func(i, j int) bool { return m[i] < m[j] })
+	}
+
+	return members
+}
+
+// DetectCommunities detects communities of the nodes in g using the given algorithm.
+// Edge directions are ignored and the edges between the same nodes are merged
+// into a single edge whose weight is the sum of their weights.
+// The results are deterministic for the given Seed option.
+// If Attrs option is set the community IDs are written to node community attributes.
+func DetectCommunities(g graph.Graph, algo Algorithm, opts ...Option) (Communities, error) {
+	aopts, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Communities
+	switch algo {
+	case Louvain:
+		c, err = louvain(g, aopts)
+	case LabelPropagation:
+		c, err = labelPropagation(g, aopts)
+	default:
+		return nil, graph.Errorf(graph.EINVALID, "unsupported algorithm: %q", algo)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if aopts.Attrs {
+		nodes := g.Nodes()
+		for nodes.Next() {
+			n, ok := nodes.Node().(graph.Node)
+			if !ok {
+				return nil, graph.Errorf(graph.EINVALID, "invalid node in graph %s", g.UID())
+			}
+
+			a := n.Attrs()
+			if a == nil {
+				return nil, graph.Errorf(graph.EINVALID, "node %s has no attributes", n.UID())
+			}
+			a[CommunityAttr] = int64(c[n.ID()])
+		}
+	}
+
+	return c, nil
+}
+
+// CommunitySchema returns the attribute schema of node community attribute.
+func CommunitySchema() attrs.Schema {
+	return attrs.Schema{CommunityAttr: attrs.Int}
+}
+
+// louvain detects communities using Louvain modularity maximization.
+func louvain(g graph.Graph, opts Options) (Communities, error) {
+	edges := g.Edges()
+	for edges.Next() {
+		if e, ok := edges.Edge().(gonum.WeightedEdge); ok && e.Weight() < 0 {
+			return nil, graph.Errorf(graph.EINVALID, "negative edge weight: %f", e.Weight())
+		}
+	}
+
+	reduced := community.Modularize(undirected(g), opts.Resolution, exprand.NewSource(uint64(opts.Seed)))
+
+	var members [][]int64
+	for _, comm := range reduced.Communities() {
+		ids := make([]int64, len(comm))
+		for i, n := range comm {
+			ids[i] = n.ID()
+		}
+		members = append(members, ids)
+	}
+
+	return newCommunities(members), nil
+}
+
+// labelPropagation detects communities using label propagation.
+// Nodes are visited in random order and adopt the label with the highest
+// total edge weight among their neighbours. Ties are resolved in favour
+// of the current label of the node, then the smallest label.
+func labelPropagation(g graph.Graph, opts Options) (Communities, error) {
+	u := undirected(g)
+	nodes := gonum.NodesOf(u.Nodes())
+
+	labels := make(map[int64]int64, len(nodes))
+	order := make([]int64, len(nodes))
+	for i, n := range nodes {
+		labels[n.ID()] = n.ID()
+		order[i] = n.ID()
+	}
+
+	rnd := rand.New(rand.NewSource(opts.Seed))
+
+	for i := 0; i < maxIterations; i++ {
+		rnd.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+		changed := false
+		for _, id := range order {
+			weights := make(map[int64]float64)
+			from := u.From(id)
+			for from.Next() {
+				vid := from.Node().ID()
+				w, _ := u.Weight(id, vid)
+				weights[labels[vid]] += w
+			}
+
+			if len(weights) == 0 {
+				continue
+			}
+
+			if best := bestLabel(labels[id], weights); best != labels[id] {
+				labels[id] = best
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	groups := make(map[int64][]int64)
+	for _, n := range nodes {
+		groups[labels[n.ID()]] = append(groups[labels[n.ID()]], n.ID())
+	}
+
+	members := make([][]int64, 0, len(groups))
+	for _, m := range groups {
+		members = append(members, m)
+	}
+
+	return newCommunities(members), nil
+}
+
+// bestLabel returns the label with the highest weight.
+// Ties are resolved in favour of the current label cur, then the smallest label.
+func bestLabel(cur int64, weights map[int64]float64) int64 {
+	max := math.Inf(-1)
+	for _, w := range weights {
+		if w > max {
+			max = w
+		}
+	}
+
+	if w, ok := weights[cur]; ok && w == max {
+		return cur
+	}
+
+	best, found := cur, false
+	for l, w := range weights {
+		if w == max && (!found || l < best) {
+			best, found = l, true
+		}
+	}
+
+	return best
+}
+
+// newCommunities numbers communities by their size in descending order.
+// Communities of the same size are ordered by their smallest node ID.
+func newCommunities(members [][]int64) Communities {
+	for _, m := range members {
+		sort.Slice(m, func(i, j int) bool { return m[i] < m[j] })
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if len(members[i]) != len(members[j]) {
+			return len(members[i]) > len(members[j])
+		}
+		return members[i][0] < members[j][0]
+	})
+
+	c := make(Communities)
+	for cid, m := range members {
+		for _, id := range m {
+			c[id] = cid
+		}
+	}
+
+	return c
+}
+
+// Count is the number of occurrences of a node in community.
+type Count struct {
+	// UID is node UID.
+	UID string `json:"uid"`
+	// Name is node name.
+	Name string `json:"name"`
+	// Count is the number of occurrences.
+	Count int `json:"count"`
+}
+
+// Summary summarizes community.
+type Summary struct {
+	// ID is community ID.
+	ID int `json:"id"`
+	// Size is the number of community members.
+	Size int `json:"size"`
+	// Top are the most frequent nodes in the community keyed by their labels.
+	Top map[string][]Count `json:"top"`
+}
+
+// Summarize returns summaries of communities c of the nodes in g.
+// The summary of each community contains the top n nodes with the given labels
+// which are either the members of the community or the neighbours of its members,
+// ordered by the number of their occurrences. If n is not positive all the nodes are returned.
+func Summarize(g graph.Graph, c Communities, n int, labels ...string) ([]Summary, error) {
+	u := undirected(g)
+
+	selected := make(map[string]bool, len(labels))
+	for _, l := range labels {
+		selected[l] = true
+	}
+
+	var summaries []Summary
+	for cid, members := range c.Members() {
+		counts := make(map[int64]int)
+		for _, id := range members {
+			if node, ok := g.Node(id).(graph.Node); ok && selected[node.Label()] {
+				counts[id]++
+			}
+
+			from := u.From(id)
+			for from.Next() {
+				if node, ok := from.Node().(graph.Node); ok && selected[node.Label()] {
+					counts[node.ID()]++
+				}
+			}
+		}
+
+		top := make(map[string][]Count, len(labels))
+		for id, count := range counts {
+			node, ok := g.Node(id).(graph.Node)
+			if !ok {
+				return nil, graph.Errorf(graph.EINVALID, "invalid node in graph %s", g.UID())
+			}
+
+			name := node.UID()
+			if nm := attrs.ToString("name", node.Attrs()["name"]); nm != "" {
+				name = nm
+			}
+
+			top[node.Label()] = append(top[node.Label()], Count{
+				UID:   node.UID(),
+				Name:  name,
+				Count: count,
+			})
+		}
+
+		for label, counts := range top {
+			sort.Slice(counts, func(i, j int) bool {
+				if counts[i].Count != counts[j].Count {
+					return counts[i].Count > counts[j].Count
+				}
+				return counts[i].Name < counts[j].Name
+			})
+			if n > 0 && len(counts) > n {
+				top[label] = counts[:n]
+			}
+		}
+
+		summaries = append(summaries, Summary{
+			ID:   cid,
+			Size: len(members),
+			Top:  top,
+		})
+	}
+
+	return summaries, nil
+}
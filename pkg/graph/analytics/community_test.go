@@ -0,0 +1,149 @@
+package analytics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+// MustStarsGraph returns the graph with two clusters of repos linked to their topics and languages:
+//
+//	r1, r2, r3 -> cloud, r1, r2, r3 -> go
+//	r4, r5, r6 -> data, r4, r5, r6 -> python
+func MustStarsGraph(t *testing.T) (*memory.Graph, map[string]int64) {
+	t.Helper()
+
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	nodes := []struct {
+		uid   string
+		label string
+	}{
+		{"r1", "Repo"}, {"r2", "Repo"}, {"r3", "Repo"},
+		{"r4", "Repo"}, {"r5", "Repo"}, {"r6", "Repo"},
+		{"cloud", "Topic"}, {"data", "Topic"},
+		{"go", "Lang"}, {"python", "Lang"},
+	}
+
+	ns := make(map[string]*memory.Node)
+	ids := make(map[string]int64)
+	for _, tn := range nodes {
+		n, err := memory.NewNode(g.NewNode().ID(),
+			memory.WithUID(tn.uid),
+			memory.WithLabel(tn.label),
+			memory.WithAttrs(map[string]interface{}{"name": tn.uid}),
+		)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(n)
+		ns[tn.uid] = n
+		ids[tn.uid] = n.ID()
+	}
+
+	for _, te := range [][2]string{
+		{"r1", "cloud"}, {"r2", "cloud"}, {"r3", "cloud"},
+		{"r1", "go"}, {"r2", "go"}, {"r3", "go"},
+		{"r4", "data"}, {"r5", "data"}, {"r6", "data"},
+		{"r4", "python"}, {"r5", "python"}, {"r6", "python"},
+	} {
+		e, err := memory.NewEdge(ns[te[0]], ns[te[1]])
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(e)
+	}
+
+	return g, ids
+}
+
+func TestDetectCommunities(t *testing.T) {
+	for _, algo := range []Algorithm{Louvain, LabelPropagation} {
+		t.Run(string(algo), func(t *testing.T) {
+			g, ids := MustStarsGraph(t)
+
+			c, err := DetectCommunities(g, algo, WithSeed(42), WithAttrs())
+			if err != nil {
+				t.Fatalf("failed to detect communities: %v", err)
+			}
+
+			exp := [][]string{
+				{"r1", "r2", "r3", "cloud", "go"},
+				{"r4", "r5", "r6", "data", "python"},
+			}
+
+			for cid, uids := range exp {
+				for _, uid := range uids {
+					if got := c[ids[uid]]; got != cid {
+						t.Errorf("node %s: expected community: %d, got: %d", uid, cid, got)
+					}
+				}
+			}
+
+			if a := g.Node(ids["r4"]).(graph.Node).Attrs(); a[CommunityAttr] != int64(1) {
+				t.Errorf("expected community attribute: %d, got: %v", 1, a[CommunityAttr])
+			}
+
+			c2, err := DetectCommunities(g, algo, WithSeed(42))
+			if err != nil {
+				t.Fatalf("failed to detect communities: %v", err)
+			}
+
+			if !reflect.DeepEqual(c, c2) {
+				t.Errorf("expected deterministic communities: %v, got: %v", c, c2)
+			}
+		})
+	}
+
+	g, _ := MustStarsGraph(t)
+
+	if _, err := DetectCommunities(g, "foo"); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+
+	if _, err := DetectCommunities(g, Louvain, WithResolution(0)); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	g, _ := MustStarsGraph(t)
+
+	c, err := DetectCommunities(g, Louvain)
+	if err != nil {
+		t.Fatalf("failed to detect communities: %v", err)
+	}
+
+	summaries, err := Summarize(g, c, 1, "Topic", "Lang")
+	if err != nil {
+		t.Fatalf("failed to summarize communities: %v", err)
+	}
+
+	exp := []Summary{
+		{
+			ID:   0,
+			Size: 5,
+			Top: map[string][]Count{
+				"Topic": {{UID: "cloud", Name: "cloud", Count: 4}},
+				"Lang":  {{UID: "go", Name: "go", Count: 4}},
+			},
+		},
+		{
+			ID:   1,
+			Size: 5,
+			Top: map[string][]Count{
+				"Topic": {{UID: "data", Name: "data", Count: 4}},
+				"Lang":  {{UID: "python", Name: "python", Count: 4}},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(summaries, exp) {
+		t.Errorf("expected summaries: %#v, got: %#v", exp, summaries)
+	}
+}
@@ -0,0 +1,93 @@
+package analytics
+
+import "github.com/milosgajdos/orbnet/pkg/graph"
+
+const (
+	// DefaultDamping is the default PageRank damping factor.
+	DefaultDamping = 0.85
+	// DefaultTolerance is the default tolerance of iterative algorithms.
+	DefaultTolerance = 1e-6
+	// DefaultResolution is the default Louvain modularity resolution.
+	DefaultResolution = 1.0
+	// DefaultSeed is the default seed of randomized algorithms.
+	DefaultSeed = 1
+)
+
+// Options configure analytics.
+type Options struct {
+	// Damping is PageRank damping factor.
+	Damping float64
+	// Tolerance terminates PageRank and HITS iterations.
+	Tolerance float64
+	// Resolution is Louvain modularity resolution.
+	Resolution float64
+	// Seed seeds randomized algorithms.
+	Seed int64
+	// Attrs writes the results back to node attributes.
+	Attrs bool
+}
+
+// Option is functional analytics option.
+type Option func(*Options)
+
+// WithDamping sets Damping option.
+func WithDamping(d float64) Option {
+	return func(o *Options) {
+		o.Damping = d
+	}
+}
+
+// WithTolerance sets Tolerance option.
+func WithTolerance(t float64) Option {
+	return func(o *Options) {
+		o.Tolerance = t
+	}
+}
+
+// WithResolution sets Resolution option.
+func WithResolution(r float64) Option {
+	return func(o *Options) {
+		o.Resolution = r
+	}
+}
+
+// WithSeed sets Seed option.
+func WithSeed(s int64) Option {
+	return func(o *Options) {
+		o.Seed = s
+	}
+}
+
+// WithAttrs sets Attrs option.
+func WithAttrs() Option {
+	return func(o *Options) {
+		o.Attrs = true
+	}
+}
+
+func newOptions(opts ...Option) (Options, error) {
+	aopts := Options{
+		Damping:    DefaultDamping,
+		Tolerance:  DefaultTolerance,
+		Resolution: DefaultResolution,
+		Seed:       DefaultSeed,
+	}
+
+	for _, apply := range opts {
+		apply(&aopts)
+	}
+
+	if aopts.Damping <= 0 || aopts.Damping >= 1 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid damping factor: %f", aopts.Damping)
+	}
+
+	if aopts.Tolerance <= 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid tolerance: %f", aopts.Tolerance)
+	}
+
+	if aopts.Resolution <= 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid resolution: %f", aopts.Resolution)
+	}
+
+	return aopts, nil
+}
@@ -0,0 +1,143 @@
+package analytics
+
+import (
+	"sort"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	gonum "gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/iterator"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// directedGraph is a directed view of undirected graph.
+type directedGraph struct {
+	graph.Graph
+}
+
+// To returns all nodes that can reach directly to the node with the given ID.
+func (g directedGraph) To(id int64) gonum.Nodes {
+	return g.From(id)
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (g directedGraph) HasEdgeFromTo(uid, vid int64) bool {
+	return g.HasEdgeBetween(uid, vid)
+}
+
+// directed returns g as directed graph.
+// Undirected graphs are returned as directed graphs with each edge in both directions.
+func directed(g graph.Graph) gonum.Directed {
+	if dg, ok := g.(gonum.Directed); ok {
+		return dg
+	}
+	return directedGraph{Graph: g}
+}
+
+// undirectedGraph is an undirected view of graph.
+// Nodes and neighbours are iterated in the order of their IDs.
+// The weight of the edge between two nodes is the sum of
+// the weights of the edges between them in both directions.
+type undirectedGraph struct {
+	g graph.Graph
+	// to is set if g is directed.
+	to gonum.Directed
+}
+
+// undirected returns g as undirected graph.
+func undirected(g graph.Graph) undirectedGraph {
+	u := undirectedGraph{g: g}
+	if dg, ok := g.(gonum.Directed); ok && graph.IsDirected(g.Type()) {
+		u.to = dg
+	}
+	return u
+}
+
+// Node returns the node with the given ID if it exists in the graph.
+func (u undirectedGraph) Node(id int64) gonum.Node {
+	return u.g.Node(id)
+}
+
+// Nodes returns all the nodes in the graph ordered by their IDs.
+func (u undirectedGraph) Nodes() gonum.Nodes {
+	return iterator.NewOrderedNodes(sortedNodes(u.g.Nodes()))
+}
+
+// From returns all nodes connected to the node with the given ID ordered by their IDs.
+func (u undirectedGraph) From(id int64) gonum.Nodes {
+	nodes := gonum.NodesOf(u.g.From(id))
+	if u.to != nil {
+		seen := make(map[int64]bool, len(nodes))
+		for _, n := range nodes {
+			seen[n.ID()] = true
+		}
+		to := u.to.To(id)
+		for to.Next() {
+			if n := to.Node(); !seen[n.ID()] {
+				nodes = append(nodes, n)
+			}
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID() < nodes[j].ID()
+	})
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y.
+func (u undirectedGraph) HasEdgeBetween(xid, yid int64) bool {
+	return u.g.HasEdgeBetween(xid, yid)
+}
+
+// Edge returns the edge between nodes u and v.
+func (u undirectedGraph) Edge(uid, vid int64) gonum.Edge {
+	return u.WeightedEdge(uid, vid)
+}
+
+// EdgeBetween returns the edge between nodes x and y.
+func (u undirectedGraph) EdgeBetween(xid, yid int64) gonum.Edge {
+	return u.WeightedEdge(xid, yid)
+}
+
+// WeightedEdge returns the weighted edge between nodes u and v.
+func (u undirectedGraph) WeightedEdge(uid, vid int64) gonum.WeightedEdge {
+	w, ok := u.Weight(uid, vid)
+	if !ok || uid == vid {
+		return nil
+	}
+	return simple.WeightedEdge{F: u.g.Node(uid), T: u.g.Node(vid), W: w}
+}
+
+// WeightedEdgeBetween returns the weighted edge between nodes x and y.
+func (u undirectedGraph) WeightedEdgeBetween(xid, yid int64) gonum.WeightedEdge {
+	return u.WeightedEdge(xid, yid)
+}
+
+// Weight returns the weight of the edge between nodes x and y.
+func (u undirectedGraph) Weight(xid, yid int64) (float64, bool) {
+	if xid == yid {
+		return 0, true
+	}
+
+	w, ok := u.g.Weight(xid, yid)
+	if !ok {
+		w = 0
+	}
+
+	if u.to != nil {
+		if rw, rok := u.g.Weight(yid, xid); rok {
+			w += rw
+			ok = true
+		}
+	}
+
+	return w, ok
+}
+
+// sortedNodes returns nodes sorted by their IDs.
+func sortedNodes(it gonum.Nodes) []gonum.Node {
+	nodes := gonum.NodesOf(it)
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID() < nodes[j].ID()
+	})
+	return nodes
+}
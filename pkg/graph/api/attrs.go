@@ -0,0 +1,12 @@
+package api
+
+import "github.com/milosgajdos/orbnet/pkg/graph/attrs"
+
+// CoerceAttrs coerces attributes a to the types declared in schema s.
+// It returns EINVALID error if any of the attributes can't be coerced.
+func CoerceAttrs(s attrs.Schema, a map[string]interface{}) error {
+	if err := s.Coerce(a); err != nil {
+		return Errorf(EINVALID, "invalid attributes: %v", err)
+	}
+	return nil
+}
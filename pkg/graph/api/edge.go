@@ -35,7 +35,9 @@ type EdgeService interface {
 	// the number of returned edges if the Limit field is set.
 	FindEdges(ctx context.Context, uid string, filter EdgeFilter) ([]*Edge, int, error)
 	// UpdateEdgeBetween updates an edge between two nodes.
-	UpdateEdgeBetween(ctx context.Context, uid string, source, target string, update EdgeUpdate) (*Edge, error)
+	// If label is not empty only the edges with the given label are considered.
+	// It fails with ECONFLICT if more than one edge matches.
+	UpdateEdgeBetween(ctx context.Context, uid string, source, target, label string, update EdgeUpdate) (*Edge, error)
 	// DeleteEdge permanently removes an edge by UID.
 	DeleteEdge(ctx context.Context, guid, euid string) error
 	// DeleteEdgeBetween permanently deletes all edges between two nodes.
@@ -13,6 +13,8 @@ type Graph struct {
 	Nodes int `json:"nodes"`
 	// Edge is the edge count.
 	Edges int `json:"edges"`
+	// Type is graph type.
+	Type string `json:"type,omitempty"`
 	// Label is graph label.
 	Label *string `json:"label,omitempty"`
 	// Attrs are graph attributes.
@@ -0,0 +1,308 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/analytics"
+	"github.com/milosgajdos/orbnet/pkg/graph/api"
+	"github.com/milosgajdos/orbnet/pkg/graph/builder/stars"
+	"github.com/milosgajdos/orbnet/pkg/graph/embeddings"
+	"github.com/milosgajdos/orbnet/pkg/graph/recommend"
+)
+
+func (s *Server) registerAnalyticsRoutes(r fiber.Router) {
+	routes := fiber.New()
+	// get communities of the graph nodes
+	routes.Get("/:guid/communities", s.GetCommunities)
+	// get recommendations of the graph nodes
+	routes.Get("/:guid/recommendations", s.GetRecommendations)
+	// get nodes with embeddings similar to the node embedding
+	routes.Get("/:guid/similar", s.GetSimilar)
+	// mount analytics routes to /graphs
+	r.Mount("/graphs", routes)
+}
+
+// GetCommunities detects communities of the graph nodes and returns their summaries.
+// @Summary Graph node communities.
+// @Description Detect communities of the graph nodes and summarize their top nodes.
+// @Tags analytics
+// @Produce json
+// @Param guid path string true "Graph UID"
+// @Param algorithm query string false "Community detection algorithm (louvain, labelprop)"
+// @Param seed query int false "Community detection seed"
+// @Param resolution query number false "Louvain modularity resolution"
+// @Param top query int false "Number of top nodes of each label"
+// @Param labels query string false "Comma separated list of summarized node labels"
+// @Success 200 {object} CommunitiesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/graphs/{guid}/communities [get]
+func (s *Server) GetCommunities(c *fiber.Ctx) error {
+	graphUID := c.Params("guid")
+
+	algo := analytics.Louvain
+	if a := c.Query("algorithm"); a != "" {
+		algo = analytics.Algorithm(a)
+	}
+
+	opts := []analytics.Option{}
+
+	if v := c.Query("seed"); v != "" {
+		seed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Error: "invalid seed: " + v,
+			})
+		}
+		opts = append(opts, analytics.WithSeed(seed))
+	}
+
+	if v := c.Query("resolution"); v != "" {
+		resolution, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Error: "invalid resolution: " + v,
+			})
+		}
+		opts = append(opts, analytics.WithResolution(resolution))
+	}
+
+	// NOTE(milosgajdos): we don't care if the conversion fails
+	top, _ := strconv.Atoi(c.Query("top"))
+	if top <= 0 {
+		top = DefaultTop
+	}
+
+	labels := []string{stars.TopicEntity.String(), stars.LangEntity.String()}
+	if l := c.Query("labels"); l != "" {
+		labels = strings.Split(l, ",")
+	}
+
+	g, err := s.loadGraph(c.Context(), graphUID)
+	if err != nil {
+		if code := api.ErrorCode(err); code == api.ENOTFOUND {
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	communities, err := analytics.DetectCommunities(g, algo, opts...)
+	if err != nil {
+		if code := graph.ErrorCode(err); code == graph.EINVALID {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	summaries, err := analytics.Summarize(g, communities, top, labels...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.JSON(CommunitiesResponse{
+		Communities: summaries,
+		N:           len(summaries),
+	})
+}
+
+// GetRecommendations recommends graph nodes related to the seed nodes.
+// @Summary Graph node recommendations.
+// @Description Recommend graph nodes related to the seed nodes and explain them by the paths from the seeds.
+// @Tags analytics
+// @Produce json
+// @Param guid path string true "Graph UID"
+// @Param seeds query string true "Comma separated list of UIDs or names of the seed nodes"
+// @Param method query string false "Scoring method (pagerank, adamic-adar, common)"
+// @Param label query string false "Label of recommended nodes"
+// @Param via query string false "Comma separated list of labels of the nodes linking recommended nodes"
+// @Param limit query int false "Maximum number of recommendations"
+// @Param paths query int false "Maximum number of explanation paths of each recommendation"
+// @Success 200 {object} RecommendationsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/graphs/{guid}/recommendations [get]
+func (s *Server) GetRecommendations(c *fiber.Ctx) error {
+	graphUID := c.Params("guid")
+
+	if c.Query("seeds") == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: "missing seeds",
+		})
+	}
+	seeds := strings.Split(c.Query("seeds"), ",")
+
+	opts := []recommend.Option{}
+
+	if m := c.Query("method"); m != "" {
+		opts = append(opts, recommend.WithMethod(recommend.Method(m)))
+	}
+
+	if l := c.Query("label"); l != "" {
+		opts = append(opts, recommend.WithLabel(l))
+	}
+
+	if v := c.Query("via"); v != "" {
+		opts = append(opts, recommend.WithVia(strings.Split(v, ",")...))
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Error: "invalid limit: " + v,
+			})
+		}
+		opts = append(opts, recommend.WithLimit(limit))
+	}
+
+	if v := c.Query("paths"); v != "" {
+		paths, err := strconv.Atoi(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Error: "invalid paths: " + v,
+			})
+		}
+		opts = append(opts, recommend.WithPaths(paths))
+	}
+
+	g, err := s.loadGraph(c.Context(), graphUID)
+	if err != nil {
+		if code := api.ErrorCode(err); code == api.ENOTFOUND {
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	recs, err := recommend.Recommend(g, seeds, opts...)
+	if err != nil {
+		switch graph.ErrorCode(err) {
+		case graph.EINVALID:
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		case graph.ENOTFOUND:
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	if recs == nil {
+		recs = []recommend.Recommendation{}
+	}
+
+	return c.JSON(RecommendationsResponse{
+		Recommendations: recs,
+		N:               len(recs),
+	})
+}
+
+// GetSimilar returns the nodes with the embeddings most similar to the embedding of the given node.
+// @Summary Graph nodes similar to a node.
+// @Description Find the nearest neighbours of the node by the cosine similarity of the node embeddings stored in the embedding node attribute.
+// @Tags analytics
+// @Produce json
+// @Param guid path string true "Graph UID"
+// @Param node query string true "Node UID"
+// @Param k query int false "Number of similar nodes"
+// @Param label query string false "Label of similar nodes"
+// @Success 200 {object} SimilarResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/graphs/{guid}/similar [get]
+func (s *Server) GetSimilar(c *fiber.Ctx) error {
+	graphUID := c.Params("guid")
+
+	uid := c.Query("node")
+	if uid == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: "missing node",
+		})
+	}
+
+	k := DefaultLimit
+	if v := c.Query("k"); v != "" {
+		var err error
+		k, err = strconv.Atoi(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Error: "invalid k: " + v,
+			})
+		}
+	}
+
+	label := c.Query("label")
+
+	g, err := s.loadGraph(c.Context(), graphUID)
+	if err != nil {
+		if code := api.ErrorCode(err); code == api.ENOTFOUND {
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	e, err := embeddings.FromAttrs(g)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	nn, err := e.Nearest(uid, k, func(uid string) bool {
+		n := g.NodeWithUID(uid)
+		return n != nil && (label == "" || n.Label() == label)
+	})
+	if err != nil {
+		switch graph.ErrorCode(err) {
+		case graph.EINVALID:
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		case graph.ENOTFOUND:
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.JSON(SimilarResponse{
+		Nodes: nn,
+		N:     len(nn),
+	})
+}
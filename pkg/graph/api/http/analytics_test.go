@@ -0,0 +1,347 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/api"
+	"github.com/milosgajdos/orbnet/pkg/graph/embeddings"
+)
+
+func TestGetCommunities(t *testing.T) {
+	t.Run("200", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.GraphService = MustGraphService(t, db)
+		s.NodeService = MustNodeService(t, db)
+		s.EdgeService = MustEdgeService(t, db)
+
+		uid := "cc099040-9dab-4f3d-848e-3046912aa281"
+		urlPath := fmt.Sprintf("/api/v1/graphs/%s/communities?algorithm=labelprop&seed=2&top=1", uid)
+
+		req := httptest.NewRequest("GET", urlPath, nil)
+
+		resp, err := s.app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to get response: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if code := resp.StatusCode; code != http.StatusOK {
+			t.Fatalf("expected status code: %d, got: %d", http.StatusOK, code)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read response body: %v", err)
+		}
+
+		ret := new(CommunitiesResponse)
+		if err := json.Unmarshal(body, ret); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+
+		if ret.N == 0 || ret.N != len(ret.Communities) {
+			t.Fatalf("expected communities: %d, got: %d", len(ret.Communities), ret.N)
+		}
+
+		var size int
+		for _, c := range ret.Communities {
+			size += c.Size
+			for label, top := range c.Top {
+				if len(top) > 1 {
+					t.Errorf("expected at most 1 top %s node, got: %d", label, len(top))
+				}
+			}
+		}
+
+		if exp := 6; size != exp {
+			t.Errorf("expected community members: %d, got: %d", exp, size)
+		}
+	})
+
+	t.Run("400", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.GraphService = MustGraphService(t, db)
+		s.NodeService = MustNodeService(t, db)
+		s.EdgeService = MustEdgeService(t, db)
+
+		uid := "cc099040-9dab-4f3d-848e-3046912aa281"
+		urlPath := fmt.Sprintf("/api/v1/graphs/%s/communities?algorithm=foo", uid)
+
+		req := httptest.NewRequest("GET", urlPath, nil)
+
+		resp, err := s.app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to get response: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if code := resp.StatusCode; code != http.StatusBadRequest {
+			t.Fatalf("expected status code: %d, got: %d", http.StatusBadRequest, code)
+		}
+	})
+
+	t.Run("404", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.GraphService = MustGraphService(t, db)
+		s.NodeService = MustNodeService(t, db)
+		s.EdgeService = MustEdgeService(t, db)
+
+		req := httptest.NewRequest("GET", "/api/v1/graphs/foo/communities", nil)
+
+		resp, err := s.app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to get response: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if code := resp.StatusCode; code != http.StatusNotFound {
+			t.Fatalf("expected status code: %d, got: %d", http.StatusNotFound, code)
+		}
+	})
+}
+
+func TestGetRecommendations(t *testing.T) {
+	t.Run("200", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.GraphService = MustGraphService(t, db)
+		s.NodeService = MustNodeService(t, db)
+		s.EdgeService = MustEdgeService(t, db)
+
+		uid := "dd099040-9dab-4f3d-848e-3046912aa281"
+		seed := "c2aff266-5103-43af-8977-6c89916da62a"
+		urlPath := fmt.Sprintf("/api/v1/graphs/%s/recommendations?seeds=%s&method=common", uid, seed)
+
+		req := httptest.NewRequest("GET", urlPath, nil)
+
+		resp, err := s.app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to get response: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if code := resp.StatusCode; code != http.StatusOK {
+			t.Fatalf("expected status code: %d, got: %d", http.StatusOK, code)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read response body: %v", err)
+		}
+
+		ret := new(RecommendationsResponse)
+		if err := json.Unmarshal(body, ret); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+
+		if ret.N != 1 || ret.N != len(ret.Recommendations) {
+			t.Fatalf("expected recommendations: %d, got: %d", 1, ret.N)
+		}
+
+		rec := ret.Recommendations[0]
+		if exp := "3d549bb2-dae1-4049-9578-bf70447bedb5"; rec.UID != exp {
+			t.Errorf("expected recommendation: %s, got: %s", exp, rec.UID)
+		}
+
+		if len(rec.Paths) == 0 {
+			t.Errorf("expected recommendation paths")
+		}
+	})
+
+	t.Run("400", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.GraphService = MustGraphService(t, db)
+		s.NodeService = MustNodeService(t, db)
+		s.EdgeService = MustEdgeService(t, db)
+
+		uid := "dd099040-9dab-4f3d-848e-3046912aa281"
+		seed := "c2aff266-5103-43af-8977-6c89916da62a"
+
+		for _, query := range []string{
+			"",
+			"?seeds=" + seed + "&method=foo",
+			"?seeds=" + seed + "&limit=foo",
+		} {
+			urlPath := fmt.Sprintf("/api/v1/graphs/%s/recommendations%s", uid, query)
+
+			req := httptest.NewRequest("GET", urlPath, nil)
+
+			resp, err := s.app.Test(req)
+			if err != nil {
+				t.Fatalf("failed to get response: %v", err)
+			}
+			resp.Body.Close()
+
+			if code := resp.StatusCode; code != http.StatusBadRequest {
+				t.Errorf("%s: expected status code: %d, got: %d", query, http.StatusBadRequest, code)
+			}
+		}
+	})
+
+	t.Run("404", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.GraphService = MustGraphService(t, db)
+		s.NodeService = MustNodeService(t, db)
+		s.EdgeService = MustEdgeService(t, db)
+
+		for _, urlPath := range []string{
+			"/api/v1/graphs/foo/recommendations?seeds=foo",
+			"/api/v1/graphs/dd099040-9dab-4f3d-848e-3046912aa281/recommendations?seeds=foo",
+		} {
+			req := httptest.NewRequest("GET", urlPath, nil)
+
+			resp, err := s.app.Test(req)
+			if err != nil {
+				t.Fatalf("failed to get response: %v", err)
+			}
+			resp.Body.Close()
+
+			if code := resp.StatusCode; code != http.StatusNotFound {
+				t.Errorf("%s: expected status code: %d, got: %d", urlPath, http.StatusNotFound, code)
+			}
+		}
+	})
+}
+
+func TestGetSimilar(t *testing.T) {
+	uid := "dd099040-9dab-4f3d-848e-3046912aa281"
+	node := "c2aff266-5103-43af-8977-6c89916da62a"
+
+	mustEmbed := func(t *testing.T, ns api.NodeService) {
+		t.Helper()
+
+		vectors := map[int64][]interface{}{
+			1: {1.0, 0.0},
+			2: {0.9, 0.1},
+			3: {0.0, 1.0},
+			5: {0.8, 0.4},
+			6: {-1.0, 0.0},
+		}
+
+		for id, vec := range vectors {
+			update := api.NodeUpdate{
+				Attrs: map[string]interface{}{embeddings.Attr: vec},
+			}
+			if _, err := ns.UpdateNode(context.Background(), uid, id, update); err != nil {
+				t.Fatalf("failed to update node %d: %v", id, err)
+			}
+		}
+	}
+
+	t.Run("200", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.GraphService = MustGraphService(t, db)
+		s.NodeService = MustNodeService(t, db)
+		s.EdgeService = MustEdgeService(t, db)
+		mustEmbed(t, s.NodeService)
+
+		for _, tc := range []struct {
+			query string
+			exp   []string
+		}{
+			{"&k=2", []string{"4d021bde-301d-4bf4-bce2-be4813b80c62", "3d549bb2-dae1-4049-9578-bf70447bedb5"}},
+			{"&label=Repo", []string{"3d549bb2-dae1-4049-9578-bf70447bedb5", "01d57f3d-12b0-4c6c-aa66-9d91b3779c7f"}},
+		} {
+			urlPath := fmt.Sprintf("/api/v1/graphs/%s/similar?node=%s%s", uid, node, tc.query)
+
+			req := httptest.NewRequest("GET", urlPath, nil)
+
+			resp, err := s.app.Test(req)
+			if err != nil {
+				t.Fatalf("failed to get response: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if code := resp.StatusCode; code != http.StatusOK {
+				t.Fatalf("%s: expected status code: %d, got: %d", tc.query, http.StatusOK, code)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("failed to read response body: %v", err)
+			}
+
+			ret := new(SimilarResponse)
+			if err := json.Unmarshal(body, ret); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+
+			if ret.N != len(tc.exp) || ret.N != len(ret.Nodes) {
+				t.Fatalf("%s: expected similar nodes: %d, got: %d", tc.query, len(tc.exp), ret.N)
+			}
+
+			for i, n := range ret.Nodes {
+				if n.UID != tc.exp[i] {
+					t.Errorf("%s: expected similar node: %s, got: %s", tc.query, tc.exp[i], n.UID)
+				}
+			}
+		}
+	})
+
+	t.Run("400", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.GraphService = MustGraphService(t, db)
+		s.NodeService = MustNodeService(t, db)
+		s.EdgeService = MustEdgeService(t, db)
+		mustEmbed(t, s.NodeService)
+
+		for _, query := range []string{
+			"",
+			"?node=" + node + "&k=foo",
+			"?node=" + node + "&k=0",
+		} {
+			urlPath := fmt.Sprintf("/api/v1/graphs/%s/similar%s", uid, query)
+
+			req := httptest.NewRequest("GET", urlPath, nil)
+
+			resp, err := s.app.Test(req)
+			if err != nil {
+				t.Fatalf("failed to get response: %v", err)
+			}
+			resp.Body.Close()
+
+			if code := resp.StatusCode; code != http.StatusBadRequest {
+				t.Errorf("%s: expected status code: %d, got: %d", query, http.StatusBadRequest, code)
+			}
+		}
+	})
+
+	t.Run("404", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.GraphService = MustGraphService(t, db)
+		s.NodeService = MustNodeService(t, db)
+		s.EdgeService = MustEdgeService(t, db)
+
+		for _, urlPath := range []string{
+			"/api/v1/graphs/foo/similar?node=foo",
+			// no node embeddings
+			"/api/v1/graphs/" + uid + "/similar?node=" + node,
+		} {
+			req := httptest.NewRequest("GET", urlPath, nil)
+
+			resp, err := s.app.Test(req)
+			if err != nil {
+				t.Fatalf("failed to get response: %v", err)
+			}
+			resp.Body.Close()
+
+			if code := resp.StatusCode; code != http.StatusNotFound {
+				t.Errorf("%s: expected status code: %d, got: %d", urlPath, http.StatusNotFound, code)
+			}
+		}
+	})
+}
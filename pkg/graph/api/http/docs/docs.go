@@ -103,6 +103,83 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/graphs/{guid}/communities": {
+            "get": {
+                "description": "Detect communities of the graph nodes and summarize their top nodes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Graph node communities.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Graph UID",
+                        "name": "guid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Community detection algorithm (louvain, labelprop)",
+                        "name": "algorithm",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Community detection seed",
+                        "name": "seed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Louvain modularity resolution",
+                        "name": "resolution",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of top nodes of each label",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated list of summarized node labels",
+                        "name": "labels",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.CommunitiesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/graphs/{guid}/edges": {
             "get": {
                 "description": "Get all edges matching a query.",
@@ -203,6 +280,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/http.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -308,6 +391,12 @@ const docTemplate = `{
                         "description": "Target node ID",
                         "name": "target",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Edge label",
+                        "name": "label",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -329,6 +418,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/http.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -432,6 +527,63 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/graphs/{guid}/export": {
+            "get": {
+                "description": "Send the graph marshaled in the given format as a file attachment.\nTable formats (neptune, neo4j, parquet, arrow) are sent as zip archives.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/html",
+                    "text/plain",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "graphs"
+                ],
+                "summary": "Export graph.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Graph UID",
+                        "name": "guid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Export format (jsonapi, dot, gexf, graphml, cytoscape, sigma, networkx, html, neptune, neo4j, parquet, arrow, cypher, gremlin, turtle, ntriples, jsonld, svg, png)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/graphs/{guid}/nodes": {
             "get": {
                 "description": "Get all nodes matching a query.",
@@ -805,6 +957,241 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/graphs/{guid}/paths": {
+            "get": {
+                "description": "Find the shortest, k shortest or all simple paths between two graph nodes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "paths"
+                ],
+                "summary": "Get paths between graph nodes.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Graph UID",
+                        "name": "guid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source node UID",
+                        "name": "source",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target node UID",
+                        "name": "target",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Path finding algorithm (dijkstra, astar, yen, all)",
+                        "name": "algorithm",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of the shortest paths found by yen (default 3, maximum 20)",
+                        "name": "k",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of edges of the paths found by all (default 4, maximum 8)",
+                        "name": "max_depth",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of the paths found by all (default 20, maximum 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.PathsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/graphs/{guid}/recommendations": {
+            "get": {
+                "description": "Recommend graph nodes related to the seed nodes and explain them by the paths from the seeds.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Graph node recommendations.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Graph UID",
+                        "name": "guid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated list of UIDs or names of the seed nodes",
+                        "name": "seeds",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scoring method (pagerank, adamic-adar, common)",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label of recommended nodes",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated list of labels of the nodes linking recommended nodes",
+                        "name": "via",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of recommendations",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of explanation paths of each recommendation",
+                        "name": "paths",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.RecommendationsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/graphs/{guid}/similar": {
+            "get": {
+                "description": "Find the nearest neighbours of the node by the cosine similarity of the node embeddings stored in the embedding node attribute.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Graph nodes similar to a node.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Graph UID",
+                        "name": "guid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Node UID",
+                        "name": "node",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of similar nodes",
+                        "name": "k",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label of similar nodes",
+                        "name": "label",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.SimilarResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/graphs/{uid}": {
             "get": {
                 "description": "Get graph returns graph with the given UID.",
@@ -944,6 +1331,46 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "analytics.Count": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Count is the number of occurrences.",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name is node name.",
+                    "type": "string"
+                },
+                "uid": {
+                    "description": "UID is node UID.",
+                    "type": "string"
+                }
+            }
+        },
+        "analytics.Summary": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID is community ID.",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "Size is the number of community members.",
+                    "type": "integer"
+                },
+                "top": {
+                    "description": "Top are the most frequent nodes in the community keyed by their labels.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/analytics.Count"
+                        }
+                    }
+                }
+            }
+        },
         "api.Edge": {
             "type": "object",
             "properties": {
@@ -952,6 +1379,10 @@ const docTemplate = `{
                     "type": "object",
                     "additionalProperties": true
                 },
+                "createdAt": {
+                    "description": "Timestamps for graph creation \u0026 last update.",
+                    "type": "string"
+                },
                 "label": {
                     "description": "Label is the edge label",
                     "type": "string"
@@ -968,6 +1399,9 @@ const docTemplate = `{
                     "description": "UID is edge UUID.",
                     "type": "string"
                 },
+                "updatedAt": {
+                    "type": "string"
+                },
                 "weight": {
                     "description": "Weight is the edge weight.",
                     "type": "number"
@@ -1013,6 +1447,10 @@ const docTemplate = `{
                     "description": "Nodes is the node count.",
                     "type": "integer"
                 },
+                "type": {
+                    "description": "Type is graph type.",
+                    "type": "string"
+                },
                 "uid": {
                     "description": "UID is graph UUID.",
                     "type": "string"
@@ -1083,6 +1521,56 @@ const docTemplate = `{
                 }
             }
         },
+        "api.Path": {
+            "type": "object",
+            "properties": {
+                "edges": {
+                    "description": "Edges are the path edges ordered from the source to the target node.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.Edge"
+                    }
+                },
+                "nodes": {
+                    "description": "Nodes are the path nodes ordered from the source to the target node.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.Node"
+                    }
+                },
+                "weight": {
+                    "description": "Weight is the sum of the path edge weights.",
+                    "type": "number"
+                }
+            }
+        },
+        "embeddings.Neighbour": {
+            "type": "object",
+            "properties": {
+                "similarity": {
+                    "description": "Similarity is the cosine similarity of the node vector to the queried vector.",
+                    "type": "number"
+                },
+                "uid": {
+                    "description": "UID is node UID.",
+                    "type": "string"
+                }
+            }
+        },
+        "http.CommunitiesResponse": {
+            "type": "object",
+            "properties": {
+                "communities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/analytics.Summary"
+                    }
+                },
+                "n": {
+                    "type": "integer"
+                }
+            }
+        },
         "http.EdgesResponse": {
             "type": "object",
             "properties": {
@@ -1132,6 +1620,109 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "http.PathsResponse": {
+            "type": "object",
+            "properties": {
+                "n": {
+                    "type": "integer"
+                },
+                "paths": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.Path"
+                    }
+                }
+            }
+        },
+        "http.RecommendationsResponse": {
+            "type": "object",
+            "properties": {
+                "n": {
+                    "type": "integer"
+                },
+                "recommendations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/recommend.Recommendation"
+                    }
+                }
+            }
+        },
+        "http.SimilarResponse": {
+            "type": "object",
+            "properties": {
+                "n": {
+                    "type": "integer"
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/embeddings.Neighbour"
+                    }
+                }
+            }
+        },
+        "recommend.Hop": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "description": "Label is node label.",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is node name.",
+                    "type": "string"
+                },
+                "uid": {
+                    "description": "UID is node UID.",
+                    "type": "string"
+                }
+            }
+        },
+        "recommend.Path": {
+            "type": "object",
+            "properties": {
+                "hops": {
+                    "description": "Hops are the nodes on the path.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/recommend.Hop"
+                    }
+                },
+                "score": {
+                    "description": "Score is the contribution of the path to Adamic-Adar score.",
+                    "type": "number"
+                }
+            }
+        },
+        "recommend.Recommendation": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "description": "Label is node label.",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is node name.",
+                    "type": "string"
+                },
+                "paths": {
+                    "description": "Paths explain the recommendation.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/recommend.Path"
+                    }
+                },
+                "score": {
+                    "description": "Score is recommendation score.",
+                    "type": "number"
+                },
+                "uid": {
+                    "description": "UID is node UID.",
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -121,6 +121,7 @@ func (s *Server) GetEdgeByUID(c *fiber.Ctx) error {
 // @Success 200 {object} api.Edge
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /v1/graphs/{guid}/edges [post]
 func (s *Server) CreateEdge(c *fiber.Ctx) error {
@@ -140,12 +141,24 @@ func (s *Server) CreateEdge(c *fiber.Ctx) error {
 	}
 
 	if err := s.EdgeService.CreateEdge(c.Context(), graphUID, edge); err != nil {
+		if code := api.ErrorCode(err); code == api.EINVALID {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
 		if code := api.ErrorCode(err); code == api.ENOTFOUND {
 			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
 				Error: err.Error(),
 			})
 		}
 
+		if code := api.ErrorCode(err); code == api.ECONFLICT {
+			return c.Status(fiber.StatusConflict).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
 			Error: err.Error(),
 		})
@@ -164,9 +177,11 @@ func (s *Server) CreateEdge(c *fiber.Ctx) error {
 // @Param graph body api.EdgeUpdate true "Update an edge"
 // @Param source query int false "Source node ID"
 // @Param target query int false "Target node ID"
+// @Param label query string false "Edge label"
 // @Success 200 {object} api.Edge
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /v1/graphs/{guid}/edges [patch]
 func (s *Server) UpdateEdgeBetween(c *fiber.Ctx) error {
@@ -193,14 +208,26 @@ func (s *Server) UpdateEdgeBetween(c *fiber.Ctx) error {
 		})
 	}
 
-	edge, err := s.EdgeService.UpdateEdgeBetween(c.Context(), graphUID, source, target, *update)
+	edge, err := s.EdgeService.UpdateEdgeBetween(c.Context(), graphUID, source, target, c.Query("label"), *update)
 	if err != nil {
+		if code := api.ErrorCode(err); code == api.EINVALID {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
 		if code := api.ErrorCode(err); code == api.ENOTFOUND {
 			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
 				Error: err.Error(),
 			})
 		}
 
+		if code := api.ErrorCode(err); code == api.ECONFLICT {
+			return c.Status(fiber.StatusConflict).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
 			Error: err.Error(),
 		})
@@ -250,8 +250,8 @@ func TestCreateEdge(t *testing.T) {
 			"two": "twostring",
 		}
 		apiEdge := &api.Edge{
-			Source: "a877d937-673d-4b43-bb5c-8f9387e43298",
-			Target: "3ba4972d-c780-4308-9ca8-fe466b60da20",
+			Source: "014e5c91-5d5e-4d34-8284-4354aa9f62cd",
+			Target: "a877d937-673d-4b43-bb5c-8f9387e43298",
 			Label:  label,
 			Attrs:  attrs,
 		}
@@ -413,6 +413,38 @@ func TestCreateEdge(t *testing.T) {
 			t.Fatalf("expected status code: %d, got: %d", http.StatusInternalServerError, code)
 		}
 	})
+
+	t.Run("409", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.EdgeService = MustEdgeService(t, db)
+
+		apiEdge := &api.Edge{
+			Source: "a877d937-673d-4b43-bb5c-8f9387e43298",
+			Target: "3ba4972d-c780-4308-9ca8-fe466b60da20",
+			Label:  "foolabel",
+		}
+
+		testBody, err := json.Marshal(apiEdge)
+		if err != nil {
+			t.Fatalf("failed to serialise req body: %v", err)
+		}
+
+		uid := "cc099040-9dab-4f3d-848e-3046912aa281"
+		urlPath := fmt.Sprintf("/api/v1/graphs/%s/edges", uid)
+		req := httptest.NewRequest("POST", urlPath, bytes.NewReader(testBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to get response: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if code := resp.StatusCode; code != http.StatusConflict {
+			t.Fatalf("expected status code: %d, got: %d", http.StatusConflict, code)
+		}
+	})
 }
 
 func TestDeleteEdgeByUID(t *testing.T) {
@@ -0,0 +1,192 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/api"
+	jsonapi "github.com/milosgajdos/orbnet/pkg/graph/api/memory/marshal/json"
+	"github.com/milosgajdos/orbnet/pkg/graph/builder/stars"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/cypher"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/cytoscape"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/dot"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/gexf"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/graphml"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/gremlin"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/html"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/neptune"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/networkx"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/parquet"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/rdf"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/render"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/sigma"
+)
+
+const (
+	// DefaultExportFormat is the default graph export format.
+	DefaultExportFormat = "jsonapi"
+)
+
+// exportFormat is a graph export format.
+type exportFormat struct {
+	// ext is the extension of the exported file.
+	ext string
+	// contentType is the content type of the exported graph.
+	contentType string
+	// stream is true if the marshaler writes the graph while marshaling it.
+	// Other formats are marshaled before the response is sent.
+	stream bool
+	// marshaler returns the marshaler of the graph named name.
+	marshaler func(name string) (graph.StreamMarshaler, error)
+}
+
+// exportFormats are the supported graph export formats.
+// NOTE(milosgajdos): mermaid and d2 formats are not supported
+// as they refuse graphs with more than a few dozen nodes.
+var exportFormats = map[string]exportFormat{
+	"jsonapi": {"json", fiber.MIMEApplicationJSON, true, func(name string) (graph.StreamMarshaler, error) {
+		return jsonapi.NewMarshaler(name, "", "  ")
+	}},
+	"dot": {"dot", "text/vnd.graphviz", false, func(name string) (graph.StreamMarshaler, error) {
+		return dot.NewMarshaler(name, "", "  ")
+	}},
+	"gexf": {"gexf", fiber.MIMEApplicationXML, false, func(name string) (graph.StreamMarshaler, error) {
+		return gexf.NewMarshaler(name, "", "  ", gexf.WithSchema(stars.Schema()))
+	}},
+	"graphml": {"graphml", fiber.MIMEApplicationXML, false, func(name string) (graph.StreamMarshaler, error) {
+		return graphml.NewMarshaler(name, "", "  ", graphml.WithSchema(stars.Schema()))
+	}},
+	"cytoscape": {"json", fiber.MIMEApplicationJSON, true, func(name string) (graph.StreamMarshaler, error) {
+		return cytoscape.NewMarshaler(name, "", "  ")
+	}},
+	"sigma": {"json", fiber.MIMEApplicationJSON, true, func(name string) (graph.StreamMarshaler, error) {
+		return sigma.NewMarshaler(name, "", "  ")
+	}},
+	"networkx": {"json", fiber.MIMEApplicationJSON, true, func(name string) (graph.StreamMarshaler, error) {
+		return networkx.NewMarshaler(name, "", "  ")
+	}},
+	"html": {"html", fiber.MIMETextHTMLCharsetUTF8, false, func(name string) (graph.StreamMarshaler, error) {
+		return html.NewMarshaler(name)
+	}},
+	"neptune": {"zip", "application/zip", false, func(string) (graph.StreamMarshaler, error) {
+		return neptune.NewMarshaler(neptune.WithDialect(neptune.Gremlin), neptune.WithSchema(stars.Schema()))
+	}},
+	"neo4j": {"zip", "application/zip", false, func(string) (graph.StreamMarshaler, error) {
+		return neptune.NewMarshaler(neptune.WithDialect(neptune.Neo4j), neptune.WithSchema(stars.Schema()))
+	}},
+	"parquet": {"zip", "application/zip", false, func(string) (graph.StreamMarshaler, error) {
+		return parquet.NewMarshaler(parquet.WithFormat(parquet.Parquet), parquet.WithSchema(stars.Schema()))
+	}},
+	"arrow": {"zip", "application/zip", false, func(string) (graph.StreamMarshaler, error) {
+		return parquet.NewMarshaler(parquet.WithFormat(parquet.Arrow), parquet.WithSchema(stars.Schema()))
+	}},
+	"cypher": {"cypher", fiber.MIMETextPlainCharsetUTF8, false, func(string) (graph.StreamMarshaler, error) {
+		return cypher.NewMarshaler(cypher.WithSchema(stars.Schema()))
+	}},
+	"gremlin": {"groovy", fiber.MIMETextPlainCharsetUTF8, false, func(string) (graph.StreamMarshaler, error) {
+		return gremlin.NewMarshaler(gremlin.WithSchema(stars.Schema()))
+	}},
+	"turtle": {"ttl", "text/turtle", true, func(string) (graph.StreamMarshaler, error) {
+		return rdf.NewMarshaler(rdf.WithFormat(rdf.Turtle), rdf.WithSchema(stars.Schema()))
+	}},
+	"ntriples": {"nt", "application/n-triples", true, func(string) (graph.StreamMarshaler, error) {
+		return rdf.NewMarshaler(rdf.WithFormat(rdf.NTriples), rdf.WithSchema(stars.Schema()))
+	}},
+	"jsonld": {"jsonld", "application/ld+json", true, func(string) (graph.StreamMarshaler, error) {
+		return rdf.NewMarshaler(rdf.WithFormat(rdf.JSONLD), rdf.WithSchema(stars.Schema()))
+	}},
+	"svg": {"svg", "image/svg+xml", false, func(string) (graph.StreamMarshaler, error) {
+		return render.NewMarshaler(render.WithFormat(render.SVG))
+	}},
+	"png": {"png", "image/png", false, func(string) (graph.StreamMarshaler, error) {
+		return render.NewMarshaler(render.WithFormat(render.PNG))
+	}},
+}
+
+func (s *Server) registerExportRoutes(r fiber.Router) {
+	routes := fiber.New()
+	// export graph in the given format
+	routes.Get("/:guid/export", s.ExportGraph)
+	// mount export routes to /graphs
+	r.Mount("/graphs", routes)
+}
+
+// ExportGraph sends the graph marshaled in the given format.
+// JSON and RDF formats are streamed while they're marshaled; the other
+// formats are marshaled first so that marshaling errors are reported.
+// @Summary Export graph.
+// @Description Send the graph marshaled in the given format as a file attachment.
+// @Description Table formats (neptune, neo4j, parquet, arrow) are sent as zip archives.
+// @Tags graphs
+// @Produce json,xml,html,plain,octet-stream
+// @Param guid path string true "Graph UID"
+// @Param format query string false "Export format (jsonapi, dot, gexf, graphml, cytoscape, sigma, networkx, html, neptune, neo4j, parquet, arrow, cypher, gremlin, turtle, ntriples, jsonld, svg, png)"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/graphs/{guid}/export [get]
+func (s *Server) ExportGraph(c *fiber.Ctx) error {
+	graphUID := c.Params("guid")
+
+	name := c.Query("format", DefaultExportFormat)
+	format, ok := exportFormats[name]
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: "unsupported format: " + name,
+		})
+	}
+
+	g, err := s.loadGraph(c.Context(), graphUID)
+	if err != nil {
+		if code := api.ErrorCode(err); code == api.ENOTFOUND {
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	m, err := format.marshaler(graphUID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	var buf bytes.Buffer
+	if !format.stream {
+		if err := m.MarshalTo(&buf, g); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, format.contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", graphUID+"."+format.ext))
+
+	if !format.stream {
+		return c.Send(buf.Bytes())
+	}
+
+	// NOTE(milosgajdos): the response status has been sent by the time
+	// streaming fails so the error is logged and aborts the response.
+	pr, pw := io.Pipe()
+	go func() {
+		err := m.MarshalTo(pw, g)
+		if err != nil {
+			log.Printf("failed to export graph %s as %s: %v", graphUID, name, err)
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return c.SendStream(pr)
+}
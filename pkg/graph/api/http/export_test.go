@@ -0,0 +1,201 @@
+package http
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/api"
+)
+
+// failMarshaler fails to marshal graphs after writing partial data.
+type failMarshaler struct{}
+
+func (failMarshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	if _, err := io.WriteString(w, "partial"); err != nil {
+		return err
+	}
+	return graph.Errorf(graph.EINTERNAL, "marshal failed")
+}
+
+func TestExportGraph(t *testing.T) {
+	uid := "cc099040-9dab-4f3d-848e-3046912aa281"
+
+	t.Run("200", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.GraphService = MustGraphService(t, db)
+		s.NodeService = MustNodeService(t, db)
+		s.EdgeService = MustEdgeService(t, db)
+
+		urlPath := fmt.Sprintf("/api/v1/graphs/%s/export", uid)
+
+		req := httptest.NewRequest("GET", urlPath, nil)
+
+		resp, err := s.app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to get response: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if code := resp.StatusCode; code != http.StatusOK {
+			t.Fatalf("expected status code: %d, got: %d", http.StatusOK, code)
+		}
+
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("expected content type: application/json, got: %s", ct)
+		}
+
+		if cd, exp := resp.Header.Get("Content-Disposition"), uid+".json"; !strings.Contains(cd, exp) {
+			t.Errorf("expected content disposition with file: %s, got: %s", exp, cd)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read response body: %v", err)
+		}
+
+		ret := new(struct {
+			Graph api.Graph  `json:"graph"`
+			Nodes []api.Node `json:"nodes"`
+			Edges []api.Edge `json:"edges"`
+		})
+		if err := json.Unmarshal(body, ret); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+
+		if ret.Graph.UID != uid {
+			t.Errorf("expected graph: %s, got: %s", uid, ret.Graph.UID)
+		}
+
+		if len(ret.Nodes) == 0 || len(ret.Nodes) != ret.Graph.Nodes {
+			t.Errorf("expected nodes: %d, got: %d", ret.Graph.Nodes, len(ret.Nodes))
+		}
+
+		if len(ret.Edges) != ret.Graph.Edges {
+			t.Errorf("expected edges: %d, got: %d", ret.Graph.Edges, len(ret.Edges))
+		}
+	})
+
+	t.Run("200Zip", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.GraphService = MustGraphService(t, db)
+		s.NodeService = MustNodeService(t, db)
+		s.EdgeService = MustEdgeService(t, db)
+
+		urlPath := fmt.Sprintf("/api/v1/graphs/%s/export?format=parquet", uid)
+
+		req := httptest.NewRequest("GET", urlPath, nil)
+
+		resp, err := s.app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to get response: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if code := resp.StatusCode; code != http.StatusOK {
+			t.Fatalf("expected status code: %d, got: %d", http.StatusOK, code)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read response body: %v", err)
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			t.Fatalf("failed to read zip archive: %v", err)
+		}
+
+		var names []string
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+
+		if exp := "nodes.parquet,edges.parquet"; strings.Join(names, ",") != exp {
+			t.Errorf("expected files: %s, got: %v", exp, names)
+		}
+	})
+
+	t.Run("400", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.GraphService = MustGraphService(t, db)
+		s.NodeService = MustNodeService(t, db)
+		s.EdgeService = MustEdgeService(t, db)
+
+		urlPath := fmt.Sprintf("/api/v1/graphs/%s/export?format=foo", uid)
+
+		req := httptest.NewRequest("GET", urlPath, nil)
+
+		resp, err := s.app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to get response: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if code := resp.StatusCode; code != http.StatusBadRequest {
+			t.Fatalf("expected status code: %d, got: %d", http.StatusBadRequest, code)
+		}
+	})
+
+	t.Run("404", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.GraphService = MustGraphService(t, db)
+		s.NodeService = MustNodeService(t, db)
+		s.EdgeService = MustEdgeService(t, db)
+
+		req := httptest.NewRequest("GET", "/api/v1/graphs/foo/export", nil)
+
+		resp, err := s.app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to get response: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if code := resp.StatusCode; code != http.StatusNotFound {
+			t.Fatalf("expected status code: %d, got: %d", http.StatusNotFound, code)
+		}
+	})
+
+	t.Run("500", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.GraphService = MustGraphService(t, db)
+		s.NodeService = MustNodeService(t, db)
+		s.EdgeService = MustEdgeService(t, db)
+
+		exportFormats["fail"] = exportFormat{"txt", fiber.MIMETextPlainCharsetUTF8, false, func(string) (graph.StreamMarshaler, error) {
+			return failMarshaler{}, nil
+		}}
+		defer delete(exportFormats, "fail")
+
+		urlPath := fmt.Sprintf("/api/v1/graphs/%s/export?format=fail", uid)
+
+		req := httptest.NewRequest("GET", urlPath, nil)
+
+		resp, err := s.app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to get response: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if code := resp.StatusCode; code != http.StatusInternalServerError {
+			t.Fatalf("expected status code: %d, got: %d", http.StatusInternalServerError, code)
+		}
+
+		if cd := resp.Header.Get("Content-Disposition"); cd != "" {
+			t.Errorf("expected no content disposition, got: %s", cd)
+		}
+	})
+}
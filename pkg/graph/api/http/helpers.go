@@ -1,9 +1,15 @@
 package http
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
 
 	"github.com/milosgajdos/orbnet/pkg/graph/api"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
 )
 
 func validateEdge(e api.Edge) error {
@@ -17,6 +23,116 @@ func validateEdge(e api.Edge) error {
 	return nil
 }
 
+// queryInt returns the value of the integer query parameter with the given name.
+// If the parameter is not set def is returned. It returns error if the value
+// is not a positive integer or if it exceeds maxValue.
+func queryInt(c *fiber.Ctx, name string, def, maxValue int) (int, error) {
+	v := c.Query(name)
+	if v == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", name, v)
+	}
+
+	if n <= 0 || n > maxValue {
+		return 0, fmt.Errorf("%s out of range [1, %d]: %d", name, maxValue, n)
+	}
+
+	return n, nil
+}
+
 func StringPtr(s string) *string {
 	return &s
 }
+
+// loadGraph loads the graph with the given uid from the API services into memory.
+func (s *Server) loadGraph(ctx context.Context, uid string) (*memory.Graph, error) {
+	ag, err := s.GraphService.FindGraphByUID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []memory.Option{
+		memory.WithUID(ag.UID),
+	}
+
+	if ag.Attrs != nil {
+		opts = append(opts, memory.WithAttrs(ag.Attrs))
+	}
+
+	if ag.Type != "" {
+		opts = append(opts, memory.WithType(ag.Type))
+	}
+
+	if ag.Label != nil {
+		opts = append(opts, memory.WithLabel(*ag.Label))
+	}
+
+	g, err := memory.NewGraph(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, _, err := s.NodeService.FindNodes(ctx, uid, api.NodeFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range nodes {
+		nopts := []memory.Option{
+			memory.WithUID(n.UID),
+		}
+
+		if n.Attrs != nil {
+			nopts = append(nopts, memory.WithAttrs(n.Attrs))
+		}
+
+		if n.Label != nil {
+			nopts = append(nopts, memory.WithLabel(*n.Label))
+		}
+
+		node, err := memory.NewNode(n.ID, nopts...)
+		if err != nil {
+			return nil, err
+		}
+		g.AddNode(node)
+	}
+
+	edges, _, err := s.EdgeService.FindEdges(ctx, uid, api.EdgeFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range edges {
+		from := g.NodeWithUID(e.Source)
+		if from == nil {
+			return nil, api.Errorf(api.EINTERNAL, "source node %s not found", e.Source)
+		}
+
+		to := g.NodeWithUID(e.Target)
+		if to == nil {
+			return nil, api.Errorf(api.EINTERNAL, "target node %s not found", e.Target)
+		}
+
+		eopts := []memory.Option{
+			memory.WithUID(e.UID),
+			memory.WithLabel(e.Label),
+			memory.WithWeight(e.Weight),
+		}
+
+		if e.Attrs != nil {
+			eopts = append(eopts, memory.WithAttrs(e.Attrs))
+		}
+
+		edge, err := memory.NewEdge(from, to, eopts...)
+		if err != nil {
+			return nil, err
+		}
+		g.SetWeightedEdge(edge)
+	}
+
+	return g, nil
+}
@@ -1,10 +1,23 @@
 package http
 
-import "github.com/milosgajdos/orbnet/pkg/graph/api"
+import (
+	"github.com/milosgajdos/orbnet/pkg/graph/analytics"
+	"github.com/milosgajdos/orbnet/pkg/graph/api"
+	"github.com/milosgajdos/orbnet/pkg/graph/embeddings"
+	"github.com/milosgajdos/orbnet/pkg/graph/recommend"
+)
 
 const (
 	// DefaultLimit defines default results limit
 	DefaultLimit = 20
+	// DefaultTop defines default number of top nodes in community summaries.
+	DefaultTop = 5
+	// MaxPathK defines maximum number of the shortest paths found by yen.
+	MaxPathK = 20
+	// MaxPathDepth defines maximum number of edges of the paths found by all.
+	MaxPathDepth = 8
+	// MaxPathLimit defines maximum number of the paths found by all.
+	MaxPathLimit = 100
 )
 
 // GraphsResponse is returned when querying graphs.
@@ -25,6 +38,30 @@ type EdgesResponse struct {
 	N     int         `json:"n"`
 }
 
+// CommunitiesResponse is returned when querying graph communities.
+type CommunitiesResponse struct {
+	Communities []analytics.Summary `json:"communities"`
+	N           int                 `json:"n"`
+}
+
+// RecommendationsResponse is returned when querying graph node recommendations.
+type RecommendationsResponse struct {
+	Recommendations []recommend.Recommendation `json:"recommendations"`
+	N               int                        `json:"n"`
+}
+
+// SimilarResponse is returned when querying graph nodes similar to a node.
+type SimilarResponse struct {
+	Nodes []embeddings.Neighbour `json:"nodes"`
+	N     int                    `json:"n"`
+}
+
+// PathsResponse is returned when querying paths between graph nodes.
+type PathsResponse struct {
+	Paths []*api.Path `json:"paths"`
+	N     int         `json:"n"`
+}
+
 // ErrorResponse represents a JSON structure for error output.
 type ErrorResponse struct {
 	Error string `json:"error"`
@@ -181,6 +181,12 @@ func (s *Server) CreateNode(c *fiber.Ctx) error {
 
 	// TODO(milosgajdos): validate node here
 	if err := s.NodeService.CreateNode(c.Context(), graphUID, node); err != nil {
+		if code := api.ErrorCode(err); code == api.EINVALID {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
 		if code := api.ErrorCode(err); code == api.ENOTFOUND {
 			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
 				Error: err.Error(),
@@ -236,6 +242,12 @@ func (s *Server) UpdateNode(c *fiber.Ctx) error {
 
 	node, err := s.NodeService.UpdateNode(c.Context(), graphUID, id, *update)
 	if err != nil {
+		if code := api.ErrorCode(err); code == api.EINVALID {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
 		if code := api.ErrorCode(err); code == api.ENOTFOUND {
 			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
 				Error: err.Error(),
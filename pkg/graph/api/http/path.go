@@ -0,0 +1,98 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/milosgajdos/orbnet/pkg/graph/api"
+	"github.com/milosgajdos/orbnet/pkg/graph/paths"
+)
+
+func (s *Server) registerPathRoutes(r fiber.Router) {
+	routes := fiber.New()
+	// get paths between two nodes in the graph
+	routes.Get("/:guid/paths", s.GetPaths)
+	// mount path routes to /graphs
+	r.Mount("/graphs", routes)
+}
+
+// GetPaths returns the paths between two graph nodes.
+// @Summary Get paths between graph nodes.
+// @Description Find the shortest, k shortest or all simple paths between two graph nodes.
+// @Tags paths
+// @Produce json
+// @Param guid path string true "Graph UID"
+// @Param source query string true "Source node UID"
+// @Param target query string true "Target node UID"
+// @Param algorithm query string false "Path finding algorithm (dijkstra, astar, yen, all)"
+// @Param k query int false "Number of the shortest paths found by yen (default 3, maximum 20)"
+// @Param max_depth query int false "Maximum number of edges of the paths found by all (default 4, maximum 8)"
+// @Param limit query int false "Maximum number of the paths found by all (default 20, maximum 100)"
+// @Success 200 {object} PathsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/graphs/{guid}/paths [get]
+func (s *Server) GetPaths(c *fiber.Ctx) error {
+	graphUID := c.Params("guid")
+
+	source, target := c.Query("source"), c.Query("target")
+	if source == "" || target == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: "missing source or target",
+		})
+	}
+
+	var filter api.PathFilter
+
+	if algo := c.Query("algorithm"); algo != "" {
+		filter.Algorithm = new(string)
+		*filter.Algorithm = algo
+	}
+
+	k, err := queryInt(c, "k", paths.DefaultK, MaxPathK)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	filter.K = &k
+
+	depth, err := queryInt(c, "max_depth", paths.DefaultMaxDepth, MaxPathDepth)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	filter.MaxDepth = &depth
+
+	// NOTE: the limit is always set as unlimited search of all the simple paths
+	// would hold the store for as long as it takes to enumerate them.
+	filter.Limit, err = queryInt(c, "limit", DefaultLimit, MaxPathLimit)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	px, err := s.PathService.FindPaths(c.Context(), graphUID, source, target, filter)
+	if err != nil {
+		switch api.ErrorCode(err) {
+		case api.EINVALID:
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		case api.ENOTFOUND:
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.JSON(PathsResponse{
+		Paths: px,
+		N:     len(px),
+	})
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/api"
+	"github.com/milosgajdos/orbnet/pkg/graph/api/memory"
+)
+
+func MustPathService(t *testing.T, db *memory.DB) api.PathService {
+	ps, err := memory.NewPathService(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ps
+}
+
+func TestGetPaths(t *testing.T) {
+	guid := "cc099040-9dab-4f3d-848e-3046912aa281"
+	source := "8796cc19-a6f4-4bbf-8fd7-4dde5ad74a70"
+	target := "7817e62b-1625-48d1-88b2-b7cc5560eab0"
+
+	t.Run("200", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.PathService = MustPathService(t, db)
+
+		urlPath := fmt.Sprintf("/api/v1/graphs/%s/paths?source=%s&target=%s&algorithm=yen&k=2", guid, source, target)
+
+		req := httptest.NewRequest("GET", urlPath, nil)
+
+		resp, err := s.app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to get response: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if code := resp.StatusCode; code != http.StatusOK {
+			t.Fatalf("expected status code: %d, got: %d", http.StatusOK, code)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read response body: %v", err)
+		}
+
+		ret := new(PathsResponse)
+		if err := json.Unmarshal(body, ret); err != nil {
+			t.Fatalf("failed to decode body: %v", err)
+		}
+
+		if ret.N != 1 || len(ret.Paths) != 1 {
+			t.Fatalf("expected paths: %d, got: %d", 1, ret.N)
+		}
+
+		p := ret.Paths[0]
+		if len(p.Nodes) != 5 || len(p.Edges) != 4 {
+			t.Fatalf("unexpected path: %d nodes, %d edges", len(p.Nodes), len(p.Edges))
+		}
+
+		if p.Nodes[0].UID != source || p.Nodes[4].UID != target {
+			t.Errorf("unexpected path ends: %s, %s", p.Nodes[0].UID, p.Nodes[4].UID)
+		}
+	})
+
+	t.Run("400", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.PathService = MustPathService(t, db)
+
+		for _, query := range []string{
+			fmt.Sprintf("source=%s", source),
+			fmt.Sprintf("source=%s&target=%s&algorithm=foo", source, target),
+			fmt.Sprintf("source=%s&target=%s&k=foo", source, target),
+			fmt.Sprintf("source=%s&target=%s&algorithm=yen&k=0", source, target),
+			fmt.Sprintf("source=%s&target=%s&algorithm=yen&k=%d", source, target, MaxPathK+1),
+			fmt.Sprintf("source=%s&target=%s&algorithm=all&max_depth=%d", source, target, MaxPathDepth+1),
+			fmt.Sprintf("source=%s&target=%s&algorithm=all&limit=0", source, target),
+			fmt.Sprintf("source=%s&target=%s&algorithm=all&limit=%d", source, target, MaxPathLimit+1),
+		} {
+			urlPath := fmt.Sprintf("/api/v1/graphs/%s/paths?%s", guid, query)
+
+			req := httptest.NewRequest("GET", urlPath, nil)
+
+			resp, err := s.app.Test(req)
+			if err != nil {
+				t.Fatalf("failed to get response: %v", err)
+			}
+			resp.Body.Close()
+
+			if code := resp.StatusCode; code != http.StatusBadRequest {
+				t.Errorf("%s: expected status code: %d, got: %d", query, http.StatusBadRequest, code)
+			}
+		}
+	})
+
+	t.Run("404", func(t *testing.T) {
+		s := MustServer(t)
+		db := MustOpenDB(t, testDir)
+		s.PathService = MustPathService(t, db)
+
+		for _, urlPath := range []string{
+			fmt.Sprintf("/api/v1/graphs/foo/paths?source=%s&target=%s", source, target),
+			fmt.Sprintf("/api/v1/graphs/%s/paths?source=foo&target=%s", guid, target),
+		} {
+			req := httptest.NewRequest("GET", urlPath, nil)
+
+			resp, err := s.app.Test(req)
+			if err != nil {
+				t.Fatalf("failed to get response: %v", err)
+			}
+			resp.Body.Close()
+
+			if code := resp.StatusCode; code != http.StatusNotFound {
+				t.Errorf("%s: expected status code: %d, got: %d", urlPath, http.StatusNotFound, code)
+			}
+		}
+	})
+}
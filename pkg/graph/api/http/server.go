@@ -29,6 +29,8 @@ type Server struct {
 	NodeService api.NodeService
 	// EdgeService provides access to Edge endpoints.
 	EdgeService api.EdgeService
+	// PathService provides access to Path endpoints.
+	PathService api.PathService
 }
 
 type Config struct {
@@ -61,6 +63,9 @@ func NewServer(config ...Config) (*Server, error) {
 	s.registerGraphRoutes(v1)
 	s.registerNodeRoutes(v1)
 	s.registerEdgeRoutes(v1)
+	s.registerPathRoutes(v1)
+	s.registerAnalyticsRoutes(v1)
+	s.registerExportRoutes(v1)
 
 	return s, nil
 }
@@ -79,10 +79,23 @@ func (es *EdgeService) FindEdges(ctx context.Context, guid string, filter api.Ed
 	edges := make([]*api.Edge, len(ex))
 
 	for i, e := range ex {
+		src, ok := e.From().(*memory.Node)
+		if !ok {
+			return nil, 0, fmt.Errorf("failed to unpack source node for edge: %s", e.UID())
+		}
+
+		target, ok := e.To().(*memory.Node)
+		if !ok {
+			return nil, 0, fmt.Errorf("failed to unpack target node for edge: %s", e.UID())
+		}
+
 		edges[i] = &api.Edge{
-			UID:   e.UID(),
-			Label: e.Label(),
-			Attrs: e.Attrs(),
+			UID:    e.UID(),
+			Source: src.UID(),
+			Target: target.UID(),
+			Weight: e.Weight(),
+			Label:  e.Label(),
+			Attrs:  e.Attrs(),
 		}
 	}
 
@@ -90,13 +103,14 @@ func (es *EdgeService) FindEdges(ctx context.Context, guid string, filter api.Ed
 }
 
 // UpdateEdgeBetween updates an edge between two nodes.
-func (es *EdgeService) UpdateEdgeBetween(ctx context.Context, guid string, source, target string, update api.EdgeUpdate) (*api.Edge, error) {
+// If label is not empty only the edges with the given label are considered.
+func (es *EdgeService) UpdateEdgeBetween(ctx context.Context, guid string, source, target, label string, update api.EdgeUpdate) (*api.Edge, error) {
 	tx, err := es.db.BeginTx(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	edge, err := tx.UpdateEdgeBetween(ctx, guid, source, target, update)
+	edge, err := tx.UpdateEdgeBetween(ctx, guid, source, target, label, update)
 	if err != nil {
 		return nil, err
 	}
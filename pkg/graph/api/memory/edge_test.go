@@ -154,6 +154,64 @@ func TestFindEdgeByUID(t *testing.T) {
 	})
 }
 
+func TestFindEdges(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		testGraphUID := "testUID"
+
+		es := MustEdgeServiceInGraph(t, DSN, testGraphUID)
+		n := MustAddNodeEdgeService(t, es, testGraphUID)
+		n2 := MustAddNodeEdgeService(t, es, testGraphUID)
+
+		e := &api.Edge{
+			Source: n.UID(),
+			Target: n2.UID(),
+			Weight: 2.5,
+			Label:  "testEdge",
+			Attrs:  map[string]interface{}{"foo": 1},
+		}
+
+		if err := es.CreateEdge(context.TODO(), testGraphUID, e); err != nil {
+			t.Fatal(err)
+		}
+
+		edges, count, err := es.FindEdges(context.TODO(), testGraphUID, api.EdgeFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if count != 1 || len(edges) != 1 {
+			t.Fatalf("expected edges: 1, got: %d (count: %d)", len(edges), count)
+		}
+
+		edge := edges[0]
+
+		if edge.UID != e.UID {
+			t.Errorf("expected UID: %s, got: %s", e.UID, edge.UID)
+		}
+
+		if edge.Source != n.UID() || edge.Target != n2.UID() {
+			t.Errorf("expected edge: %s->%s, got: %s->%s", n.UID(), n2.UID(), edge.Source, edge.Target)
+		}
+
+		if edge.Weight != e.Weight {
+			t.Errorf("expected weight: %v, got: %v", e.Weight, edge.Weight)
+		}
+	})
+
+	t.Run("ErrGraphNotFound", func(t *testing.T) {
+		testGraphUID := "randUID"
+		es := MustEdgeService(t, DSN)
+
+		if _, _, err := es.FindEdges(context.TODO(), testGraphUID, api.EdgeFilter{}); api.ErrorCode(err) != api.ENOTFOUND {
+			t.Fatalf("expected error: %s, got: %s", api.ENOTFOUND, api.ErrorCode(err))
+		}
+	})
+}
+
 func TestUpdateEdgeBetween(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test in short mode.")
@@ -185,7 +243,7 @@ func TestUpdateEdgeBetween(t *testing.T) {
 
 		update := api.EdgeUpdate{Weight: &weight, Label: &label, Attrs: attrs}
 
-		e2, err := es.UpdateEdgeBetween(context.TODO(), testGraphUID, e.Source, e.Target, update)
+		e2, err := es.UpdateEdgeBetween(context.TODO(), testGraphUID, e.Source, e.Target, "", update)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -228,11 +286,11 @@ func TestUpdateEdgeBetween(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		if _, err := es.UpdateEdgeBetween(context.TODO(), testGraphUID, n.UID(), "sdfsdfd", api.EdgeUpdate{}); api.ErrorCode(err) != api.ENOTFOUND {
+		if _, err := es.UpdateEdgeBetween(context.TODO(), testGraphUID, n.UID(), "sdfsdfd", "", api.EdgeUpdate{}); api.ErrorCode(err) != api.ENOTFOUND {
 			t.Fatal(err)
 		}
 
-		if _, err := es.UpdateEdgeBetween(context.TODO(), testGraphUID, "sdfd", n.UID(), api.EdgeUpdate{}); api.ErrorCode(err) != api.ENOTFOUND {
+		if _, err := es.UpdateEdgeBetween(context.TODO(), testGraphUID, "sdfd", n.UID(), "", api.EdgeUpdate{}); api.ErrorCode(err) != api.ENOTFOUND {
 			t.Fatal(err)
 		}
 	})
@@ -241,7 +299,7 @@ func TestUpdateEdgeBetween(t *testing.T) {
 		testGraphUID := "randUID"
 		es := MustEdgeServiceInGraph(t, DSN, testGraphUID)
 
-		if _, err := es.UpdateEdgeBetween(context.TODO(), testGraphUID, "foo", "bar", api.EdgeUpdate{}); api.ErrorCode(err) != api.ENOTFOUND {
+		if _, err := es.UpdateEdgeBetween(context.TODO(), testGraphUID, "foo", "bar", "", api.EdgeUpdate{}); api.ErrorCode(err) != api.ENOTFOUND {
 			t.Fatalf("expected error: %s, got: %s", api.ENOTFOUND, api.ErrorCode(err))
 		}
 	})
@@ -41,6 +41,7 @@ func (gs *GraphService) FindGraphByUID(ctx context.Context, uid string) (*api.Gr
 
 	return &api.Graph{
 		UID:   dg.UID(),
+		Type:  dg.Type(),
 		Nodes: dg.Nodes().Len(),
 		Edges: dg.Edges().Len(),
 		Label: StringPtr(dg.Label()),
@@ -67,6 +68,7 @@ func (gs *GraphService) FindGraphs(ctx context.Context, filter api.GraphFilter)
 	for i, fg := range gx {
 		graphs[i] = &api.Graph{
 			UID:   fg.UID(),
+			Type:  fg.Type(),
 			Nodes: fg.Nodes().Len(),
 			Edges: fg.Edges().Len(),
 			Label: StringPtr(fg.Label()),
@@ -91,6 +93,7 @@ func (gs *GraphService) UpdateGraph(ctx context.Context, uid string, update api.
 
 	return &api.Graph{
 		UID:   ug.UID(),
+		Type:  ug.Type(),
 		Nodes: ug.Nodes().Len(),
 		Edges: ug.Edges().Len(),
 		Label: StringPtr(ug.Label()),
@@ -1,6 +1,11 @@
 package json
 
-import "github.com/milosgajdos/orbnet/pkg/graph"
+import (
+	"encoding/json"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
 
 const (
 	DefaultName   = "marshaler"
@@ -27,3 +32,23 @@ func Unmarshal(data []byte, g graph.Graph) error {
 
 	return m.Unmarshal(data, g)
 }
+
+// GraphType returns the type of the graph encoded in JSON data.
+// It returns memory.DefaultType if data do not encode graph type.
+func GraphType(data []byte) (string, error) {
+	var mg struct {
+		Graph struct {
+			Type string `json:"type"`
+		} `json:"graph"`
+	}
+
+	if err := json.Unmarshal(data, &mg); err != nil {
+		return "", err
+	}
+
+	if mg.Graph.Type == "" {
+		return memory.DefaultType, nil
+	}
+
+	return mg.Graph.Type, nil
+}
@@ -1,11 +1,12 @@
 package json
 
 import (
-	"encoding/json"
+	"bytes"
+	"io"
 
 	"github.com/milosgajdos/orbnet/pkg/graph"
 	"github.com/milosgajdos/orbnet/pkg/graph/api"
-	"github.com/milosgajdos/orbnet/pkg/graph/api/memory/marshal"
+	"github.com/milosgajdos/orbnet/pkg/graph/internal/jsonstream"
 	gonum "gonum.org/v1/gonum/graph"
 )
 
@@ -27,20 +28,34 @@ func NewMarshaler(name, prefix, indent string) (*Marshaler, error) {
 
 // Marshal marshals g into JSON api model.
 func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
-	ag := &marshal.Graph{
-		Graph: api.Graph{
-			UID:   g.UID(),
-			Nodes: g.Nodes().Len(),
-			Edges: g.Edges().Len(),
-			Label: StringPtr(g.Label()),
-			Attrs: g.Attrs(),
-		},
-		Nodes: make([]api.Node, g.Nodes().Len()),
-		Edges: make([]api.Edge, g.Edges().Len()),
+	var b bytes.Buffer
+	if err := m.MarshalTo(&b, g); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo marshals g into JSON api model written to w.
+// Nodes and edges are encoded one by one.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	enc := jsonstream.NewEncoder(w, m.prefix, m.indent)
+
+	if err := enc.Field("graph", api.Graph{
+		UID:   g.UID(),
+		Type:  g.Type(),
+		Nodes: g.Nodes().Len(),
+		Edges: g.Edges().Len(),
+		Label: StringPtr(g.Label()),
+		Attrs: g.Attrs(),
+	}); err != nil {
+		return err
+	}
+
+	if err := enc.BeginArray("nodes"); err != nil {
+		return err
 	}
 
 	nodes := g.Nodes()
-	i := 0
 	for nodes.Next() {
 		n := nodes.Node().(graph.Node)
 
@@ -52,36 +67,47 @@ func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
 			degIn = dg.To(n.ID()).Len()
 		}
 
-		ag.Nodes[i] = api.Node{
+		if err := enc.Elem(api.Node{
 			ID:     n.ID(),
 			UID:    n.UID(),
 			DegOut: degOut,
 			DegIn:  degIn,
 			Label:  StringPtr(n.Label()),
 			Attrs:  n.Attrs(),
+		}); err != nil {
+			return err
 		}
+	}
 
-		i++
+	if err := enc.EndArray(); err != nil {
+		return err
+	}
+
+	if err := enc.BeginArray("edges"); err != nil {
+		return err
 	}
 
 	edges := g.Edges()
-	i = 0
 	for edges.Next() {
 		e := edges.Edge().(graph.Edge)
 
-		ag.Edges[i] = api.Edge{
+		if err := enc.Elem(api.Edge{
 			UID:    e.UID(),
 			Source: e.From().(graph.Node).UID(),
 			Target: e.To().(graph.Node).UID(),
 			Weight: e.Weight(),
 			Label:  e.Label(),
 			Attrs:  e.Attrs(),
+		}); err != nil {
+			return err
 		}
+	}
 
-		i++
+	if err := enc.EndArray(); err != nil {
+		return err
 	}
 
-	return json.MarshalIndent(ag, m.prefix, m.indent)
+	return enc.Close()
 }
 
 func StringPtr(s string) *string {
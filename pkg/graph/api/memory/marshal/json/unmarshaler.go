@@ -9,11 +9,16 @@ import (
 )
 
 // Unmarshaler implements graph.Unmarshaler.
-type Unmarshaler struct{}
+type Unmarshaler struct {
+	opts []marshal.Option
+}
 
 // NewUnmarshaler creates a new Unmarshaler and returns it.
-func NewUnmarshaler() (*Unmarshaler, error) {
-	return &Unmarshaler{}, nil
+// The options configure the builder of the unmarshaled graph.
+func NewUnmarshaler(opts ...marshal.Option) (*Unmarshaler, error) {
+	return &Unmarshaler{
+		opts: opts,
+	}, nil
 }
 
 // Unmarshal unmarshals data into graph.
@@ -28,7 +33,7 @@ func (u *Unmarshaler) Unmarshal(data []byte, g graph.Graph) error {
 		return err
 	}
 
-	b, err := marshal.NewBuilder()
+	b, err := marshal.NewBuilder(u.opts...)
 	if err != nil {
 		return err
 	}
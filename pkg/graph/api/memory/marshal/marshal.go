@@ -21,13 +21,20 @@ type Graph struct {
 // Builder is builds in-memory API graph.
 type Builder struct {
 	nodes map[string]*memory.Node
+	opts  Options
 	mu    *sync.RWMutex
 }
 
 // NewBuilder creates a new builder and returns it.
-func NewBuilder() (*Builder, error) {
+func NewBuilder(opts ...Option) (*Builder, error) {
+	bopts := Options{}
+	for _, apply := range opts {
+		apply(&bopts)
+	}
+
 	return &Builder{
 		nodes: make(map[string]*memory.Node),
+		opts:  bopts,
 		mu:    &sync.RWMutex{},
 	}, nil
 }
@@ -50,6 +57,9 @@ func (b *Builder) addNodes(_ context.Context, g graph.Adder, nodes []api.Node) e
 		}
 
 		if attrs := node.Attrs; attrs != nil {
+			if err := b.opts.Schema.CoerceNode(*node.Label, attrs); err != nil {
+				return graph.Errorf(graph.EINVALID, "node %s: %v", node.UID, err)
+			}
 			opts = append(opts, memory.WithAttrs(attrs))
 		}
 
@@ -86,7 +96,8 @@ func (b *Builder) addEdges(_ context.Context, g graph.Adder, edges []api.Edge) e
 		}
 
 		// dont create a new edge if an edge already exists
-		if e := g.Edge(from.ID(), to.ID()); e != nil {
+		// unless the graph allows parallel edges
+		if e := g.Edge(from.ID(), to.ID()); e != nil && !graph.IsMulti(g.Type()) {
 			continue
 		}
 
@@ -100,6 +111,9 @@ func (b *Builder) addEdges(_ context.Context, g graph.Adder, edges []api.Edge) e
 		}
 
 		if attrs := edge.Attrs; attrs != nil {
+			if err := b.opts.Schema.CoerceEdge(edge.Label, attrs); err != nil {
+				return graph.Errorf(graph.EINVALID, "edge %s: %v", edge.UID, err)
+			}
 			opts = append(opts, memory.WithAttrs(attrs))
 		}
 
@@ -125,6 +139,10 @@ func (b *Builder) Build(ctx context.Context, g graph.Adder, dataChan <-chan inte
 			}
 
 			if d, ok := data.(Graph); ok {
+				if t := d.Graph.Type; t != "" && t != g.Type() {
+					return graph.Errorf(graph.EINVALID, "graph type mismatch: %s, expected: %s", t, g.Type())
+				}
+
 				if l, ok := g.(graph.LabelSetter); ok {
 					l.SetLabel(*d.Graph.Label)
 				}
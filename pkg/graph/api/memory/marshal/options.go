@@ -0,0 +1,19 @@
+package marshal
+
+import "github.com/milosgajdos/orbnet/pkg/graph/attrs"
+
+// Options configure builder.
+type Options struct {
+	// Schema coerces node and edge attributes.
+	Schema *attrs.Registry
+}
+
+// Option is functional builder option.
+type Option func(*Options)
+
+// WithSchema sets Schema option.
+func WithSchema(r *attrs.Registry) Option {
+	return func(o *Options) {
+		o.Schema = r
+	}
+}
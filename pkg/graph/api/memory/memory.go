@@ -8,7 +8,9 @@ import (
 	"os"
 	"sync"
 
+	"github.com/milosgajdos/orbnet/pkg/graph/api/memory/marshal"
 	"github.com/milosgajdos/orbnet/pkg/graph/api/memory/marshal/json"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
 	"github.com/milosgajdos/orbnet/pkg/graph/memory"
 )
 
@@ -30,6 +32,8 @@ type DB struct {
 	DSN string
 	// Closed flag for DB operations.
 	Closed bool
+	// Schema validates and coerces node and edge attributes.
+	Schema *attrs.Registry
 	// db stores memory db
 	db map[string]*memory.Graph
 	*sync.RWMutex
@@ -65,7 +69,7 @@ func (db *DB) Open() (err error) {
 	}
 
 	if db.DSN != DSN {
-		if db.db, err = openFromFS(os.DirFS(db.DSN)); err != nil {
+		if db.db, err = openFromFS(os.DirFS(db.DSN), db.Schema); err != nil {
 			return err
 		}
 		db.Closed = false
@@ -111,7 +115,7 @@ func StringPtr(s string) *string {
 //
 // TODO(milosgajods): allow to specify graph.Unmarshaler for unmarshaling graph stored in path.
 // At the moment we use the only available unmarshaler: json.Unmarshaler.
-func openFromFS(sys fs.FS) (map[string]*memory.Graph, error) {
+func openFromFS(sys fs.FS, schema *attrs.Registry) (map[string]*memory.Graph, error) {
 	graphs := make(map[string]*memory.Graph)
 
 	if err := fs.WalkDir(sys, ".", func(path string, e fs.DirEntry, err error) error {
@@ -128,13 +132,23 @@ func openFromFS(sys fs.FS) (map[string]*memory.Graph, error) {
 			return err
 		}
 
+		typ, err := json.GraphType(data)
+		if err != nil {
+			return err
+		}
+
 		// NOTE: DefaultLabel and attributes are overridden during deserialization.
-		g, err := memory.NewGraph()
+		g, err := memory.NewGraph(memory.WithType(typ))
+		if err != nil {
+			return err
+		}
+
+		u, err := json.NewUnmarshaler(marshal.WithSchema(schema))
 		if err != nil {
 			return err
 		}
 
-		if err := json.Unmarshal(data, g); err != nil {
+		if err := u.Unmarshal(data, g); err != nil {
 			return err
 		}
 
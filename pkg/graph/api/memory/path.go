@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/api"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+// PathService finds paths between graph nodes.
+type PathService struct {
+	db *DB
+}
+
+// NewPathService creates an instance of PathService and returns it.
+func NewPathService(db *DB) (*PathService, error) {
+	return &PathService{
+		db: db,
+	}, nil
+}
+
+// FindPaths returns the paths from the source to the target node matching the filter.
+func (ps *PathService) FindPaths(ctx context.Context, uid string, source, target string, filter api.PathFilter) ([]*api.Path, error) {
+	tx, err := ps.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	px, err := tx.FindPaths(ctx, uid, source, target, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	apiPaths := make([]*api.Path, len(px))
+
+	for i, p := range px {
+		apiPath := &api.Path{
+			Nodes:  make([]*api.Node, len(p.Nodes)),
+			Edges:  make([]*api.Edge, len(p.Edges)),
+			Weight: p.Weight,
+		}
+
+		for j, n := range p.Nodes {
+			apiPath.Nodes[j] = &api.Node{
+				ID:     n.ID(),
+				UID:    n.UID(),
+				Label:  StringPtr(n.Label()),
+				Attrs:  n.Attrs(),
+				DegOut: n.DegOut,
+				DegIn:  n.DegIn,
+			}
+		}
+
+		for j, e := range p.Edges {
+			src, ok := e.From().(*memory.Node)
+			if !ok {
+				return nil, fmt.Errorf("failed to unpack source node for edge: %s", e.UID())
+			}
+
+			target, ok := e.To().(*memory.Node)
+			if !ok {
+				return nil, fmt.Errorf("failed to unpack target node for edge: %s", e.UID())
+			}
+
+			apiPath.Edges[j] = &api.Edge{
+				UID:    e.UID(),
+				Source: src.UID(),
+				Target: target.UID(),
+				Weight: e.Weight(),
+				Label:  e.Label(),
+				Attrs:  e.Attrs(),
+			}
+		}
+
+		apiPaths[i] = apiPath
+	}
+
+	return apiPaths, nil
+}
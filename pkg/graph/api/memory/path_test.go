@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/api"
+)
+
+const (
+	pathGraphUID = "cc099040-9dab-4f3d-848e-3046912aa281"
+	pathSource   = "8796cc19-a6f4-4bbf-8fd7-4dde5ad74a70"
+	pathTarget   = "7817e62b-1625-48d1-88b2-b7cc5560eab0"
+)
+
+func MustPathService(t *testing.T, dsn string) *PathService {
+	db := MustOpenDB(t, dsn)
+	ps, err := NewPathService(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ps
+}
+
+func TestFindPaths(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		ps := MustPathService(t, testDir)
+
+		for _, algo := range []string{"dijkstra", "astar", "yen", "all"} {
+			px, err := ps.FindPaths(context.TODO(), pathGraphUID, pathSource, pathTarget, api.PathFilter{
+				Algorithm: StringPtr(algo),
+			})
+			if err != nil {
+				t.Fatalf("%s: failed to find paths: %v", algo, err)
+			}
+
+			if len(px) != 1 {
+				t.Fatalf("%s: expected paths: %d, got: %d", algo, 1, len(px))
+			}
+
+			p := px[0]
+			if len(p.Nodes) != 5 || len(p.Edges) != 4 || p.Weight != 4 {
+				t.Fatalf("%s: unexpected path: %d nodes, %d edges, weight %f", algo, len(p.Nodes), len(p.Edges), p.Weight)
+			}
+
+			if p.Nodes[0].UID != pathSource || p.Nodes[4].UID != pathTarget {
+				t.Errorf("%s: unexpected path ends: %s, %s", algo, p.Nodes[0].UID, p.Nodes[4].UID)
+			}
+
+			for i, e := range p.Edges {
+				if e.Source != p.Nodes[i].UID || e.Target != p.Nodes[i+1].UID {
+					t.Errorf("%s: edge %d: expected %s->%s, got: %s->%s", algo, i, p.Nodes[i].UID, p.Nodes[i+1].UID, e.Source, e.Target)
+				}
+			}
+		}
+	})
+
+	t.Run("NoPath", func(t *testing.T) {
+		ps := MustPathService(t, testDir)
+
+		depth := 3
+		px, err := ps.FindPaths(context.TODO(), pathGraphUID, pathSource, pathTarget, api.PathFilter{
+			Algorithm: StringPtr("all"),
+			MaxDepth:  &depth,
+		})
+		if err != nil {
+			t.Fatalf("failed to find paths: %v", err)
+		}
+
+		if len(px) != 0 {
+			t.Errorf("expected no paths, got: %d", len(px))
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		ps := MustPathService(t, testDir)
+
+		testCases := []struct {
+			name   string
+			guid   string
+			source string
+			filter api.PathFilter
+			code   string
+		}{
+			{"GraphNotFound", "foo", pathSource, api.PathFilter{}, api.ENOTFOUND},
+			{"NodeNotFound", pathGraphUID, "foo", api.PathFilter{}, api.ENOTFOUND},
+			{"Algorithm", pathGraphUID, pathSource, api.PathFilter{Algorithm: StringPtr("foo")}, api.EINVALID},
+		}
+
+		for _, tc := range testCases {
+			if _, err := ps.FindPaths(context.TODO(), tc.guid, tc.source, pathTarget, tc.filter); api.ErrorCode(err) != tc.code {
+				t.Errorf("%s: expected error: %s, got: %v", tc.name, tc.code, err)
+			}
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/milosgajdos/orbnet/pkg/graph"
 	"github.com/milosgajdos/orbnet/pkg/graph/api"
 	"github.com/milosgajdos/orbnet/pkg/graph/memory"
 
@@ -50,8 +51,15 @@ func (t *Tx) CreateEdge(ctx context.Context, uid string, e *api.Edge) error {
 		return api.Errorf(api.ENOTFOUND, "target node %s not found in graph %s", e.Target, uid)
 	}
 
-	if e := g.Edge(source.ID(), target.ID()); e != nil {
-		return nil
+	// only multigraphs allow parallel edges
+	if ge := g.Edge(source.ID(), target.ID()); ge != nil && !graph.IsMulti(g.Type()) {
+		return api.Errorf(api.ECONFLICT, "edge %s->%s already exists", e.Source, e.Target)
+	}
+
+	if e.Attrs != nil {
+		if err := api.CoerceAttrs(t.db.Schema.EdgeSchema(e.Label), e.Attrs); err != nil {
+			return err
+		}
 	}
 
 	var opts []memory.Option
@@ -82,16 +90,14 @@ func (t *Tx) CreateEdge(ctx context.Context, uid string, e *api.Edge) error {
 // findEdgeByUID is a helper function that finds a node with the given id ore it returns error.
 // nolint:revive
 func (t Tx) findEdgeByUID(ctx context.Context, g *memory.Graph, uid string) (*TxEdge, error) {
-	edges := g.Edges()
-	for edges.Next() {
-		e := edges.Edge().(*memory.Edge)
-		if e.UID() == uid {
-			return &TxEdge{
-				Edge: memory.EdgeDeepCopy(e),
-			}, nil
-		}
+	e, ok := g.EdgeWithUID(uid).(*memory.Edge)
+	if !ok {
+		return nil, api.Errorf(api.ENOTFOUND, "edge %s not found", uid)
 	}
-	return nil, api.Errorf(api.ENOTFOUND, "edge %s not found", uid)
+
+	return &TxEdge{
+		Edge: memory.EdgeDeepCopy(e),
+	}, nil
 }
 
 // FindEdgeByUID returns edge with the given UID.
@@ -110,7 +116,7 @@ func (t *Tx) FindEdgeByUID(ctx context.Context, guid, euid string) (*TxEdge, err
 // nolint:revive
 func filterNodeEdges(ctx context.Context, nodes gonum.Nodes, g *memory.Graph, filter api.EdgeFilter) ([]*TxEdge, error) {
 	var ex []*TxEdge
-	var e gonum.Edge
+	var lines gonum.Lines
 	var srcID, trgID int64
 
 	if filter.Source != nil {
@@ -134,21 +140,18 @@ func filterNodeEdges(ctx context.Context, nodes gonum.Nodes, g *memory.Graph, fi
 	for nodes.Next() {
 		n := nodes.Node().(*memory.Node)
 		if filter.Source != nil {
-			e = g.Edge(srcID, n.ID())
+			lines = g.Lines(srcID, n.ID())
 		} else {
-			e = g.Edge(n.ID(), trgID)
-		}
-
-		// NOTE: this should never happen
-		if e == nil {
-			continue
+			lines = g.Lines(n.ID(), trgID)
 		}
 
-		memEdge := e.(*memory.Edge)
-		if l := filter.Label; l != nil {
-			if memEdge.Label() == *l {
-				txEdge := &TxEdge{Edge: memEdge}
-				ex = append(ex, txEdge)
+		for lines.Next() {
+			memEdge := lines.Line().(*memory.Edge)
+			if l := filter.Label; l != nil {
+				if memEdge.Label() == *l {
+					txEdge := &TxEdge{Edge: memEdge}
+					ex = append(ex, txEdge)
+				}
 			}
 		}
 	}
@@ -209,23 +212,18 @@ func (t *Tx) FindEdges(ctx context.Context, uid string, filter api.EdgeFilter) (
 	var err error
 
 	// Both Source and Target have been provided
-	// we are looking for a single edge
+	// we are looking for the edges between them:
+	// there is at most one unless the graph is a multigraph
 	if filter.Source != nil && filter.Target != nil {
-		e := g.Edge(srcID, trgtID)
-		if e == nil {
-			return edges, 0, nil
-		}
-
-		memEdge := e.(*memory.Edge)
-		if l := filter.Label; l != nil {
-			if memEdge.Label() == *l {
-				txEdge := &TxEdge{Edge: memEdge}
-				return []*TxEdge{txEdge}, 1, nil
+		lines := g.Lines(srcID, trgtID)
+		for lines.Next() {
+			memEdge := lines.Line().(*memory.Edge)
+			if l := filter.Label; l != nil && memEdge.Label() != *l {
+				continue
 			}
-			return []*TxEdge{}, 0, nil
+			edges = append(edges, &TxEdge{Edge: memEdge})
 		}
-		txEdge := &TxEdge{Edge: memEdge}
-		return []*TxEdge{txEdge}, 1, nil
+		return applyOffsetLimit(edges, filter.Offset, filter.Limit).([]*TxEdge), len(edges), nil
 	}
 
 	// Source has been provided
@@ -254,8 +252,10 @@ func (t *Tx) FindEdges(ctx context.Context, uid string, filter api.EdgeFilter) (
 }
 
 // UpdateEdgeBetween updates edge between two nodes.
+// If label is not empty only the edges with the given label are considered.
+// It fails with ECONFLICT if more than one edge between the nodes matches.
 // nolint:revive
-func (t *Tx) UpdateEdgeBetween(ctx context.Context, uid string, source, target string, update api.EdgeUpdate) (*TxEdge, error) {
+func (t *Tx) UpdateEdgeBetween(ctx context.Context, uid string, source, target, label string, update api.EdgeUpdate) (*TxEdge, error) {
 	t.db.Lock()
 	defer t.db.Unlock()
 
@@ -274,20 +274,41 @@ func (t *Tx) UpdateEdgeBetween(ctx context.Context, uid string, source, target s
 		return nil, api.Errorf(api.ENOTFOUND, "target node: %s in graph: %s", target, g.UID())
 	}
 
-	e := g.Edge(srcNode.ID(), trgNode.ID())
-	if e == nil {
-		return nil, api.Errorf(api.ENOTFOUND, "edge %s->%s not found", source, target)
+	var edge *memory.Edge
+	lines := g.Lines(srcNode.ID(), trgNode.ID())
+	for lines.Next() {
+		e, ok := lines.Line().(*memory.Edge)
+		if !ok {
+			return nil, api.Errorf(api.EINTERNAL, "invalid edge data found")
+		}
+
+		if label != "" && e.Label() != label {
+			continue
+		}
+
+		if edge != nil {
+			return nil, api.Errorf(api.ECONFLICT, "multiple edges %s->%s found", source, target)
+		}
+		edge = e
 	}
 
-	edge, ok := e.(*memory.Edge)
-	if !ok {
-		return nil, api.Errorf(api.EINTERNAL, "invalid edge data found")
+	if edge == nil {
+		return nil, api.Errorf(api.ENOTFOUND, "edge %s->%s not found", source, target)
 	}
 
+	newLabel := edge.Label()
 	if l := update.Label; l != nil {
-		edge.SetLabel(*l)
+		newLabel = *l
 	}
 
+	if a := update.Attrs; a != nil {
+		if err := api.CoerceAttrs(t.db.Schema.EdgeSchema(newLabel), a); err != nil {
+			return nil, err
+		}
+	}
+
+	edge.SetLabel(newLabel)
+
 	if a := update.Attrs; a != nil {
 		for k, v := range a {
 			edge.Attrs()[k] = v
@@ -314,16 +335,19 @@ func (t *Tx) DeleteEdge(ctx context.Context, guid, euid string) error {
 		return api.Errorf(api.ENOTFOUND, "graph %s not found", guid)
 	}
 
-	edges := g.Edges()
-	for edges.Next() {
-		e := edges.Edge().(*memory.Edge)
-		if e.UID() == euid {
-			g.RemoveEdge(e.From().ID(), e.To().ID())
-			return nil
-		}
+	e := g.EdgeWithUID(euid)
+	if e == nil {
+		return api.Errorf(api.ENOTFOUND, "edge %s not found", euid)
 	}
 
-	return api.Errorf(api.ENOTFOUND, "edge %s not found", euid)
+	if l, ok := e.(gonum.Line); ok {
+		g.RemoveLine(e.From().ID(), e.To().ID(), l.ID())
+		return nil
+	}
+
+	g.RemoveEdge(e.From().ID(), e.To().ID())
+
+	return nil
 }
 
 // DeleteEdgeBetween deletes all edges between source and target nodes.
@@ -347,21 +371,17 @@ func (t *Tx) DeleteEdgeBetween(ctx context.Context, uid string, source, target s
 		return api.Errorf(api.ENOTFOUND, "target node: %s in graph: %s", target, g.UID())
 	}
 
-	e := g.Edge(srcNode.ID(), trgNode.ID())
-	g.RemoveEdge(e.From().ID(), e.To().ID())
+	g.RemoveEdge(srcNode.ID(), trgNode.ID())
 
 	return nil
 }
 
 // nolint:revive
 func findNodeByUID(ctx context.Context, g *memory.Graph, uid string) (*memory.Node, error) {
-	nodes := g.Nodes()
-	for nodes.Next() {
-		n := nodes.Node().(*memory.Node)
-		if n.UID() == uid {
-			return n, nil
-		}
+	n, ok := g.NodeWithUID(uid).(*memory.Node)
+	if !ok {
+		return nil, api.Errorf(api.ENOTFOUND, "node %s not found", uid)
 	}
 
-	return nil, api.Errorf(api.ENOTFOUND, "node %s not found", uid)
+	return n, nil
 }
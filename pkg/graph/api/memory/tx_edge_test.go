@@ -5,7 +5,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/milosgajdos/orbnet/pkg/graph"
 	"github.com/milosgajdos/orbnet/pkg/graph/api"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
 )
 
 func TestTxCreateEdge(t *testing.T) {
@@ -35,6 +37,31 @@ func TestTxCreateEdge(t *testing.T) {
 		}
 	})
 
+	t.Run("ErrConflict", func(t *testing.T) {
+		ctx := context.TODO()
+		g := MustGraph(t)
+		tx := MustOpenTx(t, ctx, DSN)
+		MustAddGraph(t, ctx, tx, g)
+
+		uid := g.UID()
+		n := MustAddNode(t, ctx, tx, uid)
+		n2 := MustAddNode(t, ctx, tx, uid)
+
+		e := &api.Edge{Source: n.UID(), Target: n2.UID(), Label: "testEdge"}
+		if err := tx.CreateEdge(ctx, uid, e); err != nil {
+			t.Fatal(err)
+		}
+
+		dup := &api.Edge{Source: n.UID(), Target: n2.UID(), Label: "testEdge"}
+		if err := tx.CreateEdge(ctx, uid, dup); api.ErrorCode(err) != api.ECONFLICT {
+			t.Fatalf("expected error: %s, got: %v", api.ECONFLICT, err)
+		}
+
+		if dup.UID != "" {
+			t.Errorf("expected no edge UID, got: %s", dup.UID)
+		}
+	})
+
 	t.Run("ErrGraphNotFound", func(t *testing.T) {
 		ctx := context.TODO()
 		tx := MustOpenTx(t, ctx, DSN)
@@ -191,7 +218,7 @@ func TestTxUpdateEdgeBetween(t *testing.T) {
 
 		update := api.EdgeUpdate{Weight: &weight, Label: &label, Attrs: attrs}
 
-		e2, err := tx.UpdateEdgeBetween(ctx, uid, e.Source, e.Target, update)
+		e2, err := tx.UpdateEdgeBetween(ctx, uid, e.Source, e.Target, "", update)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -238,7 +265,7 @@ func TestTxUpdateEdgeBetween(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		if _, err := tx.UpdateEdgeBetween(ctx, uid, "ffs", "ffs2", api.EdgeUpdate{}); api.ErrorCode(err) != api.ENOTFOUND {
+		if _, err := tx.UpdateEdgeBetween(ctx, uid, "ffs", "ffs2", "", api.EdgeUpdate{}); api.ErrorCode(err) != api.ENOTFOUND {
 			t.Fatal(err)
 		}
 	})
@@ -265,7 +292,7 @@ func TestTxUpdateEdgeBetween(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		e2, err := tx.UpdateEdgeBetween(ctx, uid, e.Source, e.Target, api.EdgeUpdate{})
+		e2, err := tx.UpdateEdgeBetween(ctx, uid, e.Source, e.Target, "", api.EdgeUpdate{})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -279,10 +306,68 @@ func TestTxUpdateEdgeBetween(t *testing.T) {
 		}
 	})
 
+	t.Run("Multi", func(t *testing.T) {
+		ctx := context.TODO()
+		g := MustGraph(t, memory.WithType(graph.WeightedDirectedMulti))
+		tx := MustOpenTx(t, ctx, DSN)
+		MustAddGraph(t, ctx, tx, g)
+
+		uid := g.UID()
+		n := MustAddNode(t, ctx, tx, uid)
+		n2 := MustAddNode(t, ctx, tx, uid)
+
+		for _, label := range []string{"foo", "bar"} {
+			e := &api.Edge{Source: n.UID(), Target: n2.UID(), Weight: 1.0, Label: label}
+			if err := tx.CreateEdge(ctx, uid, e); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		weight := 5.0
+		update := api.EdgeUpdate{Weight: &weight}
+
+		if _, err := tx.UpdateEdgeBetween(ctx, uid, n.UID(), n2.UID(), "", update); api.ErrorCode(err) != api.ECONFLICT {
+			t.Fatalf("expected error: %s, got: %v", api.ECONFLICT, err)
+		}
+
+		if _, err := tx.UpdateEdgeBetween(ctx, uid, n.UID(), n2.UID(), "baz", update); api.ErrorCode(err) != api.ENOTFOUND {
+			t.Fatalf("expected error: %s, got: %v", api.ENOTFOUND, err)
+		}
+
+		e, err := tx.UpdateEdgeBetween(ctx, uid, n.UID(), n2.UID(), "bar", update)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if l := e.Label(); l != "bar" {
+			t.Errorf("expected label: bar, got: %s", l)
+		}
+
+		source, target := n.UID(), n2.UID()
+		edges, _, err := tx.FindEdges(ctx, uid, api.EdgeFilter{Source: &source, Target: &target})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(edges) != 2 {
+			t.Fatalf("expected edges: 2, got: %d", len(edges))
+		}
+
+		for _, e := range edges {
+			want := 1.0
+			if e.Label() == "bar" {
+				want = weight
+			}
+			if w := e.Weight(); w != want {
+				t.Errorf("edge %s: expected weight: %f, got: %f", e.Label(), want, w)
+			}
+		}
+	})
+
 	t.Run("GraphNotFound", func(t *testing.T) {
 		tx := MustOpenTx(t, context.TODO(), DSN)
 
-		if _, err := tx.UpdateEdgeBetween(context.TODO(), "foo", "ffs1", "ffs2", api.EdgeUpdate{}); api.ErrorCode(err) != api.ENOTFOUND {
+		if _, err := tx.UpdateEdgeBetween(context.TODO(), "foo", "ffs1", "ffs2", "", api.EdgeUpdate{}); api.ErrorCode(err) != api.ENOTFOUND {
 			t.Fatal(err)
 		}
 	})
@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 
+	"github.com/milosgajdos/orbnet/pkg/graph"
 	"github.com/milosgajdos/orbnet/pkg/graph/api"
 	"github.com/milosgajdos/orbnet/pkg/graph/memory"
 )
@@ -27,8 +28,12 @@ func (t *Tx) CreateGraph(ctx context.Context, g *api.Graph) error {
 		opts = append(opts, memory.WithAttrs(g.Attrs))
 	}
 
-	// NOTE(milosgajdos): we are ignoring g.Type
-	// as we only support weighted_directed graphs
+	if g.Type != "" {
+		if !graph.IsValidType(g.Type) {
+			return api.Errorf(api.EINVALID, "invalid graph type: %s", g.Type)
+		}
+		opts = append(opts, memory.WithType(g.Type))
+	}
 
 	mg, err := memory.NewGraph(opts...)
 	if err != nil {
@@ -42,6 +47,7 @@ func (t *Tx) CreateGraph(ctx context.Context, g *api.Graph) error {
 	t.db.db[mg.UID()] = mg
 
 	g.UID = mg.UID()
+	g.Type = mg.Type()
 	g.Nodes = mg.Nodes().Len()
 	g.Edges = mg.Edges().Len()
 	g.Label = StringPtr(mg.Label())
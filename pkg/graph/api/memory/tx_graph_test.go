@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/milosgajdos/orbnet/pkg/graph"
 	"github.com/milosgajdos/orbnet/pkg/graph/api"
 )
 
@@ -23,6 +24,35 @@ func TestTxCreateGraph(t *testing.T) {
 		}
 	})
 
+	t.Run("Type", func(t *testing.T) {
+		ctx := context.TODO()
+		tx := MustOpenTx(t, ctx, DSN)
+
+		g := &api.Graph{Label: StringPtr("testGraph"), Type: graph.WeightedUndirectedMulti}
+		if err := tx.CreateGraph(ctx, g); err != nil {
+			t.Fatal(err)
+		}
+
+		mg, err := tx.FindGraphByUID(ctx, g.UID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if typ := mg.Type(); typ != g.Type {
+			t.Fatalf("expected type: %s, got: %s", g.Type, typ)
+		}
+	})
+
+	t.Run("ErrInvalidType", func(t *testing.T) {
+		ctx := context.TODO()
+		tx := MustOpenTx(t, ctx, DSN)
+
+		g := &api.Graph{Label: StringPtr("testGraph"), Type: "foo"}
+		if err := tx.CreateGraph(ctx, g); api.ErrorCode(err) != api.EINVALID {
+			t.Fatal(err)
+		}
+	})
+
 	t.Run("ErrAlreadyExists", func(t *testing.T) {
 		ctx := context.TODO()
 		tx := MustOpenTx(t, ctx, DSN)
@@ -27,6 +27,12 @@ func (t *Tx) CreateNode(ctx context.Context, uid string, n *api.Node) error {
 		return api.Errorf(api.ENOTFOUND, "graph %s not found", uid)
 	}
 
+	if n.Attrs != nil {
+		if err := api.CoerceAttrs(t.db.Schema.NodeSchema(*n.Label), n.Attrs); err != nil {
+			return err
+		}
+	}
+
 	var opts []memory.Option
 
 	if *n.Label != "" {
@@ -37,14 +43,11 @@ func (t *Tx) CreateNode(ctx context.Context, uid string, n *api.Node) error {
 		opts = append(opts, memory.WithAttrs(n.Attrs))
 	}
 
-	gnode := g.NewNode()
-	node, err := memory.NewNode(gnode.ID(), opts...)
+	node, err := g.AddNewNode(opts...)
 	if err != nil {
 		return err
 	}
 
-	g.AddNode(node)
-
 	n.ID = node.ID()
 	n.UID = node.UID()
 	n.Label = StringPtr(node.Label())
@@ -89,19 +92,16 @@ func (t *Tx) FindNodeByID(ctx context.Context, uid string, id int64) (*TxNode, e
 // findNodeByUID is a helper function that finds a node with the given uid or it returns error.
 // nolint:revive
 func (t Tx) findNodeByUID(ctx context.Context, g *memory.Graph, uid string) (*TxNode, error) {
-	nodes := g.Nodes()
-	for nodes.Next() {
-		n := nodes.Node().(*memory.Node)
-		if n.UID() == uid {
-			return &TxNode{
-				Node:   memory.NodeDeepCopy(n),
-				DegOut: g.From(n.ID()).Len(),
-				DegIn:  g.To(n.ID()).Len(),
-			}, nil
-		}
+	n, ok := g.NodeWithUID(uid).(*memory.Node)
+	if !ok {
+		return nil, api.Errorf(api.ENOTFOUND, "node %s not found", uid)
 	}
 
-	return nil, api.Errorf(api.ENOTFOUND, "node %s not found", uid)
+	return &TxNode{
+		Node:   memory.NodeDeepCopy(n),
+		DegOut: g.From(n.ID()).Len(),
+		DegIn:  g.To(n.ID()).Len(),
+	}, nil
 }
 
 // FindNodeByUID returns node with the given UID.
@@ -270,10 +270,19 @@ func (t *Tx) UpdateNode(ctx context.Context, uid string, id int64, update api.No
 		return nil, api.Errorf(api.EINTERNAL, "invalid node data found")
 	}
 
+	label := node.Label()
 	if l := update.Label; l != nil {
-		node.SetLabel(*l)
+		label = *l
 	}
 
+	if a := update.Attrs; a != nil {
+		if err := api.CoerceAttrs(t.db.Schema.NodeSchema(label), a); err != nil {
+			return nil, err
+		}
+	}
+
+	node.SetLabel(label)
+
 	if a := update.Attrs; a != nil {
 		for k, v := range a {
 			node.Attrs()[k] = v
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/milosgajdos/orbnet/pkg/graph/api"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
 )
 
 func TestTxCreateNode(t *testing.T) {
@@ -37,6 +38,33 @@ func TestTxCreateNode(t *testing.T) {
 			t.Fatal(err)
 		}
 	})
+
+	t.Run("ErrInvalidAttrs", func(t *testing.T) {
+		ctx := context.TODO()
+		g := MustGraph(t)
+		tx := MustOpenTx(t, ctx, DSN)
+		MustAddGraph(t, ctx, tx, g)
+
+		tx.db.Schema = attrs.NewRegistry()
+		if err := tx.db.Schema.SetNodeSchema("testNode", attrs.Schema{"foo": attrs.Int}); err != nil {
+			t.Fatal(err)
+		}
+
+		n := &api.Node{Label: StringPtr("testNode"), Attrs: map[string]interface{}{"foo": "bar"}}
+
+		if err := tx.CreateNode(ctx, g.UID(), n); api.ErrorCode(err) != api.EINVALID {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		n.Attrs["foo"] = "10"
+		if err := tx.CreateNode(ctx, g.UID(), n); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := n.Attrs["foo"], int64(10); got != want {
+			t.Fatalf("foo=%#v, want %#v", got, want)
+		}
+	})
 }
 
 func TestTxFindNodeByID(t *testing.T) {
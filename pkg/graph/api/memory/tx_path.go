@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/api"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	"github.com/milosgajdos/orbnet/pkg/graph/paths"
+)
+
+// TxPath is a path between two graph nodes.
+type TxPath struct {
+	Nodes  []*TxNode
+	Edges  []*TxEdge
+	Weight float64
+}
+
+// FindPaths returns the paths from the source to the target node matching the filter.
+// It returns error if either of the nodes could not be found.
+func (t *Tx) FindPaths(ctx context.Context, uid string, source, target string, filter api.PathFilter) ([]*TxPath, error) {
+	t.db.RLock()
+	defer t.db.RUnlock()
+
+	g, ok := t.db.db[uid]
+	if !ok {
+		return nil, api.Errorf(api.ENOTFOUND, "graph %s not found", uid)
+	}
+
+	srcNode, err := findNodeByUID(ctx, g, source)
+	if err != nil {
+		return nil, api.Errorf(api.ENOTFOUND, "source node %s in graph %s", source, uid)
+	}
+
+	trgNode, err := findNodeByUID(ctx, g, target)
+	if err != nil {
+		return nil, api.Errorf(api.ENOTFOUND, "target node %s in graph %s", target, uid)
+	}
+
+	px, err := paths.Find(g, srcNode.ID(), trgNode.ID(), api.PathOptions(filter)...)
+	if err != nil {
+		if code := graph.ErrorCode(err); code == graph.EINVALID {
+			return nil, api.Errorf(api.EINVALID, "%s", graph.ErrorMessage(err))
+		}
+		return nil, err
+	}
+
+	txPaths := make([]*TxPath, len(px))
+	for i, p := range px {
+		txPath := &TxPath{
+			Nodes:  make([]*TxNode, len(p.Nodes)),
+			Edges:  make([]*TxEdge, len(p.Edges)),
+			Weight: p.Weight,
+		}
+
+		for j, n := range p.Nodes {
+			txPath.Nodes[j], err = t.findNodeByID(ctx, g, n.ID())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for j, e := range p.Edges {
+			txPath.Edges[j] = &TxEdge{
+				Edge: memory.EdgeDeepCopy(e.(*memory.Edge)),
+			}
+		}
+
+		txPaths[i] = txPath
+	}
+
+	return txPaths, nil
+}
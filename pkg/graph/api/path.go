@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/paths"
+)
+
+// Path is a path between two graph nodes.
+type Path struct {
+	// Nodes are the path nodes ordered from the source to the target node.
+	Nodes []*Node `json:"nodes"`
+	// Edges are the path edges ordered from the source to the target node.
+	Edges []*Edge `json:"edges"`
+	// Weight is the sum of the path edge weights.
+	Weight float64 `json:"weight"`
+}
+
+// PathService represents a service for finding paths between graph nodes.
+type PathService interface {
+	// FindPaths returns the paths from the source to the target node matching the filter.
+	// Source and target are node UIDs.
+	FindPaths(ctx context.Context, uid string, source, target string, filter PathFilter) ([]*Path, error)
+}
+
+// PathFilter represents a filter used by FindPaths().
+type PathFilter struct {
+	// Algorithm is path finding algorithm: dijkstra, astar, yen or all.
+	Algorithm *string `json:"algorithm"`
+	// K is the number of the shortest paths found by yen.
+	K *int `json:"k"`
+	// MaxDepth is the maximum number of edges of the paths found by all.
+	MaxDepth *int `json:"max_depth"`
+	// Limit restricts the number of the paths found by all.
+	Limit int `json:"limit"`
+}
+
+// PathOptions returns path finding options set in the filter.
+func PathOptions(filter PathFilter) []paths.Option {
+	opts := []paths.Option{paths.WithLimit(filter.Limit)}
+
+	if filter.Algorithm != nil {
+		opts = append(opts, paths.WithAlgorithm(paths.Algorithm(*filter.Algorithm)))
+	}
+
+	if filter.K != nil {
+		opts = append(opts, paths.WithK(*filter.K))
+	}
+
+	if filter.MaxDepth != nil {
+		opts = append(opts, paths.WithMaxDepth(*filter.MaxDepth))
+	}
+
+	return opts
+}
@@ -2,11 +2,14 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/milosgajdos/orbnet/pkg/graph"
 	"github.com/milosgajdos/orbnet/pkg/graph/api"
 )
 
@@ -31,6 +34,22 @@ func (es *EdgeService) CreateEdge(ctx context.Context, graphUID string, e *api.E
 	// nolint:errcheck
 	defer tx.Rollback()
 
+	typ, err := graphType(ctx, tx, graphUID)
+	if err != nil {
+		return err
+	}
+
+	// only multigraphs allow parallel edges
+	if !graph.IsMulti(typ) {
+		if _, err := findEdgeBetween(ctx, tx, graphUID, e.Source, e.Target); err == nil {
+			return api.Errorf(api.ECONFLICT, "edge %s->%s already exists", e.Source, e.Target)
+		}
+	}
+
+	if err := api.CoerceAttrs(es.db.Schema.EdgeSchema(e.Label), e.Attrs); err != nil {
+		return err
+	}
+
 	e.CreatedAt = time.Now()
 	e.UpdatedAt = e.CreatedAt
 
@@ -101,7 +120,8 @@ func (es *EdgeService) FindEdges(ctx context.Context, graphUID string, filter ap
 }
 
 // UpdateEdgeBetween updates an edge between two nodes.
-func (es *EdgeService) UpdateEdgeBetween(ctx context.Context, graphUID, source, target string, update api.EdgeUpdate) (*api.Edge, error) {
+// If label is not empty only the edges with the given label are considered.
+func (es *EdgeService) UpdateEdgeBetween(ctx context.Context, graphUID, source, target, label string, update api.EdgeUpdate) (*api.Edge, error) {
 	tx, err := es.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
@@ -109,7 +129,7 @@ func (es *EdgeService) UpdateEdgeBetween(ctx context.Context, graphUID, source,
 	// nolint:errcheck
 	defer tx.Rollback()
 
-	edge, err := updateEdgeBetween(ctx, tx, graphUID, source, target, update)
+	edge, err := updateEdgeBetween(ctx, tx, graphUID, source, target, label, update)
 	if err != nil {
 		return nil, err
 	}
@@ -286,8 +306,13 @@ func findEdges(ctx context.Context, tx *Tx, graphUID string, filter api.EdgeFilt
 	return edges, n, nil
 }
 
-func updateEdgeBetween(ctx context.Context, tx *Tx, graphUID, source, target string, update api.EdgeUpdate) (*api.Edge, error) {
-	edge, err := findEdgeBetween(ctx, tx, graphUID, source, target)
+func updateEdgeBetween(ctx context.Context, tx *Tx, graphUID, source, target, label string, update api.EdgeUpdate) (*api.Edge, error) {
+	edgeUID, err := findEdgeUIDBetween(ctx, tx, graphUID, source, target, label)
+	if err != nil {
+		return nil, err
+	}
+
+	edge, err := findEdgeByUID(ctx, tx, graphUID, edgeUID)
 	if err != nil {
 		return nil, err
 	}
@@ -295,6 +320,11 @@ func updateEdgeBetween(ctx context.Context, tx *Tx, graphUID, source, target str
 	if update.Label != nil {
 		edge.Label = *update.Label
 	}
+
+	if err := api.CoerceAttrs(tx.db.Schema.EdgeSchema(edge.Label), update.Attrs); err != nil {
+		return nil, err
+	}
+
 	if update.Weight != nil {
 		edge.Weight = *update.Weight
 	}
@@ -330,9 +360,8 @@ func updateEdgeBetween(ctx context.Context, tx *Tx, graphUID, source, target str
 		sqlQuery += ")"
 	}
 
-	// Add the source and target to the arguments for the WHERE clause.
-	sqlQuery += " WHERE source = ? AND target = ? AND graph = ?"
-	args = append(args, source, target, graphUID)
+	sqlQuery += " WHERE uid = ? AND graph = ?"
+	args = append(args, edge.UID, graphUID)
 
 	// Execute the SQL query.
 	if _, err := tx.ExecContext(ctx, sqlQuery, args...); err != nil {
@@ -342,6 +371,48 @@ func updateEdgeBetween(ctx context.Context, tx *Tx, graphUID, source, target str
 	return edge, nil
 }
 
+// findEdgeUIDBetween returns the UID of the edge between source and target.
+// If label is not empty only the edges with the given label are matched.
+// It fails with ECONFLICT if more than one edge matches.
+func findEdgeUIDBetween(ctx context.Context, tx *Tx, graphUID, source, target, label string) (string, error) {
+	where, args, err := edgeBetweenClause(ctx, tx, graphUID, source, target)
+	if err != nil {
+		return "", err
+	}
+
+	if label != "" {
+		where += " AND label = ?"
+		args = append(args, label)
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT uid FROM edges WHERE `+where+` ORDER BY rowid LIMIT 2`, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var uids []string
+	for rows.Next() {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			return "", err
+		}
+		uids = append(uids, uid)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	switch len(uids) {
+	case 0:
+		return "", &api.Error{Code: api.ENOTFOUND, Message: "Edge not found."}
+	case 1:
+		return uids[0], nil
+	}
+
+	return "", api.Errorf(api.ECONFLICT, "multiple edges %s->%s found", source, target)
+}
+
 func deleteEdge(ctx context.Context, tx *Tx, graphUID, edgeUID string) error {
 	if _, err := tx.ExecContext(ctx, `DELETE FROM edges WHERE uid = ? AND graph = ?`, edgeUID, graphUID); err != nil {
 		return err
@@ -350,16 +421,53 @@ func deleteEdge(ctx context.Context, tx *Tx, graphUID, edgeUID string) error {
 }
 
 func deleteEdgeBetween(ctx context.Context, tx *Tx, graphUID, source, target string) error {
-	if _, err := tx.ExecContext(ctx, `DELETE FROM edges WHERE source = ? AND target = ? AND graph = ?`, source, target, graphUID); err != nil {
+	where, args, err := edgeBetweenClause(ctx, tx, graphUID, source, target)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM edges WHERE `+where, args...); err != nil {
 		return err
 	}
 	return nil
 }
 
+// graphType returns the type of the graph with the given uid.
+func graphType(ctx context.Context, tx *Tx, graphUID string) (string, error) {
+	var typ string
+	if err := tx.QueryRowContext(ctx, `SELECT type FROM graphs WHERE uid = ?`, graphUID).Scan(&typ); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", &api.Error{Code: api.ENOTFOUND, Message: "Graph not found."}
+		}
+		return "", err
+	}
+	return typ, nil
+}
+
+// edgeBetweenClause returns WHERE clause and its arguments matching the edges between source and target.
+// Edges of undirected graphs are matched regardless of their direction.
+func edgeBetweenClause(ctx context.Context, tx *Tx, graphUID, source, target string) (string, []interface{}, error) {
+	typ, err := graphType(ctx, tx, graphUID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if graph.IsDirected(typ) {
+		return "source = ? AND target = ? AND graph = ?", []interface{}{source, target, graphUID}, nil
+	}
+
+	return "((source = ? AND target = ?) OR (source = ? AND target = ?)) AND graph = ?",
+		[]interface{}{source, target, target, source, graphUID}, nil
+}
+
 func findEdgeBetween(ctx context.Context, tx *Tx, graphUID, source, target string) (*api.Edge, error) {
 	var edge api.Edge
 	var attrString string
 
+	where, args, err := edgeBetweenClause(ctx, tx, graphUID, source, target)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := tx.QueryRowContext(ctx, `
 		SELECT
 			uid,
@@ -371,8 +479,10 @@ func findEdgeBetween(ctx context.Context, tx *Tx, graphUID, source, target strin
 			created_at,
 			updated_at
 		FROM edges
-		WHERE source = ? AND target = ? AND graph = ?
-	`, source, target, graphUID).Scan(
+		WHERE `+where+`
+		ORDER BY rowid
+		LIMIT 1
+	`, args...).Scan(
 		&edge.UID,
 		&edge.Source,
 		&edge.Target,
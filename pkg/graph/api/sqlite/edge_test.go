@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/milosgajdos/orbnet/pkg/graph"
 	"github.com/milosgajdos/orbnet/pkg/graph/api"
 )
 
@@ -60,6 +61,27 @@ func TestEdgeService_CreateEdge(t *testing.T) {
 		}
 	})
 
+	t.Run("ErrConflict", func(t *testing.T) {
+		db := MustOpenDB(t)
+		defer MustCloseDB(t, db)
+		es := MustEdgeService(t, db)
+
+		graphUID := "graph1"
+		MustCreateGraph(context.Background(), t, db, &api.Graph{UID: graphUID, Label: StringPtr("Graph1")})
+
+		node1 := &api.Node{UID: "node1", Label: StringPtr("Node1")}
+		node2 := &api.Node{UID: "node2", Label: StringPtr("Node2")}
+		MustCreateNode(context.Background(), t, db, graphUID, node1)
+		MustCreateNode(context.Background(), t, db, graphUID, node2)
+
+		MustCreateEdge(context.Background(), t, db, graphUID, &api.Edge{UID: "edge1", Source: node1.UID, Target: node2.UID})
+
+		dup := &api.Edge{UID: "edge2", Source: node1.UID, Target: node2.UID}
+		if err := es.CreateEdge(context.Background(), graphUID, dup); api.ErrorCode(err) != api.ECONFLICT {
+			t.Fatalf("expected error: %s, got: %v", api.ECONFLICT, err)
+		}
+	})
+
 	t.Run("ErrUIDRequired", func(t *testing.T) {
 		db := MustOpenDB(t)
 		defer MustCloseDB(t, db)
@@ -197,7 +219,7 @@ func TestEdgeService_UpdateEdge(t *testing.T) {
 		newLabel := "Edge1Updated"
 		newWeight := 2.0
 		newAttrs := map[string]interface{}{"foo": "baz"}
-		ue, err := es.UpdateEdgeBetween(ctx, graphUID, node1.UID, node2.UID,
+		ue, err := es.UpdateEdgeBetween(ctx, graphUID, node1.UID, node2.UID, "",
 			api.EdgeUpdate{
 				Label:  &newLabel,
 				Weight: &newWeight,
@@ -233,12 +255,67 @@ func TestEdgeService_UpdateEdge(t *testing.T) {
 		}
 	})
 
+	t.Run("Multi", func(t *testing.T) {
+		db := MustOpenDB(t)
+		defer MustCloseDB(t, db)
+		es := MustEdgeService(t, db)
+
+		ctx := context.Background()
+		graphUID := "graph1"
+		MustCreateGraph(ctx, t, db, &api.Graph{UID: graphUID, Type: graph.WeightedDirectedMulti, Label: StringPtr("Graph1")})
+
+		node1 := &api.Node{UID: "node1", Label: StringPtr("Node1")}
+		node2 := &api.Node{UID: "node2", Label: StringPtr("Node2")}
+		MustCreateNode(ctx, t, db, graphUID, node1)
+		MustCreateNode(ctx, t, db, graphUID, node2)
+
+		edge1 := &api.Edge{UID: "edge1", Source: node1.UID, Target: node2.UID, Label: "Edge1", Weight: 1.0}
+		edge2 := &api.Edge{UID: "edge2", Source: node1.UID, Target: node2.UID, Label: "Edge2", Weight: 1.0}
+		MustCreateEdge(ctx, t, db, graphUID, edge1)
+		MustCreateEdge(ctx, t, db, graphUID, edge2)
+
+		newWeight := 2.0
+		update := api.EdgeUpdate{Weight: &newWeight}
+
+		if _, err := es.UpdateEdgeBetween(ctx, graphUID, node1.UID, node2.UID, "", update); api.ErrorCode(err) != api.ECONFLICT {
+			t.Fatalf("code=%v, want %v", api.ErrorCode(err), api.ECONFLICT)
+		}
+
+		if _, err := es.UpdateEdgeBetween(ctx, graphUID, node1.UID, node2.UID, "Edge3", update); api.ErrorCode(err) != api.ENOTFOUND {
+			t.Fatalf("code=%v, want %v", api.ErrorCode(err), api.ENOTFOUND)
+		}
+
+		ue, err := es.UpdateEdgeBetween(ctx, graphUID, node1.UID, node2.UID, edge2.Label, update)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := ue.UID, edge2.UID; got != want {
+			t.Fatalf("uid=%v, want %v", got, want)
+		}
+
+		for _, e := range []struct {
+			uid    string
+			weight float64
+		}{
+			{edge1.UID, 1.0},
+			{edge2.UID, newWeight},
+		} {
+			got, err := es.FindEdgeByUID(ctx, graphUID, e.uid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Weight != e.weight {
+				t.Fatalf("%s weight=%v, want=%v", e.uid, got.Weight, e.weight)
+			}
+		}
+	})
+
 	t.Run("NonExistent", func(t *testing.T) {
 		db := MustOpenDB(t)
 		defer MustCloseDB(t, db)
 		es := MustEdgeService(t, db)
 
-		_, err := es.UpdateEdgeBetween(context.Background(), "graph1", "source1", "target1",
+		_, err := es.UpdateEdgeBetween(context.Background(), "graph1", "source1", "target1", "",
 			api.EdgeUpdate{Label: StringPtr("foo")})
 		if err == nil {
 			t.Fatal("expected error")
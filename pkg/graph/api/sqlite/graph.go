@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/milosgajdos/orbnet/pkg/graph"
 	"github.com/milosgajdos/orbnet/pkg/graph/api"
 )
 
@@ -31,6 +32,14 @@ func (gs *GraphService) CreateGraph(ctx context.Context, g *api.Graph) error {
 	// nolint:errcheck
 	defer tx.Rollback()
 
+	if g.Type == "" {
+		g.Type = graph.WeightedDirected
+	}
+
+	if !graph.IsValidType(g.Type) {
+		return api.Errorf(api.EINVALID, "invalid graph type: %s", g.Type)
+	}
+
 	g.CreatedAt = time.Now()
 	g.UpdatedAt = g.CreatedAt
 
@@ -43,14 +52,16 @@ func (gs *GraphService) CreateGraph(ctx context.Context, g *api.Graph) error {
 	_, err = tx.ExecContext(ctx, `
 		INSERT INTO graphs (
 			uid,
+			type,
 			label,
 			attrs,
 			created_at,
 			updated_at
 		)
-		VALUES (?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?)
 	`,
 		g.UID,
+		g.Type,
 		g.Label,
 		string(attrs),
 		(*NullTime)(&g.CreatedAt),
@@ -118,6 +129,7 @@ func findGraphs(ctx context.Context, tx *Tx, filter api.GraphFilter) (_ []*api.G
 	rows, err := tx.QueryContext(ctx, `
 		SELECT
 		    uid,
+		    type,
 		    label,
 		    attrs,
 		    created_at,
@@ -140,6 +152,7 @@ func findGraphs(ctx context.Context, tx *Tx, filter api.GraphFilter) (_ []*api.G
 		var attrString string
 		if err := rows.Scan(
 			&graph.UID,
+			&graph.Type,
 			&graph.Label,
 			&attrString,
 			(*NullTime)(&graph.CreatedAt),
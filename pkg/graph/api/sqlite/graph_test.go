@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/milosgajdos/orbnet/pkg/graph"
 	"github.com/milosgajdos/orbnet/pkg/graph/api"
 )
 
@@ -75,6 +76,9 @@ func TestGraphService_CreateGraph(t *testing.T) {
 		if got, want := *got.Label, *g.Label; got != want {
 			t.Fatalf("label=%v, want %v", got, want)
 		}
+		if got, want := got.Type, graph.WeightedDirected; got != want {
+			t.Fatalf("type=%v, want %v", got, want)
+		}
 		for k, v := range got.Attrs {
 			wantVal, ok := g.Attrs[k]
 			if !ok {
@@ -92,6 +96,40 @@ func TestGraphService_CreateGraph(t *testing.T) {
 		}
 	})
 
+	t.Run("Type", func(t *testing.T) {
+		db := MustOpenDB(t)
+		defer MustCloseDB(t, db)
+		gs := MustGraphService(t, db)
+
+		g := &api.Graph{
+			UID:   "Foobar",
+			Type:  graph.WeightedUndirectedMulti,
+			Label: StringPtr("Foo"),
+		}
+		if err := gs.CreateGraph(context.Background(), g); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := gs.FindGraphByUID(context.Background(), "Foobar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := got.Type, g.Type; got != want {
+			t.Fatalf("type=%v, want %v", got, want)
+		}
+	})
+
+	t.Run("ErrInvalidType", func(t *testing.T) {
+		db := MustOpenDB(t)
+		defer MustCloseDB(t, db)
+		gs := MustGraphService(t, db)
+
+		g := &api.Graph{UID: "Foobar", Type: "foo", Label: StringPtr("Foo")}
+		if err := gs.CreateGraph(context.Background(), g); api.ErrorCode(err) != api.EINVALID {
+			t.Fatalf("expected error: %s, got: %v", api.EINVALID, err)
+		}
+	})
+
 	t.Run("ErrUIDRequired", func(t *testing.T) {
 		db := MustOpenDB(t)
 		defer MustCloseDB(t, db)
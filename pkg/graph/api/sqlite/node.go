@@ -31,6 +31,15 @@ func (ns *NodeService) CreateNode(ctx context.Context, graphUID string, n *api.N
 	// nolint:errcheck
 	defer tx.Rollback()
 
+	var label string
+	if n.Label != nil {
+		label = *n.Label
+	}
+
+	if err := api.CoerceAttrs(ns.db.Schema.NodeSchema(label), n.Attrs); err != nil {
+		return err
+	}
+
 	n.CreatedAt = time.Now()
 	n.UpdatedAt = n.CreatedAt
 
@@ -252,6 +261,15 @@ func updateNode(ctx context.Context, tx *Tx, graphUID string, id int64, update a
 		node.Label = update.Label
 	}
 
+	var label string
+	if node.Label != nil {
+		label = *node.Label
+	}
+
+	if err := api.CoerceAttrs(tx.db.Schema.NodeSchema(label), update.Attrs); err != nil {
+		return nil, err
+	}
+
 	node.UpdatedAt = time.Now()
 
 	// Prepare the SQL query for updating the node.
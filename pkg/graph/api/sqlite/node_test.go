@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/milosgajdos/orbnet/pkg/graph/api"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
 )
 
 func MustNodeService(t *testing.T, db *DB) *NodeService {
@@ -87,6 +88,44 @@ func TestNodeService_CreateNode(t *testing.T) {
 			t.Fatal("expected error")
 		}
 	})
+
+	t.Run("ErrInvalidAttrs", func(t *testing.T) {
+		db := MustOpenDB(t)
+		defer MustCloseDB(t, db)
+		db.Schema = attrs.NewRegistry()
+		if err := db.Schema.SetNodeSchema("Node1", attrs.Schema{"num": attrs.Int}); err != nil {
+			t.Fatal(err)
+		}
+		ns := MustNodeService(t, db)
+
+		ctx := context.Background()
+
+		g := &api.Graph{UID: "graph1ian", Label: StringPtr("Graph1")}
+		MustCreateGraph(ctx, t, db, g)
+
+		n := &api.Node{
+			UID:   "node1ian",
+			Label: StringPtr("Node1"),
+			Attrs: map[string]interface{}{"num": "foo"},
+		}
+
+		if err := ns.CreateNode(ctx, g.UID, n); api.ErrorCode(err) != api.EINVALID {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		n.Attrs["num"] = "10"
+		if err := ns.CreateNode(ctx, g.UID, n); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := ns.FindNodeByUID(ctx, g.UID, n.UID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := got.Attrs["num"], int64(10); got != want {
+			t.Fatalf("num=%#v, want %#v", got, want)
+		}
+	})
 }
 
 func TestNodeService_FindNode(t *testing.T) {
@@ -0,0 +1,139 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/api"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	"github.com/milosgajdos/orbnet/pkg/graph/paths"
+)
+
+// PathService finds paths between graph nodes.
+type PathService struct {
+	db *DB
+}
+
+// NewPathService creates an instance of PathService and returns it.
+func NewPathService(db *DB) (*PathService, error) {
+	return &PathService{
+		db: db,
+	}, nil
+}
+
+// FindPaths returns the paths from the source to the target node matching the filter.
+func (ps *PathService) FindPaths(ctx context.Context, graphUID, source, target string, filter api.PathFilter) ([]*api.Path, error) {
+	tx, err := ps.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// nolint:errcheck
+	defer tx.Rollback()
+
+	return findPaths(ctx, tx, graphUID, source, target, filter)
+}
+
+// findPaths loads the topology of the graph with the given uid into memory and finds the paths in it.
+// Only the nodes and edges on the found paths are returned with their attributes.
+func findPaths(ctx context.Context, tx *Tx, graphUID, source, target string, filter api.PathFilter) ([]*api.Path, error) {
+	typ, err := graphType(ctx, tx, graphUID)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := memory.NewGraph(memory.WithUID(graphUID), memory.WithType(typ))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, uid FROM nodes WHERE graph = ?`, graphUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nodes := make(map[string]*memory.Node)
+	for rows.Next() {
+		var id int64
+		var uid string
+		if err := rows.Scan(&id, &uid); err != nil {
+			return nil, err
+		}
+
+		n, err := memory.NewNode(id, memory.WithUID(uid))
+		if err != nil {
+			return nil, err
+		}
+		g.AddNode(n)
+		nodes[uid] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	edges, _, err := findEdges(ctx, tx, graphUID, api.EdgeFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	apiEdges := make(map[string]*api.Edge, len(edges))
+	for _, e := range edges {
+		from, ok := nodes[e.Source]
+		if !ok {
+			return nil, api.Errorf(api.EINTERNAL, "source node %s of edge %s not found", e.Source, e.UID)
+		}
+
+		to, ok := nodes[e.Target]
+		if !ok {
+			return nil, api.Errorf(api.EINTERNAL, "target node %s of edge %s not found", e.Target, e.UID)
+		}
+
+		edge, err := memory.NewEdge(from, to, memory.WithUID(e.UID), memory.WithWeight(e.Weight))
+		if err != nil {
+			return nil, err
+		}
+		g.SetWeightedEdge(edge)
+		apiEdges[e.UID] = e
+	}
+
+	srcNode, ok := nodes[source]
+	if !ok {
+		return nil, api.Errorf(api.ENOTFOUND, "source node %s in graph %s", source, graphUID)
+	}
+
+	trgNode, ok := nodes[target]
+	if !ok {
+		return nil, api.Errorf(api.ENOTFOUND, "target node %s in graph %s", target, graphUID)
+	}
+
+	px, err := paths.Find(g, srcNode.ID(), trgNode.ID(), api.PathOptions(filter)...)
+	if err != nil {
+		if code := graph.ErrorCode(err); code == graph.EINVALID {
+			return nil, api.Errorf(api.EINVALID, "%s", graph.ErrorMessage(err))
+		}
+		return nil, err
+	}
+
+	apiPaths := make([]*api.Path, len(px))
+	for i, p := range px {
+		apiPath := &api.Path{
+			Nodes:  make([]*api.Node, len(p.Nodes)),
+			Edges:  make([]*api.Edge, len(p.Edges)),
+			Weight: p.Weight,
+		}
+
+		for j, n := range p.Nodes {
+			if apiPath.Nodes[j], err = findNodeByUID(ctx, tx, graphUID, n.UID()); err != nil {
+				return nil, err
+			}
+		}
+
+		for j, e := range p.Edges {
+			apiPath.Edges[j] = apiEdges[e.UID()]
+		}
+
+		apiPaths[i] = apiPath
+	}
+
+	return apiPaths, nil
+}
@@ -0,0 +1,179 @@
+package sqlite
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/api"
+)
+
+func MustPathService(t *testing.T, db *DB) *PathService {
+	ps, err := NewPathService(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ps
+}
+
+// MustCreatePathGraph creates a graph of the given type with the following edges:
+//
+//	node1 -> node2 (1), node2 -> node4 (1), node1 -> node3 (2), node3 -> node4 (1), node1 -> node4 (5)
+func MustCreatePathGraph(ctx context.Context, t *testing.T, db *DB, graphUID, typ string) {
+	t.Helper()
+
+	MustCreateGraph(ctx, t, db, &api.Graph{UID: graphUID, Type: typ, Label: StringPtr("Graph1")})
+
+	for _, uid := range []string{"node1", "node2", "node3", "node4"} {
+		MustCreateNode(ctx, t, db, graphUID, &api.Node{UID: uid, Label: StringPtr("Node")})
+	}
+
+	for _, e := range []*api.Edge{
+		{UID: "edge12", Source: "node1", Target: "node2", Weight: 1},
+		{UID: "edge24", Source: "node2", Target: "node4", Weight: 1},
+		{UID: "edge13", Source: "node1", Target: "node3", Weight: 2},
+		{UID: "edge34", Source: "node3", Target: "node4", Weight: 1},
+		{UID: "edge14", Source: "node1", Target: "node4", Weight: 5},
+	} {
+		MustCreateEdge(ctx, t, db, graphUID, e)
+	}
+}
+
+func pathUIDs(p *api.Path) ([]string, []string) {
+	nodes := make([]string, len(p.Nodes))
+	for i, n := range p.Nodes {
+		nodes[i] = n.UID
+	}
+
+	edges := make([]string, len(p.Edges))
+	for i, e := range p.Edges {
+		edges[i] = e.UID
+	}
+
+	return nodes, edges
+}
+
+func TestPathService_FindPaths(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		db := MustOpenDB(t)
+		defer MustCloseDB(t, db)
+		ps := MustPathService(t, db)
+
+		ctx := context.Background()
+		graphUID := "graph1"
+		MustCreatePathGraph(ctx, t, db, graphUID, graph.WeightedDirected)
+
+		k, depth := 3, 1
+
+		testCases := []struct {
+			name    string
+			filter  api.PathFilter
+			nodes   [][]string
+			edges   [][]string
+			weights []float64
+		}{
+			{
+				name:    "Dijkstra",
+				nodes:   [][]string{{"node1", "node2", "node4"}},
+				edges:   [][]string{{"edge12", "edge24"}},
+				weights: []float64{2},
+			},
+			{
+				name:    "Yen",
+				filter:  api.PathFilter{Algorithm: StringPtr("yen"), K: &k},
+				nodes:   [][]string{{"node1", "node2", "node4"}, {"node1", "node3", "node4"}, {"node1", "node4"}},
+				edges:   [][]string{{"edge12", "edge24"}, {"edge13", "edge34"}, {"edge14"}},
+				weights: []float64{2, 3, 5},
+			},
+			{
+				name:    "AllSimple",
+				filter:  api.PathFilter{Algorithm: StringPtr("all"), MaxDepth: &depth},
+				nodes:   [][]string{{"node1", "node4"}},
+				edges:   [][]string{{"edge14"}},
+				weights: []float64{5},
+			},
+		}
+
+		for _, tc := range testCases {
+			px, err := ps.FindPaths(ctx, graphUID, "node1", "node4", tc.filter)
+			if err != nil {
+				t.Fatalf("%s: failed to find paths: %v", tc.name, err)
+			}
+
+			if len(px) != len(tc.nodes) {
+				t.Fatalf("%s: expected paths: %d, got: %d", tc.name, len(tc.nodes), len(px))
+			}
+
+			for i, p := range px {
+				nodes, edges := pathUIDs(p)
+				if !reflect.DeepEqual(nodes, tc.nodes[i]) || !reflect.DeepEqual(edges, tc.edges[i]) {
+					t.Errorf("%s: path %d: expected: %v %v, got: %v %v", tc.name, i, tc.nodes[i], tc.edges[i], nodes, edges)
+				}
+				if p.Weight != tc.weights[i] {
+					t.Errorf("%s: path %d: expected weight: %f, got: %f", tc.name, i, tc.weights[i], p.Weight)
+				}
+			}
+		}
+
+		if px, err := ps.FindPaths(ctx, graphUID, "node4", "node1", api.PathFilter{}); err != nil || len(px) != 0 {
+			t.Errorf("expected no paths, got: %d, %v", len(px), err)
+		}
+	})
+
+	t.Run("Undirected", func(t *testing.T) {
+		db := MustOpenDB(t)
+		defer MustCloseDB(t, db)
+		ps := MustPathService(t, db)
+
+		ctx := context.Background()
+		graphUID := "graph1"
+		MustCreatePathGraph(ctx, t, db, graphUID, graph.WeightedUndirected)
+
+		px, err := ps.FindPaths(ctx, graphUID, "node4", "node1", api.PathFilter{Algorithm: StringPtr("astar")})
+		if err != nil {
+			t.Fatalf("failed to find paths: %v", err)
+		}
+
+		if len(px) != 1 {
+			t.Fatalf("expected paths: %d, got: %d", 1, len(px))
+		}
+
+		nodes, edges := pathUIDs(px[0])
+		if exp := []string{"node4", "node2", "node1"}; !reflect.DeepEqual(nodes, exp) {
+			t.Errorf("expected nodes: %v, got: %v", exp, nodes)
+		}
+
+		if exp := []string{"edge24", "edge12"}; !reflect.DeepEqual(edges, exp) {
+			t.Errorf("expected edges: %v, got: %v", exp, edges)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		db := MustOpenDB(t)
+		defer MustCloseDB(t, db)
+		ps := MustPathService(t, db)
+
+		ctx := context.Background()
+		graphUID := "graph1"
+		MustCreatePathGraph(ctx, t, db, graphUID, graph.WeightedDirected)
+
+		testCases := []struct {
+			name   string
+			guid   string
+			source string
+			filter api.PathFilter
+			code   string
+		}{
+			{"GraphNotFound", "foo", "node1", api.PathFilter{}, api.ENOTFOUND},
+			{"NodeNotFound", graphUID, "foo", api.PathFilter{}, api.ENOTFOUND},
+			{"Algorithm", graphUID, "node1", api.PathFilter{Algorithm: StringPtr("foo")}, api.EINVALID},
+		}
+
+		for _, tc := range testCases {
+			if _, err := ps.FindPaths(ctx, tc.guid, tc.source, "node4", tc.filter); api.ErrorCode(err) != tc.code {
+				t.Errorf("%s: expected error: %s, got: %v", tc.name, tc.code, err)
+			}
+		}
+	})
+}
@@ -13,6 +13,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+
 	// sqlite blank import
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -37,6 +39,8 @@ type DB struct {
 
 	// Datasource name.
 	DSN string
+	// Schema validates and coerces node and edge attributes.
+	Schema *attrs.Registry
 }
 
 // Tx wraps the SQL Tx object to provide a timestamp at the start of the transaction.
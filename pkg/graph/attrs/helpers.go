@@ -25,6 +25,59 @@ func CopyFrom(a map[string]interface{}) map[string]interface{} {
 	return attrs
 }
 
+// DeepCopy creates a deep copy of a and returns it.
+// Maps and slices stored in a are copied recursively.
+func DeepCopy(a map[string]interface{}) map[string]interface{} {
+	attrs := make(map[string]interface{}, len(a))
+
+	for k, v := range a {
+		attrs[k] = CopyValue(v)
+	}
+
+	return attrs
+}
+
+// CopyValue returns a deep copy of v if v is a map or a slice, and v otherwise.
+func CopyValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return copyValue(reflect.ValueOf(v)).Interface()
+}
+
+// copyValue copies maps and slices stored in v recursively.
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(copyValue(v.Elem()))
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), copyValue(iter.Value()))
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return c
+	}
+	return v
+}
+
 // isStringly checks if a is either a string
 // or if it implements fmt.Stringer or fmt.GoStringer.
 // It returns the bool flag indicating the result and
@@ -42,6 +42,35 @@ func TestNewCopyFrom(t *testing.T) {
 	}
 }
 
+func TestDeepCopy(t *testing.T) {
+	a := map[string]interface{}{
+		"rand":   10,
+		"date":   testDate,
+		"topics": []string{"go", "graph"},
+		"nested": map[string]interface{}{
+			"list": []interface{}{1, map[string]int{"foo": 1}},
+		},
+		"nil": nil,
+	}
+
+	a2 := DeepCopy(a)
+
+	if !reflect.DeepEqual(a, a2) {
+		t.Fatalf("expected %v, got: %v", a, a2)
+	}
+
+	a2["topics"].([]string)[0] = "rust"
+	a2["nested"].(map[string]interface{})["list"].([]interface{})[1].(map[string]int)["foo"] = 2
+
+	if topic := a["topics"].([]string)[0]; topic != "go" {
+		t.Errorf("expected copied list, got shared: %v", topic)
+	}
+
+	if foo := a["nested"].(map[string]interface{})["list"].([]interface{})[1].(map[string]int)["foo"]; foo != 1 {
+		t.Errorf("expected copied map, got shared: %v", foo)
+	}
+}
+
 func TestAttrsToStringMap(t *testing.T) {
 	a := map[string]interface{}{
 		"foo":   Foo{},
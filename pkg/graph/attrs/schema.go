@@ -0,0 +1,540 @@
+package attrs
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Type is attribute value type.
+type Type string
+
+const (
+	// String values are stored as string.
+	String Type = "string"
+	// Int values are stored as int64.
+	Int Type = "int"
+	// Float values are stored as float64.
+	Float Type = "float"
+	// Bool values are stored as bool.
+	Bool Type = "bool"
+	// Time values are stored as time.Time.
+	Time Type = "time"
+	// Color values are stored as color.RGBA.
+	Color Type = "color"
+	// List values are stored as []interface{}.
+	List Type = "list"
+	// Map values are stored as map[string]interface{}.
+	Map Type = "map"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// IsValid returns true if t is a known attribute type.
+func (t Type) IsValid() bool {
+	switch t {
+	case String, Int, Float, Bool, Time, Color, List, Map:
+		return true
+	}
+	return false
+}
+
+// Schema maps attribute keys to their types.
+// Attributes whose keys are not in the schema are left untouched.
+type Schema map[string]Type
+
+// Keys returns schema keys sorted alphabetically.
+func (s Schema) Keys() []string {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Coerce converts the values of attributes a declared in s to their schema types in place.
+// It returns error if any of the values can not be converted in which case a is left unchanged.
+func (s Schema) Coerce(a map[string]interface{}) error {
+	coerced := make(map[string]interface{}, len(s))
+
+	for k, t := range s {
+		v, ok := a[k]
+		if !ok {
+			continue
+		}
+
+		cv, err := Coerce(t, v)
+		if err != nil {
+			return fmt.Errorf("attribute %q: %w", k, err)
+		}
+		coerced[k] = cv
+	}
+
+	for k, v := range coerced {
+		a[k] = v
+	}
+
+	return nil
+}
+
+// Validate returns error if any of the values of attributes a
+// declared in s can not be converted to their schema types.
+func (s Schema) Validate(a map[string]interface{}) error {
+	for k, t := range s {
+		if v, ok := a[k]; ok {
+			if _, err := Coerce(t, v); err != nil {
+				return fmt.Errorf("attribute %q: %w", k, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Registry stores attribute schemas of nodes and edges by their labels.
+// Registry methods are safe to call on nil Registry which has no schemas.
+type Registry struct {
+	nodes map[string]Schema
+	edges map[string]Schema
+	mu    *sync.RWMutex
+}
+
+// NewRegistry creates a new empty registry and returns it.
+func NewRegistry() *Registry {
+	return &Registry{
+		nodes: make(map[string]Schema),
+		edges: make(map[string]Schema),
+		mu:    &sync.RWMutex{},
+	}
+}
+
+// SetNodeSchema sets the attribute schema of the nodes with the given label.
+// It returns error if the schema contains unknown types.
+func (r *Registry) SetNodeSchema(label string, s Schema) error {
+	return r.set(r.nodes, label, s)
+}
+
+// SetEdgeSchema sets the attribute schema of the edges with the given label.
+// It returns error if the schema contains unknown types.
+func (r *Registry) SetEdgeSchema(label string, s Schema) error {
+	return r.set(r.edges, label, s)
+}
+
+// NodeSchema returns the attribute schema of the nodes with the given label or nil.
+func (r *Registry) NodeSchema(label string) Schema {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.nodes[label]
+}
+
+// EdgeSchema returns the attribute schema of the edges with the given label or nil.
+func (r *Registry) EdgeSchema(label string) Schema {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.edges[label]
+}
+
+// NodeKeys returns the types of all the node attributes declared in the registry.
+// If the same key is declared with different types its type is String.
+func (r *Registry) NodeKeys() Schema {
+	if r == nil {
+		return Schema{}
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return union(r.nodes)
+}
+
+// EdgeKeys returns the types of all the edge attributes declared in the registry.
+// If the same key is declared with different types its type is String.
+func (r *Registry) EdgeKeys() Schema {
+	if r == nil {
+		return Schema{}
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return union(r.edges)
+}
+
+// ExtendNodeSchemas extends the attribute schemas of all the node labels with s.
+// It returns error if s contains unknown types.
+func (r *Registry) ExtendNodeSchemas(s Schema) error {
+	for k, t := range s {
+		if !t.IsValid() {
+			return fmt.Errorf("attribute %q type %q: %w", k, t, ErrInvalidInput)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for label, ns := range r.nodes {
+		es := make(Schema, len(ns)+len(s))
+		for k, t := range ns {
+			es[k] = t
+		}
+		for k, t := range s {
+			es[k] = t
+		}
+		r.nodes[label] = es
+	}
+
+	return nil
+}
+
+// CoerceNode coerces attributes a of the node with the given label.
+func (r *Registry) CoerceNode(label string, a map[string]interface{}) error {
+	return r.NodeSchema(label).Coerce(a)
+}
+
+// CoerceEdge coerces attributes a of the edge with the given label.
+func (r *Registry) CoerceEdge(label string, a map[string]interface{}) error {
+	return r.EdgeSchema(label).Coerce(a)
+}
+
+func (r *Registry) set(schemas map[string]Schema, label string, s Schema) error {
+	for k, t := range s {
+		if !t.IsValid() {
+			return fmt.Errorf("attribute %q type %q: %w", k, t, ErrInvalidInput)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	schemas[label] = s
+
+	return nil
+}
+
+func union(schemas map[string]Schema) Schema {
+	u := make(Schema)
+	for _, s := range schemas {
+		for k, t := range s {
+			if ut, ok := u[k]; ok && ut != t {
+				u[k] = String
+				continue
+			}
+			u[k] = t
+		}
+	}
+	return u
+}
+
+// Coerce converts v to the Go type of attribute type t and returns it.
+// Nil values and nil pointers are returned as nil.
+func Coerce(t Type, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, nil
+	}
+
+	var (
+		cv interface{}
+		ok bool
+	)
+
+	switch t {
+	case String:
+		cv, ok = toString(v)
+	case Int:
+		cv, ok = toInt(v)
+	case Float:
+		cv, ok = toFloat(v)
+	case Bool:
+		cv, ok = toBool(v)
+	case Time:
+		cv, ok = toTime(v)
+	case Color:
+		cv, ok = toColor(v)
+	case List:
+		cv, ok = toList(v)
+	case Map:
+		cv, ok = toMap(v)
+	default:
+		return nil, fmt.Errorf("unknown type %q: %w", t, ErrInvalidInput)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("can not convert %T to %s: %w", v, t, ErrInvalidInput)
+	}
+
+	return cv, nil
+}
+
+// TypeOf infers the attribute type of v from its Go type.
+// Values of unknown types are String.
+func TypeOf(v interface{}) Type {
+	switch v.(type) {
+	case string:
+		return String
+	case bool:
+		return Bool
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return Int
+	case float32, float64:
+		return Float
+	case time.Time:
+		return Time
+	case color.RGBA:
+		return Color
+	}
+
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Slice, reflect.Array:
+		return List
+	case reflect.Map:
+		return Map
+	}
+
+	return String
+}
+
+// Format returns string representation of v of attribute type t.
+// Times are formatted as per time.RFC3339, colors as hex codes
+// of their RGB channels and lists and maps are encoded in JSON.
+// If v can not be coerced to t it is formatted with fmt.Sprint.
+func Format(t Type, v interface{}) string {
+	cv, err := Coerce(t, v)
+	if err != nil || cv == nil {
+		return fmt.Sprint(v)
+	}
+
+	switch val := cv.(type) {
+	case string:
+		return val
+	case time.Time:
+		return val.Format(time.RFC3339)
+	case color.RGBA:
+		return fmt.Sprintf("#%02x%02x%02x", val.R, val.G, val.B)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case []interface{}, map[string]interface{}:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func toString(v interface{}) (interface{}, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case []byte:
+		return string(val), true
+	case time.Time:
+		return val.Format(time.RFC3339), true
+	case fmt.Stringer:
+		return val.String(), true
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(val), true
+	}
+	return nil, false
+}
+
+func toInt(v interface{}) (interface{}, bool) {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i, true
+		}
+		f, err := val.Float64()
+		if err != nil {
+			return nil, false
+		}
+		return toInt(f)
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(val), 10, 64)
+		return i, err == nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := rv.Uint()
+		return int64(u), u <= math.MaxInt64
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		return int64(f), f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64
+	}
+
+	return nil, false
+}
+
+func toFloat(v interface{}) (interface{}, bool) {
+	switch val := v.(type) {
+	case json.Number:
+		f, err := val.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return f, err == nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+
+	return nil, false
+}
+
+func toBool(v interface{}) (interface{}, bool) {
+	switch val := v.(type) {
+	case bool:
+		return val, true
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(val))
+		return b, err == nil
+	}
+	return nil, false
+}
+
+func toTime(v interface{}) (interface{}, bool) {
+	switch val := v.(type) {
+	case time.Time:
+		return val, true
+	case string:
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(val))
+		return t, err == nil
+	}
+
+	if f, ok := toFloat(v); ok {
+		sec, frac := math.Modf(f.(float64))
+		return time.Unix(int64(sec), int64(frac*1e9)).UTC(), true
+	}
+
+	// pointers to time and wrapper types e.g. type Timestamp{time.Time}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Type() == timeType {
+		return rv.Interface().(time.Time), true
+	}
+
+	if rv.Kind() == reflect.Struct && rv.NumField() == 1 && rv.Type().Field(0).Anonymous && rv.Field(0).Type() == timeType {
+		return rv.Field(0).Interface().(time.Time), true
+	}
+
+	return nil, false
+}
+
+func toColor(v interface{}) (interface{}, bool) {
+	switch val := v.(type) {
+	case color.RGBA:
+		return val, true
+	case color.Color:
+		return color.RGBAModel.Convert(val).(color.RGBA), true
+	case string:
+		return parseHexColor(strings.TrimSpace(val))
+	case map[string]interface{}:
+		// color.RGBA encoded in JSON
+		var c [4]uint8
+		for i, k := range []string{"R", "G", "B", "A"} {
+			n, ok := toInt(val[k])
+			if !ok || n.(int64) < 0 || n.(int64) > math.MaxUint8 {
+				return nil, false
+			}
+			c[i] = uint8(n.(int64))
+		}
+		return color.RGBA{R: c[0], G: c[1], B: c[2], A: c[3]}, true
+	}
+	return nil, false
+}
+
+// parseHexColor parses #rrggbb and #rrggbbaa color hex codes.
+// Colors without alpha channel are opaque.
+func parseHexColor(s string) (interface{}, bool) {
+	if !strings.HasPrefix(s, "#") || (len(s) != 7 && len(s) != 9) {
+		return nil, false
+	}
+
+	n, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return nil, false
+	}
+
+	if len(s) == 7 {
+		n = n<<8 | 0xff
+	}
+
+	return color.RGBA{R: uint8(n >> 24), G: uint8(n >> 16), B: uint8(n >> 8), A: uint8(n)}, true
+}
+
+func toList(v interface{}) (interface{}, bool) {
+	if l, ok := v.([]interface{}); ok {
+		return l, true
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	l := make([]interface{}, rv.Len())
+	for i := range l {
+		l[i] = rv.Index(i).Interface()
+	}
+
+	return l, true
+}
+
+func toMap(v interface{}) (interface{}, bool) {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m, true
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		return m, true
+	case reflect.Struct:
+		m, err := Encode(rv.Interface())
+		return m, err == nil
+	}
+
+	return nil, false
+}
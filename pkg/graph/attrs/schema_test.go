@@ -0,0 +1,238 @@
+package attrs
+
+import (
+	"encoding/json"
+	"errors"
+	"image/color"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Timestamp struct {
+	time.Time
+}
+
+func TestCoerce(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		typ Type
+		v   interface{}
+		exp interface{}
+	}{
+		{String, "foo", "foo"},
+		{String, Foo{}, fooStr},
+		{String, 10, "10"},
+		{String, testDate, dateStr},
+		{Int, 10, int64(10)},
+		{Int, uint8(10), int64(10)},
+		{Int, 10.0, int64(10)},
+		{Int, json.Number("10"), int64(10)},
+		{Int, "10", int64(10)},
+		{Float, 10, 10.0},
+		{Float, json.Number("1.5"), 1.5},
+		{Float, "1.5", 1.5},
+		{Bool, true, true},
+		{Bool, "false", false},
+		{Time, testDate, testDate},
+		{Time, &testDate, testDate},
+		{Time, Timestamp{testDate}, testDate},
+		{Time, &Timestamp{testDate}, testDate},
+		{Time, dateStr, testDate},
+		{Time, testDate.Unix(), testDate},
+		{Time, float64(testDate.Unix()), testDate},
+		{Color, testColor, testColor},
+		{Color, "#f59af0", color.RGBA{R: 245, G: 154, B: 240, A: 255}},
+		{Color, "#f59af000", testColor},
+		{Color, map[string]interface{}{"R": 245.0, "G": 154.0, "B": 240.0, "A": 0.0}, testColor},
+		{Color, color.Gray{Y: 255}, color.RGBA{R: 255, G: 255, B: 255, A: 255}},
+		{List, []string{"foo", "bar"}, []interface{}{"foo", "bar"}},
+		{List, []interface{}{1, "bar"}, []interface{}{1, "bar"}},
+		{Map, map[string]int{"foo": 1}, map[string]interface{}{"foo": 1}},
+		{Map, map[string]interface{}{"foo": 1}, map[string]interface{}{"foo": 1}},
+		{Int, nil, nil},
+		{Time, (*Timestamp)(nil), nil},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(string(tc.typ), func(t *testing.T) {
+			t.Parallel()
+			v, err := Coerce(tc.typ, tc.v)
+			if err != nil {
+				t.Fatalf("failed to coerce %#v: %v", tc.v, err)
+			}
+			if tv, ok := v.(time.Time); ok {
+				if !tv.Equal(tc.exp.(time.Time)) {
+					t.Fatalf("expected: %v, got: %v", tc.exp, tv)
+				}
+				return
+			}
+			if !reflect.DeepEqual(v, tc.exp) {
+				t.Fatalf("expected: %#v, got: %#v", tc.exp, v)
+			}
+		})
+	}
+}
+
+func TestCoerceErrors(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		typ Type
+		v   interface{}
+	}{
+		{String, []int{1}},
+		{Int, 1.5},
+		{Int, 9.223372036854775808e18},
+		{Int, json.Number("9223372036854775808")},
+		{Int, "foo"},
+		{Int, true},
+		{Float, "foo"},
+		{Bool, 1},
+		{Time, "yesterday"},
+		{Color, "red"},
+		{Color, map[string]interface{}{"R": 300}},
+		{List, "foo"},
+		{Map, map[int]int{1: 1}},
+		{Type("foo"), 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(string(tc.typ), func(t *testing.T) {
+			t.Parallel()
+			if _, err := Coerce(tc.typ, tc.v); !errors.Is(err, ErrInvalidInput) {
+				t.Fatalf("expected error: %v, got: %v", ErrInvalidInput, err)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		typ Type
+		v   interface{}
+		exp string
+	}{
+		{String, "foo", "foo"},
+		{Int, 10.0, "10"},
+		{Float, 1.5, "1.5"},
+		{Bool, true, "true"},
+		{Time, Timestamp{testDate}, dateStr},
+		{Color, testColor, colorStr},
+		{List, []string{"foo", "bar"}, `["foo","bar"]`},
+		{Map, map[string]int{"foo": 1}, `{"foo":1}`},
+		{Int, "foo", "foo"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(string(tc.typ), func(t *testing.T) {
+			t.Parallel()
+			if s := Format(tc.typ, tc.v); s != tc.exp {
+				t.Fatalf("expected: %s, got: %s", tc.exp, s)
+			}
+		})
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	testCases := []struct {
+		v   interface{}
+		exp Type
+	}{
+		{"foo", String},
+		{int64(10), Int},
+		{1.5, Float},
+		{true, Bool},
+		{testDate, Time},
+		{testColor, Color},
+		{[]string{"foo"}, List},
+		{map[string]int{"foo": 1}, Map},
+		{Timestamp{testDate}, String},
+	}
+
+	for _, tc := range testCases {
+		if typ := TypeOf(tc.v); typ != tc.exp {
+			t.Errorf("%v: expected type: %s, got: %s", tc.v, tc.exp, typ)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	if err := r.SetNodeSchema("Repo", Schema{"stars": Int, "created_at": Time, "name": String}); err != nil {
+		t.Fatalf("failed to set node schema: %v", err)
+	}
+
+	if err := r.SetNodeSchema("Topic", Schema{"stars": Float}); err != nil {
+		t.Fatalf("failed to set node schema: %v", err)
+	}
+
+	if err := r.SetEdgeSchema("HasTopic", Schema{"weight": Float}); err != nil {
+		t.Fatalf("failed to set edge schema: %v", err)
+	}
+
+	if err := r.SetEdgeSchema("Foo", Schema{"weight": "foo"}); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected error: %v, got: %v", ErrInvalidInput, err)
+	}
+
+	a := map[string]interface{}{
+		"stars":      json.Number("10"),
+		"created_at": dateStr,
+		"other":      "untouched",
+	}
+
+	if err := r.CoerceNode("Repo", a); err != nil {
+		t.Fatalf("failed to coerce attributes: %v", err)
+	}
+
+	exp := map[string]interface{}{
+		"stars":      int64(10),
+		"created_at": testDate,
+		"other":      "untouched",
+	}
+
+	if !reflect.DeepEqual(a, exp) {
+		t.Fatalf("expected: %#v, got: %#v", exp, a)
+	}
+
+	invalid := map[string]interface{}{"stars": "foo", "created_at": dateStr}
+	if err := r.CoerceNode("Repo", invalid); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected error: %v, got: %v", ErrInvalidInput, err)
+	}
+
+	if invalid["created_at"] != dateStr {
+		t.Fatalf("expected attributes to be unchanged, got: %v", invalid)
+	}
+
+	if err := r.CoerceNode("Unknown", invalid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keys := r.NodeKeys()
+	if keys["stars"] != String || keys["created_at"] != Time {
+		t.Fatalf("unexpected node keys: %v", keys)
+	}
+
+	if err := r.ExtendNodeSchemas(Schema{"score": Float}); err != nil {
+		t.Fatalf("failed to extend node schemas: %v", err)
+	}
+
+	for _, label := range []string{"Repo", "Topic"} {
+		if typ := r.NodeSchema(label)["score"]; typ != Float {
+			t.Fatalf("expected %s score type: %v, got: %v", label, Float, typ)
+		}
+	}
+
+	if err := r.ExtendNodeSchemas(Schema{"score": "foo"}); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected error: %v, got: %v", ErrInvalidInput, err)
+	}
+
+	var nilRegistry *Registry
+	if err := nilRegistry.CoerceEdge("HasTopic", invalid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -19,7 +19,11 @@ func RepoAttrs(repo *github.Repository, starredAt *github.Timestamp) (map[string
 	if err != nil {
 		return nil, err
 	}
-	attrs["starred_at"] = starredAt
+	// NOTE: storing a nil *github.Timestamp would make a non-nil interface
+	attrs["starred_at"] = nil
+	if starredAt != nil {
+		attrs["starred_at"] = starredAt
+	}
 	return attrs, nil
 }
 
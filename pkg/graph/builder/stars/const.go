@@ -9,4 +9,6 @@ const (
 	IsLangEdgeLabel = "IsLanguage"
 	// HasTopicEdgeLabel is a label for repo topic edge.
 	HasTopicEdgeLabel = "HasTopic"
+	// SuggestedTopicEdgeLabel is a label for predicted repo topic edge.
+	SuggestedTopicEdgeLabel = "SuggestedTopic"
 )
@@ -0,0 +1,106 @@
+package stars
+
+import "github.com/milosgajdos/orbnet/pkg/graph/attrs"
+
+// styleSchema declares the types of style attributes of all entities.
+var styleSchema = attrs.Schema{
+	"style": attrs.String,
+	"shape": attrs.String,
+	"color": attrs.Color,
+}
+
+// schemas declares the types of well known attributes of GitHub stars graph entities.
+var (
+	ownerSchema = attrs.Schema{
+		"id":         attrs.Int,
+		"login":      attrs.String,
+		"name":       attrs.String,
+		"html_url":   attrs.String,
+		"type":       attrs.String,
+		"site_admin": attrs.Bool,
+	}
+
+	repoSchema = attrs.Schema{
+		"id":                attrs.Int,
+		"name":              attrs.String,
+		"full_name":         attrs.String,
+		"description":       attrs.String,
+		"html_url":          attrs.String,
+		"homepage":          attrs.String,
+		"language":          attrs.String,
+		"default_branch":    attrs.String,
+		"visibility":        attrs.String,
+		"size":              attrs.Int,
+		"stargazers_count":  attrs.Int,
+		"watchers_count":    attrs.Int,
+		"forks_count":       attrs.Int,
+		"open_issues_count": attrs.Int,
+		"fork":              attrs.Bool,
+		"archived":          attrs.Bool,
+		"disabled":          attrs.Bool,
+		"private":           attrs.Bool,
+		"topics":            attrs.List,
+		"owner":             attrs.Map,
+		"license":           attrs.Map,
+		"created_at":        attrs.Time,
+		"updated_at":        attrs.Time,
+		"pushed_at":         attrs.Time,
+		"starred_at":        attrs.Time,
+	}
+
+	topicSchema = attrs.Schema{
+		"name": attrs.String,
+		"url":  attrs.String,
+	}
+
+	langSchema = attrs.Schema{
+		"name": attrs.String,
+	}
+
+	linkSchema = attrs.Schema{
+		"relation": attrs.String,
+		"weight":   attrs.Float,
+	}
+
+	suggestedTopicSchema = attrs.Schema{
+		"relation":   attrs.String,
+		"weight":     attrs.Float,
+		"confidence": attrs.Float,
+	}
+)
+
+// Schema returns the attribute schema registry of GitHub stars graph nodes and edges.
+func Schema() *attrs.Registry {
+	r := attrs.NewRegistry()
+
+	nodes := map[Entity]attrs.Schema{
+		OwnerEntity: ownerSchema,
+		RepoEntity:  repoSchema,
+		TopicEntity: topicSchema,
+		LangEntity:  langSchema,
+	}
+
+	// NOTE: the schemas are valid so setting them never fails.
+	for e, s := range nodes {
+		_ = r.SetNodeSchema(e.String(), withStyle(s))
+	}
+
+	for _, label := range []string{OwnedByEdgeLabel, IsLangEdgeLabel, HasTopicEdgeLabel} {
+		_ = r.SetEdgeSchema(label, withStyle(linkSchema))
+	}
+	_ = r.SetEdgeSchema(SuggestedTopicEdgeLabel, withStyle(suggestedTopicSchema))
+
+	return r
+}
+
+// withStyle returns a copy of s extended with style attributes.
+func withStyle(s attrs.Schema) attrs.Schema {
+	ws := make(attrs.Schema, len(s)+len(styleSchema))
+	for k, t := range s {
+		ws[k] = t
+	}
+	for k, t := range styleSchema {
+		ws[k] = t
+	}
+	return ws
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/go-github/v61/github"
 	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
 	"github.com/milosgajdos/orbnet/pkg/graph/memory"
 	"github.com/milosgajdos/orbnet/pkg/graph/style"
 )
@@ -18,17 +19,20 @@ const (
 
 // Stars builds GitHub stars graph.
 type Stars struct {
-	g     graph.Adder
-	nodes map[string]*memory.Node
-	mu    *sync.RWMutex
+	g      graph.Adder
+	schema *attrs.Registry
+	nodes  map[string]*memory.Node
+	mu     *sync.RWMutex
 }
 
 // NewBuilder creates a new GH stars graph builder and returns it.
+// Node and edge attributes are coerced to the types declared in Schema.
 func NewBuilder(g graph.Adder) (*Stars, error) {
 	return &Stars{
-		g:     g,
-		nodes: make(map[string]*memory.Node),
-		mu:    &sync.RWMutex{},
+		g:      g,
+		schema: Schema(),
+		nodes:  make(map[string]*memory.Node),
+		mu:     &sync.RWMutex{},
 	}, nil
 }
 
@@ -37,6 +41,10 @@ func (s *Stars) addNode(uid, label string, attrs map[string]interface{}, style s
 	attrs["shape"] = style.Shape
 	attrs["color"] = style.Color
 
+	if err := s.schema.CoerceNode(label, attrs); err != nil {
+		return nil, err
+	}
+
 	opts := []memory.Option{
 		memory.WithUID(uid),
 		memory.WithLabel(label),
@@ -44,15 +52,7 @@ func (s *Stars) addNode(uid, label string, attrs map[string]interface{}, style s
 		memory.WithStyle(style),
 	}
 
-	node := s.g.NewNode()
-
-	n, err := memory.NewNode(node.ID(), opts...)
-	if err != nil {
-		return nil, err
-	}
-	s.g.AddNode(n)
-
-	return n, nil
+	return memory.AddNewNode(s.g, opts...)
 }
 
 func (s *Stars) linkNodes(from, to *memory.Node, label string, attrs map[string]interface{}, style style.Style) (*memory.Edge, error) {
@@ -60,6 +60,10 @@ func (s *Stars) linkNodes(from, to *memory.Node, label string, attrs map[string]
 	attrs["shape"] = style.Shape
 	attrs["color"] = style.Color
 
+	if err := s.schema.CoerceEdge(label, attrs); err != nil {
+		return nil, err
+	}
+
 	opts := []memory.Option{
 		memory.WithLabel(label),
 		memory.WithAttrs(attrs),
@@ -3,8 +3,10 @@ package stars
 import (
 	"context"
 	"encoding/json"
+	"image/color"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/milosgajdos/orbnet/pkg/graph"
 	"github.com/milosgajdos/orbnet/pkg/graph/memory"
@@ -68,3 +70,77 @@ func TestBuildGraph(t *testing.T) {
 
 	g.HasEdgeFromTo(0, 1)
 }
+
+func TestBuildGraphSchema(t *testing.T) {
+	g := MustGraph(t)
+	b := MustBuilder(t, g)
+
+	data, err := os.ReadFile(testPath)
+	if err != nil {
+		t.Fatalf("failed to read test data from %s: %v", testPath, err)
+	}
+
+	var repos []*github.StarredRepository
+	if err := json.Unmarshal(data, &repos); err != nil {
+		t.Fatalf("failed to unmarshal GitHub repos: %v", err)
+	}
+
+	reposChan := make(chan interface{}, 1)
+	reposChan <- repos
+	close(reposChan)
+
+	if err := b.Build(context.Background(), reposChan); err != nil {
+		t.Fatalf("failed to build graph: %v", err)
+	}
+
+	repo := g.NodeWithUID(*repos[0].Repository.NodeID)
+	if repo == nil {
+		t.Fatalf("repo %s not found", *repos[0].Repository.NodeID)
+	}
+
+	a := repo.Attrs()
+	if _, ok := a["starred_at"].(time.Time); !ok {
+		t.Errorf("expected starred_at time.Time, got: %T", a["starred_at"])
+	}
+
+	if _, ok := a["stargazers_count"].(int64); !ok {
+		t.Errorf("expected stargazers_count int64, got: %T", a["stargazers_count"])
+	}
+
+	if _, ok := a["color"].(color.RGBA); !ok {
+		t.Errorf("expected color color.RGBA, got: %T", a["color"])
+	}
+}
+
+func TestBuildGraphNoStarredAt(t *testing.T) {
+	g := MustGraph(t)
+	b := MustBuilder(t, g)
+
+	data, err := os.ReadFile(testPath)
+	if err != nil {
+		t.Fatalf("failed to read test data from %s: %v", testPath, err)
+	}
+
+	var repos []*github.StarredRepository
+	if err := json.Unmarshal(data, &repos); err != nil {
+		t.Fatalf("failed to unmarshal GitHub repos: %v", err)
+	}
+	repos[0].StarredAt = nil
+
+	reposChan := make(chan interface{}, 1)
+	reposChan <- repos[:1]
+	close(reposChan)
+
+	if err := b.Build(context.Background(), reposChan); err != nil {
+		t.Fatalf("failed to build graph: %v", err)
+	}
+
+	repo := g.NodeWithUID(*repos[0].Repository.NodeID)
+	if repo == nil {
+		t.Fatalf("repo %s not found", *repos[0].Repository.NodeID)
+	}
+
+	if v, ok := repo.Attrs()["starred_at"]; !ok || v != nil {
+		t.Errorf("expected nil starred_at, got: %#v", v)
+	}
+}
@@ -0,0 +1,9 @@
+package stars
+
+import _ "embed"
+
+// Vocabulary is RDF vocabulary of GitHub stars graph entity and edge labels in Turtle.
+// Its namespace is the default namespace of the rdf marshaler.
+//
+//go:embed vocab.ttl
+var Vocabulary []byte
@@ -0,0 +1,25 @@
+package stars
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestVocabulary(t *testing.T) {
+	labels := []string{
+		OwnerEntity.String(),
+		RepoEntity.String(),
+		TopicEntity.String(),
+		LangEntity.String(),
+		OwnedByEdgeLabel,
+		HasTopicEdgeLabel,
+		IsLangEdgeLabel,
+		SuggestedTopicEdgeLabel,
+	}
+
+	for _, label := range labels {
+		if !bytes.Contains(Vocabulary, []byte("\norb:"+label+" a ")) {
+			t.Errorf("vocabulary does not describe label: %s", label)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+)
+
+// Node is a snapshot of a graph node.
+type Node struct {
+	UID   string                 `json:"uid"`
+	Label string                 `json:"label"`
+	Attrs map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// Edge is a snapshot of a graph edge.
+type Edge struct {
+	UID    string                 `json:"uid"`
+	Source string                 `json:"source"`
+	Target string                 `json:"target"`
+	Label  string                 `json:"label"`
+	Weight float64                `json:"weight"`
+	Attrs  map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// LabelChange is a change of label.
+type LabelChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// WeightChange is a change of edge weight.
+type WeightChange struct {
+	Old float64 `json:"old"`
+	New float64 `json:"new"`
+}
+
+// AttrChange is a change of attribute value.
+// Old is nil if the attribute was added; New is nil if the attribute was removed.
+type AttrChange struct {
+	Key string      `json:"key"`
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
+
+// NodeChange describes a modified node.
+type NodeChange struct {
+	UID   string       `json:"uid"`
+	Label *LabelChange `json:"label,omitempty"`
+	Attrs []AttrChange `json:"attributes,omitempty"`
+}
+
+// EdgeChange describes a modified edge.
+type EdgeChange struct {
+	UID    string        `json:"uid"`
+	Source string        `json:"source"`
+	Target string        `json:"target"`
+	Label  *LabelChange  `json:"label,omitempty"`
+	Weight *WeightChange `json:"weight,omitempty"`
+	Attrs  []AttrChange  `json:"attributes,omitempty"`
+}
+
+// Diff is a difference between two graphs.
+type Diff struct {
+	AddedNodes    []Node       `json:"added_nodes"`
+	RemovedNodes  []Node       `json:"removed_nodes"`
+	ModifiedNodes []NodeChange `json:"modified_nodes"`
+	AddedEdges    []Edge       `json:"added_edges"`
+	RemovedEdges  []Edge       `json:"removed_edges"`
+	ModifiedEdges []EdgeChange `json:"modified_edges"`
+	// a and b are the compared graphs.
+	a, b graph.Graph
+	// edgeUIDs is true if edges are matched by their UIDs.
+	edgeUIDs bool
+}
+
+// Empty returns true if the compared graphs are the same.
+func (d *Diff) Empty() bool {
+	return len(d.AddedNodes) == 0 &&
+		len(d.RemovedNodes) == 0 &&
+		len(d.ModifiedNodes) == 0 &&
+		len(d.AddedEdges) == 0 &&
+		len(d.RemovedEdges) == 0 &&
+		len(d.ModifiedEdges) == 0
+}
+
+// Compare compares graph a to graph b and returns their difference.
+// Nodes are matched by their UIDs. Edges are matched by the UIDs of their
+// end nodes and their labels unless configured to be matched by their UIDs.
+func Compare(a, b graph.Graph, opts ...Option) (*Diff, error) {
+	dopts := Options{}
+	for _, apply := range opts {
+		apply(&dopts)
+	}
+
+	ignore := make(map[string]bool, len(dopts.IgnoreAttrs))
+	for _, k := range dopts.IgnoreAttrs {
+		ignore[k] = true
+	}
+
+	aNodes, err := nodes(a)
+	if err != nil {
+		return nil, err
+	}
+
+	bNodes, err := nodes(b)
+	if err != nil {
+		return nil, err
+	}
+
+	aEdges, err := edges(a, dopts.EdgeUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	bEdges, err := edges(b, dopts.EdgeUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Diff{
+		AddedNodes:    []Node{},
+		RemovedNodes:  []Node{},
+		ModifiedNodes: []NodeChange{},
+		AddedEdges:    []Edge{},
+		RemovedEdges:  []Edge{},
+		ModifiedEdges: []EdgeChange{},
+		a:             a,
+		b:             b,
+		edgeUIDs:      dopts.EdgeUIDs,
+	}
+
+	for uid, an := range aNodes {
+		bn, ok := bNodes[uid]
+		if !ok {
+			d.RemovedNodes = append(d.RemovedNodes, an)
+			continue
+		}
+
+		change := NodeChange{
+			UID:   uid,
+			Attrs: compareAttrs(an.Attrs, bn.Attrs, ignore),
+		}
+
+		if an.Label != bn.Label {
+			change.Label = &LabelChange{Old: an.Label, New: bn.Label}
+		}
+
+		if change.Label != nil || len(change.Attrs) > 0 {
+			d.ModifiedNodes = append(d.ModifiedNodes, change)
+		}
+	}
+
+	for uid, bn := range bNodes {
+		if _, ok := aNodes[uid]; !ok {
+			d.AddedNodes = append(d.AddedNodes, bn)
+		}
+	}
+
+	for key, ae := range aEdges {
+		be, ok := bEdges[key]
+		if !ok {
+			d.RemovedEdges = append(d.RemovedEdges, ae)
+			continue
+		}
+
+		change := EdgeChange{
+			UID:    be.UID,
+			Source: be.Source,
+			Target: be.Target,
+			Attrs:  compareAttrs(ae.Attrs, be.Attrs, ignore),
+		}
+
+		if ae.Label != be.Label {
+			change.Label = &LabelChange{Old: ae.Label, New: be.Label}
+		}
+
+		if ae.Weight != be.Weight {
+			change.Weight = &WeightChange{Old: ae.Weight, New: be.Weight}
+		}
+
+		if change.Label != nil || change.Weight != nil || len(change.Attrs) > 0 {
+			d.ModifiedEdges = append(d.ModifiedEdges, change)
+		}
+	}
+
+	for key, be := range bEdges {
+		if _, ok := aEdges[key]; !ok {
+			d.AddedEdges = append(d.AddedEdges, be)
+		}
+	}
+
+	d.sort()
+
+	return d, nil
+}
+
+// sort sorts the diff so it's output is deterministic.
+func (d *Diff) sort() {
+	sort.Slice(d.AddedNodes, func(i, j int) bool { return d.AddedNodes[i].UID < d.AddedNodes[j].UID })
+	sort.Slice(d.RemovedNodes, func(i, j int) bool { return d.RemovedNodes[i].UID < d.RemovedNodes[j].UID })
+	sort.Slice(d.ModifiedNodes, func(i, j int) bool { return d.ModifiedNodes[i].UID < d.ModifiedNodes[j].UID })
+
+	edgeLess := func(es []Edge) func(i, j int) bool {
+		return func(i, j int) bool { return edgeKey(es[i], false) < edgeKey(es[j], false) }
+	}
+	sort.Slice(d.AddedEdges, edgeLess(d.AddedEdges))
+	sort.Slice(d.RemovedEdges, edgeLess(d.RemovedEdges))
+	sort.Slice(d.ModifiedEdges, func(i, j int) bool {
+		a, b := d.ModifiedEdges[i], d.ModifiedEdges[j]
+		return a.Source+"\x00"+a.Target+"\x00"+a.UID < b.Source+"\x00"+b.Target+"\x00"+b.UID
+	})
+}
+
+// nodes returns the snapshots of all g nodes indexed by their UIDs.
+func nodes(g graph.Graph) (map[string]Node, error) {
+	res := make(map[string]Node)
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n, ok := nodes.Node().(graph.Node)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid node in graph %s", g.UID())
+		}
+
+		res[n.UID()] = Node{
+			UID:   n.UID(),
+			Label: n.Label(),
+			Attrs: n.Attrs(),
+		}
+	}
+
+	return res, nil
+}
+
+// edges returns the snapshots of all g edges indexed by their keys.
+func edges(g graph.Graph, byUID bool) (map[string]Edge, error) {
+	res := make(map[string]Edge)
+
+	edges := g.Edges()
+	for edges.Next() {
+		e, ok := edges.Edge().(graph.Edge)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid edge in graph %s", g.UID())
+		}
+
+		from, ok := e.From().(graph.Node)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid source node of edge %s", e.UID())
+		}
+
+		to, ok := e.To().(graph.Node)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid target node of edge %s", e.UID())
+		}
+
+		edge := Edge{
+			UID:    e.UID(),
+			Source: from.UID(),
+			Target: to.UID(),
+			Label:  e.Label(),
+			Weight: e.Weight(),
+			Attrs:  e.Attrs(),
+		}
+
+		res[edgeKey(edge, byUID)] = edge
+	}
+
+	return res, nil
+}
+
+// edgeKey returns the key the edge is matched by.
+func edgeKey(e Edge, byUID bool) string {
+	if byUID {
+		return e.UID
+	}
+	return e.Source + "\x00" + e.Target + "\x00" + e.Label
+}
+
+// compareAttrs returns the changes of attributes a to attributes b.
+func compareAttrs(a, b map[string]interface{}, ignore map[string]bool) []AttrChange {
+	var changes []AttrChange
+
+	for k, av := range a {
+		if ignore[k] {
+			continue
+		}
+
+		bv, ok := b[k]
+		if !ok {
+			changes = append(changes, AttrChange{Key: k, Old: av})
+			continue
+		}
+
+		if !equal(av, bv) {
+			changes = append(changes, AttrChange{Key: k, Old: av, New: bv})
+		}
+	}
+
+	for k, bv := range b {
+		if ignore[k] {
+			continue
+		}
+
+		if _, ok := a[k]; !ok {
+			changes = append(changes, AttrChange{Key: k, New: bv})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+
+	return changes
+}
+
+// equal returns true if a and b are equal.
+// Values that are not deeply equal are compared by their decoded JSON encoding
+// so graphs built from different sources, e.g. loaded from JSON, compare equal.
+func equal(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+
+	an, err := normalize(a)
+	if err != nil {
+		return false
+	}
+
+	bn, err := normalize(b)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(an, bn)
+}
+
+// normalize round-trips v through JSON.
+func normalize(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var res interface{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
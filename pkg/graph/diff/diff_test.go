@@ -0,0 +1,292 @@
+package diff
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+type testNode struct {
+	uid   string
+	label string
+	attrs map[string]interface{}
+}
+
+type testEdge struct {
+	from, to string
+	label    string
+	weight   float64
+}
+
+func MustGraph(t *testing.T, nodes []testNode, edges []testEdge) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph(memory.WithLabel("test"))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	ids := make(map[string]*memory.Node)
+	for _, tn := range nodes {
+		n, err := memory.NewNode(g.NewNode().ID(),
+			memory.WithUID(tn.uid),
+			memory.WithLabel(tn.label),
+			memory.WithAttrs(tn.attrs),
+		)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(n)
+		ids[tn.uid] = n
+	}
+
+	for _, te := range edges {
+		e, err := memory.NewEdge(ids[te.from], ids[te.to],
+			memory.WithLabel(te.label),
+			memory.WithWeight(te.weight),
+		)
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(e)
+	}
+
+	return g
+}
+
+func MustGraphs(t *testing.T) (*memory.Graph, *memory.Graph) {
+	t.Helper()
+
+	a := MustGraph(t,
+		[]testNode{
+			{uid: "repo", label: "Repo", attrs: map[string]interface{}{"stars": 1, "lang": "Go"}},
+			{uid: "topic", label: "Topic", attrs: map[string]interface{}{"name": "go"}},
+			{uid: "go", label: "Lang", attrs: map[string]interface{}{"name": "Go"}},
+		},
+		[]testEdge{
+			{from: "repo", to: "topic", label: "HasTopic", weight: 1.0},
+			{from: "repo", to: "go", label: "IsLanguage", weight: 1.0},
+		},
+	)
+
+	b := MustGraph(t,
+		[]testNode{
+			{uid: "repo", label: "Repo", attrs: map[string]interface{}{"stars": 2.0, "archived": true}},
+			{uid: "topic", label: "Topic", attrs: map[string]interface{}{"name": "go"}},
+			{uid: "rust", label: "Lang", attrs: map[string]interface{}{"name": "Rust"}},
+		},
+		[]testEdge{
+			{from: "repo", to: "topic", label: "HasTopic", weight: 2.0},
+			{from: "repo", to: "rust", label: "IsLanguage", weight: 1.0},
+		},
+	)
+
+	return a, b
+}
+
+func TestCompare(t *testing.T) {
+	a, b := MustGraphs(t)
+
+	d, err := Compare(a, b)
+	if err != nil {
+		t.Fatalf("failed to compare graphs: %v", err)
+	}
+
+	if d.Empty() {
+		t.Fatal("expected non-empty diff")
+	}
+
+	if len(d.AddedNodes) != 1 || d.AddedNodes[0].UID != "rust" {
+		t.Errorf("unexpected added nodes: %v", d.AddedNodes)
+	}
+
+	if len(d.RemovedNodes) != 1 || d.RemovedNodes[0].UID != "go" {
+		t.Errorf("unexpected removed nodes: %v", d.RemovedNodes)
+	}
+
+	expNode := []NodeChange{
+		{
+			UID: "repo",
+			Attrs: []AttrChange{
+				{Key: "archived", New: true},
+				{Key: "lang", Old: "Go"},
+				{Key: "stars", Old: 1, New: 2.0},
+			},
+		},
+	}
+	if !reflect.DeepEqual(d.ModifiedNodes, expNode) {
+		t.Errorf("expected modified nodes: %v, got: %v", expNode, d.ModifiedNodes)
+	}
+
+	if len(d.AddedEdges) != 1 || d.AddedEdges[0].Target != "rust" {
+		t.Errorf("unexpected added edges: %v", d.AddedEdges)
+	}
+
+	if len(d.RemovedEdges) != 1 || d.RemovedEdges[0].Target != "go" {
+		t.Errorf("unexpected removed edges: %v", d.RemovedEdges)
+	}
+
+	if len(d.ModifiedEdges) != 1 || !reflect.DeepEqual(d.ModifiedEdges[0].Weight, &WeightChange{Old: 1.0, New: 2.0}) {
+		t.Errorf("unexpected modified edges: %v", d.ModifiedEdges)
+	}
+}
+
+func TestCompareSame(t *testing.T) {
+	a, _ := MustGraphs(t)
+
+	d, err := Compare(a, a)
+	if err != nil {
+		t.Fatalf("failed to compare graphs: %v", err)
+	}
+
+	if !d.Empty() {
+		t.Errorf("expected empty diff, got: %#v", d)
+	}
+}
+
+func TestCompareIgnoreAttrs(t *testing.T) {
+	a, b := MustGraphs(t)
+
+	d, err := Compare(a, b, WithIgnoreAttrs("stars", "archived", "lang"))
+	if err != nil {
+		t.Fatalf("failed to compare graphs: %v", err)
+	}
+
+	if len(d.ModifiedNodes) != 0 {
+		t.Errorf("expected no modified nodes, got: %v", d.ModifiedNodes)
+	}
+}
+
+func TestCompareEdgeUIDs(t *testing.T) {
+	a, b := MustGraphs(t)
+
+	d, err := Compare(a, b, WithEdgeUIDs(true))
+	if err != nil {
+		t.Fatalf("failed to compare graphs: %v", err)
+	}
+
+	// edge UIDs are generated so no edges match
+	if len(d.AddedEdges) != 2 || len(d.RemovedEdges) != 2 || len(d.ModifiedEdges) != 0 {
+		t.Errorf("unexpected edge diff: %#v", d)
+	}
+}
+
+func TestGraph(t *testing.T) {
+	a, b := MustGraphs(t)
+
+	d, err := Compare(a, b)
+	if err != nil {
+		t.Fatalf("failed to compare graphs: %v", err)
+	}
+
+	g, err := d.Graph()
+	if err != nil {
+		t.Fatalf("failed to render diff graph: %v", err)
+	}
+
+	if count := g.Nodes().Len(); count != 4 {
+		t.Errorf("expected nodes: %d, got: %d", 4, count)
+	}
+
+	if count := g.Edges().Len(); count != 3 {
+		t.Errorf("expected edges: %d, got: %d", 3, count)
+	}
+
+	exp := map[string]Status{
+		"repo":  Modified,
+		"topic": Unchanged,
+		"go":    Removed,
+		"rust":  Added,
+	}
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n := nodes.Node().(*memory.Node)
+		if s := n.Attrs()[StatusAttr]; s != string(exp[n.UID()]) {
+			t.Errorf("node %s: expected status: %s, got: %v", n.UID(), exp[n.UID()], s)
+		}
+		if c := n.Color(); c != statusColor(exp[n.UID()]) {
+			t.Errorf("node %s: expected color: %v, got: %v", n.UID(), statusColor(exp[n.UID()]), c)
+		}
+	}
+
+	edges := g.Edges()
+	for edges.Next() {
+		e := edges.Edge().(graph.Edge)
+		if to := e.To().(graph.Node).UID(); to == "topic" && e.Attrs()[StatusAttr] != string(Modified) {
+			t.Errorf("expected edge status: %s, got: %v", Modified, e.Attrs()[StatusAttr])
+		}
+	}
+}
+
+func TestGraphEdgeLabel(t *testing.T) {
+	nodes := []testNode{
+		{uid: "repo", label: "Repo"},
+		{uid: "owner", label: "Owner"},
+	}
+
+	a := MustGraph(t, nodes, []testEdge{{from: "repo", to: "owner", label: "OwnedBy", weight: 1.0}})
+	b := MustGraph(t, nodes, []testEdge{{from: "repo", to: "owner", label: "ContributedBy", weight: 1.0}})
+
+	d, err := Compare(a, b)
+	if err != nil {
+		t.Fatalf("failed to compare graphs: %v", err)
+	}
+
+	g, err := d.Graph()
+	if err != nil {
+		t.Fatalf("failed to render diff graph: %v", err)
+	}
+
+	if typ := g.Type(); typ != graph.WeightedDirectedMulti {
+		t.Errorf("expected graph type: %s, got: %s", graph.WeightedDirectedMulti, typ)
+	}
+
+	exp := map[string]Status{
+		"OwnedBy":       Removed,
+		"ContributedBy": Added,
+	}
+
+	edges := g.Edges()
+	if count := edges.Len(); count != len(exp) {
+		t.Fatalf("expected edges: %d, got: %d", len(exp), count)
+	}
+
+	for edges.Next() {
+		e := edges.Edge().(graph.Edge)
+		if s := e.Attrs()[StatusAttr]; s != string(exp[e.Label()]) {
+			t.Errorf("edge %s: expected status: %s, got: %v", e.Label(), exp[e.Label()], s)
+		}
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	a, b := MustGraphs(t)
+
+	d, err := Compare(a, b)
+	if err != nil {
+		t.Fatalf("failed to compare graphs: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := d.WriteText(&buf); err != nil {
+		t.Fatalf("failed to write diff: %v", err)
+	}
+
+	for _, line := range []string{
+		"+ node rust [Lang]",
+		"- node go [Lang]",
+		"~ node repo",
+		"    stars: 1 -> 2",
+		"    weight: 1 -> 2",
+		"nodes: +1 -1 ~1, edges: +1 -1 ~1",
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("expected line %q in:\n%s", line, buf.String())
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package diff
+
+// Options configure graph diff.
+type Options struct {
+	// EdgeUIDs matches edges by their UIDs.
+	// By default edges are matched by the UIDs of their end nodes and their labels.
+	EdgeUIDs bool
+	// IgnoreAttrs are attributes ignored when comparing nodes and edges.
+	IgnoreAttrs []string
+}
+
+// Option is functional diff option.
+type Option func(*Options)
+
+// WithEdgeUIDs sets EdgeUIDs option.
+func WithEdgeUIDs(b bool) Option {
+	return func(o *Options) {
+		o.EdgeUIDs = b
+	}
+}
+
+// WithIgnoreAttrs sets IgnoreAttrs option.
+func WithIgnoreAttrs(keys ...string) Option {
+	return func(o *Options) {
+		o.IgnoreAttrs = keys
+	}
+}
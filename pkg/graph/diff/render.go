@@ -0,0 +1,299 @@
+package diff
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	"github.com/milosgajdos/orbnet/pkg/graph/style"
+)
+
+const (
+	// StatusAttr stores the diff status of rendered nodes and edges.
+	StatusAttr = "diff"
+)
+
+// Status is a diff status of graph node or edge.
+type Status string
+
+const (
+	// Added marks added nodes and edges.
+	Added Status = "added"
+	// Removed marks removed nodes and edges.
+	Removed Status = "removed"
+	// Modified marks modified nodes and edges.
+	Modified Status = "modified"
+	// Unchanged marks unchanged nodes and edges.
+	Unchanged Status = "unchanged"
+)
+
+var (
+	// AddedColor is the color of added nodes and edges.
+	AddedColor = color.RGBA{R: 0, G: 204, B: 102}
+	// RemovedColor is the color of removed nodes and edges.
+	RemovedColor = color.RGBA{R: 255, G: 51, B: 51}
+	// ModifiedColor is the color of modified nodes and edges.
+	ModifiedColor = color.RGBA{R: 255, G: 153, B: 0}
+	// UnchangedColor is the color of unchanged nodes and edges.
+	UnchangedColor = color.RGBA{R: 204, G: 204, B: 204}
+)
+
+// statusColor returns the color of diff status s.
+func statusColor(s Status) color.RGBA {
+	switch s {
+	case Added:
+		return AddedColor
+	case Removed:
+		return RemovedColor
+	case Modified:
+		return ModifiedColor
+	}
+	return UnchangedColor
+}
+
+// NodeStyle returns the style of nodes with the given diff status.
+func NodeStyle(s Status) style.Style {
+	st := style.DefaultNode()
+	st.Color = statusColor(s)
+	return st
+}
+
+// EdgeStyle returns the style of edges with the given diff status.
+func EdgeStyle(s Status) style.Style {
+	st := style.DefaultEdge()
+	st.Color = statusColor(s)
+	return st
+}
+
+// Graph renders the diff into a union of the compared graphs.
+// Nodes and edges are styled by their diff status and the status
+// is stored in their StatusAttr attribute. The rendered graph is
+// a multigraph with the direction of the compared graphs so that
+// the removed and added edges between the same nodes are drawn separately.
+func (d *Diff) Graph() (*memory.Graph, error) {
+	g, err := memory.NewGraph(
+		memory.WithType(renderType(d.b)),
+		memory.WithLabel(d.b.Label()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeStatus := make(map[string]Status)
+	for _, n := range d.AddedNodes {
+		nodeStatus[n.UID] = Added
+	}
+	for _, n := range d.RemovedNodes {
+		nodeStatus[n.UID] = Removed
+	}
+	for _, n := range d.ModifiedNodes {
+		nodeStatus[n.UID] = Modified
+	}
+
+	added := make(map[string]bool)
+	for _, e := range d.AddedEdges {
+		added[edgeKey(e, d.edgeUIDs)] = true
+	}
+
+	removed := make(map[string]bool)
+	for _, e := range d.RemovedEdges {
+		removed[edgeKey(e, d.edgeUIDs)] = true
+	}
+
+	// NOTE: modified edges carry the UIDs of b edges
+	modified := make(map[string]bool)
+	for _, e := range d.ModifiedEdges {
+		modified[e.UID] = true
+	}
+
+	nodes := make(map[string]*memory.Node)
+
+	// NOTE: b nodes are added first so that the rendered
+	// nodes carry the most recent labels and attributes.
+	for _, src := range []graph.Graph{d.b, d.a} {
+		it := src.Nodes()
+		for it.Next() {
+			n := it.Node().(graph.Node)
+			if _, ok := nodes[n.UID()]; ok {
+				continue
+			}
+
+			s, ok := nodeStatus[n.UID()]
+			if !ok {
+				s = Unchanged
+			}
+
+			node, err := g.AddNewNode(nodeOptions(n, s)...)
+			if err != nil {
+				return nil, err
+			}
+			nodes[node.UID()] = node
+		}
+	}
+
+	// NOTE: all b edges are rendered, a edges only if they were removed.
+	for i, src := range []graph.Graph{d.b, d.a} {
+		isA := i == 1
+
+		it := src.Edges()
+		for it.Next() {
+			e := it.Edge().(graph.Edge)
+			from := nodes[e.From().(graph.Node).UID()]
+			to := nodes[e.To().(graph.Node).UID()]
+
+			key := edgeKey(Edge{
+				UID:    e.UID(),
+				Source: from.UID(),
+				Target: to.UID(),
+				Label:  e.Label(),
+			}, d.edgeUIDs)
+
+			s := Unchanged
+			switch {
+			case isA && !removed[key]:
+				continue
+			case isA:
+				s = Removed
+			case added[key]:
+				s = Added
+			case modified[e.UID()]:
+				s = Modified
+			}
+
+			edge, err := newEdge(from, to, e, s)
+			if err != nil {
+				return nil, err
+			}
+			g.SetWeightedEdge(edge)
+		}
+	}
+
+	return g, nil
+}
+
+// renderType returns the type of the graph rendered from g.
+func renderType(g graph.Graph) string {
+	if graph.IsDirected(g.Type()) {
+		return graph.WeightedDirectedMulti
+	}
+	return graph.WeightedUndirectedMulti
+}
+
+// nodeOptions returns the options of the node rendered from n.
+func nodeOptions(n graph.Node, s Status) []memory.Option {
+	st := NodeStyle(s)
+
+	a := attrs.CopyFrom(n.Attrs())
+	a["style"] = st.Type
+	a["shape"] = st.Shape
+	a["color"] = st.Color
+	a[StatusAttr] = string(s)
+
+	opts := []memory.Option{
+		memory.WithUID(n.UID()),
+		memory.WithLabel(n.Label()),
+		memory.WithAttrs(a),
+		memory.WithStyle(st),
+	}
+
+	if dn, ok := n.(graph.DOTNode); ok {
+		opts = append(opts, memory.WithDotID(dn.DOTID()))
+	}
+
+	return opts
+}
+
+// newEdge creates a new rendered edge from e.
+func newEdge(from, to *memory.Node, e graph.Edge, s Status) (*memory.Edge, error) {
+	st := EdgeStyle(s)
+
+	a := attrs.CopyFrom(e.Attrs())
+	a["style"] = st.Type
+	a["shape"] = st.Shape
+	a["color"] = st.Color
+	a[StatusAttr] = string(s)
+
+	return memory.NewEdge(from, to,
+		memory.WithUID(e.UID()),
+		memory.WithLabel(e.Label()),
+		memory.WithWeight(e.Weight()),
+		memory.WithAttrs(a),
+		memory.WithStyle(st),
+	)
+}
+
+// WriteText writes human readable diff to w.
+func (d *Diff) WriteText(w io.Writer) error {
+	ew := &errWriter{w: w}
+
+	for _, n := range d.AddedNodes {
+		ew.printf("+ node %s [%s]\n", n.UID, n.Label)
+	}
+
+	for _, n := range d.RemovedNodes {
+		ew.printf("- node %s [%s]\n", n.UID, n.Label)
+	}
+
+	for _, n := range d.ModifiedNodes {
+		ew.printf("~ node %s\n", n.UID)
+		if n.Label != nil {
+			ew.printf("    label: %q -> %q\n", n.Label.Old, n.Label.New)
+		}
+		writeAttrChanges(ew, n.Attrs)
+	}
+
+	for _, e := range d.AddedEdges {
+		ew.printf("+ edge %s -> %s [%s]\n", e.Source, e.Target, e.Label)
+	}
+
+	for _, e := range d.RemovedEdges {
+		ew.printf("- edge %s -> %s [%s]\n", e.Source, e.Target, e.Label)
+	}
+
+	for _, e := range d.ModifiedEdges {
+		ew.printf("~ edge %s -> %s\n", e.Source, e.Target)
+		if e.Label != nil {
+			ew.printf("    label: %q -> %q\n", e.Label.Old, e.Label.New)
+		}
+		if e.Weight != nil {
+			ew.printf("    weight: %v -> %v\n", e.Weight.Old, e.Weight.New)
+		}
+		writeAttrChanges(ew, e.Attrs)
+	}
+
+	ew.printf("nodes: +%d -%d ~%d, edges: +%d -%d ~%d\n",
+		len(d.AddedNodes), len(d.RemovedNodes), len(d.ModifiedNodes),
+		len(d.AddedEdges), len(d.RemovedEdges), len(d.ModifiedEdges))
+
+	return ew.err
+}
+
+// writeAttrChanges writes attribute changes to ew.
+func writeAttrChanges(ew *errWriter, changes []AttrChange) {
+	for _, c := range changes {
+		switch {
+		case c.Old == nil:
+			ew.printf("    + %s: %v\n", c.Key, c.New)
+		case c.New == nil:
+			ew.printf("    - %s: %v\n", c.Key, c.Old)
+		default:
+			ew.printf("    %s: %v -> %v\n", c.Key, c.Old, c.New)
+		}
+	}
+}
+
+// errWriter remembers the first write error.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
@@ -0,0 +1,316 @@
+package embeddings
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+)
+
+const (
+	// Attr is the node attribute which stores node embedding.
+	Attr = "embedding"
+)
+
+// Embeddings are node vectors keyed by node UIDs.
+type Embeddings struct {
+	// Dimensions is the number of vector dimensions.
+	Dimensions int
+	// Vectors are node vectors keyed by node UIDs.
+	Vectors map[string][]float64
+}
+
+// Neighbour is a node similar to the queried node.
+type Neighbour struct {
+	// UID is node UID.
+	UID string `json:"uid"`
+	// Similarity is the cosine similarity of the node vector to the queried vector.
+	Similarity float64 `json:"similarity"`
+}
+
+// Train generates node2vec random walks over g and trains skip-gram embeddings of its nodes on them.
+// Edge directions are ignored and the walks move along the edges with the probability proportional
+// to their weights biased by P and Q options. Training is deterministic for the given Seed option.
+func Train(g graph.Graph, opts ...Option) (*Embeddings, error) {
+	eopts, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := newView(g)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Embeddings{
+		Dimensions: eopts.Dimensions,
+		Vectors:    make(map[string][]float64, len(v.nodes)),
+	}
+
+	if len(v.nodes) == 0 {
+		return e, nil
+	}
+
+	rnd := rand.New(rand.NewSource(eopts.Seed))
+
+	walks := v.walks(rnd, eopts)
+	vectors := skipGram(rnd, walks, len(v.nodes), eopts)
+
+	for i, n := range v.nodes {
+		e.Vectors[n.UID()] = vectors[i]
+	}
+
+	return e, nil
+}
+
+// Nearest returns at most k nodes with the vectors most similar to the vector of the node with the given uid.
+// The node itself is excluded and so are the nodes for which keep returns false unless keep is nil.
+func (e *Embeddings) Nearest(uid string, k int, keep func(uid string) bool) ([]Neighbour, error) {
+	vec, ok := e.Vectors[uid]
+	if !ok {
+		return nil, graph.Errorf(graph.ENOTFOUND, "embedding of node %s not found", uid)
+	}
+
+	return e.NearestTo(vec, k, func(id string) bool {
+		return id != uid && (keep == nil || keep(id))
+	})
+}
+
+// NearestTo returns at most k nodes with the vectors most similar to vec ordered by their cosine similarity.
+// The nodes for which keep returns false are excluded unless keep is nil.
+func (e *Embeddings) NearestTo(vec []float64, k int, keep func(uid string) bool) ([]Neighbour, error) {
+	if len(vec) != e.Dimensions {
+		return nil, graph.Errorf(graph.EINVALID, "invalid vector dimensions: %d", len(vec))
+	}
+
+	if k <= 0 {
+		return nil, graph.Errorf(graph.EINVALID, "invalid number of neighbours: %d", k)
+	}
+
+	neighbours := []Neighbour{}
+	for uid, v := range e.Vectors {
+		if keep != nil && !keep(uid) {
+			continue
+		}
+		neighbours = append(neighbours, Neighbour{
+			UID:        uid,
+			Similarity: cosine(vec, v),
+		})
+	}
+
+	sort.Slice(neighbours, func(i, j int) bool {
+		if neighbours[i].Similarity != neighbours[j].Similarity {
+			return neighbours[i].Similarity > neighbours[j].Similarity
+		}
+		return neighbours[i].UID < neighbours[j].UID
+	})
+
+	if len(neighbours) > k {
+		neighbours = neighbours[:k]
+	}
+
+	return neighbours, nil
+}
+
+// SetAttrs stores the vectors of the nodes of g in their Attr attribute.
+func (e *Embeddings) SetAttrs(g graph.Graph) {
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n, ok := nodes.Node().(graph.Node)
+		if !ok {
+			continue
+		}
+
+		if vec, ok := e.Vectors[n.UID()]; ok {
+			cp := make([]float64, len(vec))
+			copy(cp, vec)
+			n.Attrs()[Attr] = cp
+		}
+	}
+}
+
+// FromAttrs returns the embeddings stored in the Attr attribute of the nodes of g.
+// It returns error if the vectors are not lists of numbers of the same length.
+func FromAttrs(g graph.Graph) (*Embeddings, error) {
+	e := &Embeddings{
+		Vectors: make(map[string][]float64),
+	}
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n, ok := nodes.Node().(graph.Node)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid node in graph %s", g.UID())
+		}
+
+		v, ok := n.Attrs()[Attr]
+		if !ok || v == nil {
+			continue
+		}
+
+		vec, err := toVector(v)
+		if err != nil {
+			return nil, graph.Errorf(graph.EINVALID, "invalid %s of node %s: %v", Attr, n.UID(), err)
+		}
+
+		if err := e.add(n.UID(), vec); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// Schema returns the attribute schema of node embeddings.
+func Schema() attrs.Schema {
+	return attrs.Schema{
+		Attr: attrs.List,
+	}
+}
+
+// Write writes the embeddings to w in word2vec text format: the header line
+// with the number of vectors and their dimensions is followed by a line
+// with node UID and its vector for each node ordered by UIDs.
+// UIDs are percent-encoded so that they never contain whitespace.
+func (e *Embeddings) Write(w io.Writer) error {
+	uids := make([]string, 0, len(e.Vectors))
+	for uid := range e.Vectors {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+
+	bw := bufio.NewWriter(w)
+
+	if _, err := fmt.Fprintf(bw, "%d %d\n", len(uids), e.Dimensions); err != nil {
+		return err
+	}
+
+	for _, uid := range uids {
+		if _, err := bw.WriteString(url.PathEscape(uid)); err != nil {
+			return err
+		}
+		for _, x := range e.Vectors[uid] {
+			if _, err := bw.WriteString(" " + strconv.FormatFloat(x, 'g', -1, 64)); err != nil {
+				return err
+			}
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Read reads the embeddings written in word2vec text format from r.
+func Read(r io.Reader) (*Embeddings, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, graph.Errorf(graph.EINVALID, "missing embeddings header")
+	}
+
+	var count, dims int
+	if _, err := fmt.Sscanf(sc.Text(), "%d %d", &count, &dims); err != nil {
+		return nil, graph.Errorf(graph.EINVALID, "invalid embeddings header: %q", sc.Text())
+	}
+
+	e := &Embeddings{
+		Dimensions: dims,
+		Vectors:    make(map[string][]float64, count),
+	}
+
+	for line := 2; sc.Scan(); line++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		vec := make([]float64, len(fields)-1)
+		for i, f := range fields[1:] {
+			x, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				return nil, graph.Errorf(graph.EINVALID, "line %d: invalid value: %q", line, f)
+			}
+			vec[i] = x
+		}
+
+		uid, err := url.PathUnescape(fields[0])
+		if err != nil {
+			return nil, graph.Errorf(graph.EINVALID, "line %d: invalid node UID: %q", line, fields[0])
+		}
+
+		if err := e.add(uid, vec); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(e.Vectors) != count {
+		return nil, graph.Errorf(graph.EINVALID, "expected %d vectors, got: %d", count, len(e.Vectors))
+	}
+
+	return e, nil
+}
+
+// add adds the vector of the node with the given uid.
+// The dimensions of the first vector are used if they are not set.
+func (e *Embeddings) add(uid string, vec []float64) error {
+	if e.Dimensions == 0 {
+		e.Dimensions = len(vec)
+	}
+
+	if len(vec) != e.Dimensions || len(vec) == 0 {
+		return graph.Errorf(graph.EINVALID, "invalid dimensions of node %s vector: %d", uid, len(vec))
+	}
+
+	e.Vectors[uid] = vec
+	return nil
+}
+
+// toVector converts attribute value v to vector.
+func toVector(v interface{}) ([]float64, error) {
+	if vec, ok := v.([]float64); ok {
+		return vec, nil
+	}
+
+	l, err := attrs.Coerce(attrs.List, v)
+	if err != nil {
+		return nil, err
+	}
+
+	items := l.([]interface{})
+	vec := make([]float64, len(items))
+	for i, item := range items {
+		x, err := attrs.Coerce(attrs.Float, item)
+		if err != nil {
+			return nil, err
+		}
+		vec[i] = x.(float64)
+	}
+
+	return vec, nil
+}
+
+func cosine(a, b []float64) float64 {
+	na, nb := math.Sqrt(dot(a, a)), math.Sqrt(dot(b, b))
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot(a, b) / (na * nb)
+}
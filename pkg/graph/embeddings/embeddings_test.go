@@ -0,0 +1,221 @@
+package embeddings
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+// MustGraph returns two 5-node cliques a0-a4 and b0-b4 joined by a0 -> b0 edge.
+func MustGraph(t *testing.T) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	ns := make(map[string]*memory.Node)
+	for _, prefix := range []string{"a", "b"} {
+		for i := 0; i < 5; i++ {
+			uid := fmt.Sprintf("%s%d", prefix, i)
+			n, err := memory.NewNode(g.NewNode().ID(), memory.WithUID(uid), memory.WithLabel(prefix))
+			if err != nil {
+				t.Fatalf("failed to create node: %v", err)
+			}
+			g.AddNode(n)
+			ns[uid] = n
+		}
+	}
+
+	link := func(from, to string) {
+		e, err := memory.NewEdge(ns[from], ns[to])
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(e)
+	}
+
+	for _, prefix := range []string{"a", "b"} {
+		for i := 0; i < 5; i++ {
+			for j := i + 1; j < 5; j++ {
+				link(fmt.Sprintf("%s%d", prefix, i), fmt.Sprintf("%s%d", prefix, j))
+			}
+		}
+	}
+	link("a0", "b0")
+
+	return g
+}
+
+func TestTrain(t *testing.T) {
+	g := MustGraph(t)
+
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"DeepWalk", []Option{WithDimensions(16), WithEpochs(3)}},
+		{"Node2Vec", []Option{WithDimensions(16), WithEpochs(3), WithP(4), WithQ(0.5)}},
+	} {
+		e, err := Train(g, tc.opts...)
+		if err != nil {
+			t.Fatalf("%s: failed to train embeddings: %v", tc.name, err)
+		}
+
+		if e.Dimensions != 16 || len(e.Vectors) != 10 {
+			t.Fatalf("%s: expected 10 vectors of 16 dimensions, got: %d of %d", tc.name, len(e.Vectors), e.Dimensions)
+		}
+
+		for _, uid := range []string{"a3", "b3"} {
+			nn, err := e.Nearest(uid, 3, nil)
+			if err != nil {
+				t.Fatalf("%s: failed to find nearest nodes: %v", tc.name, err)
+			}
+
+			if len(nn) != 3 {
+				t.Fatalf("%s: expected 3 neighbours, got: %d", tc.name, len(nn))
+			}
+
+			for _, n := range nn {
+				if n.UID[0] != uid[0] {
+					t.Errorf("%s: expected %s neighbours in its clique, got: %v", tc.name, uid, nn)
+				}
+			}
+		}
+
+		again, err := Train(g, tc.opts...)
+		if err != nil {
+			t.Fatalf("%s: failed to train embeddings: %v", tc.name, err)
+		}
+
+		if !reflect.DeepEqual(e, again) {
+			t.Errorf("%s: expected deterministic embeddings", tc.name)
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	e := &Embeddings{
+		Dimensions: 2,
+		Vectors: map[string][]float64{
+			"x":  {1, 0},
+			"y":  {0, 1},
+			"xy": {1, 1},
+			"-x": {-1, 0},
+		},
+	}
+
+	nn, err := e.Nearest("x", 2, nil)
+	if err != nil {
+		t.Fatalf("failed to find nearest nodes: %v", err)
+	}
+
+	if len(nn) != 2 || nn[0].UID != "xy" || nn[1].UID != "y" {
+		t.Errorf("unexpected neighbours: %v", nn)
+	}
+
+	nn, err = e.Nearest("x", 10, func(uid string) bool { return strings.HasPrefix(uid, "-") })
+	if err != nil {
+		t.Fatalf("failed to find nearest nodes: %v", err)
+	}
+
+	if exp := []Neighbour{{UID: "-x", Similarity: -1}}; !reflect.DeepEqual(nn, exp) {
+		t.Errorf("expected neighbours: %v, got: %v", exp, nn)
+	}
+
+	if _, err := e.Nearest("foo", 1, nil); graph.ErrorCode(err) != graph.ENOTFOUND {
+		t.Errorf("expected error: %s, got: %v", graph.ENOTFOUND, err)
+	}
+
+	if _, err := e.NearestTo([]float64{1}, 1, nil); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
+
+func TestAttrs(t *testing.T) {
+	g := MustGraph(t)
+
+	e, err := Train(g, WithDimensions(4), WithWalks(2), WithWalkLength(5))
+	if err != nil {
+		t.Fatalf("failed to train embeddings: %v", err)
+	}
+	e.SetAttrs(g)
+
+	// vectors decoded from JSON are lists of interfaces
+	n := g.NodeWithUID("a1")
+	vec := n.Attrs()[Attr].([]float64)
+	n.Attrs()[Attr] = []interface{}{vec[0], vec[1], vec[2], vec[3]}
+
+	got, err := FromAttrs(g)
+	if err != nil {
+		t.Fatalf("failed to read embeddings from attributes: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, e) {
+		t.Errorf("expected embeddings: %v, got: %v", e, got)
+	}
+
+	n.Attrs()[Attr] = []interface{}{1.0}
+	if _, err := FromAttrs(g); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	e, err := Train(MustGraph(t), WithDimensions(4), WithWalks(2), WithWalkLength(5))
+	if err != nil {
+		t.Fatalf("failed to train embeddings: %v", err)
+	}
+
+	e.Vectors["vim script"] = e.Vectors["a0"]
+
+	var buf bytes.Buffer
+	if err := e.Write(&buf); err != nil {
+		t.Fatalf("failed to write embeddings: %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "11 4\na0 ") || !strings.Contains(buf.String(), "\nvim%20script ") {
+		t.Errorf("unexpected embeddings output: %q", buf.String())
+	}
+
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("failed to read embeddings: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, e) {
+		t.Errorf("expected embeddings: %v, got: %v", e, got)
+	}
+
+	for _, in := range []string{"", "foo", "2 2\nx 1 2\n", "1 2\nx 1\n", "1 2\nx 1 foo\n", "1 1\n%zz 1\n"} {
+		if _, err := Read(strings.NewReader(in)); graph.ErrorCode(err) != graph.EINVALID {
+			t.Errorf("%q: expected error: %s, got: %v", in, graph.EINVALID, err)
+		}
+	}
+}
+
+func TestTrainErrors(t *testing.T) {
+	g := MustGraph(t)
+
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{"Dimensions", []Option{WithDimensions(0)}},
+		{"WalkLength", []Option{WithWalkLength(1)}},
+		{"P", []Option{WithP(0)}},
+		{"Window", []Option{WithWindow(0)}},
+	}
+
+	for _, tc := range testCases {
+		if _, err := Train(g, tc.opts...); graph.ErrorCode(err) != graph.EINVALID {
+			t.Errorf("%s: expected error: %s, got: %v", tc.name, graph.EINVALID, err)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package embeddings
+
+import "github.com/milosgajdos/orbnet/pkg/graph"
+
+const (
+	// DefaultDimensions is the default number of embedding dimensions.
+	DefaultDimensions = 64
+	// DefaultWalks is the default number of random walks started from each node.
+	DefaultWalks = 10
+	// DefaultWalkLength is the default number of nodes of each random walk.
+	DefaultWalkLength = 40
+	// DefaultWindow is the default skip-gram context window size.
+	DefaultWindow = 5
+	// DefaultNegative is the default number of skip-gram negative samples.
+	DefaultNegative = 5
+	// DefaultEpochs is the default number of skip-gram training epochs.
+	DefaultEpochs = 1
+	// DefaultLearningRate is the default initial skip-gram learning rate.
+	DefaultLearningRate = 0.025
+	// DefaultSeed is the default random seed.
+	DefaultSeed = 1
+)
+
+// Options configure node embeddings.
+type Options struct {
+	// Dimensions is the number of embedding dimensions.
+	Dimensions int
+	// Walks is the number of random walks started from each node.
+	Walks int
+	// WalkLength is the number of nodes of each random walk.
+	WalkLength int
+	// P is node2vec return parameter: the higher it is
+	// the less likely walks return to the previous node.
+	P float64
+	// Q is node2vec in-out parameter: walks with Q > 1 stay close
+	// to the previous node while walks with Q < 1 move away from it.
+	// Walks with P = Q = 1 are DeepWalk walks.
+	Q float64
+	// Window is skip-gram context window size.
+	Window int
+	// Negative is the number of skip-gram negative samples.
+	Negative int
+	// Epochs is the number of skip-gram training epochs.
+	Epochs int
+	// LearningRate is the initial skip-gram learning rate.
+	LearningRate float64
+	// Seed is random seed.
+	Seed int64
+}
+
+// Option is functional embeddings option.
+type Option func(*Options)
+
+// WithDimensions sets Dimensions option.
+func WithDimensions(d int) Option {
+	return func(o *Options) {
+		o.Dimensions = d
+	}
+}
+
+// WithWalks sets Walks option.
+func WithWalks(n int) Option {
+	return func(o *Options) {
+		o.Walks = n
+	}
+}
+
+// WithWalkLength sets WalkLength option.
+func WithWalkLength(n int) Option {
+	return func(o *Options) {
+		o.WalkLength = n
+	}
+}
+
+// WithP sets P option.
+func WithP(p float64) Option {
+	return func(o *Options) {
+		o.P = p
+	}
+}
+
+// WithQ sets Q option.
+func WithQ(q float64) Option {
+	return func(o *Options) {
+		o.Q = q
+	}
+}
+
+// WithWindow sets Window option.
+func WithWindow(n int) Option {
+	return func(o *Options) {
+		o.Window = n
+	}
+}
+
+// WithNegative sets Negative option.
+func WithNegative(n int) Option {
+	return func(o *Options) {
+		o.Negative = n
+	}
+}
+
+// WithEpochs sets Epochs option.
+func WithEpochs(n int) Option {
+	return func(o *Options) {
+		o.Epochs = n
+	}
+}
+
+// WithLearningRate sets LearningRate option.
+func WithLearningRate(r float64) Option {
+	return func(o *Options) {
+		o.LearningRate = r
+	}
+}
+
+// WithSeed sets Seed option.
+func WithSeed(seed int64) Option {
+	return func(o *Options) {
+		o.Seed = seed
+	}
+}
+
+func newOptions(opts ...Option) (Options, error) {
+	eopts := Options{
+		Dimensions:   DefaultDimensions,
+		Walks:        DefaultWalks,
+		WalkLength:   DefaultWalkLength,
+		P:            1,
+		Q:            1,
+		Window:       DefaultWindow,
+		Negative:     DefaultNegative,
+		Epochs:       DefaultEpochs,
+		LearningRate: DefaultLearningRate,
+		Seed:         DefaultSeed,
+	}
+
+	for _, apply := range opts {
+		apply(&eopts)
+	}
+
+	if eopts.Dimensions <= 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid dimensions: %d", eopts.Dimensions)
+	}
+
+	if eopts.Walks <= 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid number of walks: %d", eopts.Walks)
+	}
+
+	if eopts.WalkLength < 2 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid walk length: %d", eopts.WalkLength)
+	}
+
+	if eopts.P <= 0 || eopts.Q <= 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid p, q: %f, %f", eopts.P, eopts.Q)
+	}
+
+	if eopts.Window <= 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid window: %d", eopts.Window)
+	}
+
+	if eopts.Negative < 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid number of negative samples: %d", eopts.Negative)
+	}
+
+	if eopts.Epochs <= 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid number of epochs: %d", eopts.Epochs)
+	}
+
+	if eopts.LearningRate <= 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid learning rate: %f", eopts.LearningRate)
+	}
+
+	return eopts, nil
+}
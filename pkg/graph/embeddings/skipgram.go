@@ -0,0 +1,138 @@
+package embeddings
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	// maxExp clips the arguments of the sigmoid function.
+	maxExp = 6
+	// minLearningRate is the fraction of the initial learning rate it decays to.
+	minLearningRate = 1e-4
+	// unigramPower smooths negative sampling distribution.
+	unigramPower = 0.75
+)
+
+// skipGram trains skip-gram with negative sampling on the walks of n nodes
+// and returns the input vectors of the nodes.
+func skipGram(rnd *rand.Rand, walks [][]int, n int, opts Options) [][]float64 {
+	dim := opts.Dimensions
+
+	in := make([][]float64, n)
+	out := make([][]float64, n)
+	for i := range in {
+		in[i] = make([]float64, dim)
+		out[i] = make([]float64, dim)
+		for d := range in[i] {
+			in[i][d] = (rnd.Float64() - 0.5) / float64(dim)
+		}
+	}
+
+	noise := newNoise(walks, n)
+
+	var total int
+	for _, w := range walks {
+		total += len(w)
+	}
+	total *= opts.Epochs
+
+	grad := make([]float64, dim)
+
+	var seen int
+	for epoch := 0; epoch < opts.Epochs; epoch++ {
+		for _, walk := range walks {
+			for i, center := range walk {
+				rate := opts.LearningRate * math.Max(minLearningRate, 1-float64(seen)/float64(total))
+				seen++
+
+				// NOTE: the context window is shrunk randomly which weighs closer nodes more
+				window := 1 + rnd.Intn(opts.Window)
+				for j := i - window; j <= i+window; j++ {
+					if j < 0 || j >= len(walk) || j == i {
+						continue
+					}
+
+					ctx := in[walk[j]]
+					for d := range grad {
+						grad[d] = 0
+					}
+
+					for s := 0; s <= opts.Negative; s++ {
+						target, label := center, 1.0
+						if s > 0 {
+							target, label = noise.sample(rnd), 0
+							if target == center {
+								continue
+							}
+						}
+
+						g := (label - sigmoid(dot(ctx, out[target]))) * rate
+						for d := range grad {
+							grad[d] += g * out[target][d]
+							out[target][d] += g * ctx[d]
+						}
+					}
+
+					for d := range ctx {
+						ctx[d] += grad[d]
+					}
+				}
+			}
+		}
+	}
+
+	return in
+}
+
+// noise samples negative nodes with the probability proportional
+// to their frequency in the walks raised to unigramPower.
+type noise struct {
+	cum []float64
+}
+
+func newNoise(walks [][]int, n int) *noise {
+	freqs := make([]float64, n)
+	for _, w := range walks {
+		for _, id := range w {
+			freqs[id]++
+		}
+	}
+
+	cum := make([]float64, n)
+	var total float64
+	for i, f := range freqs {
+		total += math.Pow(f, unigramPower)
+		cum[i] = total
+	}
+
+	return &noise{cum: cum}
+}
+
+func (n *noise) sample(rnd *rand.Rand) int {
+	r := rnd.Float64() * n.cum[len(n.cum)-1]
+	k := sort.Search(len(n.cum), func(i int) bool { return n.cum[i] > r })
+	if k == len(n.cum) {
+		k--
+	}
+	return k
+}
+
+func sigmoid(x float64) float64 {
+	switch {
+	case x > maxExp:
+		return 1
+	case x < -maxExp:
+		return 0
+	}
+	return 1 / (1 + math.Exp(-x))
+}
+
+func dot(a, b []float64) float64 {
+	var s float64
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
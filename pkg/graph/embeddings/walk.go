@@ -0,0 +1,160 @@
+package embeddings
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	gonum "gonum.org/v1/gonum/graph"
+)
+
+// view is an undirected weighted view of graph with nodes indexed by their UID order.
+type view struct {
+	// nodes are graph nodes sorted by their UIDs.
+	nodes []graph.Node
+	// adj stores index sorted neighbours of all nodes.
+	adj [][]int
+	// weights stores summed weights of the edges to the neighbours in adj.
+	weights [][]float64
+}
+
+func newView(g graph.Graph) (*view, error) {
+	v := &view{}
+
+	iter := g.Nodes()
+	for iter.Next() {
+		n, ok := iter.Node().(graph.Node)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid node in graph %s", g.UID())
+		}
+		v.nodes = append(v.nodes, n)
+	}
+	sort.Slice(v.nodes, func(i, j int) bool { return v.nodes[i].UID() < v.nodes[j].UID() })
+
+	index := make(map[int64]int, len(v.nodes))
+	for i, n := range v.nodes {
+		index[n.ID()] = i
+	}
+
+	links := make([]map[int]float64, len(v.nodes))
+	link := func(from, to int, w float64) {
+		if links[from] == nil {
+			links[from] = make(map[int]float64)
+		}
+		links[from][to] += w
+	}
+
+	edges := g.Edges()
+	for edges.Next() {
+		e := edges.Edge()
+
+		w := 1.0
+		if we, ok := e.(gonum.WeightedEdge); ok {
+			w = we.Weight()
+		}
+
+		if w < 0 {
+			return nil, graph.Errorf(graph.EINVALID, "negative edge weight: %f", w)
+		}
+
+		from, to := index[e.From().ID()], index[e.To().ID()]
+		link(from, to, w)
+		if from != to {
+			link(to, from, w)
+		}
+	}
+
+	v.adj = make([][]int, len(v.nodes))
+	v.weights = make([][]float64, len(v.nodes))
+
+	for i, nbrs := range links {
+		for j := range nbrs {
+			v.adj[i] = append(v.adj[i], j)
+		}
+		sort.Ints(v.adj[i])
+
+		v.weights[i] = make([]float64, len(v.adj[i]))
+		for k, j := range v.adj[i] {
+			v.weights[i][k] = nbrs[j]
+		}
+	}
+
+	return v, nil
+}
+
+// isNeighbour returns true if j is a neighbour of i.
+func (v *view) isNeighbour(i, j int) bool {
+	nbrs := v.adj[i]
+	k := sort.SearchInts(nbrs, j)
+	return k < len(nbrs) && nbrs[k] == j
+}
+
+// walks returns opts.Walks biased random walks started from each node.
+// Walks are cut short at the nodes without neighbours.
+func (v *view) walks(rnd *rand.Rand, opts Options) [][]int {
+	walks := make([][]int, 0, opts.Walks*len(v.nodes))
+
+	order := make([]int, len(v.nodes))
+	for i := range order {
+		order[i] = i
+	}
+
+	for w := 0; w < opts.Walks; w++ {
+		rnd.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+		for _, start := range order {
+			walks = append(walks, v.walk(rnd, start, opts))
+		}
+	}
+
+	return walks
+}
+
+// walk returns node2vec random walk started from the given node.
+// The next node x after the current node c reached from the previous node p
+// is sampled with the probability proportional to the weight of the edge
+// between c and x multiplied by 1/P if x is p, 1 if x is a neighbour of p and 1/Q otherwise.
+func (v *view) walk(rnd *rand.Rand, start int, opts Options) []int {
+	walk := make([]int, 1, opts.WalkLength)
+	walk[0] = start
+
+	probs := make([]float64, 0)
+
+	for len(walk) < opts.WalkLength {
+		cur := walk[len(walk)-1]
+		nbrs := v.adj[cur]
+		if len(nbrs) == 0 {
+			break
+		}
+
+		probs = probs[:0]
+		var total float64
+		for k, x := range nbrs {
+			p := v.weights[cur][k]
+			if len(walk) > 1 {
+				prev := walk[len(walk)-2]
+				switch {
+				case x == prev:
+					p /= opts.P
+				case !v.isNeighbour(prev, x):
+					p /= opts.Q
+				}
+			}
+			total += p
+			probs = append(probs, total)
+		}
+
+		if total == 0 {
+			break
+		}
+
+		r := rnd.Float64() * total
+		k := sort.Search(len(probs), func(i int) bool { return probs[i] > r })
+		if k == len(probs) {
+			k--
+		}
+		walk = append(walk, nbrs[k])
+	}
+
+	return walk
+}
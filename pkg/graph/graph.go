@@ -3,16 +3,50 @@ package graph
 import (
 	"context"
 	"image/color"
+	"io"
 
 	"gonum.org/v1/gonum/graph"
 	"gonum.org/v1/gonum/graph/encoding"
 )
 
+const (
+	// WeightedDirected is a weighted directed graph type.
+	WeightedDirected = "weighted_directed"
+	// WeightedUndirected is a weighted undirected graph type.
+	WeightedUndirected = "weighted_undirected"
+	// WeightedDirectedMulti is a weighted directed multigraph type.
+	WeightedDirectedMulti = "weighted_directed_multi"
+	// WeightedUndirectedMulti is a weighted undirected multigraph type.
+	WeightedUndirectedMulti = "weighted_undirected_multi"
+)
+
+// IsValidType returns true if typ is a supported graph type.
+func IsValidType(typ string) bool {
+	switch typ {
+	case WeightedDirected, WeightedUndirected, WeightedDirectedMulti, WeightedUndirectedMulti:
+		return true
+	}
+	return false
+}
+
+// IsDirected returns true if typ is a directed graph type.
+func IsDirected(typ string) bool {
+	return typ == WeightedDirected || typ == WeightedDirectedMulti
+}
+
+// IsMulti returns true if typ is a multigraph type.
+// Multigraphs allow parallel edges between the same pair of nodes.
+func IsMulti(typ string) bool {
+	return typ == WeightedDirectedMulti || typ == WeightedUndirectedMulti
+}
+
 // Graph is weighted graph.
 type Graph interface {
 	graph.Weighted
 	// UID returns graph UID.
 	UID() string
+	// Type returns graph type.
+	Type() string
 	// Edges returns graph edges iterator.
 	Edges() graph.Edges
 	// Label returns graph label.
@@ -124,6 +158,12 @@ type Marshaler interface {
 	Marshal(g Graph) ([]byte, error)
 }
 
+// StreamMarshaler is used for marshaling graphs into writers.
+type StreamMarshaler interface {
+	// MarshalTo marshals graph into w.
+	MarshalTo(w io.Writer, g Graph) error
+}
+
 // Unmarshaler is used for unmarshaling graphs.
 type Unmarshaler interface {
 	// Unmarshal unmarshals arbitrary bytes into graph.
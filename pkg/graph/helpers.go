@@ -0,0 +1,26 @@
+package graph
+
+import "sort"
+
+// nameKeys are node attributes which store node names in the order of precedence.
+var nameKeys = []string{"full_name", "name"}
+
+// NodeName returns the full name of node n, its name or UID.
+func NodeName(n Node) string {
+	for _, k := range nameKeys {
+		if name, ok := n.Attrs()[k].(string); ok && name != "" {
+			return name
+		}
+	}
+	return n.UID()
+}
+
+// SortedIDs returns the node IDs in set sorted in ascending order.
+func SortedIDs(set map[int64]bool) []int64 {
+	ids := make([]int64, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,39 @@
+package graph_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+func TestNodeName(t *testing.T) {
+	testCases := []struct {
+		attrs map[string]interface{}
+		exp   string
+	}{
+		{map[string]interface{}{"full_name": "foo/bar", "name": "bar"}, "foo/bar"},
+		{map[string]interface{}{"full_name": "", "name": "bar"}, "bar"},
+		{map[string]interface{}{"name": 1}, "uid"},
+		{nil, "uid"},
+	}
+
+	for _, tc := range testCases {
+		n, err := memory.NewNode(1, memory.WithUID("uid"), memory.WithAttrs(tc.attrs))
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+
+		if name := graph.NodeName(n); name != tc.exp {
+			t.Errorf("expected name: %s, got: %s", tc.exp, name)
+		}
+	}
+}
+
+func TestSortedIDs(t *testing.T) {
+	ids := graph.SortedIDs(map[int64]bool{3: true, 1: true, 2: true})
+	if exp := []int64{1, 2, 3}; !reflect.DeepEqual(ids, exp) {
+		t.Errorf("expected ids: %v, got: %v", exp, ids)
+	}
+}
@@ -0,0 +1,165 @@
+// Package jsonstream encodes JSON objects whose array fields are written
+// element by element so that large graphs don't have to be held in memory.
+// The output is the same as the output of json.MarshalIndent.
+package jsonstream
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// Encoder encodes JSON object into writer.
+type Encoder struct {
+	w      *bufio.Writer
+	prefix string
+	indent string
+	fields int
+	elems  int
+	err    error
+}
+
+// NewEncoder creates a new Encoder which writes JSON object to w
+// and indents it with the given prefix and indent.
+func NewEncoder(w io.Writer, prefix, indent string) *Encoder {
+	return &Encoder{
+		w:      bufio.NewWriter(w),
+		prefix: prefix,
+		indent: indent,
+	}
+}
+
+// Field encodes object field with the given name and value v.
+func (e *Encoder) Field(name string, v interface{}) error {
+	if err := e.key(name); err != nil {
+		return err
+	}
+	return e.value(v, 1)
+}
+
+// BeginArray begins array field with the given name.
+// The array elements are encoded with Elem.
+func (e *Encoder) BeginArray(name string) error {
+	if err := e.key(name); err != nil {
+		return err
+	}
+	e.elems = 0
+	return e.write("[")
+}
+
+// Elem encodes element v of the array field.
+func (e *Encoder) Elem(v interface{}) error {
+	sep := ","
+	if e.elems == 0 {
+		sep = ""
+	}
+	e.elems++
+
+	if err := e.write(sep); err != nil {
+		return err
+	}
+
+	if err := e.newline(2); err != nil {
+		return err
+	}
+
+	return e.value(v, 2)
+}
+
+// EndArray ends the array field.
+func (e *Encoder) EndArray() error {
+	if e.elems > 0 {
+		if err := e.newline(1); err != nil {
+			return err
+		}
+	}
+	return e.write("]")
+}
+
+// Close ends the object and flushes it into the underlying writer.
+func (e *Encoder) Close() error {
+	if e.fields == 0 {
+		if err := e.write("{"); err != nil {
+			return err
+		}
+	} else if err := e.newline(0); err != nil {
+		return err
+	}
+
+	if err := e.write("}"); err != nil {
+		return err
+	}
+
+	return e.w.Flush()
+}
+
+// key writes the key of object field with the given name.
+func (e *Encoder) key(name string) error {
+	sep := ","
+	if e.fields == 0 {
+		sep = "{"
+	}
+	e.fields++
+
+	if err := e.write(sep); err != nil {
+		return err
+	}
+
+	if err := e.newline(1); err != nil {
+		return err
+	}
+
+	k, err := json.Marshal(name)
+	if err != nil {
+		return e.fail(err)
+	}
+
+	if err := e.write(string(k)); err != nil {
+		return err
+	}
+
+	return e.write(": ")
+}
+
+// value writes v indented at the given depth.
+func (e *Encoder) value(v interface{}, depth int) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	b, err := json.MarshalIndent(v, e.prefix+strings.Repeat(e.indent, depth), e.indent)
+	if err != nil {
+		return e.fail(err)
+	}
+
+	if _, err := e.w.Write(b); err != nil {
+		return e.fail(err)
+	}
+
+	return nil
+}
+
+// newline writes a new line indented at the given depth.
+func (e *Encoder) newline(depth int) error {
+	return e.write("\n" + e.prefix + strings.Repeat(e.indent, depth))
+}
+
+// write writes s unless encoding has failed.
+func (e *Encoder) write(s string) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	if _, err := e.w.WriteString(s); err != nil {
+		return e.fail(err)
+	}
+
+	return nil
+}
+
+// fail records error err which is returned by all subsequent calls.
+func (e *Encoder) fail(err error) error {
+	e.err = err
+	return err
+}
@@ -0,0 +1,111 @@
+package jsonstream
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type elem struct {
+	ID    string                 `json:"id"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+type object struct {
+	Directed bool   `json:"directed"`
+	Elems    []elem `json:"elems"`
+	Empty    []elem `json:"empty"`
+}
+
+func TestEncoder(t *testing.T) {
+	obj := object{
+		Directed: true,
+		Elems: []elem{
+			{ID: "a<b>", Attrs: map[string]interface{}{"list": []int{1, 2}}},
+			{ID: "c"},
+		},
+		Empty: []elem{},
+	}
+
+	testCases := []struct {
+		prefix string
+		indent string
+	}{
+		{"", "\t"},
+		{">", "  "},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		exp, err := json.MarshalIndent(obj, tc.prefix, tc.indent)
+		if err != nil {
+			t.Fatalf("failed to marshal object: %v", err)
+		}
+
+		var b bytes.Buffer
+		enc := NewEncoder(&b, tc.prefix, tc.indent)
+
+		if err := enc.Field("directed", obj.Directed); err != nil {
+			t.Fatalf("failed to encode field: %v", err)
+		}
+
+		for _, name := range []string{"elems", "empty"} {
+			elems := obj.Elems
+			if name == "empty" {
+				elems = obj.Empty
+			}
+
+			if err := enc.BeginArray(name); err != nil {
+				t.Fatalf("failed to begin array: %v", err)
+			}
+
+			for _, e := range elems {
+				if err := enc.Elem(e); err != nil {
+					t.Fatalf("failed to encode element: %v", err)
+				}
+			}
+
+			if err := enc.EndArray(); err != nil {
+				t.Fatalf("failed to end array: %v", err)
+			}
+		}
+
+		if err := enc.Close(); err != nil {
+			t.Fatalf("failed to close encoder: %v", err)
+		}
+
+		if b.String() != string(exp) {
+			t.Errorf("prefix %q indent %q: expected:\n%s\ngot:\n%s", tc.prefix, tc.indent, exp, b.String())
+		}
+	}
+}
+
+func TestEncoderEmpty(t *testing.T) {
+	var b bytes.Buffer
+	if err := NewEncoder(&b, "", "\t").Close(); err != nil {
+		t.Fatalf("failed to close encoder: %v", err)
+	}
+
+	if b.String() != "{}" {
+		t.Errorf("expected: {}, got: %s", b.String())
+	}
+}
+
+func TestEncoderError(t *testing.T) {
+	var b bytes.Buffer
+	enc := NewEncoder(&b, "", "\t")
+
+	if err := enc.Field("bad", func() {}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if err := enc.Close(); err == nil {
+		t.Fatal("expected sticky error")
+	}
+
+	var ute *json.UnsupportedTypeError
+	if err := enc.Field("ok", 1); !errors.As(err, &ute) {
+		t.Errorf("expected unsupported type error, got: %v", err)
+	}
+}
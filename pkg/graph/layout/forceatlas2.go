@@ -0,0 +1,188 @@
+package layout
+
+import "math"
+
+const (
+	// theta is Barnes-Hut approximation threshold: the higher
+	// it is the faster and the less accurate the simulation is.
+	theta = 1.2
+	// maxDepth limits the depth of Barnes-Hut quadtree.
+	maxDepth = 32
+	// maxSpeedRise limits the rise of global speed per iteration.
+	maxSpeedRise = 0.5
+)
+
+// forceAtlas2 lays out the nodes of v using ForceAtlas2 algorithm: the nodes repel each other
+// proportionally to the product of their degrees, the linked nodes attract each other linearly
+// and gravity pulls the nodes to the centre proportionally to their distance from it so that
+// disconnected components stay close. The speed of every node adapts to its swinging.
+func forceAtlas2(v *view, pos []Point, opts Options) {
+	n := len(pos)
+	if n == 0 {
+		return
+	}
+
+	mass := make([]float64, n)
+	for i := range mass {
+		mass[i] = v.degree[i] + 1
+	}
+
+	forces := make([]Point, n)
+	prev := make([]Point, n)
+	speed := 1.0
+
+	for it := 0; it < opts.Iterations; it++ {
+		copy(prev, forces)
+		for i := range forces {
+			forces[i] = Point{}
+		}
+
+		tree := newQuadTree(pos, mass)
+		for i := range pos {
+			tree.repulse(i, pos, mass, opts.Scaling, &forces[i])
+		}
+
+		for _, l := range v.links {
+			dx, dy := pos[l.u].X-pos[l.v].X, pos[l.u].Y-pos[l.v].Y
+			forces[l.u].X -= dx * l.w
+			forces[l.u].Y -= dy * l.w
+			forces[l.v].X += dx * l.w
+			forces[l.v].Y += dy * l.w
+		}
+
+		strongGravity(pos, mass, forces, opts.Gravity)
+
+		var swinging, traction float64
+		for i := range forces {
+			swinging += mass[i] * math.Hypot(forces[i].X-prev[i].X, forces[i].Y-prev[i].Y)
+			traction += mass[i] * math.Hypot(forces[i].X+prev[i].X, forces[i].Y+prev[i].Y) / 2
+		}
+
+		if swinging > 0 {
+			target := traction / swinging
+			speed += math.Min(target-speed, maxSpeedRise*speed)
+		}
+
+		for i := range pos {
+			swing := mass[i] * math.Hypot(forces[i].X-prev[i].X, forces[i].Y-prev[i].Y)
+			factor := speed / (1 + math.Sqrt(speed*swing))
+			pos[i].X += forces[i].X * factor
+			pos[i].Y += forces[i].Y * factor
+		}
+	}
+}
+
+// quadTree is Barnes-Hut quadtree of nodes.
+type quadTree struct {
+	x, y, size float64
+	mass       float64
+	cx, cy     float64
+	children   [4]*quadTree
+	bodies     []int
+	leaf       bool
+}
+
+// newQuadTree returns the quadtree of the nodes at positions pos with the given mass.
+func newQuadTree(pos []Point, mass []float64) *quadTree {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range pos {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+
+	size := math.Max(maxX-minX, maxY-minY) + 1
+	root := &quadTree{x: minX, y: minY, size: size, leaf: true}
+	for i := range pos {
+		root.insert(i, pos, mass, 0)
+	}
+
+	return root
+}
+
+// insert inserts node i to the tree.
+func (q *quadTree) insert(i int, pos []Point, mass []float64, depth int) {
+	p := pos[i]
+	q.cx = (q.cx*q.mass + p.X*mass[i]) / (q.mass + mass[i])
+	q.cy = (q.cy*q.mass + p.Y*mass[i]) / (q.mass + mass[i])
+	q.mass += mass[i]
+
+	if q.leaf {
+		if len(q.bodies) == 0 || depth >= maxDepth {
+			q.bodies = append(q.bodies, i)
+			return
+		}
+
+		q.leaf = false
+		bodies := q.bodies
+		q.bodies = nil
+		for _, b := range bodies {
+			q.child(pos[b]).insert(b, pos, mass, depth+1)
+		}
+	}
+
+	q.child(p).insert(i, pos, mass, depth+1)
+}
+
+// child returns the child quadrant which contains p.
+func (q *quadTree) child(p Point) *quadTree {
+	half := q.size / 2
+
+	idx, x, y := 0, q.x, q.y
+	if p.X >= q.x+half {
+		idx, x = idx+1, x+half
+	}
+	if p.Y >= q.y+half {
+		idx, y = idx+2, y+half
+	}
+
+	if q.children[idx] == nil {
+		q.children[idx] = &quadTree{x: x, y: y, size: half, leaf: true}
+	}
+
+	return q.children[idx]
+}
+
+// repulse adds the repulsion of the nodes in the tree to the force f of node i.
+func (q *quadTree) repulse(i int, pos []Point, mass []float64, kr float64, f *Point) {
+	p := pos[i]
+
+	if q.leaf {
+		for _, j := range q.bodies {
+			if i == j {
+				continue
+			}
+			dx, dy, d := delta(p, pos[j], i, j)
+			s := kr * mass[i] * mass[j] / (d * d)
+			f.X += dx * s
+			f.Y += dy * s
+		}
+		return
+	}
+
+	inside := p.X >= q.x && p.X < q.x+q.size && p.Y >= q.y && p.Y < q.y+q.size
+
+	dx, dy := p.X-q.cx, p.Y-q.cy
+	d := math.Hypot(dx, dy)
+	if !inside && d > 0 && q.size/d < theta {
+		s := kr * mass[i] * q.mass / (d * d)
+		f.X += dx * s
+		f.Y += dy * s
+		return
+	}
+
+	for _, c := range q.children {
+		if c != nil {
+			c.repulse(i, pos, mass, kr, f)
+		}
+	}
+}
+
+// strongGravity adds the gravity to the forces f of the nodes at positions pos.
+// The gravity pulls the nodes to the centre proportionally to their mass and distance.
+func strongGravity(pos []Point, mass []float64, f []Point, g float64) {
+	for i, p := range pos {
+		f[i].X -= p.X * g * mass[i]
+		f[i].Y -= p.Y * g * mass[i]
+	}
+}
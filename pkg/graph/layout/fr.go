@@ -0,0 +1,117 @@
+package layout
+
+import "math"
+
+// fruchtermanReingold lays out the nodes of v using the grid variant of Fruchterman-Reingold
+// algorithm: the nodes repel the nodes in their neighbouring grid cells whose size is twice the
+// ideal edge length, the linked nodes attract each other and gravity pulls the nodes to the centre.
+// The displacement of nodes is limited by the temperature which cools down linearly.
+func fruchtermanReingold(v *view, pos []Point, opts Options) {
+	n := len(pos)
+	if n == 0 {
+		return
+	}
+
+	k := opts.Scaling
+	cell := 2 * k
+	t0 := k * math.Sqrt(float64(n)) / 10
+
+	disp := make([]Point, n)
+
+	for it := 0; it < opts.Iterations; it++ {
+		for i := range disp {
+			disp[i] = Point{}
+		}
+
+		grid := make(map[[2]int][]int)
+		for i, p := range pos {
+			c := [2]int{int(math.Floor(p.X / cell)), int(math.Floor(p.Y / cell))}
+			grid[c] = append(grid[c], i)
+		}
+
+		for c, members := range grid {
+			for dx := -1; dx <= 1; dx++ {
+				for dy := -1; dy <= 1; dy++ {
+					for _, j := range grid[[2]int{c[0] + dx, c[1] + dy}] {
+						for _, i := range members {
+							if i == j {
+								continue
+							}
+							ddx, ddy, d := delta(pos[i], pos[j], i, j)
+							if d > cell {
+								continue
+							}
+							f := k * k / d
+							disp[i].X += ddx / d * f
+							disp[i].Y += ddy / d * f
+						}
+					}
+				}
+			}
+		}
+
+		for _, l := range v.links {
+			ddx, ddy, d := delta(pos[l.u], pos[l.v], l.u, l.v)
+			f := d * d / k * l.w
+			disp[l.u].X -= ddx / d * f
+			disp[l.u].Y -= ddy / d * f
+			disp[l.v].X += ddx / d * f
+			disp[l.v].Y += ddy / d * f
+		}
+
+		gravity(v, pos, disp, opts.Gravity)
+
+		t := t0 * (1 - float64(it)/float64(opts.Iterations))
+		for i := range pos {
+			d := math.Hypot(disp[i].X, disp[i].Y)
+			if d == 0 {
+				continue
+			}
+			s := math.Min(d, t) / d
+			pos[i].X += disp[i].X * s
+			pos[i].Y += disp[i].Y * s
+		}
+	}
+}
+
+// delta returns the vector from q to p and its length.
+// Coincident nodes with indices i and j are separated deterministically.
+func delta(p, q Point, i, j int) (float64, float64, float64) {
+	dx, dy := p.X-q.X, p.Y-q.Y
+	d := math.Hypot(dx, dy)
+	if d > 0 {
+		return dx, dy, d
+	}
+
+	lo, hi := i, j
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+
+	a := float64(lo*31+hi) * 0.618
+	dx, dy = 0.01*math.Cos(a), 0.01*math.Sin(a)
+	if i < j {
+		dx, dy = -dx, -dy
+	}
+
+	return dx, dy, 0.01
+}
+
+// gravity adds the gravity to the forces f of the nodes at positions pos.
+// The gravity pulls the nodes to the centre with the strength
+// proportional to their degree regardless of their distance.
+func gravity(v *view, pos, f []Point, g float64) {
+	if g == 0 {
+		return
+	}
+
+	for i, p := range pos {
+		d := math.Hypot(p.X, p.Y)
+		if d == 0 {
+			continue
+		}
+		s := g * (v.degree[i] + 1) / d
+		f[i].X -= p.X * s
+		f[i].Y -= p.Y * s
+	}
+}
@@ -0,0 +1,170 @@
+// Package layout computes force-directed layouts of graphs.
+package layout
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+)
+
+// Algorithm is a layout algorithm.
+type Algorithm string
+
+const (
+	// FruchtermanReingold is Fruchterman-Reingold layout.
+	// Only the nodes closer than twice the ideal edge length repel each other.
+	FruchtermanReingold Algorithm = "fr"
+	// ForceAtlas2 is ForceAtlas2 layout.
+	// The repulsion is approximated by Barnes-Hut simulation.
+	ForceAtlas2 Algorithm = "forceatlas2"
+)
+
+const (
+	// XAttr is the name of node x coordinate attribute.
+	XAttr = "x"
+	// YAttr is the name of node y coordinate attribute.
+	YAttr = "y"
+)
+
+// Point is node position.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Positions maps node IDs to their positions.
+type Positions map[int64]Point
+
+// Layout lays out the nodes of g using the given algorithm.
+// Edge directions are ignored and the edges between the same nodes are merged
+// into a single edge whose weight is the sum of their weights.
+// The results are deterministic for the given Seed option.
+// If Attrs option is set the positions are written to node x and y attributes.
+func Layout(g graph.Graph, algo Algorithm, opts ...Option) (Positions, error) {
+	lopts, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	v := newView(g)
+
+	pos := initial(len(v.nodes), lopts)
+
+	switch algo {
+	case FruchtermanReingold:
+		fruchtermanReingold(v, pos, lopts)
+	case ForceAtlas2:
+		forceAtlas2(v, pos, lopts)
+	default:
+		return nil, graph.Errorf(graph.EINVALID, "unsupported algorithm: %q", algo)
+	}
+
+	p := make(Positions, len(v.nodes))
+	for i, n := range v.nodes {
+		p[n.ID()] = pos[i]
+	}
+
+	if lopts.Attrs {
+		p.SetAttrs(g)
+	}
+
+	return p, nil
+}
+
+// SetAttrs stores the positions of the nodes of g in their x and y attributes.
+func (p Positions) SetAttrs(g graph.Graph) {
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n, ok := nodes.Node().(graph.Node)
+		if !ok {
+			continue
+		}
+
+		if pt, ok := p[n.ID()]; ok {
+			n.Attrs()[XAttr] = pt.X
+			n.Attrs()[YAttr] = pt.Y
+		}
+	}
+}
+
+// Position returns the position of node n stored in its x and y attributes.
+// It returns false if the node has no position.
+func Position(n graph.Node) (Point, bool) {
+	x, ok := coord(n.Attrs()[XAttr])
+	if !ok {
+		return Point{}, false
+	}
+
+	y, ok := coord(n.Attrs()[YAttr])
+	if !ok {
+		return Point{}, false
+	}
+
+	return Point{X: x, Y: y}, true
+}
+
+// coord returns coordinate v converted to float64.
+// It returns false if v is not a number.
+func coord(v interface{}) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+
+	c, err := attrs.Coerce(attrs.Float, v)
+	if err != nil {
+		return 0, false
+	}
+
+	f, ok := c.(float64)
+	return f, ok && !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// FromAttrs returns the positions stored in the attributes of the nodes of g.
+// It returns false if any node has no position.
+func FromAttrs(g graph.Graph) (Positions, bool) {
+	p := make(Positions, g.Nodes().Len())
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n, ok := nodes.Node().(graph.Node)
+		if !ok {
+			return nil, false
+		}
+
+		pt, ok := Position(n)
+		if !ok {
+			return nil, false
+		}
+		p[n.ID()] = pt
+	}
+
+	return p, true
+}
+
+// Schema returns the attribute schema of node positions.
+func Schema() attrs.Schema {
+	return attrs.Schema{
+		XAttr: attrs.Float,
+		YAttr: attrs.Float,
+	}
+}
+
+// initial returns random positions of n nodes in a square
+// whose area grows with the number of nodes.
+func initial(n int, opts Options) []Point {
+	rnd := rand.New(rand.NewSource(opts.Seed))
+
+	side := opts.Scaling * math.Sqrt(float64(n))
+
+	pos := make([]Point, n)
+	for i := range pos {
+		pos[i] = Point{
+			X: (rnd.Float64() - 0.5) * side,
+			Y: (rnd.Float64() - 0.5) * side,
+		}
+	}
+
+	return pos
+}
@@ -0,0 +1,163 @@
+package layout
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+// MustGraph returns a graph of two cliques of the given size
+// connected by a single edge and a single isolated node.
+func MustGraph(t *testing.T, size int) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph(memory.WithType(graph.WeightedUndirected))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	nodes := make([]*memory.Node, 2*size+1)
+	for i := range nodes {
+		n, err := memory.NewNode(g.NewNode().ID(), memory.WithUID(fmt.Sprint(i)))
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(n)
+		nodes[i] = n
+	}
+
+	link := func(u, v *memory.Node) {
+		e, err := memory.NewEdge(u, v)
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(e)
+	}
+
+	for c := 0; c < 2; c++ {
+		for i := 0; i < size; i++ {
+			for j := i + 1; j < size; j++ {
+				link(nodes[c*size+i], nodes[c*size+j])
+			}
+		}
+	}
+	link(nodes[0], nodes[size])
+
+	return g
+}
+
+// distance returns the distance between nodes with the given IDs.
+func distance(p Positions, u, v int64) float64 {
+	return math.Hypot(p[u].X-p[v].X, p[u].Y-p[v].Y)
+}
+
+func TestLayout(t *testing.T) {
+	const size = 10
+
+	for _, algo := range []Algorithm{FruchtermanReingold, ForceAtlas2} {
+		g := MustGraph(t, size)
+
+		p, err := Layout(g, algo, WithAttrs())
+		if err != nil {
+			t.Fatalf("%s: failed to lay out graph: %v", algo, err)
+		}
+
+		if len(p) != g.Nodes().Len() {
+			t.Fatalf("%s: expected %d positions, got: %d", algo, g.Nodes().Len(), len(p))
+		}
+
+		for id, pt := range p {
+			if math.IsNaN(pt.X) || math.IsNaN(pt.Y) || math.IsInf(pt.X, 0) || math.IsInf(pt.Y, 0) {
+				t.Fatalf("%s: invalid position of node %d: %v", algo, id, pt)
+			}
+		}
+
+		// nodes of the same clique are closer to each other than to the nodes of the other clique
+		var intra, inter float64
+		for i := int64(1); i < size; i++ {
+			intra += distance(p, 1, i+1) + distance(p, size+1, size+i+1)
+			inter += distance(p, i, size+i) * 2
+		}
+		if intra >= inter {
+			t.Errorf("%s: expected clustered cliques, intra: %f, inter: %f", algo, intra, inter)
+		}
+
+		got, ok := FromAttrs(g)
+		if !ok {
+			t.Fatalf("%s: positions not found in attributes", algo)
+		}
+
+		if !reflect.DeepEqual(got, p) {
+			t.Errorf("%s: expected positions: %v, got: %v", algo, p, got)
+		}
+
+		again, err := Layout(g, algo)
+		if err != nil {
+			t.Fatalf("%s: failed to lay out graph: %v", algo, err)
+		}
+
+		if !reflect.DeepEqual(again, p) {
+			t.Errorf("%s: expected deterministic layout", algo)
+		}
+	}
+}
+
+func TestLayoutErrors(t *testing.T) {
+	g := MustGraph(t, 2)
+
+	testCases := []struct {
+		name string
+		algo Algorithm
+		opts []Option
+	}{
+		{"Algorithm", "foo", nil},
+		{"Iterations", ForceAtlas2, []Option{WithIterations(0)}},
+		{"Scaling", ForceAtlas2, []Option{WithScaling(0)}},
+		{"Gravity", FruchtermanReingold, []Option{WithGravity(-1)}},
+	}
+
+	for _, tc := range testCases {
+		if _, err := Layout(g, tc.algo, tc.opts...); graph.ErrorCode(err) != graph.EINVALID {
+			t.Errorf("%s: expected error: %s, got: %v", tc.name, graph.EINVALID, err)
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	for _, algo := range []Algorithm{FruchtermanReingold, ForceAtlas2} {
+		p, err := Layout(g, algo)
+		if err != nil {
+			t.Fatalf("%s: failed to lay out graph: %v", algo, err)
+		}
+
+		if len(p) != 0 {
+			t.Errorf("%s: expected no positions, got: %d", algo, len(p))
+		}
+	}
+}
+
+func TestPosition(t *testing.T) {
+	n, err := memory.NewNode(1, memory.WithAttrs(map[string]interface{}{XAttr: int64(1), YAttr: "2.5"}))
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+
+	p, ok := Position(n)
+	if !ok || p != (Point{X: 1, Y: 2.5}) {
+		t.Errorf("expected position: {1 2.5}, got: %v %t", p, ok)
+	}
+
+	delete(n.Attrs(), YAttr)
+	if _, ok := Position(n); ok {
+		t.Errorf("expected no position")
+	}
+}
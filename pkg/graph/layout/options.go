@@ -0,0 +1,95 @@
+package layout
+
+import "github.com/milosgajdos/orbnet/pkg/graph"
+
+const (
+	// DefaultIterations is the default number of layout iterations.
+	DefaultIterations = 300
+	// DefaultScaling is the default scaling of the distances between nodes.
+	DefaultScaling = 10.0
+	// DefaultGravity is the default strength of the gravity.
+	DefaultGravity = 1.0
+	// DefaultSeed is the default seed of initial node positions.
+	DefaultSeed = 1
+)
+
+// Options configure layout.
+type Options struct {
+	// Iterations is the number of layout iterations.
+	Iterations int
+	// Scaling scales the distances between nodes: it is the ideal edge
+	// length of Fruchterman-Reingold and ForceAtlas2 repulsion coefficient.
+	Scaling float64
+	// Gravity is the strength of the force which pulls nodes to the centre
+	// and keeps disconnected components close to each other.
+	Gravity float64
+	// Seed seeds initial node positions.
+	Seed int64
+	// Attrs writes node positions to node attributes.
+	Attrs bool
+}
+
+// Option is functional layout option.
+type Option func(*Options)
+
+// WithIterations sets Iterations option.
+func WithIterations(n int) Option {
+	return func(o *Options) {
+		o.Iterations = n
+	}
+}
+
+// WithScaling sets Scaling option.
+func WithScaling(s float64) Option {
+	return func(o *Options) {
+		o.Scaling = s
+	}
+}
+
+// WithGravity sets Gravity option.
+func WithGravity(g float64) Option {
+	return func(o *Options) {
+		o.Gravity = g
+	}
+}
+
+// WithSeed sets Seed option.
+func WithSeed(s int64) Option {
+	return func(o *Options) {
+		o.Seed = s
+	}
+}
+
+// WithAttrs sets Attrs option.
+func WithAttrs() Option {
+	return func(o *Options) {
+		o.Attrs = true
+	}
+}
+
+func newOptions(opts ...Option) (Options, error) {
+	lopts := Options{
+		Iterations: DefaultIterations,
+		Scaling:    DefaultScaling,
+		Gravity:    DefaultGravity,
+		Seed:       DefaultSeed,
+	}
+
+	for _, apply := range opts {
+		apply(&lopts)
+	}
+
+	if lopts.Iterations <= 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid number of iterations: %d", lopts.Iterations)
+	}
+
+	if lopts.Scaling <= 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid scaling: %f", lopts.Scaling)
+	}
+
+	if lopts.Gravity < 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid gravity: %f", lopts.Gravity)
+	}
+
+	return lopts, nil
+}
@@ -0,0 +1,80 @@
+package layout
+
+import (
+	"sort"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	gonum "gonum.org/v1/gonum/graph"
+)
+
+// link is an undirected weighted edge between nodes with the given indices.
+type link struct {
+	u, v int
+	w    float64
+}
+
+// view is an undirected view of graph.
+// The edges between the same nodes are merged into a single link.
+type view struct {
+	nodes  []gonum.Node
+	links  []link
+	degree []float64
+}
+
+// newView returns the view of g with nodes ordered by their IDs.
+// Self loops are ignored and so are the links with non-positive weights.
+func newView(g graph.Graph) *view {
+	nodes := gonum.NodesOf(g.Nodes())
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID() < nodes[j].ID()
+	})
+
+	index := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		index[n.ID()] = i
+	}
+
+	weights := make(map[[2]int]float64)
+	edges := g.Edges()
+	for edges.Next() {
+		e := edges.Edge()
+		u, v := index[e.From().ID()], index[e.To().ID()]
+		if u == v {
+			continue
+		}
+		if u > v {
+			u, v = v, u
+		}
+
+		w := 1.0
+		if we, ok := e.(gonum.WeightedEdge); ok {
+			w = we.Weight()
+		}
+		weights[[2]int{u, v}] += w
+	}
+
+	links := make([]link, 0, len(weights))
+	for k, w := range weights {
+		if w > 0 {
+			links = append(links, link{u: k[0], v: k[1], w: w})
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].u != links[j].u {
+			return links[i].u < links[j].u
+		}
+		return links[i].v < links[j].v
+	})
+
+	degree := make([]float64, len(nodes))
+	for _, l := range links {
+		degree[l.u]++
+		degree[l.v]++
+	}
+
+	return &view{
+		nodes:  nodes,
+		links:  links,
+		degree: degree,
+	}
+}
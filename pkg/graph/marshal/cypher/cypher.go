@@ -0,0 +1,410 @@
+package cypher
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+)
+
+const (
+	// DefaultEdgeLabel is the relationship type of edges which have no label.
+	DefaultEdgeLabel = "Undefined"
+)
+
+const (
+	// uidProp is the property which stores node and edge UIDs.
+	uidProp = "uid"
+	// weightProp is the property which stores edge weights.
+	weightProp = "weight"
+)
+
+// Marshaler marshals graphs into Cypher scripts.
+type Marshaler struct {
+	opts Options
+}
+
+// NewMarshaler creates a new Cypher marshaler and returns it.
+func NewMarshaler(opts ...Option) (*Marshaler, error) {
+	mopts := Options{
+		BatchSize: DefaultBatchSize,
+	}
+
+	for _, apply := range opts {
+		apply(&mopts)
+	}
+
+	if mopts.BatchSize <= 0 {
+		return nil, graph.Errorf(graph.EINVALID, "invalid batch size: %d", mopts.BatchSize)
+	}
+
+	return &Marshaler{
+		opts: mopts,
+	}, nil
+}
+
+// Marshal marshals g into a Cypher script which can be run by cypher-shell.
+// The script is idempotent: nodes and edges are merged on their UIDs, so it
+// can be run repeatedly against the same database without creating duplicates.
+func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
+	var b bytes.Buffer
+	if err := m.marshal(g, &b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo writes the Cypher script of g to w.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	bw := bufio.NewWriter(w)
+	if err := m.marshal(g, bw); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// marshal writes the Cypher script of g to w.
+// It creates UID uniqueness constraints for all node labels first, then it merges
+// the nodes grouped by their labels and finally it merges the edges grouped by
+// their labels and the labels of their nodes. Each group is merged by UNWIND
+// statements of at most BatchSize rows.
+func (m *Marshaler) marshal(g graph.Graph, w io.Writer) error {
+	ew := &errWriter{w: w}
+
+	nodes := make(map[string][]graph.Node)
+	it := g.Nodes()
+	for it.Next() {
+		n := it.Node().(graph.Node)
+		nodes[n.Label()] = append(nodes[n.Label()], n)
+	}
+
+	labels := make([]string, 0, len(nodes))
+	for label := range nodes {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	for _, label := range labels {
+		if label == "" {
+			continue
+		}
+		ew.printf("CREATE CONSTRAINT IF NOT EXISTS FOR (n:%s) REQUIRE n.%s IS UNIQUE;\n", name(label), uidProp)
+	}
+
+	for _, label := range labels {
+		ns := nodes[label]
+		sort.Slice(ns, func(i, j int) bool {
+			return ns[i].UID() < ns[j].UID()
+		})
+
+		schema := m.opts.Schema.NodeSchema(label)
+		rows := make([]string, len(ns))
+		for i, n := range ns {
+			rows[i] = fmt.Sprintf("{%s: %s, props: %s}", uidProp, quote(n.UID()), props(schema, n.Attrs(), nil, uidProp))
+		}
+
+		stmt := fmt.Sprintf("MERGE (n%s {%s: row.%s})\nSET n += row.props;\n", pattern(label), uidProp, uidProp)
+		m.unwind(ew, rows, stmt)
+	}
+
+	edges := make(map[edgeGroup][]graph.Edge)
+	eit := g.Edges()
+	for eit.Next() {
+		e := eit.Edge().(graph.Edge)
+		k := edgeGroup{
+			typ:  e.Label(),
+			from: e.From().(graph.Node).Label(),
+			to:   e.To().(graph.Node).Label(),
+		}
+		if k.typ == "" {
+			k.typ = DefaultEdgeLabel
+		}
+		edges[k] = append(edges[k], e)
+	}
+
+	groups := make([]edgeGroup, 0, len(edges))
+	for k := range edges {
+		groups = append(groups, k)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].less(groups[j])
+	})
+
+	for _, k := range groups {
+		es := edges[k]
+		sort.Slice(es, func(i, j int) bool {
+			fi, fj := es[i].From().(graph.Node).UID(), es[j].From().(graph.Node).UID()
+			if fi != fj {
+				return fi < fj
+			}
+			ti, tj := es[i].To().(graph.Node).UID(), es[j].To().(graph.Node).UID()
+			if ti != tj {
+				return ti < tj
+			}
+			return es[i].UID() < es[j].UID()
+		})
+
+		schema := m.opts.Schema.EdgeSchema(es[0].Label())
+		rows := make([]string, len(es))
+		for i, e := range es {
+			weight := map[string]interface{}{weightProp: e.Weight()}
+			rows[i] = fmt.Sprintf("{from: %s, to: %s, %s: %s, props: %s}",
+				quote(e.From().(graph.Node).UID()),
+				quote(e.To().(graph.Node).UID()),
+				uidProp, quote(e.UID()),
+				props(schema, e.Attrs(), weight, uidProp))
+		}
+
+		stmt := fmt.Sprintf("MATCH (a%s {%s: row.from})\nMATCH (b%s {%s: row.to})\nMERGE (a)-[r:%s {%s: row.%s}]->(b)\nSET r += row.props;\n",
+			pattern(k.from), uidProp, pattern(k.to), uidProp, name(k.typ), uidProp, uidProp)
+		m.unwind(ew, rows, stmt)
+	}
+
+	return ew.err
+}
+
+// unwind writes UNWIND statements which run stmt for batches of rows.
+func (m *Marshaler) unwind(ew *errWriter, rows []string, stmt string) {
+	for i := 0; i < len(rows); i += m.opts.BatchSize {
+		j := i + m.opts.BatchSize
+		if j > len(rows) {
+			j = len(rows)
+		}
+		ew.printf("UNWIND [\n  %s\n] AS row\n%s", strings.Join(rows[i:j], ",\n  "), stmt)
+	}
+}
+
+// edgeGroup groups edges merged by the same statement.
+type edgeGroup struct {
+	typ  string
+	from string
+	to   string
+}
+
+// less returns true if k is ordered before o.
+func (k edgeGroup) less(o edgeGroup) bool {
+	if k.typ != o.typ {
+		return k.typ < o.typ
+	}
+	if k.from != o.from {
+		return k.from < o.from
+	}
+	return k.to < o.to
+}
+
+// errWriter remembers the first write error and skips all subsequent writes.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
+
+// props returns Cypher map literal of attributes a overridden by attributes o.
+// Attribute values are coerced to the types declared in schema s. The attributes
+// with the skipped names and the values which can't be stored as properties are omitted.
+func props(s attrs.Schema, a, o map[string]interface{}, skip ...string) string {
+	merged := make(map[string]interface{}, len(a)+len(o))
+	for k, v := range a {
+		merged[k] = v
+	}
+	for k, v := range o {
+		merged[k] = v
+	}
+	for _, k := range skip {
+		delete(merged, k)
+	}
+
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fields := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := merged[k]
+		if t, ok := s[k]; ok {
+			if cv, err := attrs.Coerce(t, v); err == nil {
+				v = cv
+			}
+		}
+
+		lit, ok := literal(v)
+		if !ok {
+			continue
+		}
+		fields = append(fields, name(k)+": "+lit)
+	}
+
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+// literal returns Cypher literal of property value v.
+// Times are converted to datetimes and colors to their hex codes.
+// Lists of mixed or nested values and maps are stored as JSON strings
+// because Cypher properties can only store homogeneous lists of primitives.
+// It returns false if v can't be stored as a property.
+func literal(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return quote(val), true
+	case bool:
+		return strconv.FormatBool(val), true
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(val), true
+	case float32:
+		return float(float64(val))
+	case float64:
+		return float(val)
+	case time.Time:
+		return "datetime(" + quote(val.Format(time.RFC3339Nano)) + ")", true
+	case color.RGBA:
+		return quote(attrs.Format(attrs.Color, val)), true
+	}
+
+	switch attrs.TypeOf(v) {
+	case attrs.List:
+		l, err := attrs.Coerce(attrs.List, v)
+		if err != nil {
+			return "", false
+		}
+		if lit, ok := list(l.([]interface{})); ok {
+			return lit, true
+		}
+		return jsonString(v)
+	case attrs.Map:
+		return jsonString(v)
+	}
+
+	return quote(fmt.Sprint(v)), true
+}
+
+// list returns Cypher list literal of l.
+// It returns false if l contains values of different or non-primitive types.
+func list(l []interface{}) (string, bool) {
+	var t attrs.Type
+	elems := make([]string, len(l))
+	for i, e := range l {
+		et := attrs.TypeOf(e)
+		switch et {
+		case attrs.String, attrs.Int, attrs.Float, attrs.Bool:
+		default:
+			return "", false
+		}
+		if _, ok := e.(string); !ok && et == attrs.String {
+			return "", false
+		}
+		if t != "" && t != et {
+			return "", false
+		}
+		t = et
+
+		lit, ok := literal(e)
+		if !ok {
+			return "", false
+		}
+		elems[i] = lit
+	}
+	return "[" + strings.Join(elems, ", ") + "]", true
+}
+
+// float returns Cypher float literal of f.
+// It returns false if f is NaN or infinite.
+func float(f float64) (string, bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", false
+	}
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s, true
+}
+
+// jsonString returns Cypher string literal of JSON encoded v.
+func jsonString(v interface{}) (string, bool) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", false
+	}
+	return quote(string(b)), true
+}
+
+// quote returns Cypher string literal of s.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// name returns Cypher name of s which is quoted with backticks if s is not a valid identifier.
+func name(s string) string {
+	if isIdent(s) {
+		return s
+	}
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+// isIdent returns true if s is a valid unquoted Cypher identifier.
+func isIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// pattern returns node label pattern of label.
+// Nodes which have no label are matched by their UIDs only.
+func pattern(label string) string {
+	if label == "" {
+		return ""
+	}
+	return ":" + name(label)
+}
@@ -0,0 +1,223 @@
+package cypher
+
+import (
+	"image/color"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+func MustGraph(t *testing.T) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	nodes := []struct {
+		uid   string
+		label string
+		attrs map[string]interface{}
+	}{
+		{"repo", "Repo", map[string]interface{}{
+			"name":       `foo "bar"`,
+			"stars":      int64(10),
+			"starred_at": "2021-01-02T03:04:05Z",
+			"topics":     []interface{}{"go", "graph"},
+			"license":    map[string]interface{}{"key": "mit"},
+			"uid":        "ignored",
+		}},
+		{"go", "Topic", map[string]interface{}{"name": "go"}},
+		{"graph", "Topic", map[string]interface{}{"name": "graph", "my key": 1.0}},
+	}
+
+	for _, n := range nodes {
+		node, err := memory.NewNode(g.NewNode().ID(),
+			memory.WithUID(n.uid),
+			memory.WithLabel(n.label),
+			memory.WithAttrs(n.attrs),
+		)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(node)
+	}
+
+	edges := []struct {
+		uid   string
+		from  string
+		to    string
+		label string
+	}{
+		{"repo-go", "repo", "go", "HasTopic"},
+		{"repo-graph", "repo", "graph", "HasTopic"},
+		{"go-graph", "go", "graph", ""},
+	}
+
+	for _, e := range edges {
+		from, to := g.NodeWithUID(e.from), g.NodeWithUID(e.to)
+
+		edge, err := memory.NewEdge(from, to,
+			memory.WithUID(e.uid),
+			memory.WithLabel(e.label),
+			memory.WithWeight(2.5),
+			memory.WithAttrs(map[string]interface{}{"weight": 1.0}),
+		)
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(edge)
+	}
+
+	return g
+}
+
+func MustSchema(t *testing.T) *attrs.Registry {
+	t.Helper()
+
+	r := attrs.NewRegistry()
+	if err := r.SetNodeSchema("Repo", attrs.Schema{"starred_at": attrs.Time}); err != nil {
+		t.Fatalf("failed to set node schema: %v", err)
+	}
+
+	return r
+}
+
+func TestMarshal(t *testing.T) {
+	m, err := NewMarshaler(WithBatchSize(1), WithSchema(MustSchema(t)))
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	data, err := m.Marshal(MustGraph(t))
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	exp := `CREATE CONSTRAINT IF NOT EXISTS FOR (n:Repo) REQUIRE n.uid IS UNIQUE;
+CREATE CONSTRAINT IF NOT EXISTS FOR (n:Topic) REQUIRE n.uid IS UNIQUE;
+UNWIND [
+  {uid: "repo", props: {license: "{\"key\":\"mit\"}", name: "foo \"bar\"", starred_at: datetime("2021-01-02T03:04:05Z"), stars: 10, topics: ["go", "graph"]}}
+] AS row
+MERGE (n:Repo {uid: row.uid})
+SET n += row.props;
+UNWIND [
+  {uid: "go", props: {name: "go"}}
+] AS row
+MERGE (n:Topic {uid: row.uid})
+SET n += row.props;
+UNWIND [
+  {uid: "graph", props: {` + "`my key`" + `: 1.0, name: "graph"}}
+] AS row
+MERGE (n:Topic {uid: row.uid})
+SET n += row.props;
+UNWIND [
+  {from: "repo", to: "go", uid: "repo-go", props: {weight: 2.5}}
+] AS row
+MATCH (a:Repo {uid: row.from})
+MATCH (b:Topic {uid: row.to})
+MERGE (a)-[r:HasTopic {uid: row.uid}]->(b)
+SET r += row.props;
+UNWIND [
+  {from: "repo", to: "graph", uid: "repo-graph", props: {weight: 2.5}}
+] AS row
+MATCH (a:Repo {uid: row.from})
+MATCH (b:Topic {uid: row.to})
+MERGE (a)-[r:HasTopic {uid: row.uid}]->(b)
+SET r += row.props;
+UNWIND [
+  {from: "go", to: "graph", uid: "go-graph", props: {weight: 2.5}}
+] AS row
+MATCH (a:Topic {uid: row.from})
+MATCH (b:Topic {uid: row.to})
+MERGE (a)-[r:Undefined {uid: row.uid}]->(b)
+SET r += row.props;
+`
+
+	if string(data) != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, data)
+	}
+}
+
+func TestMarshalBatch(t *testing.T) {
+	m, err := NewMarshaler()
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	data, err := m.Marshal(MustGraph(t))
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	exp := `UNWIND [
+  {uid: "go", props: {name: "go"}},
+  {uid: "graph", props: {` + "`my key`" + `: 1.0, name: "graph"}}
+] AS row
+MERGE (n:Topic {uid: row.uid})
+`
+
+	if !strings.Contains(string(data), exp) {
+		t.Errorf("expected batch:\n%s\ngot:\n%s", exp, data)
+	}
+}
+
+func TestNewMarshalerErrors(t *testing.T) {
+	if _, err := NewMarshaler(WithBatchSize(0)); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	ts := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		v   interface{}
+		exp string
+		ok  bool
+	}{
+		{"a\\b\"c\nd\x01", `"a\\b\"c\nd\u0001"`, true},
+		{true, "true", true},
+		{10, "10", true},
+		{1e21, "1000000000000000000000.0", true},
+		{math.NaN(), "", false},
+		{ts, `datetime("2021-01-02T03:04:05Z")`, true},
+		{color.RGBA{R: 255, A: 255}, `"#ff0000"`, true},
+		{[]float64{1, 0.5}, "[1.0, 0.5]", true},
+		{[]interface{}{"a", 1}, `"[\"a\",1]"`, true},
+		{map[string]interface{}{"a": 1}, `"{\"a\":1}"`, true},
+		{nil, "", false},
+	}
+
+	for _, tc := range testCases {
+		got, ok := literal(tc.v)
+		if ok != tc.ok || got != tc.exp {
+			t.Errorf("%v: expected: %s (%t), got: %s (%t)", tc.v, tc.exp, tc.ok, got, ok)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	testCases := []struct {
+		s   string
+		exp string
+	}{
+		{"Repo", "Repo"},
+		{"has_topic2", "has_topic2"},
+		{"2a", "`2a`"},
+		{"a b", "`a b`"},
+		{"a`b", "`a``b`"},
+	}
+
+	for _, tc := range testCases {
+		if got := name(tc.s); got != tc.exp {
+			t.Errorf("%s: expected: %s, got: %s", tc.s, tc.exp, got)
+		}
+	}
+}
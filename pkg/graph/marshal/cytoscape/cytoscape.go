@@ -1,13 +1,24 @@
 package cytoscape
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/internal/jsonstream"
+	"github.com/milosgajdos/orbnet/pkg/graph/layout"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/meta"
 	"gonum.org/v1/gonum/graph/formats/cytoscapejs"
 )
 
+var (
+	// nodeKeys are the keys of node data fields reserved by CytoscapeJS.
+	nodeKeys = []string{"id", "parent"}
+	// edgeKeys are the keys of edge data fields reserved by CytoscapeJS.
+	edgeKeys = []string{"id", "source", "target", "parent"}
+)
+
 // Marshaler implements graph.Marshaler.
 type Marshaler struct {
 	name   string
@@ -26,49 +37,86 @@ func NewMarshaler(name, prefix, indent string) (*Marshaler, error) {
 
 // Marshal marshals g into format that can be used by
 // CytoscapJS https://js.cytoscape.org/
+// Node UIDs, labels, styles and edge weights are stored in the reserved data field.
+// Nodes with x and y attributes are placed at their positions.
 func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
-	c := cytoscapejs.Elements{
-		Nodes: make([]cytoscapejs.Node, g.Nodes().Len()),
-		Edges: make([]cytoscapejs.Edge, g.Edges().Len()),
+	var b bytes.Buffer
+	if err := m.MarshalTo(&b, g); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo marshals g into w encoding its nodes and edges one by one.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	enc := jsonstream.NewEncoder(w, m.prefix, m.indent)
+
+	if err := enc.BeginArray("nodes"); err != nil {
+		return err
 	}
 
 	nodes := g.Nodes()
-	i := 0
 	for nodes.Next() {
 		n := nodes.Node().(graph.Node)
 
+		m := meta.Node(n)
+		a := m.Reserve(n.Attrs(), nodeKeys...)
+
 		ndata := cytoscapejs.NodeData{
 			ID:         fmt.Sprint(n.ID()),
-			Attributes: n.Attrs(),
+			Attributes: meta.Attrs(a, m),
 		}
 
-		c.Nodes[i] = cytoscapejs.Node{
+		node := cytoscapejs.Node{
 			Data:       ndata,
 			Selectable: true,
 		}
 
-		i++
+		if p, ok := layout.Position(n); ok {
+			node.Position = &cytoscapejs.Position{X: p.X, Y: p.Y}
+		}
+
+		if err := enc.Elem(&node); err != nil {
+			return err
+		}
+	}
+
+	if err := enc.EndArray(); err != nil {
+		return err
+	}
+
+	if err := enc.BeginArray("edges"); err != nil {
+		return err
 	}
 
 	edges := g.Edges()
-	i = 0
+	i := 0
 	for edges.Next() {
 		e := edges.Edge().(graph.Edge)
 
+		m := meta.Edge(e)
+		a := m.Reserve(e.Attrs(), edgeKeys...)
+
 		edata := cytoscapejs.EdgeData{
 			ID:         fmt.Sprint(i),
 			Source:     fmt.Sprint(e.From().ID()),
 			Target:     fmt.Sprint(e.To().ID()),
-			Attributes: e.Attrs(),
+			Attributes: meta.Attrs(a, m),
 		}
 
-		c.Edges[i] = cytoscapejs.Edge{
+		if err := enc.Elem(&cytoscapejs.Edge{
 			Data:       edata,
 			Selectable: true,
+		}); err != nil {
+			return err
 		}
 
 		i++
 	}
 
-	return json.MarshalIndent(c, m.prefix, m.indent)
+	if err := enc.EndArray(); err != nil {
+		return err
+	}
+
+	return enc.Close()
 }
@@ -0,0 +1,174 @@
+package cytoscape
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/marshaltest"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	"gonum.org/v1/gonum/graph/formats/cytoscapejs"
+)
+
+func TestUnmarshal(t *testing.T) {
+	// Cytoscape desktop export
+	data := `{
+  "data": {"name": "stars"},
+  "elements": {
+    "nodes": [
+      {"data": {"id": "a", "size": 3, "color": "#ff0000"}, "position": {"x": 1, "y": 2}},
+      {"data": {"id": "b"}}
+    ],
+    "edges": [
+      {"data": {"id": "e0", "source": "a", "target": "b", "weight": 0.5}},
+      {"data": {"id": "e1", "source": "a", "target": "b"}}
+    ]
+  }
+}`
+
+	typ, err := GraphType([]byte(data))
+	if err != nil {
+		t.Fatalf("failed to get graph type: %v", err)
+	}
+
+	if typ != graph.WeightedDirectedMulti {
+		t.Errorf("expected graph type: %s, got: %s", graph.WeightedDirectedMulti, typ)
+	}
+
+	u, err := NewUnmarshaler()
+	if err != nil {
+		t.Fatalf("failed to create unmarshaler: %v", err)
+	}
+
+	g, err := memory.NewGraph(memory.WithType(graph.WeightedDirected))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	if err := u.Unmarshal([]byte(data), g); err != nil {
+		t.Fatalf("failed to unmarshal graph: %v", err)
+	}
+
+	a, b := g.NodeWithUID("a"), g.NodeWithUID("b")
+	if a == nil || b == nil {
+		t.Fatalf("expected nodes a and b")
+	}
+
+	if size := a.Attrs()["size"]; size != 3.0 {
+		t.Errorf("expected size: 3, got: %v", size)
+	}
+
+	if c := a.(*memory.Node).Color(); c != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("expected color: red, got: %v", c)
+	}
+
+	if x, y := a.Attrs()["x"], a.Attrs()["y"]; x != 1.0 || y != 2.0 {
+		t.Errorf("expected position: 1 2, got: %v %v", x, y)
+	}
+
+	if n := g.Edges().Len(); n != 1 {
+		t.Errorf("expected 1 edge, got: %d", n)
+	}
+
+	if w := g.Edge(a.ID(), b.ID()).(graph.Edge).Weight(); w != 0.5 {
+		t.Errorf("expected weight: 0.5, got: %v", w)
+	}
+}
+
+func TestUnmarshalElements(t *testing.T) {
+	// CytoscapeJS mixed elements array
+	data := `{"elements": [
+  {"group": "nodes", "data": {"id": "a"}},
+  {"data": {"id": "b"}},
+  {"data": {"id": "e", "source": "a", "target": "b"}}
+]}`
+
+	u, err := NewUnmarshaler()
+	if err != nil {
+		t.Fatalf("failed to create unmarshaler: %v", err)
+	}
+
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	if err := u.Unmarshal([]byte(data), g); err != nil {
+		t.Fatalf("failed to unmarshal graph: %v", err)
+	}
+
+	if n := g.Nodes().Len(); n != 2 {
+		t.Errorf("expected 2 nodes, got: %d", n)
+	}
+
+	if n := g.Edges().Len(); n != 1 {
+		t.Errorf("expected 1 edge, got: %d", n)
+	}
+}
+
+func TestMarshalPosition(t *testing.T) {
+	g := marshaltest.Graph(t, graph.WeightedDirected)
+	g.NodeWithUID("repo").Attrs()["x"] = 1.5
+	g.NodeWithUID("repo").Attrs()["y"] = -2.0
+
+	m, err := NewMarshaler("test", "", "")
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	data, err := m.Marshal(g)
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	var c cytoscapejs.Elements
+	if err := json.Unmarshal(data, &c); err != nil {
+		t.Fatalf("failed to decode CytoscapeJS: %v", err)
+	}
+
+	repo := fmt.Sprint(g.NodeWithUID("repo").ID())
+	for _, n := range c.Nodes {
+		p := n.Position
+		switch n.Data.ID {
+		case repo:
+			if p == nil || p.X != 1.5 || p.Y != -2.0 {
+				t.Errorf("expected position: {1.5 -2}, got: %v", p)
+			}
+		default:
+			if p != nil {
+				t.Errorf("node %s: expected no position, got: %v", n.Data.ID, p)
+			}
+		}
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{"JSON", `{`},
+		{"Node", `{"nodes": [{"data": {"id": "a"}}, {"data": {"id": "a"}}]}`},
+		{"Edge", `{"nodes": [{"data": {"id": "a"}}], "edges": [{"data": {"id": "e", "source": "a", "target": "b"}}]}`},
+		{"Meta", `{"nodes": [{"data": {"id": "a", "orbnet": 1}}]}`},
+		{"Value", `{"nodes": [{"data": {"id": "a", "orbnet": {"types": {"n": "int"}}, "n": "foo"}}]}`},
+	}
+
+	u, err := NewUnmarshaler()
+	if err != nil {
+		t.Fatalf("failed to create unmarshaler: %v", err)
+	}
+
+	for _, tc := range testCases {
+		g, err := memory.NewGraph()
+		if err != nil {
+			t.Fatalf("failed to create graph: %v", err)
+		}
+
+		if err := u.Unmarshal([]byte(tc.data), g); graph.ErrorCode(err) != graph.EINVALID {
+			t.Errorf("%s: expected error: %s, got: %v", tc.name, graph.EINVALID, err)
+		}
+	}
+}
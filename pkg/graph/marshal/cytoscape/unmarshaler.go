@@ -0,0 +1,152 @@
+package cytoscape
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/layout"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/meta"
+	"gonum.org/v1/gonum/graph/formats/cytoscapejs"
+)
+
+// Unmarshaler implements graph.Unmarshaler.
+type Unmarshaler struct {
+	opts Options
+}
+
+// NewUnmarshaler creates a new Unmarshaler and returns it.
+func NewUnmarshaler(opts ...Option) (*Unmarshaler, error) {
+	uopts := Options{}
+	for _, apply := range opts {
+		apply(&uopts)
+	}
+
+	return &Unmarshaler{
+		opts: uopts,
+	}, nil
+}
+
+// document is either CytoscapeJS elements or a graph whose elements
+// are either separated into nodes and edges or mixed.
+type document struct {
+	Nodes    []cytoscapejs.Node `json:"nodes"`
+	Edges    []cytoscapejs.Edge `json:"edges"`
+	Elements json.RawMessage    `json:"elements"`
+}
+
+// Unmarshal unmarshals CytoscapeJS data into g.
+// It accepts the documents created by Marshaler as well as the graphs exported by
+// Cytoscape. The data of the elements which were not marshaled by Marshaler are
+// used as attributes and their IDs as node UIDs. Node positions are stored in
+// the x and y attributes unless the nodes have them.
+func (u *Unmarshaler) Unmarshal(data []byte, g graph.Graph) error {
+	nodes, edges, err := decode(data)
+	if err != nil {
+		return err
+	}
+
+	return meta.Build(g, nodes, edges, u.opts.Schema)
+}
+
+// GraphType returns the type of graph encoded in CytoscapeJS data.
+// CytoscapeJS does not store edge directions so the graph is always directed.
+func GraphType(data []byte) (string, error) {
+	_, edges, err := decode(data)
+	if err != nil {
+		return "", err
+	}
+
+	return meta.GraphType(true, edges), nil
+}
+
+// decode decodes the nodes and edges of CytoscapeJS data.
+func decode(data []byte) ([]meta.Element, []meta.Element, error) {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, graph.Errorf(graph.EINVALID, "invalid CytoscapeJS: %v", err)
+	}
+
+	if raw := bytes.TrimSpace(doc.Elements); len(raw) > 0 {
+		if err := doc.elements(raw); err != nil {
+			return nil, nil, graph.Errorf(graph.EINVALID, "invalid CytoscapeJS elements: %v", err)
+		}
+	}
+
+	nodes := make([]meta.Element, len(doc.Nodes))
+	for i, n := range doc.Nodes {
+		a := n.Data.Attributes
+		if p := n.Position; p != nil {
+			a = position(a, p.X, p.Y)
+		}
+
+		nodes[i] = meta.Element{
+			ID:    n.Data.ID,
+			Attrs: a,
+		}
+	}
+
+	edges := make([]meta.Element, len(doc.Edges))
+	for i, e := range doc.Edges {
+		edges[i] = meta.Element{
+			ID:     e.Data.ID,
+			Source: e.Data.Source,
+			Target: e.Data.Target,
+			Attrs:  e.Data.Attributes,
+		}
+	}
+
+	return nodes, edges, nil
+}
+
+// elements decodes the graph elements stored in raw.
+func (d *document) elements(raw json.RawMessage) error {
+	if raw[0] != '[' {
+		var elems cytoscapejs.Elements
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return err
+		}
+		d.Nodes = append(d.Nodes, elems.Nodes...)
+		d.Edges = append(d.Edges, elems.Edges...)
+		return nil
+	}
+
+	var elems []cytoscapejs.Element
+	if err := json.Unmarshal(raw, &elems); err != nil {
+		return err
+	}
+
+	// NOTE: elements are classified by their data
+	// as the groups used by CytoscapeJS vary
+	for _, el := range elems {
+		if el.Data.Source == "" && el.Data.Target == "" {
+			d.Nodes = append(d.Nodes, cytoscapejs.Node{
+				Data:     cytoscapejs.NodeData{ID: el.Data.ID, Attributes: el.Data.Attributes},
+				Position: el.Position,
+			})
+			continue
+		}
+		d.Edges = append(d.Edges, cytoscapejs.Edge{
+			Data: cytoscapejs.EdgeData{ID: el.Data.ID, Source: el.Data.Source, Target: el.Data.Target, Attributes: el.Data.Attributes},
+		})
+	}
+
+	return nil
+}
+
+// position returns attributes a with the node position stored in
+// the x and y attributes unless a already contains either of them.
+func position(a map[string]interface{}, x, y float64) map[string]interface{} {
+	if a == nil {
+		a = make(map[string]interface{})
+	}
+
+	_, okx := a[layout.XAttr]
+	_, oky := a[layout.YAttr]
+	if !okx && !oky {
+		a[layout.XAttr] = x
+		a[layout.YAttr] = y
+	}
+
+	return a
+}
@@ -0,0 +1,162 @@
+// Package d2 marshals small graphs into D2 diagrams.
+// See: https://d2lang.com
+package d2
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/diagram"
+)
+
+const (
+	// strokeColor is node outline color.
+	strokeColor = "#333333"
+)
+
+// escaper escapes the text of D2 double-quoted strings.
+// Dollar signs are escaped so that the text is not substituted.
+var escaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"$", `\$`,
+	"\r\n", " ",
+	"\n", " ",
+	"\r", " ",
+)
+
+// Marshaler implements graph.Marshaler.
+type Marshaler struct {
+	opts Options
+}
+
+// NewMarshaler creates a new Marshaler and returns it.
+func NewMarshaler(opts ...Option) (*Marshaler, error) {
+	mopts := Options{
+		Direction: DefaultDirection,
+		MaxNodes:  DefaultMaxNodes,
+		NameKeys:  DefaultNameKeys,
+	}
+
+	for _, apply := range opts {
+		apply(&mopts)
+	}
+
+	switch mopts.Direction {
+	case Up, Down, Left, Right:
+	default:
+		return nil, graph.Errorf(graph.EINVALID, "unsupported direction: %q", mopts.Direction)
+	}
+
+	if mopts.MaxNodes <= 0 {
+		return nil, graph.Errorf(graph.EINVALID, "invalid max nodes: %d", mopts.MaxNodes)
+	}
+
+	return &Marshaler{
+		opts: mopts,
+	}, nil
+}
+
+// Marshal marshals g into D2 diagram.
+// Node shapes are mapped to the nearest D2 shapes and node colors to classes.
+// Graphs which have more than MaxNodes nodes are marshaled as per Truncate option.
+func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
+	var b bytes.Buffer
+	if err := m.MarshalTo(&b, g); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo marshals g into D2 diagram written to w.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	sg, err := diagram.Limit(g, m.opts.MaxNodes, m.opts.Truncate)
+	if err != nil {
+		return err
+	}
+
+	b := bufio.NewWriter(w)
+
+	if n, total := sg.Nodes().Len(), g.Nodes().Len(); n < total {
+		fmt.Fprintf(b, "# truncated: %d of %d nodes\n", n, total)
+	}
+	fmt.Fprintf(b, "direction: %s\n", m.opts.Direction)
+
+	nodes := diagram.SortedNodes(sg)
+
+	colors := make(map[string]bool)
+	for _, n := range nodes {
+		colors[diagram.NodeHex(n)] = true
+	}
+
+	if len(colors) > 0 {
+		hexes := make([]string, 0, len(colors))
+		for c := range colors {
+			hexes = append(hexes, c)
+		}
+		sort.Strings(hexes)
+
+		b.WriteString("classes: {\n")
+		for _, c := range hexes {
+			fmt.Fprintf(b, "  %s: {\n", diagram.Class(c))
+			fmt.Fprintf(b, "    style.fill: \"%s\"\n", c)
+			fmt.Fprintf(b, "    style.stroke: \"%s\"\n", strokeColor)
+			b.WriteString("  }\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	ids := make(map[int64]string, len(nodes))
+	for i, n := range nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[n.ID()] = id
+
+		fmt.Fprintf(b, "%s: \"%s\" {\n", id, escaper.Replace(diagram.NodeName(n, m.opts.NameKeys)))
+		fmt.Fprintf(b, "  shape: %s\n", shape(diagram.NodeShape(n)))
+		fmt.Fprintf(b, "  class: %s\n", diagram.Class(diagram.NodeHex(n)))
+		b.WriteString("}\n")
+	}
+
+	arrow := "--"
+	if graph.IsDirected(g.Type()) {
+		arrow = "->"
+	}
+
+	for _, e := range diagram.SortedEdges(sg) {
+		from, to := ids[e.From().ID()], ids[e.To().ID()]
+		if l := e.Label(); l != "" {
+			fmt.Fprintf(b, "%s %s %s: \"%s\"\n", from, arrow, to, escaper.Replace(l))
+			continue
+		}
+		fmt.Fprintf(b, "%s %s %s\n", from, arrow, to)
+	}
+
+	return b.Flush()
+}
+
+// shape returns D2 shape nearest to shape s.
+func shape(s string) string {
+	switch s {
+	case "circle", "point":
+		return "circle"
+	case "ellipse", "oval":
+		return "oval"
+	case "square":
+		return "square"
+	case "diamond":
+		return "diamond"
+	case "hexagon":
+		return "hexagon"
+	case "parallelogram":
+		return "parallelogram"
+	case "cylinder":
+		return "cylinder"
+	default:
+		return "rectangle"
+	}
+}
@@ -0,0 +1,71 @@
+package d2
+
+import "github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/diagram"
+
+// Direction is diagram direction.
+type Direction string
+
+const (
+	// Up lays out diagram from bottom to top.
+	Up Direction = "up"
+	// Down lays out diagram from top to bottom.
+	Down Direction = "down"
+	// Left lays out diagram from right to left.
+	Left Direction = "left"
+	// Right lays out diagram from left to right.
+	Right Direction = "right"
+)
+
+const (
+	// DefaultDirection is default diagram direction.
+	DefaultDirection = Right
+	// DefaultMaxNodes is default max number of marshaled nodes.
+	DefaultMaxNodes = diagram.MaxNodes
+)
+
+// DefaultNameKeys are default node attributes which store node names.
+var DefaultNameKeys = diagram.NameKeys
+
+// Options configure marshaler.
+type Options struct {
+	// Direction is diagram direction.
+	Direction Direction
+	// MaxNodes is the max number of marshaled nodes.
+	MaxNodes int
+	// Truncate marshals MaxNodes nodes with the highest degree of
+	// graphs which have more nodes instead of refusing to marshal them.
+	Truncate bool
+	// NameKeys are node attributes which store node names in the order of precedence.
+	NameKeys []string
+}
+
+// Option is functional marshaler option.
+type Option func(*Options)
+
+// WithDirection sets Direction option.
+func WithDirection(d Direction) Option {
+	return func(o *Options) {
+		o.Direction = d
+	}
+}
+
+// WithMaxNodes sets MaxNodes option.
+func WithMaxNodes(n int) Option {
+	return func(o *Options) {
+		o.MaxNodes = n
+	}
+}
+
+// WithTruncate sets Truncate option.
+func WithTruncate() Option {
+	return func(o *Options) {
+		o.Truncate = true
+	}
+}
+
+// WithNameKeys sets NameKeys option.
+func WithNameKeys(keys ...string) Option {
+	return func(o *Options) {
+		o.NameKeys = keys
+	}
+}
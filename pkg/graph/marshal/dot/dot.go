@@ -1,8 +1,15 @@
 package dot
 
 import (
+	"fmt"
+	"io"
+
 	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/meta"
+	gonum "gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
 	"gonum.org/v1/gonum/graph/encoding/dot"
+	"gonum.org/v1/gonum/graph/iterator"
 )
 
 // Marshaler is used for marshaling graph to DOT format.
@@ -21,7 +28,174 @@ func NewMarshaler(name, prefix, indent string) (*Marshaler, error) {
 	}, nil
 }
 
+// attributed exposes the attributes and the metadata of the nodes and edges of g as DOT attributes.
+// It hides the To method of g so g is marshaled as an undirected graph.
+type attributed struct {
+	graph.Graph
+}
+
+// Nodes returns the nodes of the graph.
+func (a attributed) Nodes() gonum.Nodes {
+	var nodes []gonum.Node
+	it := a.Graph.Nodes()
+	for it.Next() {
+		nodes = append(nodes, node{Node: it.Node().(graph.Node)})
+	}
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// Edge returns the edge from u to v.
+func (a attributed) Edge(uid, vid int64) gonum.Edge {
+	e := a.Graph.Edge(uid, vid)
+	if e == nil {
+		return nil
+	}
+	return edge{Edge: e.(graph.Edge)}
+}
+
+// directed is a directed graph.
+type directed struct {
+	attributed
+	g gonum.Directed
+}
+
+// HasEdgeFromTo returns whether an edge exists from u to v.
+func (d directed) HasEdgeFromTo(uid, vid int64) bool {
+	return d.g.HasEdgeFromTo(uid, vid)
+}
+
+// To returns the nodes that can reach directly to the node with the given ID.
+func (d directed) To(id int64) gonum.Nodes {
+	return d.g.To(id)
+}
+
+// multigraph exposes the lines of g to the multigraph marshaler.
+type multigraph struct {
+	attributed
+	lines func(uid, vid int64) gonum.Lines
+}
+
+// Lines returns the lines from u to v.
+func (m multigraph) Lines(uid, vid int64) gonum.Lines {
+	var lines []gonum.Line
+	it := m.lines(uid, vid)
+	for it.Next() {
+		l := it.Line()
+		if e, ok := l.(lineEdge); ok {
+			l = line{lineEdge: e}
+		}
+		lines = append(lines, l)
+	}
+	return iterator.NewOrderedLines(lines)
+}
+
+// directedMultigraph is a directed multigraph.
+type directedMultigraph struct {
+	multigraph
+	to func(id int64) gonum.Nodes
+}
+
+// HasEdgeFromTo returns whether a line exists from u to v.
+func (d directedMultigraph) HasEdgeFromTo(uid, vid int64) bool {
+	return d.Lines(uid, vid).Len() > 0
+}
+
+// To returns the nodes that can reach directly to the node with the given ID.
+func (d directedMultigraph) To(id int64) gonum.Nodes {
+	return d.to(id)
+}
+
+// node exposes node attributes and metadata as DOT attributes.
+type node struct {
+	graph.Node
+}
+
+// DOTID returns DOT ID of the node.
+func (n node) DOTID() string {
+	if d, ok := n.Node.(dot.Node); ok {
+		return d.DOTID()
+	}
+	return fmt.Sprint(n.ID())
+}
+
+// Attributes returns DOT attributes of the node.
+func (n node) Attributes() []encoding.Attribute {
+	return attributes(n.Attrs(), meta.Node(n.Node))
+}
+
+// edge exposes edge attributes and metadata as DOT attributes.
+type edge struct {
+	graph.Edge
+}
+
+// Attributes returns DOT attributes of the edge.
+func (e edge) Attributes() []encoding.Attribute {
+	return attributes(e.Attrs(), meta.Edge(e.Edge))
+}
+
+// lineEdge is multigraph edge.
+type lineEdge interface {
+	graph.Edge
+	gonum.Line
+}
+
+// line exposes line attributes and metadata as DOT attributes.
+type line struct {
+	lineEdge
+}
+
+// Attributes returns DOT attributes of the line.
+func (l line) Attributes() []encoding.Attribute {
+	return attributes(l.Attrs(), meta.Edge(l.lineEdge))
+}
+
+// attributes returns attributes a and metadata m as DOT attributes.
+// The metadata is omitted if it can not be encoded.
+func attributes(a map[string]interface{}, m meta.Meta) []encoding.Attribute {
+	strs, err := meta.Strings(a, m)
+	if err != nil {
+		strs, _ = meta.Strings(a, meta.Meta{})
+	}
+
+	attrs := make([]encoding.Attribute, len(strs))
+	for i, s := range strs {
+		attrs[i] = encoding.Attribute{Key: s.Key, Value: s.Value}
+	}
+	return attrs
+}
+
 // Marshal marshal g into DOT and returns it.
+// Multigraphs must implement gonum graph.Multigraph to have their
+// parallel edges marshaled, otherwise only a single edge is marshaled
+// between every pair of nodes. Node and edge attributes are marshaled
+// as DOT attributes along with their UIDs, labels, styles and weights.
 func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
-	return dot.Marshal(g, m.name, m.prefix, m.indent)
+	isDirected := graph.IsDirected(g.Type())
+	ag := attributed{Graph: g}
+
+	if mg, ok := g.(gonum.Multigraph); ok && graph.IsMulti(g.Type()) {
+		multi := multigraph{attributed: ag, lines: mg.Lines}
+		if dg, ok := g.(gonum.Directed); ok && isDirected {
+			return dot.MarshalMulti(directedMultigraph{multigraph: multi, to: dg.To}, m.name, m.prefix, m.indent)
+		}
+		return dot.MarshalMulti(multi, m.name, m.prefix, m.indent)
+	}
+
+	if dg, ok := g.(gonum.Directed); ok && isDirected {
+		return dot.Marshal(directed{attributed: ag, g: dg}, m.name, m.prefix, m.indent)
+	}
+
+	return dot.Marshal(ag, m.name, m.prefix, m.indent)
+}
+
+// MarshalTo marshals g into DOT written to w.
+// The graph is marshaled in memory first as gonum DOT encoder does not support writers.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	b, err := m.Marshal(g)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
 }
@@ -0,0 +1,92 @@
+package dot
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+func TestUnmarshal(t *testing.T) {
+	// Graphviz graph with attribute statements and edge chains
+	data := `graph G {
+  node [shape=box];
+  a [label="A", size=3, color="#ff0000"];
+  a -- b [weight=0.5];
+  b -- a -- c;
+}`
+
+	typ, err := GraphType([]byte(data))
+	if err != nil {
+		t.Fatalf("failed to get graph type: %v", err)
+	}
+
+	if typ != graph.WeightedUndirectedMulti {
+		t.Errorf("expected graph type: %s, got: %s", graph.WeightedUndirectedMulti, typ)
+	}
+
+	u, err := NewUnmarshaler()
+	if err != nil {
+		t.Fatalf("failed to create unmarshaler: %v", err)
+	}
+
+	g, err := memory.NewGraph(memory.WithType(graph.WeightedUndirected))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	if err := u.Unmarshal([]byte(data), g); err != nil {
+		t.Fatalf("failed to unmarshal graph: %v", err)
+	}
+
+	a, b := g.NodeWithUID("a"), g.NodeWithUID("b")
+	if a == nil || b == nil || g.NodeWithUID("c") == nil {
+		t.Fatalf("expected nodes a, b and c")
+	}
+
+	if size := a.Attrs()["size"]; size != "3" {
+		t.Errorf("expected size: 3, got: %v", size)
+	}
+
+	if c := a.(*memory.Node).Color(); c != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("expected color: red, got: %v", c)
+	}
+
+	if n := g.Edges().Len(); n != 2 {
+		t.Errorf("expected 2 edges, got: %d", n)
+	}
+
+	if w := g.Edge(a.ID(), b.ID()).(graph.Edge).Weight(); w != 0.5 {
+		t.Errorf("expected weight: 0.5, got: %v", w)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{"DOT", `digraph {`},
+		{"Graphs", `digraph {} digraph {}`},
+		{"Type", `graph {}`},
+		{"Meta", `digraph { a [orbnet=1]; }`},
+		{"Value", `digraph { a [orbnet="{\"types\":{\"n\":\"int\"}}", n=foo]; }`},
+	}
+
+	u, err := NewUnmarshaler()
+	if err != nil {
+		t.Fatalf("failed to create unmarshaler: %v", err)
+	}
+
+	for _, tc := range testCases {
+		g, err := memory.NewGraph()
+		if err != nil {
+			t.Fatalf("failed to create graph: %v", err)
+		}
+
+		if err := u.Unmarshal([]byte(tc.data), g); graph.ErrorCode(err) != graph.EINVALID {
+			t.Errorf("%s: expected error: %s, got: %v", tc.name, graph.EINVALID, err)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package dot
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/meta"
+	gonum "gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/encoding/dot"
+	ast "gonum.org/v1/gonum/graph/formats/dot"
+	"gonum.org/v1/gonum/graph/multi"
+)
+
+const (
+	// weightAttr is DOT edge weight attribute.
+	weightAttr = "weight"
+)
+
+// Unmarshaler implements graph.Unmarshaler.
+type Unmarshaler struct {
+	opts Options
+}
+
+// NewUnmarshaler creates a new Unmarshaler and returns it.
+func NewUnmarshaler(opts ...Option) (*Unmarshaler, error) {
+	uopts := Options{}
+	for _, apply := range opts {
+		apply(&uopts)
+	}
+
+	return &Unmarshaler{
+		opts: uopts,
+	}, nil
+}
+
+// Unmarshal unmarshals DOT data into g.
+// DOT node IDs are used as node UIDs unless the nodes were marshaled by Marshaler.
+// DOT attribute values are strings: they are converted to the types declared
+// in the schema or stored in the metadata. Global attributes are ignored.
+func (u *Unmarshaler) Unmarshal(data []byte, g graph.Graph) error {
+	directed, elems, err := decode(data)
+	if err != nil {
+		return err
+	}
+
+	if directed != graph.IsDirected(g.Type()) {
+		return graph.Errorf(graph.EINVALID, "graph type mismatch: directed: %t, expected: %s", directed, g.Type())
+	}
+
+	return meta.Build(g, elems.nodes(), elems.edges(), u.opts.Schema)
+}
+
+// GraphType returns the type of graph encoded in DOT data.
+func GraphType(data []byte) (string, error) {
+	directed, elems, err := decode(data)
+	if err != nil {
+		return "", err
+	}
+
+	return meta.GraphType(directed, elems.edges()), nil
+}
+
+// decode decodes DOT data and returns whether the graph is directed along with its elements.
+func decode(data []byte) (bool, *elements, error) {
+	file, err := ast.ParseBytes(data)
+	if err != nil {
+		return false, nil, graph.Errorf(graph.EINVALID, "invalid DOT: %v", err)
+	}
+
+	if len(file.Graphs) != 1 {
+		return false, nil, graph.Errorf(graph.EINVALID, "invalid DOT: expected 1 graph, got %d", len(file.Graphs))
+	}
+
+	directed := file.Graphs[0].Directed
+	elems := &elements{}
+
+	var b encoding.MultiBuilder = undirectedBuilder{UndirectedGraph: multi.NewUndirectedGraph(), elems: elems}
+	if directed {
+		b = directedBuilder{DirectedGraph: multi.NewDirectedGraph(), elems: elems}
+	}
+
+	if err := dot.UnmarshalMulti(data, b); err != nil {
+		return false, nil, graph.Errorf(graph.EINVALID, "invalid DOT: %v", err)
+	}
+
+	return directed, elems, nil
+}
+
+// elements records the nodes and lines decoded from DOT.
+type elements struct {
+	dotNodes []*dotNode
+	dotLines []*dotLine
+}
+
+// newNode returns a new DOT node with the ID of n.
+func (e *elements) newNode(n gonum.Node) gonum.Node {
+	return &dotNode{id: n.ID(), attrs: make(map[string]interface{})}
+}
+
+// addNode records n.
+func (e *elements) addNode(n gonum.Node) {
+	if dn, ok := n.(*dotNode); ok {
+		e.dotNodes = append(e.dotNodes, dn)
+	}
+}
+
+// newLine returns a new DOT line with the ID of l.
+func (e *elements) newLine(l gonum.Line) gonum.Line {
+	return &dotLine{
+		id:    l.ID(),
+		from:  l.From(),
+		to:    l.To(),
+		attrs: make(map[string]interface{}),
+	}
+}
+
+// addLine records l.
+func (e *elements) addLine(l gonum.Line) {
+	if dl, ok := l.(*dotLine); ok {
+		e.dotLines = append(e.dotLines, dl)
+	}
+}
+
+// nodes returns the recorded nodes ordered by their IDs.
+func (e *elements) nodes() []meta.Element {
+	sort.Slice(e.dotNodes, func(i, j int) bool { return e.dotNodes[i].id < e.dotNodes[j].id })
+
+	nodes := make([]meta.Element, len(e.dotNodes))
+	for i, n := range e.dotNodes {
+		nodes[i] = meta.Element{
+			ID:    n.dotid,
+			Attrs: n.attrs,
+		}
+	}
+	return nodes
+}
+
+// edges returns the recorded lines in the order they were decoded.
+// Lines are weighted by their weight attribute if it's a number.
+func (e *elements) edges() []meta.Element {
+	edges := make([]meta.Element, len(e.dotLines))
+	for i, l := range e.dotLines {
+		edges[i] = meta.Element{
+			ID:     fmt.Sprint(l.id),
+			Source: l.from.(*dotNode).dotid,
+			Target: l.to.(*dotNode).dotid,
+			Attrs:  l.attrs,
+		}
+		if v, ok := l.attrs[weightAttr].(string); ok {
+			if w, err := strconv.ParseFloat(v, 64); err == nil {
+				edges[i].Weight = &w
+			}
+		}
+	}
+	return edges
+}
+
+// directedBuilder builds directed DOT graphs.
+type directedBuilder struct {
+	*multi.DirectedGraph
+	elems *elements
+}
+
+// NewNode returns a new node.
+func (b directedBuilder) NewNode() gonum.Node {
+	return b.elems.newNode(b.DirectedGraph.NewNode())
+}
+
+// AddNode adds n to the graph.
+func (b directedBuilder) AddNode(n gonum.Node) {
+	b.DirectedGraph.AddNode(n)
+	b.elems.addNode(n)
+}
+
+// NewLine returns a new line from u to v.
+func (b directedBuilder) NewLine(from, to gonum.Node) gonum.Line {
+	return b.elems.newLine(b.DirectedGraph.NewLine(from, to))
+}
+
+// SetLine adds l to the graph.
+func (b directedBuilder) SetLine(l gonum.Line) {
+	b.DirectedGraph.SetLine(l)
+	b.elems.addLine(l)
+}
+
+// undirectedBuilder builds undirected DOT graphs.
+type undirectedBuilder struct {
+	*multi.UndirectedGraph
+	elems *elements
+}
+
+// NewNode returns a new node.
+func (b undirectedBuilder) NewNode() gonum.Node {
+	return b.elems.newNode(b.UndirectedGraph.NewNode())
+}
+
+// AddNode adds n to the graph.
+func (b undirectedBuilder) AddNode(n gonum.Node) {
+	b.UndirectedGraph.AddNode(n)
+	b.elems.addNode(n)
+}
+
+// NewLine returns a new line from u to v.
+func (b undirectedBuilder) NewLine(from, to gonum.Node) gonum.Line {
+	return b.elems.newLine(b.UndirectedGraph.NewLine(from, to))
+}
+
+// SetLine adds l to the graph.
+func (b undirectedBuilder) SetLine(l gonum.Line) {
+	b.UndirectedGraph.SetLine(l)
+	b.elems.addLine(l)
+}
+
+// dotNode is a decoded DOT node.
+type dotNode struct {
+	id    int64
+	dotid string
+	attrs map[string]interface{}
+}
+
+// ID returns node ID.
+func (n *dotNode) ID() int64 {
+	return n.id
+}
+
+// SetDOTID sets DOT ID.
+func (n *dotNode) SetDOTID(id string) {
+	n.dotid = id
+}
+
+// SetAttribute sets DOT attribute.
+func (n *dotNode) SetAttribute(a encoding.Attribute) error {
+	n.attrs[a.Key] = a.Value
+	return nil
+}
+
+// dotLine is a decoded DOT line.
+type dotLine struct {
+	id    int64
+	from  gonum.Node
+	to    gonum.Node
+	attrs map[string]interface{}
+}
+
+// ID returns line ID.
+func (l *dotLine) ID() int64 {
+	return l.id
+}
+
+// From returns the from node of the line.
+func (l *dotLine) From() gonum.Node {
+	return l.from
+}
+
+// To returns the to node of the line.
+func (l *dotLine) To() gonum.Node {
+	return l.to
+}
+
+// ReversedLine returns a new line with end points of the pair swapped.
+func (l *dotLine) ReversedLine() gonum.Line {
+	return &dotLine{id: l.id, from: l.to, to: l.from, attrs: l.attrs}
+}
+
+// SetAttribute sets DOT attribute.
+func (l *dotLine) SetAttribute(a encoding.Attribute) error {
+	l.attrs[a.Key] = a.Value
+	return nil
+}
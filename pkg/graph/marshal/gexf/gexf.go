@@ -3,8 +3,10 @@ package gexf
 import (
 	"bytes"
 	"encoding/xml"
+	"io"
 
 	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/meta"
 	"gonum.org/v1/gonum/graph/formats/gexf12"
 )
 
@@ -20,25 +22,49 @@ type Marshaler struct {
 	name   string
 	prefix string
 	indent string
+	opts   Options
 }
 
 // NewMarshaler creates a new graph marshaler and returns it.
-func NewMarshaler(name, prefix, indent string) (*Marshaler, error) {
+func NewMarshaler(name, prefix, indent string, opts ...Option) (*Marshaler, error) {
+	mopts := Options{}
+	for _, apply := range opts {
+		apply(&mopts)
+	}
+
 	return &Marshaler{
 		name:   name,
 		prefix: prefix,
 		indent: indent,
+		opts:   mopts,
 	}, nil
 }
 
 // Marshal marshals g into format that can be used by
 // Gephi https://gephi.org/
 // To learn more about Gexf see here: https://gephi.org/gexf/format/
+// The attributes which are not declared in the schema are declared with the types
+// inferred from their values. Node UIDs, labels, styles, edge weights and the attributes
+// whose keys are reserved are stored in the reserved attribute.
 func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
+	var b bytes.Buffer
+	if err := m.MarshalTo(&b, g); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo marshals g into GEXF document written to w.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	edgeType := "directed"
+	if !graph.IsDirected(g.Type()) {
+		edgeType = "undirected"
+	}
+
 	c := gexf12.Content{
 		Graph: gexf12.Graph{
 			TimeFormat:      "dateTime",
-			DefaultEdgeType: "directed",
+			DefaultEdgeType: edgeType,
 			Mode:            "dynamic",
 			Attributes: []gexf12.Attributes{
 				{
@@ -62,30 +88,59 @@ func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
 		Version: "1.2",
 	}
 
+	nodeKeys := newKeySet(m.opts.Schema.NodeKeys(), nameAttr)
 	nodes := g.Nodes()
+	for nodes.Next() {
+		nodeKeys.add(nodes.Node().(graph.Node).Attrs())
+	}
+
+	edgeKeys := newKeySet(m.opts.Schema.EdgeKeys(), relAttr)
+	edges := g.Edges()
+	for edges.Next() {
+		edgeKeys.add(edges.Edge().(graph.Edge).Attrs())
+	}
+
+	c.Graph.Attributes[0].Attributes = append(c.Graph.Attributes[0].Attributes, edgeKeys.attributes()...)
+	c.Graph.Attributes[1].Attributes = append(c.Graph.Attributes[1].Attributes, nodeKeys.attributes()...)
+
+	nodes = g.Nodes()
 	c.Graph.Nodes.Count = nodes.Len()
 	c.Graph.Nodes.Nodes = make([]gexf12.Node, 0, nodes.Len())
 	for nodes.Next() {
-		n := NewNode(nodes.Node().(graph.Node))
+		node := nodes.Node().(graph.Node)
+		n := NewNode(node)
+
+		md := meta.Node(node)
+		vals, err := nodeKeys.attValues(md.Reserve(node.Attrs(), nameAttr), md)
+		if err != nil {
+			return err
+		}
+		n.AttValues.AttValues = append(n.AttValues.AttValues, vals...)
 		c.Graph.Nodes.Nodes = append(c.Graph.Nodes.Nodes, *n)
 	}
 
-	edges := g.Edges()
+	edges = g.Edges()
 	i := 0
 	for edges.Next() {
-		e := NewEdge(i, edges.Edge().(graph.Edge))
+		edge := edges.Edge().(graph.Edge)
+		e := NewEdge(i, edge)
+
+		md := meta.Edge(edge)
+		vals, err := edgeKeys.attValues(md.Reserve(edge.Attrs(), relAttr), md)
+		if err != nil {
+			return err
+		}
+		if e.AttValues == nil {
+			e.AttValues = &gexf12.AttValues{}
+		}
+		e.AttValues.AttValues = append(e.AttValues.AttValues, vals...)
 		c.Graph.Edges.Edges = append(c.Graph.Edges.Edges, *e)
 		i++
 	}
 	c.Graph.Edges.Count = len(c.Graph.Edges.Edges)
 
-	var b bytes.Buffer
-	enc := xml.NewEncoder(&b)
+	enc := xml.NewEncoder(w)
 	enc.Indent(m.prefix, m.indent)
 
-	if err := enc.Encode(c); err != nil {
-		return nil, err
-	}
-
-	return b.Bytes(), nil
+	return enc.Encode(c)
 }
@@ -0,0 +1,160 @@
+package gexf
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/marshaltest"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	"gonum.org/v1/gonum/graph/formats/gexf12"
+)
+
+func TestUnmarshal(t *testing.T) {
+	// Gephi export
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://www.gexf.net/1.2draft" xmlns:viz="http://www.gexf.net/1.2draft/viz" version="1.2">
+  <graph defaultedgetype="undirected" mode="static">
+    <attributes class="node">
+      <attribute id="0" title="size" type="integer"/>
+    </attributes>
+    <nodes>
+      <node id="a" label="A">
+        <attvalues><attvalue for="0" value="3"/></attvalues>
+        <viz:color r="255" g="0" b="0"/>
+        <viz:position x="1" y="2" z="0"/>
+      </node>
+      <node id="b" label="B"/>
+    </nodes>
+    <edges>
+      <edge id="0" source="a" target="b" weight="0.5"/>
+      <edge id="1" source="b" target="a"/>
+    </edges>
+  </graph>
+</gexf>`
+
+	typ, err := GraphType([]byte(data))
+	if err != nil {
+		t.Fatalf("failed to get graph type: %v", err)
+	}
+
+	if typ != graph.WeightedUndirectedMulti {
+		t.Errorf("expected graph type: %s, got: %s", graph.WeightedUndirectedMulti, typ)
+	}
+
+	u, err := NewUnmarshaler()
+	if err != nil {
+		t.Fatalf("failed to create unmarshaler: %v", err)
+	}
+
+	g, err := memory.NewGraph(memory.WithType(graph.WeightedUndirected))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	if err := u.Unmarshal([]byte(data), g); err != nil {
+		t.Fatalf("failed to unmarshal graph: %v", err)
+	}
+
+	a, b := g.NodeWithUID("a"), g.NodeWithUID("b")
+	if a == nil || b == nil {
+		t.Fatalf("expected nodes a and b")
+	}
+
+	if size := a.Attrs()["size"]; size != int64(3) {
+		t.Errorf("expected size: 3, got: %v", size)
+	}
+
+	if l := a.Attrs()["label"]; l != "A" {
+		t.Errorf("expected label attribute: A, got: %v", l)
+	}
+
+	if c := a.(*memory.Node).Color(); c != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("expected color: red, got: %v", c)
+	}
+
+	if x, y := a.Attrs()["x"], a.Attrs()["y"]; x != 1.0 || y != 2.0 {
+		t.Errorf("expected position: 1 2, got: %v %v", x, y)
+	}
+
+	if n := g.Edges().Len(); n != 1 {
+		t.Errorf("expected 1 edge, got: %d", n)
+	}
+
+	if w := g.Edge(a.ID(), b.ID()).(graph.Edge).Weight(); w != 0.5 {
+		t.Errorf("expected weight: 0.5, got: %v", w)
+	}
+}
+
+func TestMarshalPosition(t *testing.T) {
+	g := marshaltest.Graph(t, graph.WeightedDirected)
+	g.NodeWithUID("repo").Attrs()["x"] = 1.5
+	g.NodeWithUID("repo").Attrs()["y"] = -2.0
+
+	m, err := NewMarshaler("test", "", "")
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	data, err := m.Marshal(g)
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	var c gexf12.Content
+	if err := xml.Unmarshal(data, &c); err != nil {
+		t.Fatalf("failed to decode GEXF: %v", err)
+	}
+
+	repo := fmt.Sprint(g.NodeWithUID("repo").ID())
+	for _, n := range c.Graph.Nodes.Nodes {
+		p := n.Position
+		switch n.ID {
+		case repo:
+			if p == nil || p.X != 1.5 || p.Y != -2.0 {
+				t.Errorf("expected position: {1.5 -2}, got: %v", p)
+			}
+		default:
+			if p != nil {
+				t.Errorf("node %s: expected no position, got: %v", n.ID, p)
+			}
+		}
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	const (
+		header = `<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2">`
+		footer = `</gexf>`
+	)
+
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{"XML", `<gexf`},
+		{"Type", header + `<graph defaultedgetype="undirected"/>` + footer},
+		{"Node", header + `<graph><nodes><node id="a"/><node id="a"/></nodes></graph>` + footer},
+		{"Edge", header + `<graph><nodes><node id="a"/></nodes><edges><edge id="0" source="a" target="b"/></edges></graph>` + footer},
+		{"Value", header + `<graph><attributes class="node"><attribute id="0" title="n" type="integer"/></attributes>` +
+			`<nodes><node id="a"><attvalues><attvalue for="0" value="foo"/></attvalues></node></nodes></graph>` + footer},
+	}
+
+	u, err := NewUnmarshaler()
+	if err != nil {
+		t.Fatalf("failed to create unmarshaler: %v", err)
+	}
+
+	for _, tc := range testCases {
+		g, err := memory.NewGraph()
+		if err != nil {
+			t.Fatalf("failed to create graph: %v", err)
+		}
+
+		if err := u.Unmarshal([]byte(tc.data), g); graph.ErrorCode(err) != graph.EINVALID {
+			t.Errorf("%s: expected error: %s, got: %v", tc.name, graph.EINVALID, err)
+		}
+	}
+}
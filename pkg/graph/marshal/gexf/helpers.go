@@ -2,9 +2,12 @@ package gexf
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/milosgajdos/orbnet/pkg/graph"
 	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/layout"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/meta"
 	"gonum.org/v1/gonum/graph/formats/gexf12"
 )
 
@@ -27,6 +30,10 @@ func NewNode(n graph.Node) *gexf12.Node {
 		}
 	}
 
+	if p, ok := layout.Position(n); ok {
+		node.Position = &gexf12.Position{X: p.X, Y: p.Y}
+	}
+
 	a := n.Attrs()
 
 	name := n.UID()
@@ -59,6 +66,7 @@ func NewEdge(id int, e graph.Edge) *gexf12.Edge {
 		ID:     fmt.Sprint(id),
 		Source: fmt.Sprint(e.From().ID()),
 		Target: fmt.Sprint(e.To().ID()),
+		Weight: e.Weight(),
 	}
 
 	a := e.Attrs()
@@ -84,3 +92,114 @@ func NewEdge(id int, e graph.Edge) *gexf12.Edge {
 
 	return edge
 }
+
+// attrType returns gexf attribute type for the given attribute type.
+func attrType(t attrs.Type) string {
+	switch t {
+	case attrs.Int:
+		return "long"
+	case attrs.Float:
+		return "double"
+	case attrs.Bool:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// keySet collects the keys of node or edge attributes and their types.
+type keySet struct {
+	schema   attrs.Schema
+	reserved string
+	types    map[string]attrs.Type
+}
+
+// newKeySet creates a new keySet which contains the keys declared in schema s.
+// The reserved attribute is not collected.
+func newKeySet(s attrs.Schema, reserved string) *keySet {
+	ks := &keySet{
+		schema:   s,
+		reserved: reserved,
+		types:    make(map[string]attrs.Type),
+	}
+
+	for k, t := range s {
+		if k != reserved {
+			ks.types[k] = t
+		}
+	}
+
+	return ks
+}
+
+// add adds the keys of attributes a to the key set.
+// The types of keys which are not declared in the schema are inferred from their
+// values; if the values of the same key are of different types its type is String.
+func (ks *keySet) add(a map[string]interface{}) {
+	for k, v := range a {
+		if v == nil || k == ks.reserved || k == meta.Key {
+			continue
+		}
+
+		if _, ok := ks.schema[k]; ok {
+			continue
+		}
+
+		t := attrs.TypeOf(v)
+		if kt, ok := ks.types[k]; ok && kt != t {
+			t = attrs.String
+		}
+		ks.types[k] = t
+	}
+}
+
+// keys returns the keys ordered by their names.
+func (ks *keySet) keys() []string {
+	keys := make([]string, 0, len(ks.types))
+	for k := range ks.types {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// attributes returns gexf attribute declarations of the keys and of the metadata attribute.
+func (ks *keySet) attributes() []gexf12.Attribute {
+	var atts []gexf12.Attribute
+	for _, k := range ks.keys() {
+		atts = append(atts, gexf12.Attribute{
+			ID:    k,
+			Title: k,
+			Type:  attrType(ks.types[k]),
+		})
+	}
+	return append(atts, gexf12.Attribute{
+		ID:    meta.Key,
+		Title: meta.Key,
+		Type:  "string",
+	})
+}
+
+// attValues returns gexf attribute values of attributes a and of metadata m.
+// The types of attribute values are stored in the metadata.
+func (ks *keySet) attValues(a map[string]interface{}, m meta.Meta) ([]gexf12.AttValue, error) {
+	var vals []gexf12.AttValue
+	for _, k := range ks.keys() {
+		v, ok := a[k]
+		if !ok || v == nil {
+			continue
+		}
+		m.SetType(k, v)
+		vals = append(vals, gexf12.AttValue{
+			For:   k,
+			Value: meta.Format(attrs.TypeOf(v), v),
+		})
+	}
+
+	data, err := m.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(vals, gexf12.AttValue{For: meta.Key, Value: data}), nil
+}
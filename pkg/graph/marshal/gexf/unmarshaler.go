@@ -0,0 +1,221 @@
+package gexf
+
+import (
+	"encoding/xml"
+	"image/color"
+	"math"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/layout"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/meta"
+	"github.com/milosgajdos/orbnet/pkg/graph/style"
+	"gonum.org/v1/gonum/graph/formats/gexf12"
+)
+
+const (
+	// labelAttr stores the labels of nodes which were not marshaled by Marshaler.
+	labelAttr = "label"
+)
+
+// Unmarshaler implements graph.Unmarshaler.
+type Unmarshaler struct {
+	opts Options
+}
+
+// NewUnmarshaler creates a new Unmarshaler and returns it.
+func NewUnmarshaler(opts ...Option) (*Unmarshaler, error) {
+	uopts := Options{}
+	for _, apply := range opts {
+		apply(&uopts)
+	}
+
+	return &Unmarshaler{
+		opts: uopts,
+	}, nil
+}
+
+// Unmarshal unmarshals GEXF data into g.
+// It accepts the documents created by Marshaler as well as the graphs exported by Gephi.
+// The attribute values are converted to the types declared in the schema or in GEXF.
+// The nodes which were not marshaled by Marshaler use their IDs as UIDs, their labels
+// are stored in the label attribute and their colors are used as their style colors.
+// Node viz positions are stored in the x and y attributes unless the nodes have them.
+func (u *Unmarshaler) Unmarshal(data []byte, g graph.Graph) error {
+	c, err := decode(data)
+	if err != nil {
+		return err
+	}
+
+	if d := c.Graph.DefaultEdgeType; d != "" && d != "mutual" && (d == "directed") != graph.IsDirected(g.Type()) {
+		return graph.Errorf(graph.EINVALID, "graph type mismatch: %s, expected: %s", d, g.Type())
+	}
+
+	decls := map[string]map[string]gexf12.Attribute{
+		"node": make(map[string]gexf12.Attribute),
+		"edge": make(map[string]gexf12.Attribute),
+	}
+	for _, atts := range c.Graph.Attributes {
+		if _, ok := decls[atts.Class]; !ok {
+			continue
+		}
+		for _, att := range atts.Attributes {
+			decls[atts.Class][att.ID] = att
+		}
+	}
+
+	nodes := make([]meta.Element, len(c.Graph.Nodes.Nodes))
+	for i, n := range c.Graph.Nodes.Nodes {
+		a, err := values(decls["node"], n.AttValues, nameAttr)
+		if err != nil {
+			return graph.Errorf(graph.EINVALID, "node %s: %v", n.ID, err)
+		}
+
+		if _, ok := a[meta.Key]; !ok && n.Label != "" {
+			a[labelAttr] = n.Label
+		}
+
+		if p := n.Position; p != nil {
+			setPosition(a, p.X, p.Y)
+		}
+
+		nodes[i] = meta.Element{
+			ID:    n.ID,
+			Style: nodeStyle(n.Color),
+			Attrs: a,
+		}
+	}
+
+	edges := make([]meta.Element, len(c.Graph.Edges.Edges))
+	for i, e := range c.Graph.Edges.Edges {
+		a, err := values(decls["edge"], e.AttValues, relAttr)
+		if err != nil {
+			return graph.Errorf(graph.EINVALID, "edge %s: %v", e.ID, err)
+		}
+
+		el := meta.Element{
+			ID:     e.ID,
+			Source: e.Source,
+			Target: e.Target,
+			Label:  e.Label,
+			Attrs:  a,
+		}
+		if e.Weight != 0 {
+			w := e.Weight
+			el.Weight = &w
+		}
+
+		edges[i] = el
+	}
+
+	return meta.Build(g, nodes, edges, u.opts.Schema)
+}
+
+// GraphType returns the type of graph encoded in GEXF data.
+// Graphs whose default edge type is not declared are undirected.
+func GraphType(data []byte) (string, error) {
+	c, err := decode(data)
+	if err != nil {
+		return "", err
+	}
+
+	edges := make([]meta.Element, len(c.Graph.Edges.Edges))
+	for i, e := range c.Graph.Edges.Edges {
+		edges[i] = meta.Element{
+			ID:     e.ID,
+			Source: e.Source,
+			Target: e.Target,
+		}
+	}
+
+	return meta.GraphType(c.Graph.DefaultEdgeType == "directed", edges), nil
+}
+
+// decode decodes GEXF data.
+func decode(data []byte) (*gexf12.Content, error) {
+	var c gexf12.Content
+	if err := xml.Unmarshal(data, &c); err != nil {
+		return nil, graph.Errorf(graph.EINVALID, "invalid GEXF: %v", err)
+	}
+	return &c, nil
+}
+
+// values returns the attribute values converted to their declared types.
+// The values of undeclared attributes are strings. The value of reserved
+// attribute is omitted if the attributes contain the metadata.
+func values(decls map[string]gexf12.Attribute, vals *gexf12.AttValues, reserved string) (map[string]interface{}, error) {
+	a := make(map[string]interface{})
+	if vals == nil {
+		return a, nil
+	}
+
+	for _, val := range vals.AttValues {
+		d, ok := decls[val.For]
+		if !ok {
+			a[val.For] = val.Value
+			continue
+		}
+
+		name := d.Title
+		if name == "" {
+			name = d.ID
+		}
+
+		v, err := attrs.Coerce(valueType(d.Type), val.Value)
+		if err != nil {
+			return nil, graph.Errorf(graph.EINVALID, "attribute %q: %v", name, err)
+		}
+		a[name] = v
+	}
+
+	if _, ok := a[meta.Key]; ok {
+		delete(a, reserved)
+	}
+
+	return a, nil
+}
+
+// valueType returns attribute type of gexf attribute type t.
+func valueType(t string) attrs.Type {
+	switch t {
+	case "integer", "long":
+		return attrs.Int
+	case "float", "double":
+		return attrs.Float
+	case "boolean":
+		return attrs.Bool
+	default:
+		return attrs.String
+	}
+}
+
+// setPosition stores the position of node in its x and y attributes
+// unless the attributes already contain either of them.
+func setPosition(a map[string]interface{}, x, y float64) {
+	if _, ok := a[layout.XAttr]; ok {
+		return
+	}
+	if _, ok := a[layout.YAttr]; ok {
+		return
+	}
+	a[layout.XAttr] = x
+	a[layout.YAttr] = y
+}
+
+// nodeStyle returns the style of nodes whose color is c.
+// It returns nil if c is nil.
+func nodeStyle(c *gexf12.Color) *style.Style {
+	if c == nil {
+		return nil
+	}
+
+	alpha := uint8(0xff)
+	if c.A > 0 && c.A < 1 {
+		alpha = uint8(math.Round(c.A * 0xff))
+	}
+
+	s := style.DefaultNode()
+	s.Color = color.RGBA{R: c.R, G: c.G, B: c.B, A: alpha}
+
+	return &s
+}
@@ -0,0 +1,182 @@
+package graphml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/meta"
+)
+
+const (
+	// Namespace is GraphML XML namespace.
+	Namespace = "http://graphml.graphdrawing.org/xmlns"
+)
+
+// reserved keys store the graph properties which are not attributes.
+const (
+	labelKey      = "label"
+	weightKey     = "weight"
+	styleTypeKey  = "style.type"
+	styleShapeKey = "style.shape"
+	styleColorKey = "style.color"
+)
+
+const (
+	// attrPrefix prefixes the names of the keys of attributes named as reserved keys.
+	attrPrefix = "attr."
+)
+
+// isReserved returns true if the key with the given id stores
+// graph element properties rather than its attributes.
+func isReserved(id string) bool {
+	switch id {
+	case labelKey, weightKey, styleTypeKey, styleShapeKey, styleColorKey:
+		return true
+	}
+	return false
+}
+
+// keyName returns the name of the key of attribute name.
+// Attributes named as reserved keys are prefixed so their key names are unique.
+func keyName(name string) string {
+	if isReserved(name) {
+		return attrPrefix + name
+	}
+	return name
+}
+
+// attrName returns the name of the attribute stored in the key with the given name.
+func attrName(name string) string {
+	if n := strings.TrimPrefix(name, attrPrefix); n != name && isReserved(n) {
+		return n
+	}
+	return name
+}
+
+// GraphML is GraphML document.
+// To learn more about GraphML see here: http://graphml.graphdrawing.org/
+type GraphML struct {
+	XMLName xml.Name `xml:"graphml"`
+	XMLNS   string   `xml:"xmlns,attr,omitempty"`
+	Keys    []Key    `xml:"key"`
+	Graph   Graph    `xml:"graph"`
+}
+
+// Key declares data key.
+// Desc of the keys of attributes whose types GraphML does
+// not support stores the attribute type e.g. time or list.
+type Key struct {
+	ID      string  `xml:"id,attr"`
+	For     string  `xml:"for,attr"`
+	Name    string  `xml:"attr.name,attr,omitempty"`
+	Type    string  `xml:"attr.type,attr,omitempty"`
+	Desc    string  `xml:"desc,omitempty"`
+	Default *string `xml:"default,omitempty"`
+}
+
+// Graph is GraphML graph.
+type Graph struct {
+	ID          string `xml:"id,attr,omitempty"`
+	EdgeDefault string `xml:"edgedefault,attr"`
+	Desc        string `xml:"desc,omitempty"`
+	Data        []Data `xml:"data"`
+	Nodes       []Node `xml:"node"`
+	Edges       []Edge `xml:"edge"`
+}
+
+// Node is GraphML node.
+type Node struct {
+	ID   string `xml:"id,attr"`
+	Data []Data `xml:"data"`
+}
+
+// Edge is GraphML edge.
+type Edge struct {
+	ID     string `xml:"id,attr,omitempty"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+	Data   []Data `xml:"data"`
+}
+
+// Data is a value of the declared key.
+type Data struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// keyType returns GraphML key type for the given attribute type.
+// Attribute types GraphML does not support are encoded as strings.
+func keyType(t attrs.Type) string {
+	switch t {
+	case attrs.Int:
+		return "long"
+	case attrs.Float:
+		return "double"
+	case attrs.Bool:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// keyDesc returns GraphML key description for the given attribute type.
+func keyDesc(t attrs.Type) string {
+	switch t {
+	case attrs.Time, attrs.Color, attrs.List, attrs.Map:
+		return string(t)
+	}
+	return ""
+}
+
+// attrType returns attribute type of the given GraphML key.
+func attrType(k Key) attrs.Type {
+	if t := attrs.Type(k.Desc); t.IsValid() {
+		return t
+	}
+
+	switch k.Type {
+	case "int", "long":
+		return attrs.Int
+	case "float", "double":
+		return attrs.Float
+	case "boolean":
+		return attrs.Bool
+	default:
+		return attrs.String
+	}
+}
+
+// format returns string representation of v of attribute type t.
+// Colors which are not opaque are encoded along with their alpha channel.
+func format(t attrs.Type, v interface{}) string {
+	if t == attrs.Color {
+		if c, err := attrs.Coerce(attrs.Color, v); err == nil && c.(color.RGBA).A != 0xff {
+			return attrs.Format(t, c) + fmt.Sprintf("%02x", c.(color.RGBA).A)
+		}
+	}
+	return attrs.Format(t, v)
+}
+
+// GraphType returns the type of the graph encoded in GraphML data.
+// Graphs with parallel edges are multigraphs. Self-loops are ignored.
+func GraphType(data []byte) (string, error) {
+	var doc GraphML
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return "", graph.Errorf(graph.EINVALID, "invalid GraphML: %v", err)
+	}
+
+	edges := make([]meta.Element, len(doc.Graph.Edges))
+	for i, e := range doc.Graph.Edges {
+		edges[i] = meta.Element{
+			ID:     e.ID,
+			Source: e.Source,
+			Target: e.Target,
+		}
+	}
+
+	return meta.GraphType(doc.Graph.EdgeDefault != "undirected", edges), nil
+}
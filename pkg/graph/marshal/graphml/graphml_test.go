@@ -0,0 +1,179 @@
+package graphml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/marshaltest"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+func TestMarshalKeys(t *testing.T) {
+	m, err := NewMarshaler("stars", "", "  ", WithSchema(marshaltest.Schema(t)))
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	data, err := m.Marshal(marshaltest.Graph(t, graph.WeightedDirected))
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	for _, key := range []string{
+		`<key id="n6" for="node" attr.name="stars" attr.type="long"></key>`,
+		`<key id="n4" for="node" attr.name="score" attr.type="double"></key>`,
+		`<key id="n0" for="node" attr.name="fork" attr.type="boolean"></key>`,
+		`<key id="n5" for="node" attr.name="starred_at" attr.type="string">`,
+		`<desc>time</desc>`,
+		`<key id="e2" for="edge" attr.name="attr.weight" attr.type="double"></key>`,
+	} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("expected key %s in:\n%s", key, data)
+		}
+	}
+
+	if n := strings.Count(string(data), `attr.name="weight"`); n != 1 {
+		t.Errorf("expected 1 weight key, got: %d", n)
+	}
+}
+
+func TestMarshalKeyTypes(t *testing.T) {
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	for _, a := range []map[string]interface{}{
+		{"size": int64(1), "name": "foo"},
+		{"size": 1.5, "name": true},
+	} {
+		if _, err := memory.AddNewNode(g, memory.WithAttrs(a)); err != nil {
+			t.Fatalf("failed to add node: %v", err)
+		}
+	}
+
+	m, err := NewMarshaler("test", "", "  ")
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	data, err := m.Marshal(g)
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	// numeric attributes are widened to double and the others to string
+	for _, key := range []string{
+		`<key id="n0" for="node" attr.name="name" attr.type="string"></key>`,
+		`<key id="n1" for="node" attr.name="size" attr.type="double"></key>`,
+	} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("expected key %s in:\n%s", key, data)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	// NetworkX style GraphML with generic key IDs and no orbnet keys
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="size" attr.type="int"><default>1</default></key>
+  <key id="d1" for="edge" attr.name="weight" attr.type="double"/>
+  <graph edgedefault="undirected">
+    <node id="a"><data key="d0">3</data></node>
+    <node id="b"/>
+    <edge source="a" target="b"><data key="d1">0.5</data></edge>
+  </graph>
+</graphml>`
+
+	u, err := NewUnmarshaler()
+	if err != nil {
+		t.Fatalf("failed to create unmarshaler: %v", err)
+	}
+
+	g, err := memory.NewGraph(memory.WithType(graph.WeightedUndirected))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	if err := u.Unmarshal([]byte(data), g); err != nil {
+		t.Fatalf("failed to unmarshal graph: %v", err)
+	}
+
+	a, b := g.NodeWithUID("a"), g.NodeWithUID("b")
+	if a == nil || b == nil {
+		t.Fatalf("expected nodes a and b")
+	}
+
+	if size := a.Attrs()["size"]; size != int64(3) {
+		t.Errorf("expected size: 3, got: %v", size)
+	}
+
+	if size := b.Attrs()["size"]; size != int64(1) {
+		t.Errorf("expected default size: 1, got: %v", size)
+	}
+
+	if w := g.Edge(a.ID(), b.ID()).(graph.Edge).Weight(); w != 0.5 {
+		t.Errorf("expected weight: 0.5, got: %v", w)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{"XML", `<graphml`},
+		{"Type", `<graphml><graph edgedefault="undirected"/></graphml>`},
+		{"Node", `<graphml><graph edgedefault="directed"><node id="a"/><node id="a"/></graph></graphml>`},
+		{"Edge", `<graphml><graph edgedefault="directed"><node id="a"/><edge source="a" target="b"/></graph></graphml>`},
+		{"Key", `<graphml><graph edgedefault="directed"><node id="a"><data key="d0">1</data></node></graph></graphml>`},
+		{"Value", `<graphml><key id="d0" for="node" attr.name="n" attr.type="int"/><graph edgedefault="directed"><node id="a"><data key="d0">foo</data></node></graph></graphml>`},
+	}
+
+	u, err := NewUnmarshaler()
+	if err != nil {
+		t.Fatalf("failed to create unmarshaler: %v", err)
+	}
+
+	for _, tc := range testCases {
+		g, err := memory.NewGraph()
+		if err != nil {
+			t.Fatalf("failed to create graph: %v", err)
+		}
+
+		if err := u.Unmarshal([]byte(tc.data), g); graph.ErrorCode(err) != graph.EINVALID {
+			t.Errorf("%s: expected error: %s, got: %v", tc.name, graph.EINVALID, err)
+		}
+	}
+}
+
+func TestGraphType(t *testing.T) {
+	testCases := []struct {
+		data string
+		exp  string
+	}{
+		{`<graphml><graph edgedefault="directed"/></graphml>`, graph.WeightedDirected},
+		{`<graphml><graph edgedefault="undirected"/></graphml>`, graph.WeightedUndirected},
+		{`<graphml><graph edgedefault="directed"><edge source="a" target="b"/><edge source="b" target="a"/></graph></graphml>`, graph.WeightedDirected},
+		{`<graphml><graph edgedefault="directed"><edge source="a" target="b"/><edge source="a" target="b"/></graph></graphml>`, graph.WeightedDirectedMulti},
+		{`<graphml><graph edgedefault="undirected"><edge source="a" target="b"/><edge source="b" target="a"/></graph></graphml>`, graph.WeightedUndirectedMulti},
+		{`<graphml><graph edgedefault="directed"><edge source="a" target="a"/><edge source="a" target="a"/></graph></graphml>`, graph.WeightedDirected},
+	}
+
+	for _, tc := range testCases {
+		typ, err := GraphType([]byte(tc.data))
+		if err != nil {
+			t.Fatalf("failed to get graph type: %v", err)
+		}
+
+		if typ != tc.exp {
+			t.Errorf("%s: expected type: %s, got: %s", tc.data, tc.exp, typ)
+		}
+	}
+
+	if _, err := GraphType([]byte(`<graphml`)); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
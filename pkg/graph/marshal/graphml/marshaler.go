@@ -0,0 +1,220 @@
+package graphml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/columns"
+)
+
+// edgeStyler is implemented by styled edges.
+type edgeStyler interface {
+	Style() string
+	Shape() string
+	Color() color.RGBA
+}
+
+// Marshaler is used for marshaling graphs.
+type Marshaler struct {
+	name   string
+	prefix string
+	indent string
+	opts   Options
+}
+
+// NewMarshaler creates a new graph marshaler and returns it.
+func NewMarshaler(name, prefix, indent string, opts ...Option) (*Marshaler, error) {
+	mopts := Options{}
+	for _, apply := range opts {
+		apply(&mopts)
+	}
+
+	return &Marshaler{
+		name:   name,
+		prefix: prefix,
+		indent: indent,
+		opts:   mopts,
+	}, nil
+}
+
+// Marshal marshals g into GraphML which can be read by yEd, igraph, Gephi or NetworkX.
+// Node and edge UIDs are used as GraphML IDs and their labels, weights and styles are
+// stored as data of the reserved keys. The keys of attributes named as reserved keys
+// are named with the attr. prefix. The types of attribute keys are taken from the
+// schema and inferred from the attribute values for the keys the schema does not declare.
+func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
+	var b bytes.Buffer
+	if err := m.MarshalTo(&b, g); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo marshals g into GraphML document written to w.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	edgeDefault := "directed"
+	if !graph.IsDirected(g.Type()) {
+		edgeDefault = "undirected"
+	}
+
+	graphKeys := newKeySet("g", nil)
+	graphKeys.Add(g.Attrs())
+
+	nodeKeys := newKeySet("n", m.opts.Schema.NodeKeys())
+	nodes := g.Nodes()
+	for nodes.Next() {
+		nodeKeys.Add(nodes.Node().(graph.Node).Attrs())
+	}
+
+	edgeKeys := newKeySet("e", m.opts.Schema.EdgeKeys())
+	edges := g.Edges()
+	for edges.Next() {
+		edgeKeys.Add(edges.Edge().(graph.Edge).Attrs())
+	}
+
+	doc := GraphML{
+		XMLNS: Namespace,
+		Keys: []Key{
+			{ID: labelKey, For: "all", Name: labelKey, Type: "string"},
+			{ID: weightKey, For: "edge", Name: weightKey, Type: "double"},
+			{ID: styleTypeKey, For: "all", Name: styleTypeKey, Type: "string"},
+			{ID: styleShapeKey, For: "all", Name: styleShapeKey, Type: "string"},
+			{ID: styleColorKey, For: "all", Name: styleColorKey, Type: "string"},
+		},
+		Graph: Graph{
+			ID:          g.UID(),
+			EdgeDefault: edgeDefault,
+			Desc:        m.name,
+		},
+	}
+
+	doc.Keys = append(doc.Keys, graphKeys.keys("graph")...)
+	doc.Keys = append(doc.Keys, nodeKeys.keys("node")...)
+	doc.Keys = append(doc.Keys, edgeKeys.keys("edge")...)
+
+	if l := g.Label(); l != "" {
+		doc.Graph.Data = append(doc.Graph.Data, Data{Key: labelKey, Value: l})
+	}
+	doc.Graph.Data = append(doc.Graph.Data, graphKeys.data(g.Attrs())...)
+
+	nodes = g.Nodes()
+	doc.Graph.Nodes = make([]Node, 0, nodes.Len())
+	for nodes.Next() {
+		n := nodes.Node().(graph.Node)
+
+		node := Node{ID: n.UID()}
+		if l := n.Label(); l != "" {
+			node.Data = append(node.Data, Data{Key: labelKey, Value: l})
+		}
+		if s, ok := n.(graph.Styler); ok {
+			node.Data = append(node.Data, styleData(s.Type(), s.Shape(), s.Color())...)
+		}
+		node.Data = append(node.Data, nodeKeys.data(n.Attrs())...)
+
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+	}
+
+	edges = g.Edges()
+	doc.Graph.Edges = make([]Edge, 0, edges.Len())
+	for edges.Next() {
+		e := edges.Edge().(graph.Edge)
+
+		edge := Edge{
+			ID:     e.UID(),
+			Source: e.From().(graph.Node).UID(),
+			Target: e.To().(graph.Node).UID(),
+		}
+		if l := e.Label(); l != "" {
+			edge.Data = append(edge.Data, Data{Key: labelKey, Value: l})
+		}
+		edge.Data = append(edge.Data, Data{Key: weightKey, Value: attrs.Format(attrs.Float, e.Weight())})
+		if s, ok := e.(edgeStyler); ok {
+			edge.Data = append(edge.Data, styleData(s.Style(), s.Shape(), s.Color())...)
+		}
+		edge.Data = append(edge.Data, edgeKeys.data(e.Attrs())...)
+
+		doc.Graph.Edges = append(doc.Graph.Edges, edge)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent(m.prefix, m.indent)
+
+	return enc.Encode(doc)
+}
+
+// styleData returns the data of style keys.
+func styleData(typ, shape string, c color.RGBA) []Data {
+	return []Data{
+		{Key: styleTypeKey, Value: typ},
+		{Key: styleShapeKey, Value: shape},
+		{Key: styleColorKey, Value: format(attrs.Color, c)},
+	}
+}
+
+// keySet collects the attribute keys of graph elements along with their types.
+type keySet struct {
+	*columns.Columns
+	prefix string
+}
+
+// newKeySet creates a new key set with the given key ID prefix.
+// The types of the attributes declared in schema s are not inferred.
+func newKeySet(prefix string, s attrs.Schema) *keySet {
+	return &keySet{
+		Columns: columns.New(s),
+		prefix:  prefix,
+	}
+}
+
+// keys returns the key declarations for the given GraphML element sorted by attribute names.
+func (k *keySet) keys(domain string) []Key {
+	k.Sort()
+
+	keys := make([]Key, len(k.Names()))
+	for i, name := range k.Names() {
+		keys[i] = Key{
+			ID:   k.id(i),
+			For:  domain,
+			Name: keyName(name),
+			Type: keyType(k.Type(name)),
+			Desc: keyDesc(k.Type(name)),
+		}
+	}
+
+	return keys
+}
+
+// data returns the data of attributes a ordered by their key IDs.
+// It must be called after the keys have been declared.
+func (k *keySet) data(a map[string]interface{}) []Data {
+	var data []Data
+	for i, name := range k.Names() {
+		v, ok := a[name]
+		if !ok || v == nil {
+			continue
+		}
+
+		t := k.Type(name)
+		if t == attrs.String {
+			t = attrs.TypeOf(v)
+		}
+
+		data = append(data, Data{Key: k.id(i), Value: format(t, v)})
+	}
+
+	return data
+}
+
+// id returns the ID of i-th key.
+func (k *keySet) id(i int) string {
+	return fmt.Sprintf("%s%d", k.prefix, i)
+}
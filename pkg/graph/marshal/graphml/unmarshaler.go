@@ -0,0 +1,246 @@
+package graphml
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"image/color"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/meta"
+	"github.com/milosgajdos/orbnet/pkg/graph/style"
+)
+
+// Unmarshaler implements graph.Unmarshaler.
+type Unmarshaler struct {
+	opts Options
+}
+
+// NewUnmarshaler creates a new Unmarshaler and returns it.
+func NewUnmarshaler(opts ...Option) (*Unmarshaler, error) {
+	uopts := Options{}
+	for _, apply := range opts {
+		apply(&uopts)
+	}
+
+	return &Unmarshaler{
+		opts: uopts,
+	}, nil
+}
+
+// Unmarshal unmarshals GraphML data into g.
+// GraphML IDs are used as node and edge UIDs. The attribute values are converted to the types
+// declared in the schema or to the types of their keys if the schema does not declare them.
+// Edges without the weight key data are weighted by their weight attribute if it's a number.
+// Self-loops are skipped.
+func (u *Unmarshaler) Unmarshal(data []byte, g graph.Graph) error {
+	var doc GraphML
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return graph.Errorf(graph.EINVALID, "invalid GraphML: %v", err)
+	}
+
+	if d := doc.Graph.EdgeDefault; d != "" && (d == "directed") != graph.IsDirected(g.Type()) {
+		return graph.Errorf(graph.EINVALID, "graph type mismatch: %s, expected: %s", d, g.Type())
+	}
+
+	keys := make(map[string]Key, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Name == "" {
+			k.Name = k.ID
+		}
+		if !isReserved(k.ID) {
+			k.Name = attrName(k.Name)
+		}
+		keys[k.ID] = k
+	}
+
+	r := &reader{keys: keys}
+
+	a, label, err := r.attrs("graph", doc.Graph.Data, nil)
+	if err != nil {
+		return graph.Errorf(graph.EINVALID, "graph %s: %v", doc.Graph.ID, err)
+	}
+
+	nodes := make([]meta.Element, len(doc.Graph.Nodes))
+	for i, node := range doc.Graph.Nodes {
+		label := r.value(node.Data, labelKey)
+
+		a, _, err := r.attrs("node", node.Data, u.opts.Schema.NodeSchema(label))
+		if err != nil {
+			return graph.Errorf(graph.EINVALID, "node %s: %v", node.ID, err)
+		}
+
+		s, err := r.style(node.Data, style.DefaultNode())
+		if err != nil {
+			return graph.Errorf(graph.EINVALID, "node %s: %v", node.ID, err)
+		}
+
+		nodes[i] = meta.Element{
+			ID:    node.ID,
+			Label: label,
+			Style: &s,
+			Attrs: a,
+		}
+	}
+
+	edges := make([]meta.Element, len(doc.Graph.Edges))
+	for i, edge := range doc.Graph.Edges {
+		label := r.value(edge.Data, labelKey)
+
+		a, _, err := r.attrs("edge", edge.Data, u.opts.Schema.EdgeSchema(label))
+		if err != nil {
+			return graph.Errorf(graph.EINVALID, "edge %s: %v", edge.ID, err)
+		}
+
+		s, err := r.style(edge.Data, style.DefaultEdge())
+		if err != nil {
+			return graph.Errorf(graph.EINVALID, "edge %s: %v", edge.ID, err)
+		}
+
+		el := meta.Element{
+			ID:     edge.ID,
+			UID:    edge.ID,
+			Source: edge.Source,
+			Target: edge.Target,
+			Label:  label,
+			Style:  &s,
+			Attrs:  a,
+		}
+
+		if v := r.value(edge.Data, weightKey); v != "" {
+			w, err := attrs.Coerce(attrs.Float, v)
+			if err != nil {
+				return graph.Errorf(graph.EINVALID, "edge %s: weight: %v", edge.ID, err)
+			}
+			weight := w.(float64)
+			el.Weight = &weight
+		}
+
+		edges[i] = el
+	}
+
+	if err := meta.Build(g, nodes, edges, u.opts.Schema); err != nil {
+		return err
+	}
+
+	if l, ok := g.(graph.LabelSetter); ok && label != "" {
+		l.SetLabel(label)
+	}
+
+	if s, ok := g.(graph.UIDSetter); ok && doc.Graph.ID != "" {
+		s.SetUID(doc.Graph.ID)
+	}
+
+	for k, v := range a {
+		g.Attrs()[k] = v
+	}
+
+	return nil
+}
+
+// reader reads the data of graph elements.
+type reader struct {
+	keys map[string]Key
+}
+
+// value returns the value of the reserved key with the given id.
+func (r *reader) value(data []Data, id string) string {
+	for _, d := range data {
+		if d.Key == id {
+			return d.Value
+		}
+	}
+	return ""
+}
+
+// attrs returns the attributes of the given GraphML element and its label.
+// The keys declared for the element which have a default value and
+// no data are set to their default values.
+func (r *reader) attrs(domain string, data []Data, s attrs.Schema) (map[string]interface{}, string, error) {
+	a := make(map[string]interface{})
+
+	var label string
+	for _, d := range data {
+		if d.Key == labelKey {
+			label = d.Value
+		}
+
+		if isReserved(d.Key) {
+			continue
+		}
+
+		k, ok := r.keys[d.Key]
+		if !ok {
+			return nil, "", graph.Errorf(graph.EINVALID, "undeclared key: %s", d.Key)
+		}
+
+		v, err := r.parse(k, d.Value, s)
+		if err != nil {
+			return nil, "", err
+		}
+		a[k.Name] = v
+	}
+
+	for _, k := range r.keys {
+		if k.Default == nil || isReserved(k.ID) || (k.For != domain && k.For != "all") {
+			continue
+		}
+
+		if _, ok := a[k.Name]; ok {
+			continue
+		}
+
+		v, err := r.parse(k, *k.Default, s)
+		if err != nil {
+			return nil, "", err
+		}
+		a[k.Name] = v
+	}
+
+	return a, label, nil
+}
+
+// parse converts value of key k to the type declared in schema s or to the attribute type of k.
+func (r *reader) parse(k Key, value string, s attrs.Schema) (interface{}, error) {
+	t, ok := s[k.Name]
+	if !ok {
+		t = attrType(k)
+	}
+
+	var v interface{} = value
+	if t == attrs.List || t == attrs.Map {
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return nil, graph.Errorf(graph.EINVALID, "attribute %q: %v", k.Name, err)
+		}
+	}
+
+	cv, err := attrs.Coerce(t, v)
+	if err != nil {
+		return nil, graph.Errorf(graph.EINVALID, "attribute %q: %v", k.Name, err)
+	}
+
+	return cv, nil
+}
+
+// style returns the style stored in the style keys or def if data contains none of them.
+func (r *reader) style(data []Data, def style.Style) (style.Style, error) {
+	s := def
+
+	if t := r.value(data, styleTypeKey); t != "" {
+		s.Type = t
+	}
+
+	if sh := r.value(data, styleShapeKey); sh != "" {
+		s.Shape = sh
+	}
+
+	if c := r.value(data, styleColorKey); c != "" {
+		v, err := attrs.Coerce(attrs.Color, c)
+		if err != nil {
+			return def, graph.Errorf(graph.EINVALID, "style color: %v", err)
+		}
+		s.Color = v.(color.RGBA)
+	}
+
+	return s, nil
+}
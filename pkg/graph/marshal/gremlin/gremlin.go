@@ -0,0 +1,311 @@
+package gremlin
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+)
+
+const (
+	// DefaultEdgeLabel is the label of edges which have no label.
+	DefaultEdgeLabel = "Undefined"
+)
+
+const (
+	// uidProp is the property which stores node and edge UIDs.
+	uidProp = "uid"
+	// weightProp is the property which stores edge weights.
+	weightProp = "weight"
+)
+
+// Marshaler marshals graphs into Gremlin scripts.
+type Marshaler struct {
+	opts Options
+}
+
+// NewMarshaler creates a new Gremlin marshaler and returns it.
+func NewMarshaler(opts ...Option) (*Marshaler, error) {
+	mopts := Options{
+		BatchSize: DefaultBatchSize,
+	}
+
+	for _, apply := range opts {
+		apply(&mopts)
+	}
+
+	if mopts.BatchSize <= 0 {
+		return nil, graph.Errorf(graph.EINVALID, "invalid batch size: %d", mopts.BatchSize)
+	}
+
+	return &Marshaler{
+		opts: mopts,
+	}, nil
+}
+
+// Marshal marshals g into a Gremlin script which can be run by the Gremlin console.
+// The script is idempotent: nodes and edges are upserted on their UIDs, so it can
+// be run repeatedly against the same database without creating duplicates.
+func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
+	var b bytes.Buffer
+	if err := m.marshal(g, &b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo writes the Gremlin script of g to w.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	bw := bufio.NewWriter(w)
+	if err := m.marshal(g, bw); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// marshal writes the Gremlin script of g to w.
+// Nodes ordered by their UIDs are upserted first followed by edges ordered by
+// the UIDs of their nodes. Each traversal upserts at most BatchSize elements.
+func (m *Marshaler) marshal(g graph.Graph, w io.Writer) error {
+	ew := &errWriter{w: w}
+
+	var nodes []graph.Node
+	it := g.Nodes()
+	for it.Next() {
+		nodes = append(nodes, it.Node().(graph.Node))
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].UID() < nodes[j].UID()
+	})
+
+	steps := make([]string, len(nodes))
+	for i, n := range nodes {
+		steps[i] = m.node(n)
+	}
+	m.batch(ew, steps)
+
+	var edges []graph.Edge
+	eit := g.Edges()
+	for eit.Next() {
+		edges = append(edges, eit.Edge().(graph.Edge))
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		fi, fj := edges[i].From().(graph.Node).UID(), edges[j].From().(graph.Node).UID()
+		if fi != fj {
+			return fi < fj
+		}
+		ti, tj := edges[i].To().(graph.Node).UID(), edges[j].To().(graph.Node).UID()
+		if ti != tj {
+			return ti < tj
+		}
+		return edges[i].UID() < edges[j].UID()
+	})
+
+	steps = make([]string, len(edges))
+	for i, e := range edges {
+		steps[i] = m.edge(e, i%m.opts.BatchSize)
+	}
+	m.batch(ew, steps)
+
+	return ew.err
+}
+
+// batch writes traversals which chain at most BatchSize steps.
+func (m *Marshaler) batch(ew *errWriter, steps []string) {
+	for i := 0; i < len(steps); i += m.opts.BatchSize {
+		j := i + m.opts.BatchSize
+		if j > len(steps) {
+			j = len(steps)
+		}
+		ew.printf("g.%s.\n  iterate()\n", strings.Join(steps[i:j], ".\n  "))
+	}
+}
+
+// node returns the steps which upsert node n.
+func (m *Marshaler) node(n graph.Node) string {
+	uid := quote(n.UID())
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "V().%s.fold().coalesce(unfold(), addV(%s).property(%s, %s))",
+		has(n.Label(), uid), label(n.Label()), quote(uidProp), uid)
+	b.WriteString(props(m.opts.Schema.NodeSchema(n.Label()), n.Attrs(), nil, "single, "))
+
+	return b.String()
+}
+
+// edge returns the steps which upsert edge e.
+// The from node of the edge is labeled with the i-th step label.
+func (m *Marshaler) edge(e graph.Edge, i int) string {
+	from, to := e.From().(graph.Node), e.To().(graph.Node)
+	uid := quote(e.UID())
+
+	l := e.Label()
+	if l == "" {
+		l = DefaultEdgeLabel
+	}
+
+	as := quote("from" + strconv.Itoa(i))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "V().%s.as(%s).V().%s.coalesce(inE(%s).has(%s, %s), addE(%s).from(%s).property(%s, %s))",
+		has(from.Label(), quote(from.UID())), as, has(to.Label(), quote(to.UID())),
+		quote(l), quote(uidProp), uid, quote(l), as, quote(uidProp), uid)
+	weight := map[string]interface{}{weightProp: e.Weight()}
+	b.WriteString(props(m.opts.Schema.EdgeSchema(e.Label()), e.Attrs(), weight, ""))
+
+	return b.String()
+}
+
+// errWriter remembers the first write error and skips all subsequent writes.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
+
+// has returns the step which finds the node with the given label and quoted uid.
+func has(l, uid string) string {
+	if l == "" {
+		return fmt.Sprintf("has(%s, %s)", quote(uidProp), uid)
+	}
+	return fmt.Sprintf("has(%s, %s, %s)", quote(l), quote(uidProp), uid)
+}
+
+// label returns the argument of addV step for the given node label.
+// Nodes which have no label are added with the default vertex label.
+func label(l string) string {
+	if l == "" {
+		return ""
+	}
+	return quote(l)
+}
+
+// props returns property steps of attributes a overridden by attributes o.
+// Attribute values are coerced to the types declared in schema s. The uid attribute
+// and the values which can't be stored as properties are omitted.
+// Each property step is prefixed with the given cardinality.
+func props(s attrs.Schema, a, o map[string]interface{}, cardinality string) string {
+	merged := make(map[string]interface{}, len(a)+len(o))
+	for k, v := range a {
+		merged[k] = v
+	}
+	for k, v := range o {
+		merged[k] = v
+	}
+	delete(merged, uidProp)
+
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		v := merged[k]
+		if t, ok := s[k]; ok {
+			if cv, err := attrs.Coerce(t, v); err == nil {
+				v = cv
+			}
+		}
+
+		lit, ok := literal(v)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, ".property(%s%s, %s)", cardinality, quote(k), lit)
+	}
+
+	return b.String()
+}
+
+// literal returns Groovy literal of property value v.
+// Times are converted to RFC3339 strings, colors to their hex codes and
+// lists and maps to JSON strings as not all graph databases support them.
+// It returns false if v can't be stored as a property.
+func literal(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return quote(val), true
+	case bool:
+		return strconv.FormatBool(val), true
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(val) + "L", true
+	case float32:
+		return double(float64(val))
+	case float64:
+		return double(val)
+	case time.Time:
+		return quote(val.Format(time.RFC3339Nano)), true
+	case color.RGBA:
+		return quote(attrs.Format(attrs.Color, val)), true
+	}
+
+	switch attrs.TypeOf(v) {
+	case attrs.List, attrs.Map:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return quote(string(b)), true
+	}
+
+	return quote(fmt.Sprint(v)), true
+}
+
+// double returns Groovy double literal of f.
+// It returns false if f is NaN or infinite.
+func double(f float64) (string, bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", false
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64) + "d", true
+}
+
+// quote returns single-quoted Groovy string literal of s.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\'':
+			b.WriteString(`\'`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
@@ -0,0 +1,147 @@
+package gremlin
+
+import (
+	"image/color"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+func MustGraph(t *testing.T) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	repo, err := memory.NewNode(g.NewNode().ID(),
+		memory.WithUID("repo"),
+		memory.WithLabel("Repo"),
+		memory.WithAttrs(map[string]interface{}{
+			"name":       "it's",
+			"stars":      int64(10),
+			"starred_at": "2021-01-02T03:04:05Z",
+			"topics":     []interface{}{"go"},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(repo)
+
+	topic, err := memory.NewNode(g.NewNode().ID(),
+		memory.WithUID("go"),
+		memory.WithLabel("Topic"),
+		memory.WithAttrs(map[string]interface{}{"name": "go"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(topic)
+
+	e, err := memory.NewEdge(repo, topic,
+		memory.WithUID("repo-go"),
+		memory.WithLabel("HasTopic"),
+		memory.WithWeight(2.5),
+	)
+	if err != nil {
+		t.Fatalf("failed to create edge: %v", err)
+	}
+	g.SetWeightedEdge(e)
+
+	return g
+}
+
+func MustSchema(t *testing.T) *attrs.Registry {
+	t.Helper()
+
+	r := attrs.NewRegistry()
+	if err := r.SetNodeSchema("Repo", attrs.Schema{"starred_at": attrs.Time}); err != nil {
+		t.Fatalf("failed to set node schema: %v", err)
+	}
+
+	return r
+}
+
+func TestMarshal(t *testing.T) {
+	testCases := []struct {
+		batch int
+		exp   string
+	}{
+		{
+			batch: DefaultBatchSize,
+			exp: `g.V().has('Topic', 'uid', 'go').fold().coalesce(unfold(), addV('Topic').property('uid', 'go')).property(single, 'name', 'go').
+  V().has('Repo', 'uid', 'repo').fold().coalesce(unfold(), addV('Repo').property('uid', 'repo')).property(single, 'name', 'it\'s').property(single, 'starred_at', '2021-01-02T03:04:05Z').property(single, 'stars', 10L).property(single, 'topics', '["go"]').
+  iterate()
+g.V().has('Repo', 'uid', 'repo').as('from0').V().has('Topic', 'uid', 'go').coalesce(inE('HasTopic').has('uid', 'repo-go'), addE('HasTopic').from('from0').property('uid', 'repo-go')).property('weight', 2.5d).
+  iterate()
+`,
+		},
+		{
+			batch: 1,
+			exp: `g.V().has('Topic', 'uid', 'go').fold().coalesce(unfold(), addV('Topic').property('uid', 'go')).property(single, 'name', 'go').
+  iterate()
+g.V().has('Repo', 'uid', 'repo').fold().coalesce(unfold(), addV('Repo').property('uid', 'repo')).property(single, 'name', 'it\'s').property(single, 'starred_at', '2021-01-02T03:04:05Z').property(single, 'stars', 10L).property(single, 'topics', '["go"]').
+  iterate()
+g.V().has('Repo', 'uid', 'repo').as('from0').V().has('Topic', 'uid', 'go').coalesce(inE('HasTopic').has('uid', 'repo-go'), addE('HasTopic').from('from0').property('uid', 'repo-go')).property('weight', 2.5d).
+  iterate()
+`,
+		},
+	}
+
+	g := MustGraph(t)
+
+	for _, tc := range testCases {
+		m, err := NewMarshaler(WithBatchSize(tc.batch), WithSchema(MustSchema(t)))
+		if err != nil {
+			t.Fatalf("failed to create marshaler: %v", err)
+		}
+
+		data, err := m.Marshal(g)
+		if err != nil {
+			t.Fatalf("failed to marshal graph: %v", err)
+		}
+
+		if string(data) != tc.exp {
+			t.Errorf("batch %d: expected:\n%s\ngot:\n%s", tc.batch, tc.exp, data)
+		}
+	}
+}
+
+func TestNewMarshalerErrors(t *testing.T) {
+	if _, err := NewMarshaler(WithBatchSize(-1)); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	ts := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		v   interface{}
+		exp string
+		ok  bool
+	}{
+		{"a\\b'c\n", `'a\\b\'c\n'`, true},
+		{false, "false", true},
+		{int64(10), "10L", true},
+		{0.5, "0.5d", true},
+		{math.Inf(1), "", false},
+		{ts, `'2021-01-02T03:04:05Z'`, true},
+		{color.RGBA{B: 255, A: 255}, `'#0000ff'`, true},
+		{map[string]interface{}{"a": 1}, `'{"a":1}'`, true},
+		{nil, "", false},
+	}
+
+	for _, tc := range testCases {
+		got, ok := literal(tc.v)
+		if ok != tc.ok || got != tc.exp {
+			t.Errorf("%v: expected: %s (%t), got: %s (%t)", tc.v, tc.exp, tc.ok, got, ok)
+		}
+	}
+}
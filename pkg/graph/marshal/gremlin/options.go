@@ -0,0 +1,33 @@
+package gremlin
+
+import "github.com/milosgajdos/orbnet/pkg/graph/attrs"
+
+const (
+	// DefaultBatchSize is default number of nodes or edges upserted by a single traversal.
+	DefaultBatchSize = 100
+)
+
+// Options configure marshaler.
+type Options struct {
+	// BatchSize is the max number of nodes or edges upserted by a single traversal.
+	BatchSize int
+	// Schema declares typed node and edge attributes.
+	Schema *attrs.Registry
+}
+
+// Option is functional marshaler option.
+type Option func(*Options)
+
+// WithBatchSize sets BatchSize option.
+func WithBatchSize(n int) Option {
+	return func(o *Options) {
+		o.BatchSize = n
+	}
+}
+
+// WithSchema sets Schema option.
+func WithSchema(r *attrs.Registry) Option {
+	return func(o *Options) {
+		o.Schema = r
+	}
+}
@@ -0,0 +1,148 @@
+package html
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"html/template"
+	"io"
+	"net/url"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/diagram"
+)
+
+//go:embed template.html
+var page string
+
+// tmpl renders graph page.
+var tmpl = template.Must(template.New("graph").Parse(page))
+
+// Node is graph page node.
+type Node struct {
+	UID   string `json:"uid"`
+	Label string `json:"label"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+	URL   string `json:"url,omitempty"`
+}
+
+// Edge is graph page edge.
+// Source and Target are the indices of edge nodes.
+type Edge struct {
+	Source int    `json:"source"`
+	Target int    `json:"target"`
+	Label  string `json:"label,omitempty"`
+}
+
+// Graph is graph page data.
+type Graph struct {
+	Directed bool   `json:"directed"`
+	Nodes    []Node `json:"nodes"`
+	Edges    []Edge `json:"edges"`
+}
+
+// Marshaler implements graph.Marshaler.
+type Marshaler struct {
+	name string
+	opts Options
+}
+
+// NewMarshaler creates a new Marshaler and returns it.
+func NewMarshaler(name string, opts ...Option) (*Marshaler, error) {
+	mopts := Options{
+		NameKeys: DefaultNameKeys,
+		URLKey:   DefaultURLKey,
+	}
+
+	for _, apply := range opts {
+		apply(&mopts)
+	}
+
+	return &Marshaler{
+		name: name,
+		opts: mopts,
+	}, nil
+}
+
+// Marshal marshals g into a self-contained HTML page which renders the graph.
+// The page embeds all its scripts and data so it can be viewed without network access.
+// Nodes can be searched by their names, filtered by their labels and opened via their URLs.
+// Nodes are coloured by their color attribute or their style.
+func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
+	var b bytes.Buffer
+	if err := m.MarshalTo(&b, g); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo marshals g into HTML page written to w.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	data := Graph{
+		Directed: graph.IsDirected(g.Type()),
+		Nodes:    make([]Node, 0, g.Nodes().Len()),
+		Edges:    make([]Edge, 0, g.Edges().Len()),
+	}
+
+	index := make(map[int64]int, g.Nodes().Len())
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n := nodes.Node().(graph.Node)
+
+		index[n.ID()] = len(data.Nodes)
+		data.Nodes = append(data.Nodes, Node{
+			UID:   n.UID(),
+			Label: n.Label(),
+			Name:  diagram.NodeName(n, m.opts.NameKeys),
+			Color: diagram.NodeHex(n),
+			URL:   m.nodeURL(n),
+		})
+	}
+
+	edges := g.Edges()
+	for edges.Next() {
+		e := edges.Edge().(graph.Edge)
+
+		data.Edges = append(data.Edges, Edge{
+			Source: index[e.From().ID()],
+			Target: index[e.To().ID()],
+			Label:  e.Label(),
+		})
+	}
+
+	title := m.name
+	if title == "" {
+		title = g.Label()
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := tmpl.Execute(bw, struct {
+		Title string
+		Graph Graph
+	}{
+		Title: title,
+		Graph: data,
+	}); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// nodeURL returns the URL of node n.
+// URLs whose scheme is not http or https are omitted.
+func (m *Marshaler) nodeURL(n graph.Node) string {
+	v, ok := n.Attrs()[m.opts.URLKey].(string)
+	if !ok {
+		return ""
+	}
+
+	u, err := url.Parse(v)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+
+	return u.String()
+}
@@ -0,0 +1,129 @@
+package html
+
+import (
+	"encoding/json"
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/diagram"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	"github.com/milosgajdos/orbnet/pkg/graph/style"
+)
+
+func MustGraph(t *testing.T) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph(memory.WithLabel("stars"))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	repo, err := memory.NewNode(g.NewNode().ID(),
+		memory.WithUID("repo"),
+		memory.WithLabel("Repo"),
+		memory.WithAttrs(map[string]interface{}{
+			"name":      "bar",
+			"full_name": "foo/</script><script>alert(1)</script>",
+			"html_url":  "https://github.com/foo/bar",
+			"color":     color.RGBA{R: 0, G: 255, B: 153},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(repo)
+
+	owner, err := memory.NewNode(g.NewNode().ID(),
+		memory.WithUID("foo"),
+		memory.WithLabel("Owner"),
+		memory.WithAttrs(map[string]interface{}{
+			"html_url": "javascript:alert(1)",
+		}),
+		memory.WithStyle(style.Style{Color: color.RGBA{R: 245, G: 154, B: 240}}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(owner)
+
+	e, err := memory.NewEdge(repo, owner, memory.WithLabel("OwnedBy"))
+	if err != nil {
+		t.Fatalf("failed to create edge: %v", err)
+	}
+	g.SetWeightedEdge(e)
+
+	return g
+}
+
+func TestMarshal(t *testing.T) {
+	m, err := NewMarshaler("")
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	data, err := m.Marshal(MustGraph(t))
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	page := string(data)
+
+	if !strings.Contains(page, "<title>stars</title>") {
+		t.Errorf("expected graph label title")
+	}
+
+	for _, external := range []string{"src=", "href=\"http", "@import"} {
+		if strings.Contains(page, external) {
+			t.Errorf("unexpected external resource %q", external)
+		}
+	}
+
+	if strings.Contains(page, "</script><script>") {
+		t.Errorf("unescaped node name")
+	}
+
+	const prefix = "var graph = "
+	start := strings.Index(page, prefix)
+	if start < 0 {
+		t.Fatalf("graph data not found")
+	}
+	start += len(prefix)
+	end := strings.Index(page[start:], ";\n")
+
+	var got Graph
+	if err := json.Unmarshal([]byte(page[start:start+end]), &got); err != nil {
+		t.Fatalf("failed to decode graph data: %v", err)
+	}
+
+	exp := Graph{
+		Directed: true,
+		Nodes: []Node{
+			{UID: "repo", Label: "Repo", Name: "foo/</script><script>alert(1)</script>", Color: "#00ff99", URL: "https://github.com/foo/bar"},
+			{UID: "foo", Label: "Owner", Name: "foo", Color: "#f59af0"},
+		},
+		Edges: []Edge{{Source: 0, Target: 1, Label: "OwnedBy"}},
+	}
+
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected graph data: %#v, got: %#v", exp, got)
+	}
+}
+
+func TestNameKeys(t *testing.T) {
+	m, err := NewMarshaler("repos", WithNameKeys("name"), WithURLKey("url"))
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	g := MustGraph(t)
+
+	if name := diagram.NodeName(g.NodeWithUID("repo"), m.opts.NameKeys); name != "bar" {
+		t.Errorf("expected name: bar, got: %s", name)
+	}
+
+	if u := m.nodeURL(g.NodeWithUID("repo")); u != "" {
+		t.Errorf("expected no URL, got: %s", u)
+	}
+}
@@ -0,0 +1,34 @@
+package html
+
+import "github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/diagram"
+
+// DefaultURLKey is default node attribute which stores node URL.
+const DefaultURLKey = "html_url"
+
+// DefaultNameKeys are default node attributes which store node names.
+var DefaultNameKeys = diagram.NameKeys
+
+// Options configure marshaler.
+type Options struct {
+	// NameKeys are node attributes which store node names in the order of precedence.
+	NameKeys []string
+	// URLKey is node attribute which stores node URL.
+	URLKey string
+}
+
+// Option is functional marshaler option.
+type Option func(*Options)
+
+// WithNameKeys sets NameKeys option.
+func WithNameKeys(keys ...string) Option {
+	return func(o *Options) {
+		o.NameKeys = keys
+	}
+}
+
+// WithURLKey sets URLKey option.
+func WithURLKey(key string) Option {
+	return func(o *Options) {
+		o.URLKey = key
+	}
+}
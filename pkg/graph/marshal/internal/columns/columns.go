@@ -0,0 +1,144 @@
+// Package columns infers the types of the attribute columns of tabular
+// formats from the attributes of graph nodes or edges.
+package columns
+
+import (
+	"sort"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+)
+
+// Columns collects the attribute columns of nodes or edges along with their types.
+type Columns struct {
+	schema attrs.Schema
+	skip   map[string]bool
+	types  map[string]attrs.Type
+	elems  map[string]attrs.Type
+	flat   map[string]bool
+	names  []string
+}
+
+// New creates new attribute columns.
+// The types of the attributes declared in schema s are not inferred.
+// The attributes with the skipped names are not added to the columns.
+func New(s attrs.Schema, skip ...string) *Columns {
+	c := &Columns{
+		schema: s,
+		skip:   make(map[string]bool, len(skip)),
+		types:  make(map[string]attrs.Type),
+		elems:  make(map[string]attrs.Type),
+		flat:   make(map[string]bool),
+	}
+
+	for _, name := range skip {
+		c.skip[name] = true
+	}
+
+	return c
+}
+
+// Add adds the attributes a to the columns.
+// If the inferred types of the same attribute differ its type is String
+// unless they're both numeric in which case its type is Float.
+func (c *Columns) Add(a map[string]interface{}) {
+	for name, v := range a {
+		if v == nil || c.skip[name] {
+			continue
+		}
+
+		t, ok := c.schema[name]
+		if !ok {
+			t = attrs.TypeOf(v)
+		}
+
+		kt, seen := c.types[name]
+		if seen {
+			t = merge(kt, t)
+		}
+		c.types[name] = t
+
+		if !seen {
+			c.flat[name] = true
+		}
+
+		if t == attrs.List {
+			c.addElems(name, v)
+		}
+	}
+}
+
+// addElems infers the type of the elements of list attribute.
+// Lists of lists or maps are not flat.
+func (c *Columns) addElems(name string, v interface{}) {
+	l, err := attrs.Coerce(attrs.List, v)
+	if err != nil {
+		c.flat[name] = false
+		return
+	}
+
+	for _, e := range l.([]interface{}) {
+		if e == nil {
+			continue
+		}
+
+		t := attrs.TypeOf(e)
+		switch t {
+		case attrs.List, attrs.Map:
+			c.flat[name] = false
+			continue
+		case attrs.Time, attrs.Color:
+			t = attrs.String
+		}
+
+		if et, ok := c.elems[name]; ok {
+			t = merge(et, t)
+		}
+		c.elems[name] = t
+	}
+}
+
+// merge returns the type of the attribute whose values are of types a and b.
+func merge(a, b attrs.Type) attrs.Type {
+	switch {
+	case a == b:
+		return a
+	case (a == attrs.Int || a == attrs.Float) && (b == attrs.Int || b == attrs.Float):
+		return attrs.Float
+	}
+	return attrs.String
+}
+
+// Sort sorts the columns by attribute names.
+// It must be called after all the attributes have been added.
+func (c *Columns) Sort() {
+	c.names = make([]string, 0, len(c.types))
+	for name := range c.types {
+		c.names = append(c.names, name)
+	}
+	sort.Strings(c.names)
+}
+
+// Names returns the sorted column names.
+func (c *Columns) Names() []string {
+	return c.names
+}
+
+// Type returns the type of the column with the given name.
+func (c *Columns) Type(name string) attrs.Type {
+	return c.types[name]
+}
+
+// Elem returns the type of the elements of list column with the given name.
+// The elements of lists which contain no values are of type String.
+func (c *Columns) Elem(name string) attrs.Type {
+	if t, ok := c.elems[name]; ok {
+		return t
+	}
+	return attrs.String
+}
+
+// IsFlat returns true if the column with the given name is a list
+// whose elements are neither lists nor maps.
+func (c *Columns) IsFlat(name string) bool {
+	return c.types[name] == attrs.List && c.flat[name]
+}
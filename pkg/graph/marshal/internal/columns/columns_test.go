@@ -0,0 +1,76 @@
+package columns
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+)
+
+func TestColumns(t *testing.T) {
+	c := New(attrs.Schema{"id": attrs.String}, "skip")
+
+	c.Add(map[string]interface{}{
+		"id":     int64(1),
+		"skip":   "foo",
+		"num":    int64(1),
+		"name":   "foo",
+		"tags":   []interface{}{"a", "b"},
+		"nested": []interface{}{[]interface{}{"a"}},
+		"empty":  nil,
+	})
+	c.Add(map[string]interface{}{
+		"num":  1.5,
+		"name": true,
+		"tags": []interface{}{int64(1)},
+	})
+	c.Sort()
+
+	if names, exp := c.Names(), []string{"id", "name", "nested", "num", "tags"}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("expected names: %v, got: %v", exp, names)
+	}
+
+	testCases := []struct {
+		name string
+		typ  attrs.Type
+		elem attrs.Type
+		flat bool
+	}{
+		{"id", attrs.String, attrs.String, false},
+		{"name", attrs.String, attrs.String, false},
+		{"nested", attrs.List, attrs.String, false},
+		{"num", attrs.Float, attrs.String, false},
+		{"tags", attrs.List, attrs.String, true},
+	}
+
+	for _, tc := range testCases {
+		if typ := c.Type(tc.name); typ != tc.typ {
+			t.Errorf("%s: expected type: %s, got: %s", tc.name, tc.typ, typ)
+		}
+		if elem := c.Elem(tc.name); elem != tc.elem {
+			t.Errorf("%s: expected elem type: %s, got: %s", tc.name, tc.elem, elem)
+		}
+		if flat := c.IsFlat(tc.name); flat != tc.flat {
+			t.Errorf("%s: expected flat: %v, got: %v", tc.name, tc.flat, flat)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	testCases := []struct {
+		a, b attrs.Type
+		exp  attrs.Type
+	}{
+		{attrs.Int, attrs.Int, attrs.Int},
+		{attrs.Int, attrs.Float, attrs.Float},
+		{attrs.Float, attrs.Int, attrs.Float},
+		{attrs.Int, attrs.Bool, attrs.String},
+		{attrs.Time, attrs.String, attrs.String},
+	}
+
+	for _, tc := range testCases {
+		if got := merge(tc.a, tc.b); got != tc.exp {
+			t.Errorf("merge(%s, %s): expected: %s, got: %s", tc.a, tc.b, tc.exp, got)
+		}
+	}
+}
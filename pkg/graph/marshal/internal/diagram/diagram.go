@@ -0,0 +1,139 @@
+// Package diagram provides the node naming and styling shared by the
+// marshalers which draw graphs rather than store them.
+package diagram
+
+import (
+	"image/color"
+	"sort"
+	"strings"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/meta"
+	"github.com/milosgajdos/orbnet/pkg/graph/subgraph"
+)
+
+const (
+	// ColorKey is node attribute which stores node color.
+	ColorKey = "color"
+	// ShapeKey is node attribute which stores node shape.
+	ShapeKey = "shape"
+)
+
+const (
+	// MaxNodes is default max number of drawn nodes.
+	MaxNodes = 50
+)
+
+// NameKeys are default node attributes which store node names.
+var NameKeys = []string{"full_name", "name", "login"}
+
+// NodeName returns the name of node n stored in the first of the given
+// attributes which is a non-empty string. Nodes which have none of them
+// are named by their UIDs.
+func NodeName(n graph.Node, keys []string) string {
+	for _, k := range keys {
+		if name, ok := n.Attrs()[k].(string); ok && name != "" {
+			return name
+		}
+	}
+	return n.UID()
+}
+
+// NodeColor returns the color of node n style overridden by its color attribute.
+// The color is opaque as the default styles are transparent.
+func NodeColor(n graph.Node) color.RGBA {
+	var c color.RGBA
+
+	if s, ok := n.(graph.Styler); ok {
+		c = s.Color()
+	}
+
+	if v, ok := n.Attrs()[ColorKey]; ok && v != nil {
+		if cv, err := attrs.Coerce(attrs.Color, v); err == nil {
+			c = cv.(color.RGBA)
+		}
+	}
+
+	c.A = 0xff
+
+	return c
+}
+
+// NodeShape returns the shape of node n style overridden by its shape attribute.
+func NodeShape(n graph.Node) string {
+	var shape string
+
+	if s, ok := n.(graph.Styler); ok {
+		shape = s.Shape()
+	}
+
+	if v, ok := n.Attrs()[ShapeKey].(string); ok && v != "" {
+		shape = v
+	}
+
+	return shape
+}
+
+// NodeHex returns hex code of node n color.
+func NodeHex(n graph.Node) string {
+	return meta.FormatColor(NodeColor(n))
+}
+
+// Class returns the name of the class of nodes whose color has hex code c.
+func Class(c string) string {
+	return "c" + strings.TrimPrefix(c, "#")
+}
+
+// Limit returns g if it has at most maxNodes nodes.
+// Otherwise it returns the subgraph induced by maxNodes nodes with the highest
+// degree if truncate is true or an error if it's not.
+func Limit(g graph.Graph, maxNodes int, truncate bool) (graph.Graph, error) {
+	n := g.Nodes().Len()
+	if n <= maxNodes {
+		return g, nil
+	}
+
+	if !truncate {
+		return nil, graph.Errorf(graph.EINVALID, "graph too large: %d nodes, max: %d", n, maxNodes)
+	}
+
+	return subgraph.Extract(g, subgraph.WithTopN(maxNodes))
+}
+
+// SortedNodes returns the nodes of g sorted by their IDs.
+func SortedNodes(g graph.Graph) []graph.Node {
+	nodes := make([]graph.Node, 0, g.Nodes().Len())
+	it := g.Nodes()
+	for it.Next() {
+		nodes = append(nodes, it.Node().(graph.Node))
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID() < nodes[j].ID()
+	})
+
+	return nodes
+}
+
+// SortedEdges returns the edges of g sorted by the IDs of their nodes and their UIDs.
+func SortedEdges(g graph.Graph) []graph.Edge {
+	edges := make([]graph.Edge, 0, g.Edges().Len())
+	it := g.Edges()
+	for it.Next() {
+		edges = append(edges, it.Edge().(graph.Edge))
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		ei, ej := edges[i], edges[j]
+		if ei.From().ID() != ej.From().ID() {
+			return ei.From().ID() < ej.From().ID()
+		}
+		if ei.To().ID() != ej.To().ID() {
+			return ei.To().ID() < ej.To().ID()
+		}
+		return ei.UID() < ej.UID()
+	})
+
+	return edges
+}
@@ -0,0 +1,165 @@
+package diagram
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	"github.com/milosgajdos/orbnet/pkg/graph/style"
+)
+
+func MustNode(t *testing.T, a map[string]interface{}) *memory.Node {
+	t.Helper()
+
+	n, err := memory.NewNode(1,
+		memory.WithUID("uid"),
+		memory.WithAttrs(a),
+		memory.WithStyle(style.Style{Shape: "circle", Color: color.RGBA{R: 1, G: 2, B: 3}}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+
+	return n
+}
+
+func TestNodeName(t *testing.T) {
+	testCases := []struct {
+		attrs map[string]interface{}
+		keys  []string
+		exp   string
+	}{
+		{map[string]interface{}{"full_name": "foo/bar", "name": "bar"}, NameKeys, "foo/bar"},
+		{map[string]interface{}{"full_name": "", "name": "bar"}, NameKeys, "bar"},
+		{map[string]interface{}{"name": 1}, NameKeys, "uid"},
+		{map[string]interface{}{"full_name": "foo/bar", "name": "bar"}, []string{"name"}, "bar"},
+		{nil, nil, "uid"},
+	}
+
+	for _, tc := range testCases {
+		if name := NodeName(MustNode(t, tc.attrs), tc.keys); name != tc.exp {
+			t.Errorf("expected name: %s, got: %s", tc.exp, name)
+		}
+	}
+}
+
+func TestNodeColor(t *testing.T) {
+	testCases := []struct {
+		attrs map[string]interface{}
+		exp   string
+	}{
+		{nil, "#010203"},
+		{map[string]interface{}{ColorKey: "#ff000080"}, "#ff0000"},
+		{map[string]interface{}{ColorKey: "red"}, "#010203"},
+	}
+
+	for _, tc := range testCases {
+		if c := NodeHex(MustNode(t, tc.attrs)); c != tc.exp {
+			t.Errorf("expected color: %s, got: %s", tc.exp, c)
+		}
+	}
+}
+
+func TestNodeShape(t *testing.T) {
+	testCases := []struct {
+		attrs map[string]interface{}
+		exp   string
+	}{
+		{nil, "circle"},
+		{map[string]interface{}{ShapeKey: "box"}, "box"},
+		{map[string]interface{}{ShapeKey: ""}, "circle"},
+	}
+
+	for _, tc := range testCases {
+		if s := NodeShape(MustNode(t, tc.attrs)); s != tc.exp {
+			t.Errorf("expected shape: %s, got: %s", tc.exp, s)
+		}
+	}
+}
+
+// MustStar returns a star graph with a hub node and the given number of leaves.
+func MustStar(t *testing.T, leaves int) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph(memory.WithType(graph.WeightedDirectedMulti))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	hub, err := memory.AddNewNode(g, memory.WithUID("hub"))
+	if err != nil {
+		t.Fatalf("failed to add node: %v", err)
+	}
+
+	for i := 0; i < leaves; i++ {
+		leaf, err := memory.AddNewNode(g, memory.WithUID(fmt.Sprintf("leaf%d", i)))
+		if err != nil {
+			t.Fatalf("failed to add node: %v", err)
+		}
+
+		for _, uid := range []string{"b", "a"} {
+			e, err := memory.NewEdge(hub, leaf, memory.WithUID(fmt.Sprintf("%s%d", uid, i)))
+			if err != nil {
+				t.Fatalf("failed to create edge: %v", err)
+			}
+			g.SetWeightedEdge(e)
+		}
+	}
+
+	return g
+}
+
+func TestLimit(t *testing.T) {
+	g := MustStar(t, 3)
+
+	sg, err := Limit(g, 4, false)
+	if err != nil {
+		t.Fatalf("failed to limit graph: %v", err)
+	}
+	if sg != graph.Graph(g) {
+		t.Errorf("expected the graph itself")
+	}
+
+	if _, err := Limit(g, 2, false); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+
+	sg, err = Limit(g, 2, true)
+	if err != nil {
+		t.Fatalf("failed to truncate graph: %v", err)
+	}
+	if n := sg.Nodes().Len(); n != 2 {
+		t.Errorf("expected nodes: 2, got: %d", n)
+	}
+	if n := SortedNodes(sg)[0]; n.UID() != "hub" {
+		t.Errorf("expected node with the highest degree: hub, got: %s", n.UID())
+	}
+}
+
+func TestSorted(t *testing.T) {
+	g := MustStar(t, 2)
+
+	var nodes []string
+	for _, n := range SortedNodes(g) {
+		nodes = append(nodes, n.UID())
+	}
+	if got, exp := fmt.Sprint(nodes), "[hub leaf0 leaf1]"; got != exp {
+		t.Errorf("expected nodes: %s, got: %s", exp, got)
+	}
+
+	var edges []string
+	for _, e := range SortedEdges(g) {
+		edges = append(edges, e.UID())
+	}
+	if got, exp := fmt.Sprint(edges), "[a0 b0 a1 b1]"; got != exp {
+		t.Errorf("expected edges: %s, got: %s", exp, got)
+	}
+}
+
+func TestClass(t *testing.T) {
+	if c := Class("#ff0000"); c != "cff0000" {
+		t.Errorf("expected class: cff0000, got: %s", c)
+	}
+}
@@ -0,0 +1,103 @@
+// Package marshaltest provides the graph fixtures shared by the tests of
+// the marshalers which round-trip graphs.
+package marshaltest
+
+import (
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	"github.com/milosgajdos/orbnet/pkg/graph/style"
+)
+
+// EdgeAttrs are the attributes of the fixture edge.
+// Their names clash with the attributes reserved by the marshalers.
+var EdgeAttrs = map[string]interface{}{
+	"source":   "reserved",
+	"relation": "reserved",
+	"weight":   2.5,
+}
+
+// Schema returns the attribute schema of the fixture graph.
+func Schema(t *testing.T) *attrs.Registry {
+	t.Helper()
+
+	r := attrs.NewRegistry()
+	if err := r.SetNodeSchema("Repo", attrs.Schema{
+		"stars":      attrs.Int,
+		"starred_at": attrs.Time,
+		"topics":     attrs.List,
+		"license":    attrs.Map,
+	}); err != nil {
+		t.Fatalf("failed to set node schema: %v", err)
+	}
+	if err := r.SetEdgeSchema("HasTopic", attrs.Schema{
+		"weight": attrs.Float,
+	}); err != nil {
+		t.Fatalf("failed to set edge schema: %v", err)
+	}
+
+	return r
+}
+
+// Graph returns the fixture graph of the given type.
+// It contains a repo node and a topic node joined by a single edge.
+func Graph(t *testing.T, typ string) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph(memory.WithUID("stars"), memory.WithLabel("GitHub Stars"), memory.WithType(typ))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+	g.Attrs()["user"] = "foo"
+
+	repo, err := memory.NewNode(g.NewNode().ID(),
+		memory.WithUID("repo"),
+		memory.WithLabel("Repo"),
+		memory.WithAttrs(map[string]interface{}{
+			"id":         "reserved",
+			"name":       "foo & <bar>",
+			"stars":      int64(10),
+			"fork":       false,
+			"score":      0.5,
+			"starred_at": time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+			"topics":     []interface{}{"go", "graph"},
+			"license":    map[string]interface{}{"key": "mit"},
+		}),
+		memory.WithStyle(style.Style{Type: "filled", Shape: "box", Color: color.RGBA{R: 1, G: 2, B: 3, A: 128}}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(repo)
+
+	topic, err := memory.NewNode(g.NewNode().ID(),
+		memory.WithUID("go-Topic"),
+		memory.WithLabel("Topic"),
+		memory.WithAttrs(map[string]interface{}{"name": "go"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(topic)
+
+	edgeAttrs := make(map[string]interface{}, len(EdgeAttrs))
+	for k, v := range EdgeAttrs {
+		edgeAttrs[k] = v
+	}
+
+	e, err := memory.NewEdge(repo, topic,
+		memory.WithUID("repo-go"),
+		memory.WithLabel("HasTopic"),
+		memory.WithWeight(2.5),
+		memory.WithAttrs(edgeAttrs),
+	)
+	if err != nil {
+		t.Fatalf("failed to create edge: %v", err)
+	}
+	g.SetWeightedEdge(e)
+
+	return g
+}
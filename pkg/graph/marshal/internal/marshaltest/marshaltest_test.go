@@ -0,0 +1,311 @@
+package marshaltest_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/cytoscape"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/dot"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/gexf"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/graphml"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/marshaltest"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/networkx"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/sigma"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	"github.com/milosgajdos/orbnet/pkg/graph/style"
+)
+
+type format struct {
+	name string
+	// marshaler returns the marshaler of graphs with attributes described by r.
+	marshaler func(r *attrs.Registry) (graph.Marshaler, error)
+	// unmarshaler returns the unmarshaler of graphs with attributes described by r.
+	unmarshaler func(r *attrs.Registry) (graph.Unmarshaler, error)
+	graphType   func(data []byte) (string, error)
+	// directed is true if the format always stores directed graphs.
+	directed bool
+	// meta is true if the format stores graph UID, label and attributes.
+	meta bool
+	// selfLoop is a directed graph with two self-loops and one other edge.
+	selfLoop string
+	// edgeUID is the UID of the other edge if it survives the round trip.
+	edgeUID string
+}
+
+var formats = []format{
+	{
+		name: "dot",
+		marshaler: func(*attrs.Registry) (graph.Marshaler, error) {
+			return dot.NewMarshaler("stars", "", "  ")
+		},
+		unmarshaler: func(r *attrs.Registry) (graph.Unmarshaler, error) {
+			return dot.NewUnmarshaler(dot.WithSchema(r))
+		},
+		graphType: dot.GraphType,
+		selfLoop: `digraph G {
+  a -> a;
+  a -> a;
+  a -> b;
+}`,
+	},
+	{
+		name: "gexf",
+		marshaler: func(*attrs.Registry) (graph.Marshaler, error) {
+			return gexf.NewMarshaler("stars", "", "  ")
+		},
+		unmarshaler: func(r *attrs.Registry) (graph.Unmarshaler, error) {
+			return gexf.NewUnmarshaler(gexf.WithSchema(r))
+		},
+		graphType: gexf.GraphType,
+		selfLoop: `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2">
+  <graph defaultedgetype="directed" mode="static">
+    <nodes>
+      <node id="a"/>
+      <node id="b"/>
+    </nodes>
+    <edges>
+      <edge id="0" source="a" target="a"/>
+      <edge id="1" source="a" target="a"/>
+      <edge id="2" source="a" target="b"/>
+    </edges>
+  </graph>
+</gexf>`,
+	},
+	{
+		name: "graphml",
+		marshaler: func(r *attrs.Registry) (graph.Marshaler, error) {
+			return graphml.NewMarshaler("stars", "", "  ", graphml.WithSchema(r))
+		},
+		unmarshaler: func(r *attrs.Registry) (graph.Unmarshaler, error) {
+			return graphml.NewUnmarshaler(graphml.WithSchema(r))
+		},
+		graphType: graphml.GraphType,
+		meta:      true,
+		selfLoop: `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <graph edgedefault="directed">
+    <node id="a"/>
+    <node id="b"/>
+    <edge id="e0" source="a" target="a"/>
+    <edge id="e1" source="a" target="a"/>
+    <edge id="e2" source="a" target="b"/>
+  </graph>
+</graphml>`,
+		edgeUID: "e2",
+	},
+	{
+		name: "cytoscape",
+		marshaler: func(*attrs.Registry) (graph.Marshaler, error) {
+			return cytoscape.NewMarshaler("stars", "", "  ")
+		},
+		unmarshaler: func(r *attrs.Registry) (graph.Unmarshaler, error) {
+			return cytoscape.NewUnmarshaler(cytoscape.WithSchema(r))
+		},
+		graphType: cytoscape.GraphType,
+		directed:  true,
+		selfLoop: `{
+  "elements": {
+    "nodes": [{"data": {"id": "a"}}, {"data": {"id": "b"}}],
+    "edges": [
+      {"data": {"id": "e0", "source": "a", "target": "a"}},
+      {"data": {"id": "e1", "source": "a", "target": "a"}},
+      {"data": {"id": "e2", "source": "a", "target": "b"}}
+    ]
+  }
+}`,
+	},
+	{
+		name: "networkx",
+		marshaler: func(*attrs.Registry) (graph.Marshaler, error) {
+			return networkx.NewMarshaler("stars", "", "  ")
+		},
+		unmarshaler: func(r *attrs.Registry) (graph.Unmarshaler, error) {
+			return networkx.NewUnmarshaler(networkx.WithSchema(r))
+		},
+		graphType: networkx.GraphType,
+		selfLoop: `{
+  "directed": true,
+  "multigraph": false,
+  "graph": {},
+  "nodes": [{"id": "a"}, {"id": "b"}],
+  "links": [
+    {"source": "a", "target": "a"},
+    {"source": "a", "target": "a"},
+    {"source": "a", "target": "b"}
+  ]
+}`,
+	},
+	{
+		name: "sigma",
+		marshaler: func(*attrs.Registry) (graph.Marshaler, error) {
+			return sigma.NewMarshaler("stars", "", "  ")
+		},
+		unmarshaler: func(r *attrs.Registry) (graph.Unmarshaler, error) {
+			return sigma.NewUnmarshaler(sigma.WithSchema(r))
+		},
+		graphType: sigma.GraphType,
+		directed:  true,
+		selfLoop: `{
+  "nodes": [{"id": "a"}, {"id": "b"}],
+  "edges": [
+    {"id": "e0", "source": "a", "target": "a"},
+    {"id": "e1", "source": "a", "target": "a"},
+    {"id": "e2", "source": "a", "target": "b"}
+  ]
+}`,
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	testCases := []struct {
+		typ    string
+		schema *attrs.Registry
+	}{
+		{graph.WeightedDirected, marshaltest.Schema(t)},
+		{graph.WeightedUndirected, marshaltest.Schema(t)},
+		// attribute types are restored from the formats metadata
+		{graph.WeightedDirected, nil},
+	}
+
+	for _, f := range formats {
+		for _, tc := range testCases {
+			typ := tc.typ
+			g := marshaltest.Graph(t, typ)
+
+			m, err := f.marshaler(tc.schema)
+			if err != nil {
+				t.Fatalf("%s: failed to create marshaler: %v", f.name, err)
+			}
+
+			data, err := m.Marshal(g)
+			if err != nil {
+				t.Fatalf("%s %s: failed to marshal graph: %v", f.name, typ, err)
+			}
+
+			gt, err := f.graphType(data)
+			if err != nil {
+				t.Fatalf("%s %s: failed to get graph type: %v", f.name, typ, err)
+			}
+
+			expType := typ
+			if f.directed {
+				expType = graph.WeightedDirected
+			}
+
+			if gt != expType {
+				t.Errorf("%s %s: expected graph type: %s, got: %s", f.name, typ, expType, gt)
+			}
+
+			u, err := f.unmarshaler(tc.schema)
+			if err != nil {
+				t.Fatalf("%s: failed to create unmarshaler: %v", f.name, err)
+			}
+
+			got, err := memory.NewGraph(memory.WithType(typ))
+			if err != nil {
+				t.Fatalf("failed to create graph: %v", err)
+			}
+
+			if err := u.Unmarshal(data, got); err != nil {
+				t.Fatalf("%s %s: failed to unmarshal graph: %v", f.name, typ, err)
+			}
+
+			if f.meta && (got.UID() != g.UID() || got.Label() != g.Label() || !reflect.DeepEqual(got.Attrs(), g.Attrs())) {
+				t.Errorf("%s %s: expected graph %s %s %v, got: %s %s %v", f.name, typ, g.UID(), g.Label(), g.Attrs(), got.UID(), got.Label(), got.Attrs())
+			}
+
+			for _, uid := range []string{"repo", "go-Topic"} {
+				exp, n := g.NodeWithUID(uid).(*memory.Node), got.NodeWithUID(uid)
+				if n == nil {
+					t.Fatalf("%s %s: node %s not found", f.name, typ, uid)
+				}
+
+				node := n.(*memory.Node)
+				if node.Label() != exp.Label() || node.Type() != exp.Type() || node.Shape() != exp.Shape() || node.Color() != exp.Color() {
+					t.Errorf("%s %s: expected node %s %s %v, got: %s %s %v", f.name, typ, exp.Label(), exp.Shape(), exp.Color(), node.Label(), node.Shape(), node.Color())
+				}
+
+				if !reflect.DeepEqual(node.Attrs(), exp.Attrs()) {
+					t.Errorf("%s %s: expected node attrs: %#v, got: %#v", f.name, typ, exp.Attrs(), node.Attrs())
+				}
+			}
+
+			if n := got.Edges().Len(); n != 1 {
+				t.Fatalf("%s %s: expected 1 edge, got: %d", f.name, typ, n)
+			}
+
+			e := got.Edge(got.NodeWithUID("repo").ID(), got.NodeWithUID("go-Topic").ID()).(*memory.Edge)
+			if e.UID() != "repo-go" || e.Label() != "HasTopic" || e.Weight() != 2.5 || e.Color() != style.DefaultEdgeColor {
+				t.Errorf("%s %s: unexpected edge: %s %s %v %v", f.name, typ, e.UID(), e.Label(), e.Weight(), e.Color())
+			}
+
+			if !reflect.DeepEqual(e.Attrs(), marshaltest.EdgeAttrs) {
+				t.Errorf("%s %s: unexpected edge attrs: %v", f.name, typ, e.Attrs())
+			}
+		}
+	}
+}
+
+func TestRoundTripSelfLoop(t *testing.T) {
+	// self-loops are skipped
+	for _, f := range formats {
+		typ, err := f.graphType([]byte(f.selfLoop))
+		if err != nil {
+			t.Fatalf("%s: failed to get graph type: %v", f.name, err)
+		}
+
+		if typ != graph.WeightedDirected {
+			t.Errorf("%s: expected graph type: %s, got: %s", f.name, graph.WeightedDirected, typ)
+		}
+
+		u, err := f.unmarshaler(nil)
+		if err != nil {
+			t.Fatalf("%s: failed to create unmarshaler: %v", f.name, err)
+		}
+
+		g, err := memory.NewGraph(memory.WithType(typ))
+		if err != nil {
+			t.Fatalf("failed to create graph: %v", err)
+		}
+
+		if err := u.Unmarshal([]byte(f.selfLoop), g); err != nil {
+			t.Fatalf("%s: failed to unmarshal graph: %v", f.name, err)
+		}
+
+		m, err := f.marshaler(nil)
+		if err != nil {
+			t.Fatalf("%s: failed to create marshaler: %v", f.name, err)
+		}
+
+		out, err := m.Marshal(g)
+		if err != nil {
+			t.Fatalf("%s: failed to marshal graph: %v", f.name, err)
+		}
+
+		got, err := memory.NewGraph(memory.WithType(typ))
+		if err != nil {
+			t.Fatalf("failed to create graph: %v", err)
+		}
+
+		if err := u.Unmarshal(out, got); err != nil {
+			t.Fatalf("%s: failed to unmarshal graph: %v", f.name, err)
+		}
+
+		for _, g := range []*memory.Graph{g, got} {
+			if n := g.Nodes().Len(); n != 2 {
+				t.Errorf("%s: expected 2 nodes, got: %d", f.name, n)
+			}
+
+			if n := g.Edges().Len(); n != 1 {
+				t.Errorf("%s: expected 1 edge, got: %d", f.name, n)
+			}
+
+			if f.edgeUID != "" && g.EdgeWithUID(f.edgeUID) == nil {
+				t.Errorf("%s: expected edge %s", f.name, f.edgeUID)
+			}
+		}
+	}
+}
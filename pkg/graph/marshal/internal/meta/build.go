@@ -0,0 +1,279 @@
+package meta
+
+import (
+	"encoding/json"
+	"image/color"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	"github.com/milosgajdos/orbnet/pkg/graph/style"
+)
+
+const (
+	// weightKey is the attribute which weights edges which have no weight metadata.
+	weightKey = "weight"
+)
+
+// Element is graph node or edge decoded from a data format.
+type Element struct {
+	// ID is element ID in the data.
+	ID string
+	// UID is element UID used if the element has no metadata.
+	// Node IDs are used as UIDs of the nodes which have neither.
+	UID string
+	// Source is the ID of edge source node.
+	Source string
+	// Target is the ID of edge target node.
+	Target string
+	// Label is element label used if the element has no metadata.
+	Label string
+	// Weight is edge weight used if the edge has no metadata.
+	Weight *float64
+	// Style is element style used if the element has no metadata.
+	Style *style.Style
+	// Attrs are element attributes including the metadata.
+	Attrs map[string]interface{}
+}
+
+// Build adds nodes and edges to g.
+// Element UIDs, labels, styles and edge weights are read from the metadata stored in the
+// attributes if present; otherwise the element UIDs, labels, weights and styles are used
+// and node IDs are used as the UIDs of the nodes without UID. Edges without weight are weighted by their
+// weight attribute if it's a number. Attribute values are converted to the types
+// declared in schema or stored in the metadata. Self-loops are skipped as the graph
+// nodes can't be connected to themselves.
+func Build(g graph.Graph, nodes, edges []Element, schema *attrs.Registry) error {
+	ga, ok := g.(graph.Adder)
+	if !ok {
+		return graph.Errorf(graph.EUNSUPPORTED, "Unable to update graph")
+	}
+
+	ids := make(map[string]*memory.Node, len(nodes))
+	for _, node := range nodes {
+		if _, ok := ids[node.ID]; ok {
+			return graph.Errorf(graph.EINVALID, "duplicate node: %s", node.ID)
+		}
+
+		m, a, err := split(node, schema.NodeSchema)
+		if err != nil {
+			return graph.Errorf(graph.EINVALID, "node %s: %v", node.ID, err)
+		}
+
+		s, err := m.style(node.Style, a, style.DefaultNode())
+		if err != nil {
+			return graph.Errorf(graph.EINVALID, "node %s: %v", node.ID, err)
+		}
+
+		uid := m.UID
+		if uid == "" {
+			uid = node.UID
+		}
+		if uid == "" {
+			uid = node.ID
+		}
+
+		n, err := memory.AddNewNode(ga,
+			memory.WithUID(uid),
+			memory.WithLabel(m.Label),
+			memory.WithAttrs(a),
+			memory.WithStyle(s),
+		)
+		if err != nil {
+			return err
+		}
+
+		ids[node.ID] = n
+	}
+
+	for _, edge := range edges {
+		from, ok := ids[edge.Source]
+		if !ok {
+			return graph.Errorf(graph.EINVALID, "edge %s: source node %s not found", edge.ID, edge.Source)
+		}
+
+		to, ok := ids[edge.Target]
+		if !ok {
+			return graph.Errorf(graph.EINVALID, "edge %s: target node %s not found", edge.ID, edge.Target)
+		}
+
+		if from.ID() == to.ID() {
+			continue
+		}
+
+		// dont create a new edge if an edge already exists
+		// unless the graph allows parallel edges
+		if e := ga.Edge(from.ID(), to.ID()); e != nil && !graph.IsMulti(g.Type()) {
+			continue
+		}
+
+		m, a, err := split(edge, schema.EdgeSchema)
+		if err != nil {
+			return graph.Errorf(graph.EINVALID, "edge %s: %v", edge.ID, err)
+		}
+
+		s, err := m.style(edge.Style, a, style.DefaultEdge())
+		if err != nil {
+			return graph.Errorf(graph.EINVALID, "edge %s: %v", edge.ID, err)
+		}
+
+		weight := memory.DefaultWeight
+		switch {
+		case m.Weight != nil:
+			weight = *m.Weight
+		case edge.Weight != nil:
+			weight = *edge.Weight
+		default:
+			if w, ok := a[weightKey].(float64); ok {
+				weight = w
+			}
+		}
+
+		opts := []memory.Option{
+			memory.WithLabel(m.Label),
+			memory.WithAttrs(a),
+			memory.WithWeight(weight),
+			memory.WithStyle(s),
+		}
+		uid := m.UID
+		if uid == "" {
+			uid = edge.UID
+		}
+		if uid != "" {
+			opts = append(opts, memory.WithUID(uid))
+		}
+
+		e, err := memory.NewEdge(from, to, opts...)
+		if err != nil {
+			return err
+		}
+
+		ga.SetWeightedEdge(e)
+	}
+
+	return nil
+}
+
+// GraphType returns the type of graph with the given edges.
+// The graph is a multigraph if any pair of its nodes is connected by more than one edge.
+// Self-loops are ignored as Build skips them.
+func GraphType(directed bool, edges []Element) string {
+	multi := false
+	seen := make(map[[2]string]bool, len(edges))
+	for _, e := range edges {
+		if e.Source == e.Target {
+			continue
+		}
+
+		line := [2]string{e.Source, e.Target}
+		if !directed && line[0] > line[1] {
+			line[0], line[1] = line[1], line[0]
+		}
+		if seen[line] {
+			multi = true
+			break
+		}
+		seen[line] = true
+	}
+
+	switch {
+	case directed && multi:
+		return graph.WeightedDirectedMulti
+	case directed:
+		return graph.WeightedDirected
+	case multi:
+		return graph.WeightedUndirectedMulti
+	default:
+		return graph.WeightedUndirected
+	}
+}
+
+// split returns the metadata and the attributes of element el.
+// The attributes stored in the metadata override the element attributes.
+// The attribute values are converted to the types declared in the schema returned
+// by schema for the element label or to the types stored in the metadata.
+func split(el Element, schema func(string) attrs.Schema) (Meta, map[string]interface{}, error) {
+	m := Meta{Label: el.Label}
+	if v, ok := el.Attrs[Key]; ok {
+		var err error
+		if m, err = Decode(v); err != nil {
+			return Meta{}, nil, graph.Errorf(graph.EINVALID, "invalid metadata: %v", err)
+		}
+	}
+
+	s := schema(m.Label)
+
+	raw := attrs.CopyFrom(el.Attrs)
+	delete(raw, Key)
+	for k, v := range m.Attrs {
+		raw[k] = v
+	}
+
+	a := make(map[string]interface{}, len(raw))
+	for k, v := range raw {
+
+		t, ok := s[k]
+		if !ok {
+			t, ok = m.Types[k]
+		}
+
+		if ok && v != nil {
+			cv, err := parse(t, v)
+			if err != nil {
+				return Meta{}, nil, graph.Errorf(graph.EINVALID, "attribute %q: %v", k, err)
+			}
+			v = cv
+		}
+
+		a[k] = v
+	}
+
+	return m, a, nil
+}
+
+// parse converts v to attribute type t.
+// Lists and maps encoded in JSON strings are decoded first.
+func parse(t attrs.Type, v interface{}) (interface{}, error) {
+	if str, ok := v.(string); ok && (t == attrs.List || t == attrs.Map) {
+		if err := json.Unmarshal([]byte(str), &v); err != nil {
+			return nil, err
+		}
+	}
+	return attrs.Coerce(t, v)
+}
+
+// style returns the element style stored in the metadata.
+// If the metadata has no style, fallback style is returned if it's not nil;
+// otherwise def style overridden by the style, shape and color attributes is returned.
+// Invalid metadata style colors are reported as errors.
+func (m Meta) style(fallback *style.Style, a map[string]interface{}, def style.Style) (style.Style, error) {
+	if m.Style == nil {
+		if fallback != nil {
+			return *fallback, nil
+		}
+		m.Style = &Style{}
+		m.Style.Type, _ = a["style"].(string)
+		m.Style.Shape, _ = a["shape"].(string)
+		// NOTE: colors which are not hex codes, e.g. DOT color names, are ignored
+		if c, err := attrs.Coerce(attrs.Color, a["color"]); err == nil && c != nil {
+			m.Style.Color = FormatColor(c.(color.RGBA))
+		}
+	}
+
+	s := def
+	if m.Style.Type != "" {
+		s.Type = m.Style.Type
+	}
+	if m.Style.Shape != "" {
+		s.Shape = m.Style.Shape
+	}
+	if m.Style.Color != "" {
+		c, err := attrs.Coerce(attrs.Color, m.Style.Color)
+		if err != nil {
+			return style.Style{}, graph.Errorf(graph.EINVALID, "style color: %v", err)
+		}
+		s.Color = c.(color.RGBA)
+	}
+
+	return s, nil
+}
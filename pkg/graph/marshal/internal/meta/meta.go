@@ -0,0 +1,217 @@
+// Package meta encodes the properties of graph nodes and edges which are not their
+// attributes, such as UIDs, labels, weights and styles, into a reserved attribute so
+// that formats which only store attributes can be unmarshaled back into graphs.
+package meta
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"sort"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+)
+
+const (
+	// Key is the reserved attribute which stores the metadata.
+	Key = "orbnet"
+)
+
+// Style is element style.
+type Style struct {
+	// Type is style type.
+	Type string `json:"type,omitempty"`
+	// Shape is style shape.
+	Shape string `json:"shape,omitempty"`
+	// Color is hex code of style color.
+	Color string `json:"color,omitempty"`
+}
+
+// Meta stores the properties of graph elements.
+type Meta struct {
+	// UID is element UID.
+	UID string `json:"uid,omitempty"`
+	// Label is element label.
+	Label string `json:"label,omitempty"`
+	// Weight is edge weight.
+	Weight *float64 `json:"weight,omitempty"`
+	// Style is element style.
+	Style *Style `json:"style,omitempty"`
+	// Attrs are the attributes whose keys are reserved by data formats.
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+	// Types are the types of attributes which are not strings.
+	// Formats which store attributes as strings use them to restore attribute values.
+	Types map[string]attrs.Type `json:"types,omitempty"`
+}
+
+// edgeStyler is implemented by styled edges.
+type edgeStyler interface {
+	Style() string
+	Shape() string
+	Color() color.RGBA
+}
+
+// Node returns the metadata of node n.
+func Node(n graph.Node) Meta {
+	m := Meta{
+		UID:   n.UID(),
+		Label: n.Label(),
+	}
+
+	if s, ok := n.(graph.Styler); ok {
+		m.Style = &Style{
+			Type:  s.Type(),
+			Shape: s.Shape(),
+			Color: FormatColor(s.Color()),
+		}
+	}
+
+	return m
+}
+
+// Edge returns the metadata of edge e.
+func Edge(e graph.Edge) Meta {
+	w := e.Weight()
+
+	m := Meta{
+		UID:    e.UID(),
+		Label:  e.Label(),
+		Weight: &w,
+	}
+
+	if s, ok := e.(edgeStyler); ok {
+		m.Style = &Style{
+			Type:  s.Style(),
+			Shape: s.Shape(),
+			Color: FormatColor(s.Color()),
+		}
+	}
+
+	return m
+}
+
+// Reserve moves the attributes of a with the given keys to the metadata
+// and returns a copy of a without them.
+func (m *Meta) Reserve(a map[string]interface{}, keys ...string) map[string]interface{} {
+	ra := attrs.CopyFrom(a)
+	for _, k := range keys {
+		v, ok := ra[k]
+		if !ok {
+			continue
+		}
+		if m.Attrs == nil {
+			m.Attrs = make(map[string]interface{})
+		}
+		m.SetType(k, v)
+		m.Attrs[k] = v
+		delete(ra, k)
+	}
+	return ra
+}
+
+// Attrs returns a copy of attributes a with metadata m stored in Key.
+// The types of the attributes which are not strings are stored in the metadata.
+func Attrs(a map[string]interface{}, m Meta) map[string]interface{} {
+	ma := attrs.CopyFrom(a)
+	for k, v := range a {
+		m.SetType(k, v)
+	}
+	ma[Key] = m
+	return ma
+}
+
+// Strings returns attributes a formatted as strings ordered by their keys
+// with metadata m encoded in JSON stored in Key. The types of the attributes
+// which are not strings are stored in the metadata. Nil attributes are omitted.
+func Strings(a map[string]interface{}, m Meta) ([]Attr, error) {
+	keys := make([]string, 0, len(a))
+	for k, v := range a {
+		if v == nil || k == Key {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	strs := make([]Attr, 0, len(keys)+1)
+	for _, k := range keys {
+		m.SetType(k, a[k])
+		strs = append(strs, Attr{Key: k, Value: Format(attrs.TypeOf(a[k]), a[k])})
+	}
+
+	data, err := m.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(strs, Attr{Key: Key, Value: data}), nil
+}
+
+// SetType stores the type of attribute k whose value is v unless it's a string.
+func (m *Meta) SetType(k string, v interface{}) {
+	t := attrs.TypeOf(v)
+	if v == nil || t == attrs.String {
+		return
+	}
+	if m.Types == nil {
+		m.Types = make(map[string]attrs.Type)
+	}
+	m.Types[k] = t
+}
+
+// Encode returns the metadata encoded in JSON.
+func (m Meta) Encode() (string, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Attr is attribute formatted as string.
+type Attr struct {
+	Key   string
+	Value string
+}
+
+// Format returns string representation of v of attribute type t.
+// Unlike attrs.Format it preserves the alpha channel of colors which are not opaque.
+func Format(t attrs.Type, v interface{}) string {
+	if c, ok := v.(color.RGBA); ok && t == attrs.Color {
+		return FormatColor(c)
+	}
+	return attrs.Format(t, v)
+}
+
+// FormatColor returns hex code of c.
+// Colors which are not opaque are formatted as #rrggbbaa.
+func FormatColor(c color.RGBA) string {
+	if c.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// Decode decodes the metadata stored in v.
+// v is either JSON encoded metadata or its decoded JSON object.
+func Decode(v interface{}) (Meta, error) {
+	var data []byte
+
+	switch val := v.(type) {
+	case string:
+		data = []byte(val)
+	default:
+		var err error
+		if data, err = json.Marshal(val); err != nil {
+			return Meta{}, err
+		}
+	}
+
+	var m Meta
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Meta{}, err
+	}
+
+	return m, nil
+}
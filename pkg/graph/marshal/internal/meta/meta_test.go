@@ -0,0 +1,107 @@
+package meta
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+)
+
+func TestFormatColor(t *testing.T) {
+	testCases := []struct {
+		c   color.RGBA
+		exp string
+	}{
+		{color.RGBA{R: 1, G: 2, B: 3, A: 255}, "#010203"},
+		{color.RGBA{R: 1, G: 2, B: 3, A: 128}, "#01020380"},
+		{color.RGBA{}, "#00000000"},
+	}
+
+	for _, tc := range testCases {
+		if got := FormatColor(tc.c); got != tc.exp {
+			t.Errorf("expected color: %s, got: %s", tc.exp, got)
+		}
+	}
+}
+
+func TestStrings(t *testing.T) {
+	a := map[string]interface{}{
+		"name":       "foo",
+		"stars":      int64(10),
+		"starred_at": time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		"none":       nil,
+		Key:          "ignored",
+	}
+
+	strs, err := Strings(a, Meta{UID: "foo"})
+	if err != nil {
+		t.Fatalf("failed to format attributes: %v", err)
+	}
+
+	exp := []Attr{
+		{Key: "name", Value: "foo"},
+		{Key: "starred_at", Value: "2021-01-02T03:04:05Z"},
+		{Key: "stars", Value: "10"},
+		{Key: Key, Value: `{"uid":"foo","types":{"starred_at":"time","stars":"int"}}`},
+	}
+
+	if !reflect.DeepEqual(strs, exp) {
+		t.Errorf("expected attributes: %v, got: %v", exp, strs)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	w := 2.5
+	exp := Meta{
+		UID:    "foo",
+		Label:  "Foo",
+		Weight: &w,
+		Style:  &Style{Type: "filled", Shape: "box", Color: "#010203"},
+		Attrs:  map[string]interface{}{"id": "bar"},
+		Types:  map[string]attrs.Type{"stars": attrs.Int},
+	}
+
+	data, err := exp.Encode()
+	if err != nil {
+		t.Fatalf("failed to encode metadata: %v", err)
+	}
+
+	for _, v := range []interface{}{data, Attrs(nil, exp)[Key]} {
+		m, err := Decode(v)
+		if err != nil {
+			t.Fatalf("failed to decode metadata: %v", err)
+		}
+
+		if !reflect.DeepEqual(m, exp) {
+			t.Errorf("expected metadata: %#v, got: %#v", exp, m)
+		}
+	}
+
+	if _, err := Decode(1); err == nil {
+		t.Errorf("expected error decoding invalid metadata")
+	}
+}
+
+func TestGraphType(t *testing.T) {
+	testCases := []struct {
+		directed bool
+		edges    []Element
+		exp      string
+	}{
+		{true, []Element{{Source: "a", Target: "b"}, {Source: "b", Target: "a"}}, graph.WeightedDirected},
+		{true, []Element{{Source: "a", Target: "b"}, {Source: "a", Target: "b"}}, graph.WeightedDirectedMulti},
+		{false, []Element{{Source: "a", Target: "b"}}, graph.WeightedUndirected},
+		{false, []Element{{Source: "a", Target: "b"}, {Source: "b", Target: "a"}}, graph.WeightedUndirectedMulti},
+		// self-loops are ignored
+		{true, []Element{{Source: "a", Target: "a"}, {Source: "a", Target: "a"}}, graph.WeightedDirected},
+	}
+
+	for _, tc := range testCases {
+		if got := GraphType(tc.directed, tc.edges); got != tc.exp {
+			t.Errorf("expected graph type: %s, got: %s", tc.exp, got)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Package mermaid marshals small graphs into Mermaid flowcharts
+// which can be embedded in Markdown documents.
+package mermaid
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/diagram"
+)
+
+const (
+	// strokeColor is node outline color.
+	strokeColor = "#333333"
+)
+
+// escaper escapes flowchart text as Mermaid entity codes.
+var escaper = strings.NewReplacer(
+	"#", "#35;",
+	`"`, "#34;",
+	"&", "#38;",
+	"<", "#60;",
+	">", "#62;",
+	"`", "#96;",
+	"\r\n", " ",
+	"\n", " ",
+	"\r", " ",
+)
+
+// Marshaler implements graph.Marshaler.
+type Marshaler struct {
+	opts Options
+}
+
+// NewMarshaler creates a new Marshaler and returns it.
+func NewMarshaler(opts ...Option) (*Marshaler, error) {
+	mopts := Options{
+		Direction: DefaultDirection,
+		MaxNodes:  DefaultMaxNodes,
+		NameKeys:  DefaultNameKeys,
+	}
+
+	for _, apply := range opts {
+		apply(&mopts)
+	}
+
+	switch mopts.Direction {
+	case TopDown, BottomUp, LeftRight, RightLeft:
+	default:
+		return nil, graph.Errorf(graph.EINVALID, "unsupported direction: %q", mopts.Direction)
+	}
+
+	if mopts.MaxNodes <= 0 {
+		return nil, graph.Errorf(graph.EINVALID, "invalid max nodes: %d", mopts.MaxNodes)
+	}
+
+	return &Marshaler{
+		opts: mopts,
+	}, nil
+}
+
+// Marshal marshals g into Mermaid flowchart.
+// Node shapes are mapped to the nearest flowchart shapes and node colors to class styles.
+// Graphs which have more than MaxNodes nodes are marshaled as per Truncate option.
+func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
+	var b bytes.Buffer
+	if err := m.MarshalTo(&b, g); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo marshals g into Mermaid flowchart written to w.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	sg, err := diagram.Limit(g, m.opts.MaxNodes, m.opts.Truncate)
+	if err != nil {
+		return err
+	}
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, "flowchart %s\n", m.opts.Direction)
+	if n, total := sg.Nodes().Len(), g.Nodes().Len(); n < total {
+		fmt.Fprintf(b, "    %%%% truncated: %d of %d nodes\n", n, total)
+	}
+
+	nodes := diagram.SortedNodes(sg)
+
+	ids := make(map[int64]string, len(nodes))
+	classes := make(map[string][]string)
+
+	for i, n := range nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[n.ID()] = id
+
+		start, end := shape(diagram.NodeShape(n))
+		fmt.Fprintf(b, "    %s%s\"%s\"%s\n", id, start, escaper.Replace(diagram.NodeName(n, m.opts.NameKeys)), end)
+
+		c := diagram.NodeHex(n)
+		classes[c] = append(classes[c], id)
+	}
+
+	arrow := "---"
+	if graph.IsDirected(g.Type()) {
+		arrow = "-->"
+	}
+
+	for _, e := range diagram.SortedEdges(sg) {
+		from, to := ids[e.From().ID()], ids[e.To().ID()]
+		if l := e.Label(); l != "" {
+			fmt.Fprintf(b, "    %s %s|\"%s\"| %s\n", from, arrow, escaper.Replace(l), to)
+			continue
+		}
+		fmt.Fprintf(b, "    %s %s %s\n", from, arrow, to)
+	}
+
+	colors := make([]string, 0, len(classes))
+	for c := range classes {
+		colors = append(colors, c)
+	}
+	sort.Strings(colors)
+
+	for _, c := range colors {
+		fmt.Fprintf(b, "    classDef %s fill:%s,stroke:%s\n", diagram.Class(c), c, strokeColor)
+		fmt.Fprintf(b, "    class %s %s\n", strings.Join(classes[c], ","), diagram.Class(c))
+	}
+
+	return b.Flush()
+}
+
+// shape returns the delimiters of flowchart node shape nearest to shape s.
+func shape(s string) (string, string) {
+	switch s {
+	case "circle", "point":
+		return "((", "))"
+	case "ellipse", "oval":
+		return "([", "])"
+	case "box", "rect", "rectangle", "square":
+		return "[", "]"
+	case "diamond":
+		return "{", "}"
+	case "hexagon":
+		return "{{", "}}"
+	case "triangle", "trapezium":
+		return "[/", `\]`
+	case "invtriangle", "invtrapezium":
+		return `[\`, "/]"
+	case "parallelogram":
+		return "[/", "/]"
+	case "cylinder":
+		return "[(", ")]"
+	default:
+		return "(", ")"
+	}
+}
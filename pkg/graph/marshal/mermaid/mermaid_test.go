@@ -0,0 +1,177 @@
+package mermaid
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	"github.com/milosgajdos/orbnet/pkg/graph/style"
+)
+
+func MustGraph(t *testing.T, topics int) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph(memory.WithType(graph.WeightedDirected))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	repo, err := memory.NewNode(g.NewNode().ID(),
+		memory.WithUID("repo"),
+		memory.WithLabel("Repo"),
+		memory.WithAttrs(map[string]interface{}{"full_name": `foo/"bar" <#1>`}),
+		memory.WithStyle(style.Style{Shape: "diamond", Color: color.RGBA{R: 0, G: 255, B: 153}}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(repo)
+
+	for i := 0; i < topics; i++ {
+		topic, err := memory.NewNode(g.NewNode().ID(),
+			memory.WithUID(fmt.Sprintf("topic%d", i)),
+			memory.WithLabel("Topic"),
+			memory.WithStyle(style.Style{Shape: "ellipse", Color: color.RGBA{R: 153, G: 153, B: 255}}),
+		)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(topic)
+
+		e, err := memory.NewEdge(repo, topic, memory.WithLabel("HasTopic"))
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(e)
+	}
+
+	return g
+}
+
+func TestMarshal(t *testing.T) {
+	g := MustGraph(t, 2)
+	g.NodeWithUID("topic1").Attrs()["color"] = "#ff0000"
+
+	m, err := NewMarshaler()
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	data, err := m.Marshal(g)
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	exp := `flowchart LR
+    n0{"foo/#34;bar#34; #60;#35;1#62;"}
+    n1(["topic0"])
+    n2(["topic1"])
+    n0 -->|"HasTopic"| n1
+    n0 -->|"HasTopic"| n2
+    classDef c00ff99 fill:#00ff99,stroke:#333333
+    class n0 c00ff99
+    classDef c9999ff fill:#9999ff,stroke:#333333
+    class n1 c9999ff
+    classDef cff0000 fill:#ff0000,stroke:#333333
+    class n2 cff0000
+`
+
+	if string(data) != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, data)
+	}
+}
+
+func TestMarshalUndirected(t *testing.T) {
+	g, err := memory.NewGraph(memory.WithType(graph.WeightedUndirected))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	a, err := memory.NewNode(g.NewNode().ID(), memory.WithUID("a"))
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(a)
+
+	b, err := memory.NewNode(g.NewNode().ID(), memory.WithUID("b\nc"))
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(b)
+
+	e, err := memory.NewEdge(a, b)
+	if err != nil {
+		t.Fatalf("failed to create edge: %v", err)
+	}
+	g.SetWeightedEdge(e)
+
+	m, err := NewMarshaler(WithDirection(TopDown))
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	data, err := m.Marshal(g)
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	for _, line := range []string{"flowchart TD\n", `n1{{"b c"}}`, "n0 --- n1\n"} {
+		if !strings.Contains(string(data), line) {
+			t.Errorf("expected %q in:\n%s", line, data)
+		}
+	}
+}
+
+func TestMarshalLimit(t *testing.T) {
+	g := MustGraph(t, 5)
+
+	m, err := NewMarshaler(WithMaxNodes(3))
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	if _, err := m.Marshal(g); graph.ErrorCode(err) != graph.EINVALID {
+		t.Fatalf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+
+	m, err = NewMarshaler(WithMaxNodes(3), WithTruncate())
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	data, err := m.Marshal(g)
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	if !strings.Contains(string(data), "%% truncated: 3 of 6 nodes\n") {
+		t.Errorf("expected truncation comment in:\n%s", data)
+	}
+
+	if !strings.Contains(string(data), "foo/") {
+		t.Errorf("expected the repo with the highest degree in:\n%s", data)
+	}
+
+	if n := strings.Count(string(data), "-->"); n != 2 {
+		t.Errorf("expected 2 edges, got: %d", n)
+	}
+}
+
+func TestNewMarshalerErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{"Direction", []Option{WithDirection("foo")}},
+		{"MaxNodes", []Option{WithMaxNodes(0)}},
+	}
+
+	for _, tc := range testCases {
+		if _, err := NewMarshaler(tc.opts...); graph.ErrorCode(err) != graph.EINVALID {
+			t.Errorf("%s: expected error: %s, got: %v", tc.name, graph.EINVALID, err)
+		}
+	}
+}
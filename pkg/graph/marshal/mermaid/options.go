@@ -0,0 +1,71 @@
+package mermaid
+
+import "github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/diagram"
+
+// Direction is flowchart direction.
+type Direction string
+
+const (
+	// TopDown lays out flowchart from top to bottom.
+	TopDown Direction = "TD"
+	// BottomUp lays out flowchart from bottom to top.
+	BottomUp Direction = "BT"
+	// LeftRight lays out flowchart from left to right.
+	LeftRight Direction = "LR"
+	// RightLeft lays out flowchart from right to left.
+	RightLeft Direction = "RL"
+)
+
+const (
+	// DefaultDirection is default flowchart direction.
+	DefaultDirection = LeftRight
+	// DefaultMaxNodes is default max number of marshaled nodes.
+	DefaultMaxNodes = diagram.MaxNodes
+)
+
+// DefaultNameKeys are default node attributes which store node names.
+var DefaultNameKeys = diagram.NameKeys
+
+// Options configure marshaler.
+type Options struct {
+	// Direction is flowchart direction.
+	Direction Direction
+	// MaxNodes is the max number of marshaled nodes.
+	MaxNodes int
+	// Truncate marshals MaxNodes nodes with the highest degree of
+	// graphs which have more nodes instead of refusing to marshal them.
+	Truncate bool
+	// NameKeys are node attributes which store node names in the order of precedence.
+	NameKeys []string
+}
+
+// Option is functional marshaler option.
+type Option func(*Options)
+
+// WithDirection sets Direction option.
+func WithDirection(d Direction) Option {
+	return func(o *Options) {
+		o.Direction = d
+	}
+}
+
+// WithMaxNodes sets MaxNodes option.
+func WithMaxNodes(n int) Option {
+	return func(o *Options) {
+		o.MaxNodes = n
+	}
+}
+
+// WithTruncate sets Truncate option.
+func WithTruncate() Option {
+	return func(o *Options) {
+		o.Truncate = true
+	}
+}
+
+// WithNameKeys sets NameKeys option.
+func WithNameKeys(keys ...string) Option {
+	return func(o *Options) {
+		o.NameKeys = keys
+	}
+}
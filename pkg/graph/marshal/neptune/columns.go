@@ -0,0 +1,123 @@
+package neptune
+
+import (
+	"strings"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/columns"
+)
+
+const (
+	// arrayDelimiter separates the values of array properties.
+	arrayDelimiter = ";"
+)
+
+// properties are the property columns of nodes or edges.
+type properties struct {
+	*columns.Columns
+}
+
+// newProperties creates new property columns.
+// The types of the attributes declared in schema s are not inferred.
+// The attributes with the skipped names are not marshaled as properties.
+func newProperties(s attrs.Schema, skip ...string) properties {
+	return properties{Columns: columns.New(s, skip...)}
+}
+
+// isArray returns true if the property with the given name is marshaled as array.
+// Lists of lists or maps can't be marshaled as arrays.
+func (p properties) isArray(name string, arrays bool) bool {
+	return arrays && p.IsFlat(name)
+}
+
+// header returns the column headers of properties.
+// Lists are marshaled as arrays only if arrays is true.
+func (p properties) header(d Dialect, arrays bool) []string {
+	header := make([]string, len(p.Names()))
+	for i, name := range p.Names() {
+		if p.isArray(name, arrays) {
+			header[i] = name + ":" + typeName(d, p.Elem(name)) + "[]"
+			continue
+		}
+		header[i] = name + ":" + typeName(d, p.Type(name))
+	}
+	return header
+}
+
+// values returns the property values of attributes a.
+func (p properties) values(a map[string]interface{}, arrays bool) []string {
+	values := make([]string, len(p.Names()))
+	for i, name := range p.Names() {
+		v := a[name]
+		if v == nil {
+			continue
+		}
+
+		if p.isArray(name, arrays) {
+			values[i] = p.array(name, v)
+			continue
+		}
+
+		values[i] = format(p.Type(name), v)
+	}
+	return values
+}
+
+// array returns the values of list v joined by arrayDelimiter.
+func (p properties) array(name string, v interface{}) string {
+	l, err := attrs.Coerce(attrs.List, v)
+	if err != nil {
+		return format(attrs.String, v)
+	}
+
+	elems := make([]string, 0, len(l.([]interface{})))
+	for _, e := range l.([]interface{}) {
+		if e == nil {
+			continue
+		}
+		elems = append(elems, format(p.Elem(name), e))
+	}
+
+	return strings.Join(elems, arrayDelimiter)
+}
+
+// format returns string representation of v of attribute type t.
+// Values of String properties are formatted as per their own types.
+func format(t attrs.Type, v interface{}) string {
+	if t == attrs.String || t == "" {
+		t = attrs.TypeOf(v)
+	}
+	return attrs.Format(t, v)
+}
+
+// typeName returns the name of dialect property type for the given attribute type.
+// Colors and maps are marshaled as strings.
+func typeName(d Dialect, t attrs.Type) string {
+	if d == Neo4j {
+		switch t {
+		case attrs.Int:
+			return "long"
+		case attrs.Float:
+			return "double"
+		case attrs.Bool:
+			return "boolean"
+		case attrs.Time:
+			return "datetime"
+		default:
+			return "string"
+		}
+	}
+
+	switch t {
+	case attrs.Int:
+		return "Long"
+	case attrs.Float:
+		return "Double"
+	case attrs.Bool:
+		return "Bool"
+	case attrs.Time:
+		return "Date"
+	default:
+		return "String"
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/milosgajdos/orbnet/pkg/graph"
 )
 
+// MarshalNodesCSV writes the nodes of g with the properties mapped by propMap in Neptune CSV to w.
+//
+// Deprecated: use Marshaler which derives the properties from node attributes.
 func MarshalNodesCSV(g graph.Graph, propMap map[string]func(interface{}) string, w io.Writer) error {
 	out := csv.NewWriter(w)
 	header := []string{"~id", "~label"}
@@ -48,6 +51,9 @@ func MarshalNodesCSV(g graph.Graph, propMap map[string]func(interface{}) string,
 	return out.Error()
 }
 
+// MarshalEdgesCSV writes the edges of g with the properties mapped by propMap in Neptune CSV to w.
+//
+// Deprecated: use Marshaler which derives the properties from edge attributes.
 func MarshalEdgesCSV(g graph.Graph, propMap map[string]func(interface{}) string, w io.Writer) error {
 	out := csv.NewWriter(w)
 	header := []string{"~id", "~from", "~to", "~label"}
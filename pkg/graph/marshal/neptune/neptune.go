@@ -0,0 +1,243 @@
+package neptune
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+)
+
+const (
+	// NodesFile is the name of nodes CSV file.
+	NodesFile = "nodes.csv"
+	// EdgesFile is the name of edges CSV file.
+	EdgesFile = "edges.csv"
+	// DefaultEdgeLabel is the label of edges which have no label.
+	DefaultEdgeLabel = "Undefined"
+)
+
+const (
+	// uidProp is the property which stores neo4j node and edge UIDs.
+	uidProp = "uid"
+	// weightProp is the property which stores edge weights.
+	weightProp = "weight"
+)
+
+// Marshaler marshals graphs into bulk load CSV files.
+type Marshaler struct {
+	opts Options
+}
+
+// NewMarshaler creates a new CSV marshaler and returns it.
+func NewMarshaler(opts ...Option) (*Marshaler, error) {
+	mopts := Options{
+		Dialect: DefaultDialect,
+	}
+
+	for _, apply := range opts {
+		apply(&mopts)
+	}
+
+	if d := mopts.Dialect; d != Gremlin && d != Neo4j {
+		return nil, graph.Errorf(graph.EINVALID, "unsupported dialect: %q", d)
+	}
+
+	return &Marshaler{
+		opts: mopts,
+	}, nil
+}
+
+// file is a CSV file.
+type file struct {
+	name    string
+	marshal func(graph.Graph, io.Writer) error
+}
+
+// files returns the CSV files of graph.
+func (m *Marshaler) files() []file {
+	return []file{
+		{NodesFile, m.MarshalNodes},
+		{EdgesFile, m.MarshalEdges},
+	}
+}
+
+// Marshal marshals g into a zip archive which contains NodesFile and EdgesFile.
+func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
+	var b bytes.Buffer
+	if err := m.MarshalTo(&b, g); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo writes a zip archive which contains NodesFile and EdgesFile of g to w.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	zw := zip.NewWriter(w)
+
+	for _, f := range m.files() {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+
+		if err := f.marshal(g, fw); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// WriteDir writes NodesFile and EdgesFile of g into dir.
+// It creates dir if it does not exist.
+func (m *Marshaler) WriteDir(g graph.Graph, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, f := range m.files() {
+		if err := writeFile(filepath.Join(dir, f.name), g, f.marshal); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarshalNodes writes the nodes of g ordered by their UIDs in CSV to w.
+// Node UIDs are used as node IDs and the property columns and their
+// types are derived from node attributes and the schema.
+func (m *Marshaler) MarshalNodes(g graph.Graph, w io.Writer) error {
+	var skip []string
+	if m.opts.Dialect == Neo4j {
+		skip = append(skip, uidProp)
+	}
+
+	cols := newProperties(m.opts.Schema.NodeKeys(), skip...)
+
+	var nodes []graph.Node
+	it := g.Nodes()
+	for it.Next() {
+		n := it.Node().(graph.Node)
+		cols.Add(n.Attrs())
+		nodes = append(nodes, n)
+	}
+	cols.Sort()
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].UID() < nodes[j].UID()
+	})
+
+	header := []string{"~id", "~label"}
+	if m.opts.Dialect == Neo4j {
+		header = []string{uidProp + ":ID", ":LABEL"}
+	}
+
+	out := csv.NewWriter(w)
+
+	if err := out.Write(append(header, cols.header(m.opts.Dialect, true)...)); err != nil {
+		return err
+	}
+
+	for _, n := range nodes {
+		record := append([]string{n.UID(), n.Label()}, cols.values(n.Attrs(), true)...)
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+
+	return out.Error()
+}
+
+// MarshalEdges writes the edges of g ordered by the UIDs of their nodes in CSV to w.
+// The edge weight is marshaled as weight property which takes precedence over the weight attribute.
+// Neptune does not support edge array properties so lists are marshaled as JSON strings.
+func (m *Marshaler) MarshalEdges(g graph.Graph, w io.Writer) error {
+	skip := []string{weightProp}
+	if m.opts.Dialect == Neo4j {
+		skip = append(skip, uidProp)
+	}
+
+	cols := newProperties(m.opts.Schema.EdgeKeys(), skip...)
+
+	var edges []graph.Edge
+	it := g.Edges()
+	for it.Next() {
+		e := it.Edge().(graph.Edge)
+		cols.Add(e.Attrs())
+		edges = append(edges, e)
+	}
+	cols.Sort()
+
+	sort.Slice(edges, func(i, j int) bool {
+		fi, fj := edges[i].From().(graph.Node).UID(), edges[j].From().(graph.Node).UID()
+		if fi != fj {
+			return fi < fj
+		}
+		ti, tj := edges[i].To().(graph.Node).UID(), edges[j].To().(graph.Node).UID()
+		if ti != tj {
+			return ti < tj
+		}
+		return edges[i].UID() < edges[j].UID()
+	})
+
+	d := m.opts.Dialect
+	arrays := d == Neo4j
+
+	header := []string{"~id", "~from", "~to", "~label", weightProp + ":" + typeName(d, attrs.Float)}
+	if d == Neo4j {
+		header = []string{":START_ID", ":END_ID", ":TYPE", weightProp + ":" + typeName(d, attrs.Float), uidProp + ":" + typeName(d, attrs.String)}
+	}
+
+	out := csv.NewWriter(w)
+
+	if err := out.Write(append(header, cols.header(d, arrays)...)); err != nil {
+		return err
+	}
+
+	for _, e := range edges {
+		label := e.Label()
+		if label == "" {
+			label = DefaultEdgeLabel
+		}
+
+		from, to := e.From().(graph.Node).UID(), e.To().(graph.Node).UID()
+		weight := attrs.Format(attrs.Float, e.Weight())
+
+		record := []string{e.UID(), from, to, label, weight}
+		if d == Neo4j {
+			record = []string{from, to, label, weight, e.UID()}
+		}
+
+		if err := out.Write(append(record, cols.values(e.Attrs(), arrays)...)); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+
+	return out.Error()
+}
+
+// writeFile creates file at path and marshals g into it.
+func writeFile(path string, g graph.Graph, marshal func(graph.Graph, io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := marshal(g, f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,208 @@
+package neptune
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+func MustGraph(t *testing.T) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	repo, err := memory.NewNode(g.NewNode().ID(),
+		memory.WithUID("repo"),
+		memory.WithLabel("Repo"),
+		memory.WithAttrs(map[string]interface{}{
+			"name":       "foo, bar",
+			"stars":      int64(10),
+			"fork":       false,
+			"starred_at": "2021-01-02T03:04:05Z",
+			"topics":     []interface{}{"go", "graph"},
+			"license":    map[string]interface{}{"key": "mit"},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(repo)
+
+	topic, err := memory.NewNode(g.NewNode().ID(),
+		memory.WithUID("go-Topic"),
+		memory.WithLabel("Topic"),
+		memory.WithAttrs(map[string]interface{}{"name": "go", "stars": 1.5}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(topic)
+
+	e, err := memory.NewEdge(repo, topic,
+		memory.WithUID("repo-go"),
+		memory.WithLabel("HasTopic"),
+		memory.WithWeight(2.5),
+		memory.WithAttrs(map[string]interface{}{"weight": 1.0, "scores": []float64{1, 0.5}}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create edge: %v", err)
+	}
+	g.SetWeightedEdge(e)
+
+	return g
+}
+
+func MustSchema(t *testing.T) *attrs.Registry {
+	t.Helper()
+
+	r := attrs.NewRegistry()
+	if err := r.SetNodeSchema("Repo", attrs.Schema{"starred_at": attrs.Time}); err != nil {
+		t.Fatalf("failed to set node schema: %v", err)
+	}
+
+	return r
+}
+
+func TestMarshal(t *testing.T) {
+	testCases := []struct {
+		dialect Dialect
+		nodes   string
+		edges   string
+	}{
+		{
+			dialect: Gremlin,
+			nodes: "~id,~label,fork:Bool,license:String,name:String,starred_at:Date,stars:Double,topics:String[]\n" +
+				"go-Topic,Topic,,,go,,1.5,\n" +
+				`repo,Repo,false,"{""key"":""mit""}","foo, bar",2021-01-02T03:04:05Z,10,go;graph` + "\n",
+			edges: "~id,~from,~to,~label,weight:Double,scores:String\n" +
+				`repo-go,repo,go-Topic,HasTopic,2.5,"[1,0.5]"` + "\n",
+		},
+		{
+			dialect: Neo4j,
+			nodes: "uid:ID,:LABEL,fork:boolean,license:string,name:string,starred_at:datetime,stars:double,topics:string[]\n" +
+				"go-Topic,Topic,,,go,,1.5,\n" +
+				`repo,Repo,false,"{""key"":""mit""}","foo, bar",2021-01-02T03:04:05Z,10,go;graph` + "\n",
+			edges: ":START_ID,:END_ID,:TYPE,weight:double,uid:string,scores:double[]\n" +
+				"repo,go-Topic,HasTopic,2.5,repo-go,1;0.5\n",
+		},
+	}
+
+	g := MustGraph(t)
+
+	for _, tc := range testCases {
+		m, err := NewMarshaler(WithDialect(tc.dialect), WithSchema(MustSchema(t)))
+		if err != nil {
+			t.Fatalf("failed to create marshaler: %v", err)
+		}
+
+		var nodes, edges bytes.Buffer
+		if err := m.MarshalNodes(g, &nodes); err != nil {
+			t.Fatalf("%s: failed to marshal nodes: %v", tc.dialect, err)
+		}
+
+		if err := m.MarshalEdges(g, &edges); err != nil {
+			t.Fatalf("%s: failed to marshal edges: %v", tc.dialect, err)
+		}
+
+		if nodes.String() != tc.nodes {
+			t.Errorf("%s: expected nodes:\n%s\ngot:\n%s", tc.dialect, tc.nodes, nodes.String())
+		}
+
+		if edges.String() != tc.edges {
+			t.Errorf("%s: expected edges:\n%s\ngot:\n%s", tc.dialect, tc.edges, edges.String())
+		}
+
+		data, err := m.Marshal(g)
+		if err != nil {
+			t.Fatalf("%s: failed to marshal graph: %v", tc.dialect, err)
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatalf("%s: failed to read archive: %v", tc.dialect, err)
+		}
+
+		exp := map[string]string{NodesFile: tc.nodes, EdgesFile: tc.edges}
+		if len(zr.File) != len(exp) {
+			t.Fatalf("%s: expected %d files, got: %d", tc.dialect, len(exp), len(zr.File))
+		}
+
+		for _, f := range zr.File {
+			r, err := f.Open()
+			if err != nil {
+				t.Fatalf("%s: failed to open %s: %v", tc.dialect, f.Name, err)
+			}
+
+			b, err := io.ReadAll(r)
+			r.Close()
+			if err != nil {
+				t.Fatalf("%s: failed to read %s: %v", tc.dialect, f.Name, err)
+			}
+
+			if string(b) != exp[f.Name] {
+				t.Errorf("%s: unexpected %s: %s", tc.dialect, f.Name, b)
+			}
+		}
+	}
+}
+
+func TestWriteDir(t *testing.T) {
+	m, err := NewMarshaler()
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "csv")
+	if err := m.WriteDir(MustGraph(t), dir); err != nil {
+		t.Fatalf("failed to write files: %v", err)
+	}
+
+	for _, name := range []string{NodesFile, EdgesFile} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("failed to stat %s: %v", name, err)
+		}
+
+		if info.Size() == 0 {
+			t.Errorf("expected non-empty %s", name)
+		}
+	}
+}
+
+func TestNewMarshalerErrors(t *testing.T) {
+	if _, err := NewMarshaler(WithDialect("foo")); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	ts := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		t   attrs.Type
+		v   interface{}
+		exp string
+	}{
+		{attrs.String, ts, "2021-01-02T03:04:05Z"},
+		{attrs.String, []interface{}{"a"}, `["a"]`},
+		{attrs.Int, 10.0, "10"},
+		{attrs.Time, "2021-01-02T03:04:05Z", "2021-01-02T03:04:05Z"},
+	}
+
+	for _, tc := range testCases {
+		if got := format(tc.t, tc.v); got != tc.exp {
+			t.Errorf("%s %v: expected: %s, got: %s", tc.t, tc.v, tc.exp, got)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package neptune
+
+import "github.com/milosgajdos/orbnet/pkg/graph/attrs"
+
+// Dialect is bulk load CSV dialect.
+type Dialect string
+
+const (
+	// Gremlin is Amazon Neptune Gremlin load data format.
+	// See: https://docs.aws.amazon.com/neptune/latest/userguide/bulk-load-tutorial-format-gremlin.html
+	Gremlin Dialect = "neptune"
+	// Neo4j is neo4j-admin import data format.
+	// See: https://neo4j.com/docs/operations-manual/current/tools/neo4j-admin/neo4j-admin-import/
+	Neo4j Dialect = "neo4j"
+)
+
+const (
+	// DefaultDialect is default CSV dialect.
+	DefaultDialect = Gremlin
+)
+
+// Options configure marshaler.
+type Options struct {
+	// Dialect is CSV dialect.
+	Dialect Dialect
+	// Schema declares typed node and edge attributes.
+	Schema *attrs.Registry
+}
+
+// Option is functional marshaler option.
+type Option func(*Options)
+
+// WithDialect sets Dialect option.
+func WithDialect(d Dialect) Option {
+	return func(o *Options) {
+		o.Dialect = d
+	}
+}
+
+// WithSchema sets Schema option.
+func WithSchema(r *attrs.Registry) Option {
+	return func(o *Options) {
+		o.Schema = r
+	}
+}
@@ -1,10 +1,13 @@
 package networkx
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/internal/jsonstream"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/meta"
 )
 
 // Node is Graph node
@@ -29,6 +32,10 @@ type Link struct {
 
 // Graph stores Nodes and Links.
 type Graph struct {
+	// Directed is true if the graph is directed
+	Directed bool `json:"directed"`
+	// Multigraph is true if the graph allows parallel links
+	Multigraph bool `json:"multigraph"`
 	// Nodes are graph nodes
 	Nodes []Node `json:"nodes"`
 	// Links connect nodes
@@ -53,39 +60,71 @@ func NewMarshaler(name, prefix, indent string) (*Marshaler, error) {
 
 // Marshal marshals g into format that can be used by
 // networkx frameworkk https://networkx.org/
+// Node UIDs, labels, styles and edge weights are stored in the reserved attribute.
 func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
-	c := Graph{
-		Nodes: make([]Node, g.Nodes().Len()),
-		Links: make([]Link, g.Edges().Len()),
+	var b bytes.Buffer
+	if err := m.MarshalTo(&b, g); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo marshals g into w encoding its nodes and links one by one.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	enc := jsonstream.NewEncoder(w, m.prefix, m.indent)
+
+	if err := enc.Field("directed", graph.IsDirected(g.Type())); err != nil {
+		return err
+	}
+
+	if err := enc.Field("multigraph", graph.IsMulti(g.Type())); err != nil {
+		return err
+	}
+
+	if err := enc.BeginArray("nodes"); err != nil {
+		return err
 	}
 
 	nodes := g.Nodes()
-	i := 0
 	for nodes.Next() {
 		n := nodes.Node().(graph.Node)
 
-		c.Nodes[i] = Node{
+		if err := enc.Elem(Node{
 			ID:         fmt.Sprint(n.ID()),
-			Attributes: n.Attrs(),
+			Attributes: meta.Attrs(n.Attrs(), meta.Node(n)),
+		}); err != nil {
+			return err
 		}
+	}
 
-		i++
+	if err := enc.EndArray(); err != nil {
+		return err
+	}
+
+	if err := enc.BeginArray("links"); err != nil {
+		return err
 	}
 
 	edges := g.Edges()
-	i = 0
+	i := 0
 	for edges.Next() {
 		e := edges.Edge().(graph.Edge)
 
-		c.Links[i] = Link{
+		if err := enc.Elem(Link{
 			ID:         fmt.Sprint(i),
 			Source:     fmt.Sprint(e.From().ID()),
 			Target:     fmt.Sprint(e.To().ID()),
-			Attributes: e.Attrs(),
+			Attributes: meta.Attrs(e.Attrs(), meta.Edge(e)),
+		}); err != nil {
+			return err
 		}
 
 		i++
 	}
 
-	return json.MarshalIndent(c, m.prefix, m.indent)
+	if err := enc.EndArray(); err != nil {
+		return err
+	}
+
+	return enc.Close()
 }
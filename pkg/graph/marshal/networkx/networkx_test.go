@@ -0,0 +1,113 @@
+package networkx
+
+import (
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+func TestUnmarshal(t *testing.T) {
+	// networkx node_link_data
+	data := `{
+  "directed": false,
+  "multigraph": false,
+  "graph": {},
+  "nodes": [
+    {"id": 1, "size": 3},
+    {"id": 2}
+  ],
+  "links": [
+    {"source": 1, "target": 2, "weight": 0.5}
+  ]
+}`
+
+	typ, err := GraphType([]byte(data))
+	if err != nil {
+		t.Fatalf("failed to get graph type: %v", err)
+	}
+
+	if typ != graph.WeightedUndirected {
+		t.Errorf("expected graph type: %s, got: %s", graph.WeightedUndirected, typ)
+	}
+
+	u, err := NewUnmarshaler()
+	if err != nil {
+		t.Fatalf("failed to create unmarshaler: %v", err)
+	}
+
+	g, err := memory.NewGraph(memory.WithType(typ))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	if err := u.Unmarshal([]byte(data), g); err != nil {
+		t.Fatalf("failed to unmarshal graph: %v", err)
+	}
+
+	a, b := g.NodeWithUID("1"), g.NodeWithUID("2")
+	if a == nil || b == nil {
+		t.Fatalf("expected nodes 1 and 2")
+	}
+
+	if size := a.Attrs()["size"]; size != 3.0 {
+		t.Errorf("expected size: 3, got: %v", size)
+	}
+
+	if w := g.Edge(b.ID(), a.ID()).(graph.Edge).Weight(); w != 0.5 {
+		t.Errorf("expected weight: 0.5, got: %v", w)
+	}
+}
+
+func TestGraphType(t *testing.T) {
+	testCases := []struct {
+		data string
+		exp  string
+	}{
+		{`{"directed": true}`, graph.WeightedDirected},
+		{`{"directed": false}`, graph.WeightedUndirected},
+		{`{"directed": true, "multigraph": true}`, graph.WeightedDirectedMulti},
+		{`{"directed": false, "links": [{"source": "a", "target": "b"}, {"source": "b", "target": "a"}]}`, graph.WeightedUndirectedMulti},
+	}
+
+	for _, tc := range testCases {
+		typ, err := GraphType([]byte(tc.data))
+		if err != nil {
+			t.Fatalf("failed to get graph type: %v", err)
+		}
+
+		if typ != tc.exp {
+			t.Errorf("expected graph type: %s, got: %s", tc.exp, typ)
+		}
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{"JSON", `{`},
+		{"Type", `{"directed": false}`},
+		{"Node", `{"directed": true, "nodes": [{"id": "a"}, {"id": "a"}]}`},
+		{"Edge", `{"directed": true, "nodes": [{"id": "a"}], "links": [{"source": "a", "target": "b"}]}`},
+		{"Meta", `{"directed": true, "nodes": [{"id": "a", "orbnet": 1}]}`},
+		{"Value", `{"directed": true, "nodes": [{"id": "a", "orbnet": {"types": {"n": "int"}}, "n": "foo"}]}`},
+	}
+
+	u, err := NewUnmarshaler()
+	if err != nil {
+		t.Fatalf("failed to create unmarshaler: %v", err)
+	}
+
+	for _, tc := range testCases {
+		g, err := memory.NewGraph()
+		if err != nil {
+			t.Fatalf("failed to create graph: %v", err)
+		}
+
+		if err := u.Unmarshal([]byte(tc.data), g); graph.ErrorCode(err) != graph.EINVALID {
+			t.Errorf("%s: expected error: %s, got: %v", tc.name, graph.EINVALID, err)
+		}
+	}
+}
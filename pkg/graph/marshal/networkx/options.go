@@ -0,0 +1,19 @@
+package networkx
+
+import "github.com/milosgajdos/orbnet/pkg/graph/attrs"
+
+// Options configure unmarshaler.
+type Options struct {
+	// Schema declares typed node and edge attributes.
+	Schema *attrs.Registry
+}
+
+// Option is functional unmarshaler option.
+type Option func(*Options)
+
+// WithSchema sets Schema option.
+func WithSchema(r *attrs.Registry) Option {
+	return func(o *Options) {
+		o.Schema = r
+	}
+}
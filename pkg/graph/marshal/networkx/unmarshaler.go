@@ -0,0 +1,145 @@
+package networkx
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/meta"
+)
+
+const (
+	// idKey is the key of node link data element IDs.
+	idKey = "id"
+	// sourceKey is the key of node link data link sources.
+	sourceKey = "source"
+	// targetKey is the key of node link data link targets.
+	targetKey = "target"
+	// attrsKey is the key of Marshaler element attributes.
+	attrsKey = "Attributes"
+)
+
+// Unmarshaler implements graph.Unmarshaler.
+type Unmarshaler struct {
+	opts Options
+}
+
+// NewUnmarshaler creates a new Unmarshaler and returns it.
+func NewUnmarshaler(opts ...Option) (*Unmarshaler, error) {
+	uopts := Options{}
+	for _, apply := range opts {
+		apply(&uopts)
+	}
+
+	return &Unmarshaler{
+		opts: uopts,
+	}, nil
+}
+
+// document is networkx graph whose nodes and links are either
+// marshaled by Marshaler or by networkx node_link_data.
+type document struct {
+	Directed   bool                     `json:"directed"`
+	Multigraph bool                     `json:"multigraph"`
+	Nodes      []map[string]interface{} `json:"nodes"`
+	Links      []map[string]interface{} `json:"links"`
+	Edges      []map[string]interface{} `json:"edges"`
+}
+
+// Unmarshal unmarshals networkx data into g.
+// It accepts the documents created by Marshaler as well as node_link_data
+// documents created by networkx whose element IDs are used as node UIDs.
+func (u *Unmarshaler) Unmarshal(data []byte, g graph.Graph) error {
+	doc, err := decode(data)
+	if err != nil {
+		return err
+	}
+
+	if doc.Directed != graph.IsDirected(g.Type()) {
+		return graph.Errorf(graph.EINVALID, "graph type mismatch: directed: %t, expected: %s", doc.Directed, g.Type())
+	}
+
+	return meta.Build(g, doc.nodes(), doc.links(), u.opts.Schema)
+}
+
+// GraphType returns the type of graph encoded in networkx data.
+func GraphType(data []byte) (string, error) {
+	doc, err := decode(data)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case doc.Multigraph && doc.Directed:
+		return graph.WeightedDirectedMulti, nil
+	case doc.Multigraph:
+		return graph.WeightedUndirectedMulti, nil
+	default:
+		return meta.GraphType(doc.Directed, doc.links()), nil
+	}
+}
+
+// decode decodes networkx data.
+func decode(data []byte) (*document, error) {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, graph.Errorf(graph.EINVALID, "invalid networkx: %v", err)
+	}
+	return &doc, nil
+}
+
+// nodes returns document nodes.
+func (d *document) nodes() []meta.Element {
+	nodes := make([]meta.Element, len(d.Nodes))
+	for i, n := range d.Nodes {
+		nodes[i] = element(n)
+	}
+	return nodes
+}
+
+// links returns document links.
+func (d *document) links() []meta.Element {
+	// NOTE: networkx 3.4 stores links in edges
+	links := append(d.Links, d.Edges...)
+
+	edges := make([]meta.Element, len(links))
+	for i, l := range links {
+		edges[i] = element(l)
+	}
+	return edges
+}
+
+// element returns graph element of node or link data.
+func element(data map[string]interface{}) meta.Element {
+	if a, ok := data[attrsKey].(map[string]interface{}); ok {
+		return meta.Element{
+			ID:     str(data["ID"]),
+			Source: str(data["Source"]),
+			Target: str(data["Target"]),
+			Attrs:  a,
+		}
+	}
+
+	a := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		a[k] = v
+	}
+	delete(a, idKey)
+	delete(a, sourceKey)
+	delete(a, targetKey)
+
+	return meta.Element{
+		ID:     str(data[idKey]),
+		Source: str(data[sourceKey]),
+		Target: str(data[targetKey]),
+		Attrs:  a,
+	}
+}
+
+// str returns string representation of element ID v.
+func str(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,159 @@
+package parquet
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/columns"
+)
+
+// attrColumns are the attribute columns of nodes or edges.
+type attrColumns struct {
+	*columns.Columns
+}
+
+// newColumns creates new attribute columns.
+// The types of the attributes declared in schema s are not inferred.
+// The attributes with the skipped names are not marshaled as columns.
+func newColumns(s attrs.Schema, skip ...string) attrColumns {
+	return attrColumns{Columns: columns.New(s, skip...)}
+}
+
+// isJSON returns true if the attribute with the given name is marshaled as JSON.
+// Maps and lists of lists or maps are marshaled as JSON.
+func (c attrColumns) isJSON(name string) bool {
+	switch c.Type(name) {
+	case attrs.Map:
+		return true
+	case attrs.List:
+		return !c.IsFlat(name)
+	}
+	return false
+}
+
+// fields returns the Arrow fields of the columns.
+func (c attrColumns) fields() []arrow.Field {
+	fields := make([]arrow.Field, len(c.Names()))
+	for i, name := range c.Names() {
+		var dt arrow.DataType
+		switch {
+		case c.isJSON(name):
+			dt = jsonType
+		case c.Type(name) == attrs.List:
+			dt = arrow.ListOf(dataType(c.Elem(name)))
+		default:
+			dt = dataType(c.Type(name))
+		}
+		fields[i] = arrow.Field{Name: name, Type: dt, Nullable: true}
+	}
+	return fields
+}
+
+// append appends the values of attributes a to builders bs.
+// Values which can not be coerced to the column type are null.
+func (c attrColumns) append(bs []array.Builder, a map[string]interface{}) {
+	for i, name := range c.Names() {
+		v := a[name]
+		if v == nil {
+			bs[i].AppendNull()
+			continue
+		}
+
+		switch {
+		case c.isJSON(name):
+			appendJSON(bs[i].(*array.ExtensionBuilder).StorageBuilder().(*array.StringBuilder), c.Type(name), v)
+		case c.Type(name) == attrs.List:
+			c.appendList(bs[i].(*array.ListBuilder), name, v)
+		default:
+			appendValue(bs[i], c.Type(name), v)
+		}
+	}
+}
+
+// appendList appends the elements of list v to list builder b.
+func (c attrColumns) appendList(b *array.ListBuilder, name string, v interface{}) {
+	l, err := attrs.Coerce(attrs.List, v)
+	if err != nil {
+		b.AppendNull()
+		return
+	}
+
+	b.Append(true)
+	vb := b.ValueBuilder()
+	t := c.Elem(name)
+
+	for _, e := range l.([]interface{}) {
+		if e == nil {
+			vb.AppendNull()
+			continue
+		}
+		appendValue(vb, t, e)
+	}
+}
+
+// appendJSON appends JSON encoding of v of attribute type t to b.
+func appendJSON(b *array.StringBuilder, t attrs.Type, v interface{}) {
+	cv, err := attrs.Coerce(t, v)
+	if err != nil {
+		b.AppendNull()
+		return
+	}
+
+	data, err := json.Marshal(cv)
+	if err != nil {
+		b.AppendNull()
+		return
+	}
+
+	b.Append(string(data))
+}
+
+// appendValue appends v of attribute type t to b.
+// Values of String columns are formatted as per their own types.
+func appendValue(b array.Builder, t attrs.Type, v interface{}) {
+	switch t {
+	case attrs.Int, attrs.Float, attrs.Bool, attrs.Time:
+	default:
+		b.(*array.StringBuilder).Append(attrs.Format(attrs.TypeOf(v), v))
+		return
+	}
+
+	cv, err := attrs.Coerce(t, v)
+	if err != nil {
+		b.AppendNull()
+		return
+	}
+
+	switch val := cv.(type) {
+	case int64:
+		b.(*array.Int64Builder).Append(val)
+	case float64:
+		b.(*array.Float64Builder).Append(val)
+	case bool:
+		b.(*array.BooleanBuilder).Append(val)
+	case time.Time:
+		b.(*array.TimestampBuilder).Append(arrow.Timestamp(val.UnixMicro()))
+	default:
+		b.AppendNull()
+	}
+}
+
+// dataType returns Arrow data type of attribute type t.
+// Colors are marshaled as hex codes and times as UTC timestamps in microseconds.
+func dataType(t attrs.Type) arrow.DataType {
+	switch t {
+	case attrs.Int:
+		return arrow.PrimitiveTypes.Int64
+	case attrs.Float:
+		return arrow.PrimitiveTypes.Float64
+	case attrs.Bool:
+		return arrow.FixedWidthTypes.Boolean
+	case attrs.Time:
+		return arrow.FixedWidthTypes.Timestamp_us
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
@@ -0,0 +1,45 @@
+package parquet
+
+import "github.com/milosgajdos/orbnet/pkg/graph/attrs"
+
+// Format is columnar file format.
+type Format string
+
+const (
+	// Parquet is Apache Parquet format.
+	// See: https://parquet.apache.org/docs/file-format/
+	Parquet Format = "parquet"
+	// Arrow is Apache Arrow IPC file format also known as Feather V2.
+	// See: https://arrow.apache.org/docs/format/Columnar.html#ipc-file-format
+	Arrow Format = "arrow"
+)
+
+const (
+	// DefaultFormat is default file format.
+	DefaultFormat = Parquet
+)
+
+// Options configure marshaler.
+type Options struct {
+	// Format is file format.
+	Format Format
+	// Schema declares typed node and edge attributes.
+	Schema *attrs.Registry
+}
+
+// Option is functional marshaler option.
+type Option func(*Options)
+
+// WithFormat sets Format option.
+func WithFormat(f Format) Option {
+	return func(o *Options) {
+		o.Format = f
+	}
+}
+
+// WithSchema sets Schema option.
+func WithSchema(r *attrs.Registry) Option {
+	return func(o *Options) {
+		o.Schema = r
+	}
+}
@@ -0,0 +1,279 @@
+// Package parquet marshals graphs into node and edge tables
+// stored in Apache Parquet or Apache Arrow IPC files.
+package parquet
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/extensions"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	pq "github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/milosgajdos/orbnet/pkg/graph"
+)
+
+const (
+	// NodesTable is the name of nodes table.
+	NodesTable = "nodes"
+	// EdgesTable is the name of edges table.
+	EdgesTable = "edges"
+	// DefaultEdgeLabel is the label of edges which have no label.
+	DefaultEdgeLabel = "Undefined"
+)
+
+const (
+	// uidCol is the column which stores node and edge UIDs.
+	uidCol = "uid"
+	// labelCol is the column which stores node and edge labels.
+	labelCol = "label"
+	// sourceCol is the column which stores the UIDs of edge source nodes.
+	sourceCol = "source"
+	// targetCol is the column which stores the UIDs of edge target nodes.
+	targetCol = "target"
+	// weightCol is the column which stores edge weights.
+	weightCol = "weight"
+)
+
+// jsonType is Arrow extension type of JSON columns.
+// It's stored as Parquet JSON logical type.
+var jsonType = &extensions.JSONType{
+	ExtensionBase: arrow.ExtensionBase{Storage: arrow.BinaryTypes.String},
+}
+
+// Marshaler marshals graphs into columnar files.
+type Marshaler struct {
+	opts Options
+}
+
+// NewMarshaler creates a new columnar marshaler and returns it.
+func NewMarshaler(opts ...Option) (*Marshaler, error) {
+	mopts := Options{
+		Format: DefaultFormat,
+	}
+
+	for _, apply := range opts {
+		apply(&mopts)
+	}
+
+	if f := mopts.Format; f != Parquet && f != Arrow {
+		return nil, graph.Errorf(graph.EINVALID, "unsupported format: %q", f)
+	}
+
+	return &Marshaler{
+		opts: mopts,
+	}, nil
+}
+
+// file is a table file.
+type file struct {
+	name    string
+	marshal func(graph.Graph, io.Writer) error
+}
+
+// files returns the table files of graph.
+func (m *Marshaler) files() []file {
+	return []file{
+		{NodesTable + "." + string(m.opts.Format), m.MarshalNodes},
+		{EdgesTable + "." + string(m.opts.Format), m.MarshalEdges},
+	}
+}
+
+// Marshal marshals g into a zip archive which contains nodes and edges table files.
+func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
+	var b bytes.Buffer
+	if err := m.MarshalTo(&b, g); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo writes a zip archive which contains nodes and edges table files of g to w.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	zw := zip.NewWriter(w)
+
+	for _, f := range m.files() {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+
+		if err := f.marshal(g, fw); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// WriteDir writes nodes and edges table files of g into dir.
+// The files are named after the tables with the format as their extension.
+// It creates dir if it does not exist.
+func (m *Marshaler) WriteDir(g graph.Graph, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, f := range m.files() {
+		if err := writeFile(filepath.Join(dir, f.name), g, f.marshal); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarshalNodes writes the nodes of g ordered by their UIDs as a table to w.
+// The table has uid and label columns followed by the attribute columns
+// whose types are derived from node attributes and the schema.
+func (m *Marshaler) MarshalNodes(g graph.Graph, w io.Writer) error {
+	cols := newColumns(m.opts.Schema.NodeKeys(), uidCol, labelCol)
+
+	var nodes []graph.Node
+	it := g.Nodes()
+	for it.Next() {
+		n := it.Node().(graph.Node)
+		cols.Add(n.Attrs())
+		nodes = append(nodes, n)
+	}
+	cols.Sort()
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].UID() < nodes[j].UID()
+	})
+
+	fields := append([]arrow.Field{
+		{Name: uidCol, Type: arrow.BinaryTypes.String},
+		{Name: labelCol, Type: arrow.BinaryTypes.String},
+	}, cols.fields()...)
+
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), arrow.NewSchema(fields, nil))
+	defer b.Release()
+
+	for _, n := range nodes {
+		b.Field(0).(*array.StringBuilder).Append(n.UID())
+		b.Field(1).(*array.StringBuilder).Append(n.Label())
+		cols.append(b.Fields()[2:], n.Attrs())
+	}
+
+	return m.write(b, w)
+}
+
+// MarshalEdges writes the edges of g ordered by the UIDs of their nodes as a table to w.
+// The table has uid, source, target, label and weight columns followed by the attribute
+// columns whose types are derived from edge attributes and the schema.
+// The edge weight takes precedence over the weight attribute.
+func (m *Marshaler) MarshalEdges(g graph.Graph, w io.Writer) error {
+	cols := newColumns(m.opts.Schema.EdgeKeys(), uidCol, sourceCol, targetCol, labelCol, weightCol)
+
+	var edges []graph.Edge
+	it := g.Edges()
+	for it.Next() {
+		e := it.Edge().(graph.Edge)
+		cols.Add(e.Attrs())
+		edges = append(edges, e)
+	}
+	cols.Sort()
+
+	sort.Slice(edges, func(i, j int) bool {
+		fi, fj := edges[i].From().(graph.Node).UID(), edges[j].From().(graph.Node).UID()
+		if fi != fj {
+			return fi < fj
+		}
+		ti, tj := edges[i].To().(graph.Node).UID(), edges[j].To().(graph.Node).UID()
+		if ti != tj {
+			return ti < tj
+		}
+		return edges[i].UID() < edges[j].UID()
+	})
+
+	fields := append([]arrow.Field{
+		{Name: uidCol, Type: arrow.BinaryTypes.String},
+		{Name: sourceCol, Type: arrow.BinaryTypes.String},
+		{Name: targetCol, Type: arrow.BinaryTypes.String},
+		{Name: labelCol, Type: arrow.BinaryTypes.String},
+		{Name: weightCol, Type: arrow.PrimitiveTypes.Float64},
+	}, cols.fields()...)
+
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), arrow.NewSchema(fields, nil))
+	defer b.Release()
+
+	for _, e := range edges {
+		label := e.Label()
+		if label == "" {
+			label = DefaultEdgeLabel
+		}
+
+		b.Field(0).(*array.StringBuilder).Append(e.UID())
+		b.Field(1).(*array.StringBuilder).Append(e.From().(graph.Node).UID())
+		b.Field(2).(*array.StringBuilder).Append(e.To().(graph.Node).UID())
+		b.Field(3).(*array.StringBuilder).Append(label)
+		b.Field(4).(*array.Float64Builder).Append(e.Weight())
+		cols.append(b.Fields()[5:], e.Attrs())
+	}
+
+	return m.write(b, w)
+}
+
+// write writes the record built by b to w in the configured format.
+// The Arrow schema is stored in Parquet files so that the column
+// types are preserved when they are read by Arrow readers.
+func (m *Marshaler) write(b *array.RecordBuilder, w io.Writer) error {
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	// NOTE: the writers close w if it's io.Closer
+	w = struct{ io.Writer }{w}
+
+	if m.opts.Format == Arrow {
+		fw, err := ipc.NewFileWriter(w, ipc.WithSchema(rec.Schema()))
+		if err != nil {
+			return err
+		}
+
+		if err := fw.Write(rec); err != nil {
+			fw.Close()
+			return err
+		}
+
+		return fw.Close()
+	}
+
+	props := pq.NewWriterProperties(pq.WithCompression(compress.Codecs.Snappy))
+	arrProps := pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema())
+
+	fw, err := pqarrow.NewFileWriter(rec.Schema(), w, props, arrProps)
+	if err != nil {
+		return err
+	}
+
+	if err := fw.Write(rec); err != nil {
+		fw.Close()
+		return err
+	}
+
+	return fw.Close()
+}
+
+// writeFile creates file at path and marshals g into it.
+func writeFile(path string, g graph.Graph, marshal func(graph.Graph, io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := marshal(g, f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,344 @@
+package parquet
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	mem "github.com/apache/arrow-go/v18/arrow/memory"
+	pqfile "github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/apache/arrow-go/v18/parquet/schema"
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+func MustGraph(t *testing.T) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	repo, err := memory.NewNode(g.NewNode().ID(),
+		memory.WithUID("repo"),
+		memory.WithLabel("Repo"),
+		memory.WithAttrs(map[string]interface{}{
+			"name":       "foo, bar",
+			"stars":      int64(10),
+			"fork":       false,
+			"starred_at": "2021-01-02T03:04:05Z",
+			"topics":     []interface{}{"go", "graph"},
+			"license":    map[string]interface{}{"key": "mit"},
+			"owner":      []interface{}{map[string]interface{}{"login": "foo"}},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(repo)
+
+	topic, err := memory.NewNode(g.NewNode().ID(),
+		memory.WithUID("go-Topic"),
+		memory.WithLabel("Topic"),
+		memory.WithAttrs(map[string]interface{}{"name": "go", "stars": 1.5, "starred_at": "never"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(topic)
+
+	e, err := memory.NewEdge(repo, topic,
+		memory.WithUID("repo-go"),
+		memory.WithLabel("HasTopic"),
+		memory.WithWeight(2.5),
+		memory.WithAttrs(map[string]interface{}{"weight": 1.0, "scores": []float64{1, 0.5}}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create edge: %v", err)
+	}
+	g.SetWeightedEdge(e)
+
+	return g
+}
+
+func MustSchema(t *testing.T) *attrs.Registry {
+	t.Helper()
+
+	r := attrs.NewRegistry()
+	if err := r.SetNodeSchema("Repo", attrs.Schema{"starred_at": attrs.Time}); err != nil {
+		t.Fatalf("failed to set node schema: %v", err)
+	}
+
+	return r
+}
+
+// table is a table read from a columnar file.
+type table struct {
+	fields []string
+	rows   [][]string
+}
+
+func MustRead(t *testing.T, f Format, data []byte) table {
+	t.Helper()
+
+	var (
+		recs []arrow.Record
+		json = make(map[int]bool)
+	)
+
+	switch f {
+	case Arrow:
+		r, err := ipc.NewFileReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("failed to create arrow reader: %v", err)
+		}
+		defer r.Close()
+
+		for i := 0; i < r.NumRecords(); i++ {
+			rec, err := r.Record(i)
+			if err != nil {
+				t.Fatalf("failed to read record: %v", err)
+			}
+			recs = append(recs, rec)
+		}
+	case Parquet:
+		pr, err := pqfile.NewParquetReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("failed to create parquet reader: %v", err)
+		}
+		defer pr.Close()
+
+		// NOTE: Arrow readers read JSON columns as binary
+		root := pr.MetaData().Schema.Root()
+		for i := 0; i < root.NumFields(); i++ {
+			if _, ok := root.Field(i).LogicalType().(schema.JSONLogicalType); ok {
+				json[i] = true
+			}
+		}
+
+		fr, err := pqarrow.NewFileReader(pr, pqarrow.ArrowReadProperties{}, mem.DefaultAllocator)
+		if err != nil {
+			t.Fatalf("failed to create arrow reader: %v", err)
+		}
+
+		tbl, err := fr.ReadTable(context.Background())
+		if err != nil {
+			t.Fatalf("failed to read parquet table: %v", err)
+		}
+		defer tbl.Release()
+
+		tr := array.NewTableReader(tbl, -1)
+		defer tr.Release()
+
+		for tr.Next() {
+			rec := tr.Record()
+			rec.Retain()
+			defer rec.Release()
+			recs = append(recs, rec)
+		}
+	}
+
+	if len(recs) == 0 {
+		t.Fatalf("no records in %s file", f)
+	}
+
+	var tb table
+	for i, field := range recs[0].Schema().Fields() {
+		tb.fields = append(tb.fields, field.Name+":"+typeName(field.Type, json[i]))
+	}
+
+	for _, rec := range recs {
+		for i := 0; i < int(rec.NumRows()); i++ {
+			row := make([]string, rec.NumCols())
+			for j, col := range rec.Columns() {
+				switch {
+				case col.IsNull(i):
+				case json[j]:
+					row[j] = string(col.(*array.Binary).Value(i))
+				default:
+					row[j] = col.ValueStr(i)
+				}
+			}
+			tb.rows = append(tb.rows, row)
+		}
+	}
+
+	return tb
+}
+
+// typeName returns the name of data type dt independent of the file format.
+func typeName(dt arrow.DataType, json bool) string {
+	if json {
+		return "json"
+	}
+
+	switch dt := dt.(type) {
+	case arrow.ExtensionType:
+		if dt.ExtensionName() == "arrow.json" {
+			return "json"
+		}
+		return dt.ExtensionName()
+	case *arrow.ListType:
+		return "list<" + dt.Elem().String() + ">"
+	}
+
+	return dt.String()
+}
+
+func TestMarshal(t *testing.T) {
+	nodes := table{
+		fields: []string{
+			"uid:utf8",
+			"label:utf8",
+			"fork:bool",
+			"license:json",
+			"name:utf8",
+			"owner:json",
+			"starred_at:timestamp[us, tz=UTC]",
+			"stars:float64",
+			"topics:list<utf8>",
+		},
+		rows: [][]string{
+			{"go-Topic", "Topic", "", "", "go", "", "", "1.5", ""},
+			{"repo", "Repo", "false", `{"key":"mit"}`, "foo, bar", `[{"login":"foo"}]`, "2021-01-02 03:04:05Z", "10", `["go","graph"]`},
+		},
+	}
+
+	edges := table{
+		fields: []string{
+			"uid:utf8",
+			"source:utf8",
+			"target:utf8",
+			"label:utf8",
+			"weight:float64",
+			"scores:list<float64>",
+		},
+		rows: [][]string{
+			{"repo-go", "repo", "go-Topic", "HasTopic", "2.5", "[1,0.5]"},
+		},
+	}
+
+	g := MustGraph(t)
+
+	for _, f := range []Format{Parquet, Arrow} {
+		m, err := NewMarshaler(WithFormat(f), WithSchema(MustSchema(t)))
+		if err != nil {
+			t.Fatalf("failed to create marshaler: %v", err)
+		}
+
+		var nb, eb bytes.Buffer
+		if err := m.MarshalNodes(g, &nb); err != nil {
+			t.Fatalf("%s: failed to marshal nodes: %v", f, err)
+		}
+
+		if err := m.MarshalEdges(g, &eb); err != nil {
+			t.Fatalf("%s: failed to marshal edges: %v", f, err)
+		}
+
+		if got := MustRead(t, f, nb.Bytes()); !reflect.DeepEqual(got, nodes) {
+			t.Errorf("%s: expected nodes:\n%v\ngot:\n%v", f, nodes, got)
+		}
+
+		if got := MustRead(t, f, eb.Bytes()); !reflect.DeepEqual(got, edges) {
+			t.Errorf("%s: expected edges:\n%v\ngot:\n%v", f, edges, got)
+		}
+
+		data, err := m.Marshal(g)
+		if err != nil {
+			t.Fatalf("%s: failed to marshal graph: %v", f, err)
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatalf("%s: failed to read archive: %v", f, err)
+		}
+
+		exp := map[string][]byte{
+			NodesTable + "." + string(f): nb.Bytes(),
+			EdgesTable + "." + string(f): eb.Bytes(),
+		}
+		if len(zr.File) != len(exp) {
+			t.Fatalf("%s: expected %d files, got: %d", f, len(exp), len(zr.File))
+		}
+
+		for _, zf := range zr.File {
+			r, err := zf.Open()
+			if err != nil {
+				t.Fatalf("%s: failed to open %s: %v", f, zf.Name, err)
+			}
+
+			b, err := io.ReadAll(r)
+			r.Close()
+			if err != nil {
+				t.Fatalf("%s: failed to read %s: %v", f, zf.Name, err)
+			}
+
+			if !bytes.Equal(b, exp[zf.Name]) {
+				t.Errorf("%s: unexpected %s", f, zf.Name)
+			}
+		}
+	}
+}
+
+func TestMarshalEmpty(t *testing.T) {
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	for _, f := range []Format{Parquet, Arrow} {
+		m, err := NewMarshaler(WithFormat(f))
+		if err != nil {
+			t.Fatalf("failed to create marshaler: %v", err)
+		}
+
+		var b bytes.Buffer
+		if err := m.MarshalEdges(g, &b); err != nil {
+			t.Fatalf("%s: failed to marshal edges: %v", f, err)
+		}
+
+		if b.Len() == 0 {
+			t.Errorf("%s: expected non-empty edges file", f)
+		}
+	}
+}
+
+func TestWriteDir(t *testing.T) {
+	m, err := NewMarshaler()
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "tables")
+	if err := m.WriteDir(MustGraph(t), dir); err != nil {
+		t.Fatalf("failed to write files: %v", err)
+	}
+
+	for _, name := range []string{"nodes.parquet", "edges.parquet"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+
+		if tb := MustRead(t, Parquet, data); len(tb.rows) == 0 {
+			t.Errorf("expected rows in %s", name)
+		}
+	}
+}
+
+func TestNewMarshalerErrors(t *testing.T) {
+	if _, err := NewMarshaler(WithFormat("foo")); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
@@ -0,0 +1,86 @@
+package rdf
+
+import (
+	"io"
+
+	"github.com/milosgajdos/orbnet/pkg/graph/internal/jsonstream"
+)
+
+// marshalJSONLD writes compacted JSON-LD document which contains
+// a node object for each subject of triples to w.
+// The node objects are encoded one by one.
+func marshalJSONLD(w io.Writer, triples []triple, prefixes []prefix) error {
+	context := make(map[string]string, len(prefixes))
+	for _, p := range prefixes {
+		context[p.name] = p.ns
+	}
+
+	enc := jsonstream.NewEncoder(w, "", "  ")
+
+	if err := enc.Field("@context", context); err != nil {
+		return err
+	}
+
+	if err := enc.BeginArray("@graph"); err != nil {
+		return err
+	}
+
+	var node map[string]interface{}
+
+	for i, t := range triples {
+		if i == 0 || t.s != triples[i-1].s {
+			if node != nil {
+				if err := enc.Elem(node); err != nil {
+					return err
+				}
+			}
+			node = map[string]interface{}{"@id": t.s}
+		}
+
+		key, val := jsonldIRI(t.p, prefixes), jsonldTerm(t.o, prefixes)
+		if t.p == rdfType {
+			key, val = "@type", jsonldIRI(t.o.iri, prefixes)
+		}
+
+		switch cur := node[key].(type) {
+		case nil:
+			node[key] = val
+		case []interface{}:
+			node[key] = append(cur, val)
+		default:
+			node[key] = []interface{}{cur, val}
+		}
+	}
+
+	if node != nil {
+		if err := enc.Elem(node); err != nil {
+			return err
+		}
+	}
+
+	if err := enc.EndArray(); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// jsonldTerm returns JSON-LD value object of t.
+// Literals which have no datatype are returned as JSON strings.
+func jsonldTerm(t term, prefixes []prefix) interface{} {
+	if t.isIRI() {
+		return map[string]string{"@id": t.iri}
+	}
+	if t.datatype == "" {
+		return t.value
+	}
+	return map[string]string{"@value": t.value, "@type": jsonldIRI(t.datatype, prefixes)}
+}
+
+// jsonldIRI returns compact IRI of iri or iri itself.
+func jsonldIRI(iri string, prefixes []prefix) string {
+	if name, ok := compact(iri, prefixes); ok {
+		return name
+	}
+	return iri
+}
@@ -0,0 +1,31 @@
+package rdf
+
+import (
+	"bufio"
+	"io"
+)
+
+// marshalNTriples writes triples in N-Triples to w.
+func marshalNTriples(w io.Writer, triples []triple) error {
+	b := bufio.NewWriter(w)
+	for _, t := range triples {
+		b.WriteString(quoteIRI(t.s))
+		b.WriteByte(' ')
+		b.WriteString(quoteIRI(t.p))
+		b.WriteByte(' ')
+		b.WriteString(ntriplesTerm(t.o))
+		b.WriteString(" .\n")
+	}
+	return b.Flush()
+}
+
+// ntriplesTerm returns N-Triples representation of t.
+func ntriplesTerm(t term) string {
+	if t.isIRI() {
+		return quoteIRI(t.iri)
+	}
+	if t.datatype == "" {
+		return quote(t.value)
+	}
+	return quote(t.value) + "^^" + quoteIRI(t.datatype)
+}
@@ -0,0 +1,82 @@
+package rdf
+
+import "github.com/milosgajdos/orbnet/pkg/graph/attrs"
+
+// Format is RDF serialization format.
+type Format string
+
+const (
+	// Turtle is Terse RDF Triple Language.
+	// See: https://www.w3.org/TR/turtle/
+	Turtle Format = "turtle"
+	// NTriples is line-based RDF triples format.
+	// See: https://www.w3.org/TR/n-triples/
+	NTriples Format = "ntriples"
+	// JSONLD is JSON-based Linked Data format.
+	// See: https://www.w3.org/TR/json-ld11/
+	JSONLD Format = "jsonld"
+)
+
+const (
+	// DefaultFormat is default RDF format.
+	DefaultFormat = Turtle
+	// DefaultNamespace is default namespace of node types, edge predicates and attribute properties.
+	DefaultNamespace = "https://github.com/milosgajdos/orbnet/ns#"
+	// DefaultBase is default base IRI of nodes which have no IRI attribute.
+	DefaultBase = "https://github.com/milosgajdos/orbnet/resource/"
+)
+
+// DefaultIRIKeys are default node attributes which store node IRIs.
+var DefaultIRIKeys = []string{"html_url", "url"}
+
+// Options configure marshaler.
+type Options struct {
+	// Format is RDF serialization format.
+	Format Format
+	// Namespace is the namespace of node types, edge predicates and attribute properties.
+	Namespace string
+	// Base is the base IRI of nodes which have no IRI attribute.
+	Base string
+	// IRIKeys are node attributes which store node IRIs in the order of precedence.
+	IRIKeys []string
+	// Schema declares typed node and edge attributes.
+	Schema *attrs.Registry
+}
+
+// Option is functional marshaler option.
+type Option func(*Options)
+
+// WithFormat sets Format option.
+func WithFormat(f Format) Option {
+	return func(o *Options) {
+		o.Format = f
+	}
+}
+
+// WithNamespace sets Namespace option.
+func WithNamespace(ns string) Option {
+	return func(o *Options) {
+		o.Namespace = ns
+	}
+}
+
+// WithBase sets Base option.
+func WithBase(base string) Option {
+	return func(o *Options) {
+		o.Base = base
+	}
+}
+
+// WithIRIKeys sets IRIKeys option.
+func WithIRIKeys(keys ...string) Option {
+	return func(o *Options) {
+		o.IRIKeys = keys
+	}
+}
+
+// WithSchema sets Schema option.
+func WithSchema(r *attrs.Registry) Option {
+	return func(o *Options) {
+		o.Schema = r
+	}
+}
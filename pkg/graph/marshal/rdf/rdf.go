@@ -0,0 +1,331 @@
+package rdf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+)
+
+const (
+	// RDFNamespace is RDF vocabulary namespace.
+	RDFNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	// RDFSNamespace is RDF Schema vocabulary namespace.
+	RDFSNamespace = "http://www.w3.org/2000/01/rdf-schema#"
+	// XSDNamespace is XML Schema datatypes namespace.
+	XSDNamespace = "http://www.w3.org/2001/XMLSchema#"
+)
+
+const (
+	// DefaultEdgeLabel is the label of edges which have no label.
+	DefaultEdgeLabel = "Undefined"
+)
+
+const (
+	// uidProp is the property which stores node UIDs.
+	uidProp = "uid"
+)
+
+const (
+	rdfType     = RDFNamespace + "type"
+	rdfJSON     = RDFNamespace + "JSON"
+	xsdInteger  = XSDNamespace + "integer"
+	xsdDouble   = XSDNamespace + "double"
+	xsdBoolean  = XSDNamespace + "boolean"
+	xsdDateTime = XSDNamespace + "dateTime"
+)
+
+// term is RDF term: either an IRI or a literal.
+type term struct {
+	// iri is the IRI of IRI terms.
+	iri string
+	// value is the lexical form of literals.
+	value string
+	// datatype is the datatype IRI of typed literals.
+	// Literals which have no datatype are xsd:string literals.
+	datatype string
+}
+
+// isIRI returns true if t is an IRI.
+func (t term) isIRI() bool {
+	return t.iri != ""
+}
+
+// triple is RDF triple.
+type triple struct {
+	s string
+	p string
+	o term
+}
+
+// Marshaler marshals graphs into RDF.
+type Marshaler struct {
+	opts Options
+}
+
+// NewMarshaler creates a new RDF marshaler and returns it.
+func NewMarshaler(opts ...Option) (*Marshaler, error) {
+	mopts := Options{
+		Format:    DefaultFormat,
+		Namespace: DefaultNamespace,
+		Base:      DefaultBase,
+		IRIKeys:   DefaultIRIKeys,
+	}
+
+	for _, apply := range opts {
+		apply(&mopts)
+	}
+
+	switch mopts.Format {
+	case Turtle, NTriples, JSONLD:
+	default:
+		return nil, graph.Errorf(graph.EINVALID, "unsupported format: %q", mopts.Format)
+	}
+
+	if !isAbsIRI(mopts.Namespace) {
+		return nil, graph.Errorf(graph.EINVALID, "invalid namespace: %q", mopts.Namespace)
+	}
+
+	if !isAbsIRI(mopts.Base) {
+		return nil, graph.Errorf(graph.EINVALID, "invalid base: %q", mopts.Base)
+	}
+
+	return &Marshaler{
+		opts: mopts,
+	}, nil
+}
+
+// Marshal marshals g into RDF.
+// Nodes are identified by the IRIs stored in their IRI attributes; the IRIs of nodes
+// which have none are made of the base IRI and node UIDs. Node labels are mapped to
+// rdf:type classes, edge labels to predicates and attributes to properties in the
+// namespace. Attribute values are typed literals with xsd datatypes as declared in
+// the schema or inferred from their values. Lists are stored as multiple values of
+// the same property and maps as rdf:JSON literals. Edge attributes are not marshaled.
+func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
+	var b bytes.Buffer
+	if err := m.MarshalTo(&b, g); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo marshals g into RDF written to w.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	triples := m.triples(g)
+
+	switch m.opts.Format {
+	case NTriples:
+		return marshalNTriples(w, triples)
+	case JSONLD:
+		return marshalJSONLD(w, triples, m.prefixes())
+	default:
+		return marshalTurtle(w, triples, m.prefixes())
+	}
+}
+
+// prefixes returns namespace prefixes ordered by their names.
+func (m *Marshaler) prefixes() []prefix {
+	return []prefix{
+		{"orb", m.opts.Namespace},
+		{"rdf", RDFNamespace},
+		{"xsd", XSDNamespace},
+	}
+}
+
+// triples returns the triples of g grouped by their subjects.
+// Nodes are ordered by their UIDs and the triples of each node are ordered by
+// their predicates, with the rdf:type and UID triples first.
+func (m *Marshaler) triples(g graph.Graph) []triple {
+	var nodes []graph.Node
+	iris := make(map[int64]string)
+
+	it := g.Nodes()
+	for it.Next() {
+		n := it.Node().(graph.Node)
+		nodes = append(nodes, n)
+		iris[n.ID()] = m.iri(n)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].UID() < nodes[j].UID()
+	})
+
+	edges := make(map[int64][]triple)
+	eit := g.Edges()
+	for eit.Next() {
+		e := eit.Edge().(graph.Edge)
+		l := e.Label()
+		if l == "" {
+			l = DefaultEdgeLabel
+		}
+
+		from := e.From().ID()
+		edges[from] = append(edges[from], triple{
+			s: iris[from],
+			p: m.opts.Namespace + local(l),
+			o: term{iri: iris[e.To().ID()]},
+		})
+	}
+
+	var triples []triple
+	for _, n := range nodes {
+		s := iris[n.ID()]
+
+		if l := n.Label(); l != "" {
+			triples = append(triples, triple{s, rdfType, term{iri: m.opts.Namespace + local(l)}})
+		}
+		triples = append(triples, triple{s, m.opts.Namespace + uidProp, term{value: n.UID()}})
+
+		var props []triple
+		schema := m.opts.Schema.NodeSchema(n.Label())
+		for k, v := range n.Attrs() {
+			if k == uidProp {
+				continue
+			}
+			if t, ok := schema[k]; ok {
+				if cv, err := attrs.Coerce(t, v); err == nil {
+					v = cv
+				}
+			}
+			p := m.opts.Namespace + local(k)
+			for _, o := range literals(v) {
+				props = append(props, triple{s, p, o})
+			}
+		}
+
+		props = append(props, edges[n.ID()]...)
+		sort.SliceStable(props, func(i, j int) bool {
+			if props[i].p != props[j].p {
+				return props[i].p < props[j].p
+			}
+			if props[i].o.isIRI() != props[j].o.isIRI() {
+				return props[j].o.isIRI()
+			}
+			return props[i].o.iri < props[j].o.iri
+		})
+
+		triples = append(triples, props...)
+	}
+
+	return triples
+}
+
+// iri returns the IRI of node n.
+func (m *Marshaler) iri(n graph.Node) string {
+	a := n.Attrs()
+	for _, k := range m.opts.IRIKeys {
+		if s, ok := a[k].(string); ok && isAbsIRI(s) {
+			return s
+		}
+	}
+	return m.opts.Base + url.PathEscape(n.UID())
+}
+
+// literals returns the literals of attribute value v.
+// Lists are returned as literals of their elements.
+func literals(v interface{}) []term {
+	var t term
+
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case string:
+		t = term{value: val}
+	case bool:
+		t = term{value: strconv.FormatBool(val), datatype: xsdBoolean}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		t = term{value: fmt.Sprint(val), datatype: xsdInteger}
+	case float32:
+		t = term{value: double(float64(val)), datatype: xsdDouble}
+	case float64:
+		t = term{value: double(val), datatype: xsdDouble}
+	case time.Time:
+		t = term{value: val.Format(time.RFC3339Nano), datatype: xsdDateTime}
+	case color.RGBA:
+		t = term{value: attrs.Format(attrs.Color, val)}
+	default:
+		switch attrs.TypeOf(v) {
+		case attrs.List:
+			l, err := attrs.Coerce(attrs.List, v)
+			if err != nil {
+				return nil
+			}
+			var terms []term
+			for _, e := range l.([]interface{}) {
+				if attrs.TypeOf(e) == attrs.List {
+					terms = append(terms, jsonLiteral(e)...)
+					continue
+				}
+				terms = append(terms, literals(e)...)
+			}
+			return terms
+		case attrs.Map:
+			return jsonLiteral(v)
+		}
+		t = term{value: fmt.Sprint(v)}
+	}
+
+	return []term{t}
+}
+
+// jsonLiteral returns rdf:JSON literal of v.
+func jsonLiteral(v interface{}) []term {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return []term{{value: string(b), datatype: rdfJSON}}
+}
+
+// double returns xsd:double lexical form of f.
+func double(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "INF"
+	case math.IsInf(f, -1):
+		return "-INF"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// local returns the local name of s in a namespace.
+// All characters other than ASCII letters, digits, '_' and '-' are percent-encoded.
+func local(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isNameChar(c) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// isNameChar returns true if c can be used in local names unescaped.
+func isNameChar(c byte) bool {
+	return c == '_' || c == '-' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// isAbsIRI returns true if s is an absolute IRI.
+func isAbsIRI(s string) bool {
+	if strings.ContainsAny(s, " <>\"{}|^`\\") {
+		return false
+	}
+	u, err := url.Parse(s)
+	return err == nil && u.IsAbs()
+}
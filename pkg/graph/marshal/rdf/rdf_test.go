@@ -0,0 +1,263 @@
+package rdf
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+func MustGraph(t *testing.T) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	nodes := []struct {
+		uid   string
+		label string
+		attrs map[string]interface{}
+	}{
+		{"repo", "Repo", map[string]interface{}{
+			"html_url":   "https://github.com/foo/bar",
+			"name":       "bar \"baz\"",
+			"stars":      int64(10),
+			"starred_at": "2021-01-02T03:04:05Z",
+			"topics":     []interface{}{"go", "graph"},
+			"license":    map[string]interface{}{"key": "mit"},
+		}},
+		{"go-Topic", "Topic", map[string]interface{}{"name": "go", "url": "https://github.com/topics/go"}},
+		{"go lang", "Lang", map[string]interface{}{"name": "go"}},
+	}
+
+	for _, n := range nodes {
+		node, err := memory.NewNode(g.NewNode().ID(),
+			memory.WithUID(n.uid),
+			memory.WithLabel(n.label),
+			memory.WithAttrs(n.attrs),
+		)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(node)
+	}
+
+	edges := []struct {
+		from  string
+		to    string
+		label string
+	}{
+		{"repo", "go-Topic", "HasTopic"},
+		{"repo", "go lang", "IsLanguage"},
+	}
+
+	for _, e := range edges {
+		edge, err := memory.NewEdge(g.NodeWithUID(e.from), g.NodeWithUID(e.to),
+			memory.WithUID(e.from+"-"+e.to),
+			memory.WithLabel(e.label),
+			memory.WithAttrs(map[string]interface{}{"weight": 1.0}),
+		)
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(edge)
+	}
+
+	return g
+}
+
+func MustSchema(t *testing.T) *attrs.Registry {
+	t.Helper()
+
+	r := attrs.NewRegistry()
+	if err := r.SetNodeSchema("Repo", attrs.Schema{"starred_at": attrs.Time}); err != nil {
+		t.Fatalf("failed to set node schema: %v", err)
+	}
+
+	return r
+}
+
+func TestMarshal(t *testing.T) {
+	testCases := []struct {
+		format Format
+		exp    string
+	}{
+		{
+			format: NTriples,
+			exp: `<https://github.com/milosgajdos/orbnet/resource/go%20lang> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <https://github.com/milosgajdos/orbnet/ns#Lang> .
+<https://github.com/milosgajdos/orbnet/resource/go%20lang> <https://github.com/milosgajdos/orbnet/ns#uid> "go lang" .
+<https://github.com/milosgajdos/orbnet/resource/go%20lang> <https://github.com/milosgajdos/orbnet/ns#name> "go" .
+<https://github.com/topics/go> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <https://github.com/milosgajdos/orbnet/ns#Topic> .
+<https://github.com/topics/go> <https://github.com/milosgajdos/orbnet/ns#uid> "go-Topic" .
+<https://github.com/topics/go> <https://github.com/milosgajdos/orbnet/ns#name> "go" .
+<https://github.com/topics/go> <https://github.com/milosgajdos/orbnet/ns#url> "https://github.com/topics/go" .
+<https://github.com/foo/bar> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <https://github.com/milosgajdos/orbnet/ns#Repo> .
+<https://github.com/foo/bar> <https://github.com/milosgajdos/orbnet/ns#uid> "repo" .
+<https://github.com/foo/bar> <https://github.com/milosgajdos/orbnet/ns#HasTopic> <https://github.com/topics/go> .
+<https://github.com/foo/bar> <https://github.com/milosgajdos/orbnet/ns#IsLanguage> <https://github.com/milosgajdos/orbnet/resource/go%20lang> .
+<https://github.com/foo/bar> <https://github.com/milosgajdos/orbnet/ns#html_url> "https://github.com/foo/bar" .
+<https://github.com/foo/bar> <https://github.com/milosgajdos/orbnet/ns#license> "{\"key\":\"mit\"}"^^<http://www.w3.org/1999/02/22-rdf-syntax-ns#JSON> .
+<https://github.com/foo/bar> <https://github.com/milosgajdos/orbnet/ns#name> "bar \"baz\"" .
+<https://github.com/foo/bar> <https://github.com/milosgajdos/orbnet/ns#starred_at> "2021-01-02T03:04:05Z"^^<http://www.w3.org/2001/XMLSchema#dateTime> .
+<https://github.com/foo/bar> <https://github.com/milosgajdos/orbnet/ns#stars> "10"^^<http://www.w3.org/2001/XMLSchema#integer> .
+<https://github.com/foo/bar> <https://github.com/milosgajdos/orbnet/ns#topics> "go" .
+<https://github.com/foo/bar> <https://github.com/milosgajdos/orbnet/ns#topics> "graph" .
+`,
+		},
+		{
+			format: Turtle,
+			exp: `@prefix orb: <https://github.com/milosgajdos/orbnet/ns#> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+
+<https://github.com/milosgajdos/orbnet/resource/go%20lang> a orb:Lang ;
+    orb:uid "go lang" ;
+    orb:name "go" .
+
+<https://github.com/topics/go> a orb:Topic ;
+    orb:uid "go-Topic" ;
+    orb:name "go" ;
+    orb:url "https://github.com/topics/go" .
+
+<https://github.com/foo/bar> a orb:Repo ;
+    orb:uid "repo" ;
+    orb:HasTopic <https://github.com/topics/go> ;
+    orb:IsLanguage <https://github.com/milosgajdos/orbnet/resource/go%20lang> ;
+    orb:html_url "https://github.com/foo/bar" ;
+    orb:license "{\"key\":\"mit\"}"^^rdf:JSON ;
+    orb:name "bar \"baz\"" ;
+    orb:starred_at "2021-01-02T03:04:05Z"^^xsd:dateTime ;
+    orb:stars "10"^^xsd:integer ;
+    orb:topics "go", "graph" .
+`,
+		},
+	}
+
+	g := MustGraph(t)
+
+	for _, tc := range testCases {
+		m, err := NewMarshaler(WithFormat(tc.format), WithSchema(MustSchema(t)))
+		if err != nil {
+			t.Fatalf("failed to create marshaler: %v", err)
+		}
+
+		data, err := m.Marshal(g)
+		if err != nil {
+			t.Fatalf("%s: failed to marshal graph: %v", tc.format, err)
+		}
+
+		if string(data) != tc.exp {
+			t.Errorf("%s: expected:\n%s\ngot:\n%s", tc.format, tc.exp, data)
+		}
+	}
+}
+
+func TestMarshalJSONLD(t *testing.T) {
+	m, err := NewMarshaler(WithFormat(JSONLD), WithSchema(MustSchema(t)))
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	data, err := m.Marshal(MustGraph(t))
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	var doc struct {
+		Context map[string]string        `json:"@context"`
+		Graph   []map[string]interface{} `json:"@graph"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("failed to decode JSON-LD: %v", err)
+	}
+
+	if ns := doc.Context["orb"]; ns != DefaultNamespace {
+		t.Errorf("expected orb namespace: %s, got: %s", DefaultNamespace, ns)
+	}
+
+	if len(doc.Graph) != 3 {
+		t.Fatalf("expected %d nodes, got: %d", 3, len(doc.Graph))
+	}
+
+	repo := doc.Graph[2]
+	if id := repo["@id"]; id != "https://github.com/foo/bar" {
+		t.Errorf("expected repo id: %s, got: %v", "https://github.com/foo/bar", id)
+	}
+
+	if typ := repo["@type"]; typ != "orb:Repo" {
+		t.Errorf("expected repo type: %s, got: %v", "orb:Repo", typ)
+	}
+
+	if topics, ok := repo["orb:topics"].([]interface{}); !ok || len(topics) != 2 {
+		t.Errorf("expected 2 topics, got: %v", repo["orb:topics"])
+	}
+
+	stars := map[string]interface{}{"@value": "10", "@type": "xsd:integer"}
+	if s, ok := repo["orb:stars"].(map[string]interface{}); !ok || s["@value"] != stars["@value"] || s["@type"] != stars["@type"] {
+		t.Errorf("expected stars: %v, got: %v", stars, repo["orb:stars"])
+	}
+
+	topic, ok := repo["orb:HasTopic"].(map[string]interface{})
+	if !ok || topic["@id"] != "https://github.com/topics/go" {
+		t.Errorf("expected topic reference, got: %v", repo["orb:HasTopic"])
+	}
+}
+
+func TestNewMarshalerErrors(t *testing.T) {
+	testCases := []Option{
+		WithFormat("foo"),
+		WithNamespace("ns#"),
+		WithBase("https://example.com/<base>/"),
+	}
+
+	for _, opt := range testCases {
+		if _, err := NewMarshaler(opt); graph.ErrorCode(err) != graph.EINVALID {
+			t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+		}
+	}
+}
+
+func TestLiterals(t *testing.T) {
+	ts := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		v   interface{}
+		exp []term
+	}{
+		{"a", []term{{value: "a"}}},
+		{true, []term{{value: "true", datatype: xsdBoolean}}},
+		{1.5, []term{{value: "1.5", datatype: xsdDouble}}},
+		{math.Inf(-1), []term{{value: "-INF", datatype: xsdDouble}}},
+		{ts, []term{{value: "2021-01-02T03:04:05Z", datatype: xsdDateTime}}},
+		{[]interface{}{1, []int{2}}, []term{{value: "1", datatype: xsdInteger}, {value: "[2]", datatype: rdfJSON}}},
+		{nil, nil},
+	}
+
+	for _, tc := range testCases {
+		got := literals(tc.v)
+		if len(got) != len(tc.exp) {
+			t.Fatalf("%v: expected %d literals, got: %d", tc.v, len(tc.exp), len(got))
+		}
+		for i := range got {
+			if got[i] != tc.exp[i] {
+				t.Errorf("%v: expected: %v, got: %v", tc.v, tc.exp[i], got[i])
+			}
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if got, exp := quote("a\\\"\n\x01"), `"a\\\"\n\u0001"`; got != exp {
+		t.Errorf("expected: %s, got: %s", exp, got)
+	}
+
+	if got, exp := quoteIRI("https://example.com/a b"), `<https://example.com/a\u0020b>`; got != exp {
+		t.Errorf("expected: %s, got: %s", exp, got)
+	}
+}
@@ -0,0 +1,82 @@
+package rdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// prefix maps namespace prefix name to namespace IRI.
+type prefix struct {
+	name string
+	ns   string
+}
+
+// compact returns prefixed name of iri.
+// It returns false if iri is not in any of the namespaces or if
+// its local name can not be used in prefixed names.
+func compact(iri string, prefixes []prefix) (string, bool) {
+	for _, p := range prefixes {
+		if !strings.HasPrefix(iri, p.ns) {
+			continue
+		}
+
+		name := iri[len(p.ns):]
+		if name == "" || name[0] == '-' {
+			return "", false
+		}
+
+		for i := 0; i < len(name); i++ {
+			if !isNameChar(name[i]) && name[i] != '%' {
+				return "", false
+			}
+		}
+
+		return p.name + ":" + name, true
+	}
+
+	return "", false
+}
+
+// quoteIRI returns IRI reference of iri.
+// Characters which are not allowed in IRI references are escaped.
+func quoteIRI(iri string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, r := range iri {
+		if r <= 0x20 || strings.ContainsRune("<>\"{}|^`\\", r) {
+			fmt.Fprintf(&b, `\u%04X`, r)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// quote returns quoted string literal of s.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
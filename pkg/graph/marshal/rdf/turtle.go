@@ -0,0 +1,64 @@
+package rdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// marshalTurtle writes triples grouped by their subjects in Turtle to w.
+// The triples of the same subject are written as a predicate list and
+// the objects of the same predicate are written as an object list.
+func marshalTurtle(w io.Writer, triples []triple, prefixes []prefix) error {
+	b := bufio.NewWriter(w)
+	for _, p := range prefixes {
+		fmt.Fprintf(b, "@prefix %s: %s .\n", p.name, quoteIRI(p.ns))
+	}
+
+	for i, t := range triples {
+		switch {
+		case i == 0 || t.s != triples[i-1].s:
+			if i > 0 {
+				b.WriteString(" .\n")
+			}
+			fmt.Fprintf(b, "\n%s %s %s", quoteIRI(t.s), turtlePredicate(t.p, prefixes), turtleTerm(t.o, prefixes))
+		case t.p != triples[i-1].p:
+			fmt.Fprintf(b, " ;\n    %s %s", turtlePredicate(t.p, prefixes), turtleTerm(t.o, prefixes))
+		default:
+			fmt.Fprintf(b, ", %s", turtleTerm(t.o, prefixes))
+		}
+	}
+
+	if len(triples) > 0 {
+		b.WriteString(" .\n")
+	}
+
+	return b.Flush()
+}
+
+// turtlePredicate returns Turtle representation of predicate p.
+func turtlePredicate(p string, prefixes []prefix) string {
+	if p == rdfType {
+		return "a"
+	}
+	return turtleIRI(p, prefixes)
+}
+
+// turtleTerm returns Turtle representation of t.
+func turtleTerm(t term, prefixes []prefix) string {
+	if t.isIRI() {
+		return turtleIRI(t.iri, prefixes)
+	}
+	if t.datatype == "" {
+		return quote(t.value)
+	}
+	return quote(t.value) + "^^" + turtleIRI(t.datatype, prefixes)
+}
+
+// turtleIRI returns prefixed name of iri or its IRI reference.
+func turtleIRI(iri string, prefixes []prefix) string {
+	if name, ok := compact(iri, prefixes); ok {
+		return name
+	}
+	return quoteIRI(iri)
+}
@@ -0,0 +1,108 @@
+package render
+
+import (
+	"github.com/milosgajdos/orbnet/pkg/graph/layout"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/diagram"
+)
+
+// Format is image format.
+type Format string
+
+const (
+	// SVG is Scalable Vector Graphics format.
+	SVG Format = "svg"
+	// PNG is Portable Network Graphics format.
+	PNG Format = "png"
+)
+
+const (
+	// DefaultFormat is default image format.
+	DefaultFormat = SVG
+	// DefaultWidth is default image width in pixels.
+	DefaultWidth = 1200
+	// DefaultHeight is default image height in pixels.
+	DefaultHeight = 900
+	// DefaultNodeSize is default node radius in pixels.
+	DefaultNodeSize = 6.0
+	// DefaultLayout is default layout of nodes which have no positions.
+	DefaultLayout = layout.ForceAtlas2
+	// DefaultSeed is default seed of node layout.
+	DefaultSeed = layout.DefaultSeed
+)
+
+// DefaultNameKeys are default node attributes which store node names.
+var DefaultNameKeys = diagram.NameKeys
+
+// Options configure marshaler.
+type Options struct {
+	// Format is image format.
+	Format Format
+	// Width is image width in pixels.
+	Width int
+	// Height is image height in pixels.
+	Height int
+	// NodeSize is node radius in pixels.
+	NodeSize float64
+	// Layout lays out the nodes of graphs which have no positions.
+	Layout layout.Algorithm
+	// Seed seeds node layout.
+	Seed int64
+	// Labels draws node names next to the nodes.
+	// PNG images have no labels.
+	Labels bool
+	// NameKeys are node attributes which store node names in the order of precedence.
+	NameKeys []string
+}
+
+// Option is functional marshaler option.
+type Option func(*Options)
+
+// WithFormat sets Format option.
+func WithFormat(f Format) Option {
+	return func(o *Options) {
+		o.Format = f
+	}
+}
+
+// WithSize sets Width and Height options.
+func WithSize(width, height int) Option {
+	return func(o *Options) {
+		o.Width = width
+		o.Height = height
+	}
+}
+
+// WithNodeSize sets NodeSize option.
+func WithNodeSize(s float64) Option {
+	return func(o *Options) {
+		o.NodeSize = s
+	}
+}
+
+// WithLayout sets Layout option.
+func WithLayout(a layout.Algorithm) Option {
+	return func(o *Options) {
+		o.Layout = a
+	}
+}
+
+// WithSeed sets Seed option.
+func WithSeed(s int64) Option {
+	return func(o *Options) {
+		o.Seed = s
+	}
+}
+
+// WithLabels sets Labels option.
+func WithLabels() Option {
+	return func(o *Options) {
+		o.Labels = true
+	}
+}
+
+// WithNameKeys sets NameKeys option.
+func WithNameKeys(keys ...string) Option {
+	return func(o *Options) {
+		o.NameKeys = keys
+	}
+}
@@ -0,0 +1,164 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+)
+
+const (
+	// supersampling is the number of samples per pixel
+	// along each axis used to smooth the image.
+	supersampling = 3
+	// circleSides is the number of sides of polygons which draw circles.
+	circleSides = 32
+)
+
+// png writes the scene encoded in PNG to w.
+// The image is rendered at a higher resolution and downsampled to smooth the edges.
+func (s *scene) png(w io.Writer) error {
+	const k = supersampling
+
+	img := image.NewRGBA(image.Rect(0, 0, s.width*k, s.height*k))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+
+	scale := func(pts []point) []point {
+		for i := range pts {
+			pts[i].X *= k
+			pts[i].Y *= k
+		}
+		return pts
+	}
+
+	for _, e := range s.edges {
+		fill(img, scale(line(e.from, e.to, 1)), e.color, edgeOpacity)
+		if len(e.arrow) > 0 {
+			fill(img, scale(append([]point(nil), e.arrow...)), e.color, edgeOpacity)
+		}
+	}
+
+	for _, n := range s.nodes {
+		fill(img, scale(shape(n.shape, n.pos, s.size+1)), strokeColor, 1)
+		fill(img, scale(shape(n.shape, n.pos, s.size)), n.color, 1)
+	}
+
+	return png.Encode(w, downsample(img, k))
+}
+
+// shape returns the vertices of node shape of the given size centred at p.
+// Circles are approximated by polygons.
+func shape(name string, p point, size float64) []point {
+	if pts := polygon(name, p, size); pts != nil {
+		return pts
+	}
+
+	pts := make([]point, circleSides)
+	for i := range pts {
+		a := 2 * math.Pi * float64(i) / circleSides
+		pts[i] = point{
+			X: p.X + size*math.Cos(a),
+			Y: p.Y + size*math.Sin(a),
+		}
+	}
+
+	return pts
+}
+
+// line returns the rectangle which draws line from-to of the given width.
+func line(from, to point, width float64) []point {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	d := math.Hypot(dx, dy)
+	if d == 0 {
+		return nil
+	}
+
+	nx, ny := -dy/d*width/2, dx/d*width/2
+
+	return []point{
+		{X: from.X + nx, Y: from.Y + ny},
+		{X: to.X + nx, Y: to.Y + ny},
+		{X: to.X - nx, Y: to.Y - ny},
+		{X: from.X - nx, Y: from.Y - ny},
+	}
+}
+
+// fill fills polygon pts with color c of the given opacity.
+// The pixels whose centres are inside the polygon are filled.
+func fill(img *image.RGBA, pts []point, c color.RGBA, opacity float64) {
+	if len(pts) < 3 {
+		return
+	}
+
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, p := range pts {
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+
+	b := img.Bounds()
+	y0 := int(math.Max(math.Floor(minY), float64(b.Min.Y)))
+	y1 := int(math.Min(math.Ceil(maxY), float64(b.Max.Y)))
+
+	var xs []float64
+	for y := y0; y < y1; y++ {
+		cy := float64(y) + 0.5
+
+		xs = xs[:0]
+		for i, p := range pts {
+			q := pts[(i+1)%len(pts)]
+			if (p.Y <= cy) == (q.Y <= cy) {
+				continue
+			}
+			xs = append(xs, p.X+(cy-p.Y)*(q.X-p.X)/(q.Y-p.Y))
+		}
+		sort.Float64s(xs)
+
+		for i := 0; i+1 < len(xs); i += 2 {
+			x0 := int(math.Max(math.Ceil(xs[i]-0.5), float64(b.Min.X)))
+			x1 := int(math.Min(math.Ceil(xs[i+1]-0.5), float64(b.Max.X)))
+			for x := x0; x < x1; x++ {
+				blend(img, x, y, c, opacity)
+			}
+		}
+	}
+}
+
+// blend blends pixel x, y of img with color c of the given opacity.
+func blend(img *image.RGBA, x, y int, c color.RGBA, opacity float64) {
+	i := img.PixOffset(x, y)
+	px := img.Pix[i : i+3 : i+3]
+	for j, v := range [3]uint8{c.R, c.G, c.B} {
+		px[j] = uint8(math.Round(float64(v)*opacity + float64(px[j])*(1-opacity)))
+	}
+}
+
+// downsample returns img scaled down k times.
+// Each pixel is the average of the k*k pixels it replaces.
+func downsample(img *image.RGBA, k int) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx()/k, b.Dy()/k))
+
+	for y := 0; y < out.Rect.Dy(); y++ {
+		for x := 0; x < out.Rect.Dx(); x++ {
+			var sum [4]int
+			for sy := 0; sy < k; sy++ {
+				i := img.PixOffset(x*k, y*k+sy)
+				for sx := 0; sx < k; sx++ {
+					for j := range sum {
+						sum[j] += int(img.Pix[i+4*sx+j])
+					}
+				}
+			}
+
+			o := out.PixOffset(x, y)
+			for j := range sum {
+				out.Pix[o+j] = uint8(sum[j] / (k * k))
+			}
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,303 @@
+// Package render renders graphs into SVG and PNG images.
+package render
+
+import (
+	"bytes"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/layout"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/diagram"
+)
+
+var (
+	// edgeColor is the color of edges which have no style.
+	edgeColor = color.RGBA{R: 0x66, G: 0x66, B: 0x66, A: 0xff}
+	// strokeColor is node outline color.
+	strokeColor = color.RGBA{R: 0x33, G: 0x33, B: 0x33, A: 0xff}
+	// background is image background color.
+	background = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+)
+
+const (
+	// edgeOpacity is edge opacity.
+	edgeOpacity = 0.4
+	// arrowSize is the length of directed edge arrows relative to node size.
+	arrowSize = 1.5
+)
+
+// edgeStyler is implemented by styled edges.
+type edgeStyler interface {
+	Color() color.RGBA
+}
+
+// point is a point in image coordinates.
+type point struct {
+	X float64
+	Y float64
+}
+
+// node is rendered node.
+type node struct {
+	pos   point
+	shape string
+	color color.RGBA
+	name  string
+}
+
+// edge is rendered edge.
+// Arrow is the arrowhead of directed edges.
+type edge struct {
+	from  point
+	to    point
+	color color.RGBA
+	arrow []point
+}
+
+// scene is rendered graph.
+type scene struct {
+	width  int
+	height int
+	size   float64
+	nodes  []node
+	edges  []edge
+}
+
+// Marshaler implements graph.Marshaler.
+type Marshaler struct {
+	opts Options
+}
+
+// NewMarshaler creates a new Marshaler and returns it.
+func NewMarshaler(opts ...Option) (*Marshaler, error) {
+	mopts := Options{
+		Format:   DefaultFormat,
+		Width:    DefaultWidth,
+		Height:   DefaultHeight,
+		NodeSize: DefaultNodeSize,
+		Layout:   DefaultLayout,
+		Seed:     DefaultSeed,
+		NameKeys: DefaultNameKeys,
+	}
+
+	for _, apply := range opts {
+		apply(&mopts)
+	}
+
+	switch mopts.Format {
+	case SVG, PNG:
+	default:
+		return nil, graph.Errorf(graph.EINVALID, "unsupported format: %q", mopts.Format)
+	}
+
+	switch mopts.Layout {
+	case layout.FruchtermanReingold, layout.ForceAtlas2:
+	default:
+		return nil, graph.Errorf(graph.EINVALID, "unsupported layout: %q", mopts.Layout)
+	}
+
+	if mopts.Width <= 0 || mopts.Height <= 0 {
+		return nil, graph.Errorf(graph.EINVALID, "invalid size: %dx%d", mopts.Width, mopts.Height)
+	}
+
+	if mopts.NodeSize <= 0 {
+		return nil, graph.Errorf(graph.EINVALID, "invalid node size: %f", mopts.NodeSize)
+	}
+
+	return &Marshaler{
+		opts: mopts,
+	}, nil
+}
+
+// Marshal renders g into an image.
+// Nodes are placed at the positions stored in their x and y attributes. If any node
+// has no position the nodes are laid out by the Layout algorithm. Nodes are drawn
+// using the colors and shapes of their styles overridden by their color and shape
+// attributes. The alpha channel is ignored as the default styles are transparent.
+func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
+	var b bytes.Buffer
+	if err := m.MarshalTo(&b, g); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo renders g into an image written to w.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	pos, ok := layout.FromAttrs(g)
+	if !ok {
+		var err error
+		pos, err = layout.Layout(g, m.opts.Layout, layout.WithSeed(m.opts.Seed))
+		if err != nil {
+			return err
+		}
+	}
+
+	s := m.scene(g, pos)
+
+	switch m.opts.Format {
+	case PNG:
+		return s.png(w)
+	default:
+		return s.svg(w, m.opts.Labels)
+	}
+}
+
+// scene returns the scene of g whose nodes are at the given positions
+// scaled to fit the image.
+func (m *Marshaler) scene(g graph.Graph, pos layout.Positions) *scene {
+	s := &scene{
+		width:  m.opts.Width,
+		height: m.opts.Height,
+		size:   m.opts.NodeSize,
+		nodes:  make([]node, 0, g.Nodes().Len()),
+		edges:  make([]edge, 0, g.Edges().Len()),
+	}
+
+	project := fit(pos, float64(s.width), float64(s.height), 2*s.size)
+
+	index := make(map[int64]point, len(pos))
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n := nodes.Node().(graph.Node)
+
+		p := project(pos[n.ID()])
+		index[n.ID()] = p
+
+		s.nodes = append(s.nodes, node{
+			pos:   p,
+			shape: diagram.NodeShape(n),
+			color: diagram.NodeColor(n),
+			name:  diagram.NodeName(n, m.opts.NameKeys),
+		})
+	}
+
+	directed := graph.IsDirected(g.Type())
+
+	edges := g.Edges()
+	for edges.Next() {
+		e := edges.Edge().(graph.Edge)
+
+		from, to := index[e.From().ID()], index[e.To().ID()]
+		if from == to {
+			continue
+		}
+
+		c := edgeColor
+		if es, ok := e.(edgeStyler); ok {
+			c = es.Color()
+			c.A = 0xff
+		}
+
+		ed := edge{
+			from:  from,
+			to:    to,
+			color: c,
+		}
+
+		if directed {
+			ed.to, ed.arrow = arrow(from, to, s.size)
+		}
+
+		s.edges = append(s.edges, ed)
+	}
+
+	return s
+}
+
+// fit returns a function which projects layout positions onto an image
+// of the given size with the given margin preserving their aspect ratio.
+// The y axis points up in the layout and down in the image.
+func fit(pos layout.Positions, width, height, margin float64) func(layout.Point) point {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range pos {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+
+	cx, cy := (minX+maxX)/2, (minY+maxY)/2
+
+	scale := 1.0
+	if dx, dy := maxX-minX, maxY-minY; dx > 0 || dy > 0 {
+		scale = math.Inf(1)
+		if dx > 0 {
+			scale = math.Max(width-2*margin, 1) / dx
+		}
+		if dy > 0 {
+			scale = math.Min(scale, math.Max(height-2*margin, 1)/dy)
+		}
+	}
+
+	return func(p layout.Point) point {
+		return point{
+			X: width/2 + (p.X-cx)*scale,
+			Y: height/2 - (p.Y-cy)*scale,
+		}
+	}
+}
+
+// arrow returns the end of edge from-to shortened to the boundary of
+// its target node of the given size and the arrowhead of the edge.
+func arrow(from, to point, size float64) (point, []point) {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	d := math.Hypot(dx, dy)
+	ux, uy := dx/d, dy/d
+
+	tip := point{X: to.X - ux*size, Y: to.Y - uy*size}
+
+	l := arrowSize * size
+	if l > d-size {
+		l = math.Max(d-size, 0)
+	}
+	w := l / 2
+
+	base := point{X: tip.X - ux*l, Y: tip.Y - uy*l}
+
+	return base, []point{
+		tip,
+		{X: base.X - uy*w, Y: base.Y + ux*w},
+		{X: base.X + uy*w, Y: base.Y - ux*w},
+	}
+}
+
+// polygon returns the vertices of node shape of the given size centred at p.
+// It returns nil for circular shapes.
+func polygon(shape string, p point, size float64) []point {
+	var (
+		sides int
+		rot   float64
+	)
+
+	switch shape {
+	case "box", "rect", "rectangle", "square":
+		sides, rot = 4, math.Pi/4
+		size *= math.Sqrt2 * 0.9
+	case "diamond":
+		sides = 4
+	case "triangle":
+		sides = 3
+	case "pentagon":
+		sides = 5
+	case "hexagon":
+		sides = 6
+	case "octagon":
+		sides, rot = 8, math.Pi/8
+	default:
+		return nil
+	}
+
+	pts := make([]point, sides)
+	for i := range pts {
+		a := rot + 2*math.Pi*float64(i)/float64(sides) - math.Pi/2
+		pts[i] = point{
+			X: p.X + size*math.Cos(a),
+			Y: p.Y + size*math.Sin(a),
+		}
+	}
+
+	return pts
+}
@@ -0,0 +1,258 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	"github.com/milosgajdos/orbnet/pkg/graph/style"
+)
+
+var (
+	repoColor  = color.RGBA{R: 0, G: 255, B: 153}
+	topicColor = color.RGBA{R: 153, G: 153, B: 255}
+)
+
+func MustGraph(t *testing.T) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph(memory.WithType(graph.WeightedDirected))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	repo, err := memory.NewNode(g.NewNode().ID(),
+		memory.WithUID("repo"),
+		memory.WithLabel("Repo"),
+		memory.WithAttrs(map[string]interface{}{"full_name": "foo/<bar>"}),
+		memory.WithStyle(style.Style{Shape: "diamond", Color: repoColor}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(repo)
+
+	topic, err := memory.NewNode(g.NewNode().ID(),
+		memory.WithUID("go-Topic"),
+		memory.WithLabel("Topic"),
+		memory.WithStyle(style.Style{Shape: "ellipse", Color: topicColor}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(topic)
+
+	e, err := memory.NewEdge(repo, topic, memory.WithLabel("HasTopic"))
+	if err != nil {
+		t.Fatalf("failed to create edge: %v", err)
+	}
+	g.SetWeightedEdge(e)
+
+	return g
+}
+
+// svgDoc is rendered SVG document.
+type svgDoc struct {
+	Width  int        `xml:"width,attr"`
+	Height int        `xml:"height,attr"`
+	Groups []svgGroup `xml:"g"`
+}
+
+// svgGroup is SVG group of shapes.
+type svgGroup struct {
+	Lines    []struct{} `xml:"line"`
+	Circles  []svgShape `xml:"circle"`
+	Polygons []svgShape `xml:"polygon"`
+	Texts    []string   `xml:"text"`
+}
+
+// svgShape is SVG shape.
+type svgShape struct {
+	CX    float64 `xml:"cx,attr"`
+	CY    float64 `xml:"cy,attr"`
+	Fill  string  `xml:"fill,attr"`
+	Title string  `xml:"title"`
+}
+
+func TestSVG(t *testing.T) {
+	g := MustGraph(t)
+	g.NodeWithUID("repo").Attrs()["x"] = -1.0
+	g.NodeWithUID("repo").Attrs()["y"] = 0.0
+	g.NodeWithUID("go-Topic").Attrs()["x"] = 1.0
+	g.NodeWithUID("go-Topic").Attrs()["y"] = 0.0
+
+	m, err := NewMarshaler(WithSize(200, 100), WithNodeSize(5), WithLabels())
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	data, err := m.Marshal(g)
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	var doc svgDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("failed to decode SVG: %v", err)
+	}
+
+	if doc.Width != 200 || doc.Height != 100 {
+		t.Errorf("expected size: 200x100, got: %dx%d", doc.Width, doc.Height)
+	}
+
+	if len(doc.Groups) != 3 {
+		t.Fatalf("expected 3 groups, got: %d", len(doc.Groups))
+	}
+
+	edges, nodes, labels := doc.Groups[0], doc.Groups[1], doc.Groups[2]
+
+	if len(edges.Lines) != 1 || len(edges.Polygons) != 1 {
+		t.Errorf("expected edge line and arrow, got: %d lines %d arrows", len(edges.Lines), len(edges.Polygons))
+	}
+
+	if len(nodes.Circles) != 1 {
+		t.Fatalf("expected 1 circle, got: %d", len(nodes.Circles))
+	}
+
+	topic := nodes.Circles[0]
+	if topic.CX != 190 || topic.CY != 50 {
+		t.Errorf("expected topic position: 190 50, got: %v %v", topic.CX, topic.CY)
+	}
+
+	if topic.Fill != "#9999ff" || topic.Title != "go-Topic" {
+		t.Errorf("expected topic fill: #9999ff, title: go-Topic, got: %s %s", topic.Fill, topic.Title)
+	}
+
+	if len(nodes.Polygons) != 1 {
+		t.Fatalf("expected 1 polygon, got: %d", len(nodes.Polygons))
+	}
+
+	repo := nodes.Polygons[0]
+	if repo.Fill != "#00ff99" || repo.Title != "foo/<bar>" {
+		t.Errorf("expected repo fill: #00ff99, title: foo/<bar>, got: %s %s", repo.Fill, repo.Title)
+	}
+
+	if len(labels.Texts) != 2 {
+		t.Errorf("expected 2 labels, got: %d", len(labels.Texts))
+	}
+
+	if !bytes.Contains(data, []byte("foo/&lt;bar&gt;")) {
+		t.Errorf("expected escaped repo name")
+	}
+}
+
+func TestSVGLayout(t *testing.T) {
+	g := MustGraph(t)
+
+	m, err := NewMarshaler()
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	data, err := m.Marshal(g)
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	if strings.Contains(string(data), "NaN") {
+		t.Errorf("expected valid coordinates")
+	}
+
+	again, err := m.Marshal(g)
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	if !bytes.Equal(data, again) {
+		t.Errorf("expected deterministic rendering")
+	}
+
+	if _, ok := g.NodeWithUID("repo").Attrs()["x"]; ok {
+		t.Errorf("expected no position attributes")
+	}
+}
+
+func TestPNG(t *testing.T) {
+	g := MustGraph(t)
+	g.NodeWithUID("repo").Attrs()["x"] = -1.0
+	g.NodeWithUID("repo").Attrs()["y"] = 0.0
+	g.NodeWithUID("go-Topic").Attrs()["x"] = 1.0
+	g.NodeWithUID("go-Topic").Attrs()["y"] = 0.0
+
+	m, err := NewMarshaler(WithFormat(PNG), WithSize(200, 100), WithNodeSize(5))
+	if err != nil {
+		t.Fatalf("failed to create marshaler: %v", err)
+	}
+
+	data, err := m.Marshal(g)
+	if err != nil {
+		t.Fatalf("failed to marshal graph: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode PNG: %v", err)
+	}
+
+	if b := img.Bounds(); b.Dx() != 200 || b.Dy() != 100 {
+		t.Fatalf("expected size: 200x100, got: %dx%d", b.Dx(), b.Dy())
+	}
+
+	testCases := []struct {
+		name string
+		x, y int
+		c    color.RGBA
+	}{
+		{"repo", 10, 50, repoColor},
+		{"topic", 190, 50, topicColor},
+		{"background", 100, 10, background},
+	}
+
+	for _, tc := range testCases {
+		tc.c.A = 0xff
+		if c := color.RGBAModel.Convert(img.At(tc.x, tc.y)).(color.RGBA); c != tc.c {
+			t.Errorf("%s: expected color: %v, got: %v", tc.name, tc.c, c)
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	for _, f := range []Format{SVG, PNG} {
+		m, err := NewMarshaler(WithFormat(f))
+		if err != nil {
+			t.Fatalf("%s: failed to create marshaler: %v", f, err)
+		}
+
+		if _, err := m.Marshal(g); err != nil {
+			t.Errorf("%s: failed to marshal graph: %v", f, err)
+		}
+	}
+}
+
+func TestNewMarshalerErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{"Format", []Option{WithFormat("foo")}},
+		{"Layout", []Option{WithLayout("foo")}},
+		{"Size", []Option{WithSize(0, 100)}},
+		{"NodeSize", []Option{WithNodeSize(-1)}},
+	}
+
+	for _, tc := range testCases {
+		if _, err := NewMarshaler(tc.opts...); graph.ErrorCode(err) != graph.EINVALID {
+			t.Errorf("%s: expected error: %s, got: %v", tc.name, graph.EINVALID, err)
+		}
+	}
+}
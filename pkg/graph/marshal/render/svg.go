@@ -0,0 +1,77 @@
+package render
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// svg writes the scene encoded in SVG to w.
+// Node names are shown as tooltips and drawn next to the nodes if labels is true.
+func (s *scene) svg(w io.Writer, labels bool) error {
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		s.width, s.height, s.width, s.height)
+	fmt.Fprintf(b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(background))
+
+	fmt.Fprintf(b, `<g stroke-width="1" stroke-opacity="%g" fill-opacity="%g">`+"\n", edgeOpacity, edgeOpacity)
+	for _, e := range s.edges {
+		fmt.Fprintf(b, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s"/>`+"\n",
+			e.from.X, e.from.Y, e.to.X, e.to.Y, hex(e.color))
+		if len(e.arrow) > 0 {
+			fmt.Fprintf(b, `<polygon points="%s" fill="%s"/>`+"\n", points(e.arrow), hex(e.color))
+		}
+	}
+	b.WriteString("</g>\n")
+
+	fmt.Fprintf(b, `<g stroke="%s" stroke-width="1">`+"\n", hex(strokeColor))
+	for _, n := range s.nodes {
+		pts := polygon(n.shape, n.pos, s.size)
+		if pts == nil {
+			fmt.Fprintf(b, `<circle cx="%.2f" cy="%.2f" r="%.2f" fill="%s"><title>%s</title></circle>`+"\n",
+				n.pos.X, n.pos.Y, s.size, hex(n.color), escape(n.name))
+			continue
+		}
+		fmt.Fprintf(b, `<polygon points="%s" fill="%s"><title>%s</title></polygon>`+"\n",
+			points(pts), hex(n.color), escape(n.name))
+	}
+	b.WriteString("</g>\n")
+
+	if labels {
+		fmt.Fprintf(b, `<g font-family="sans-serif" font-size="%.2f" fill="%s">`+"\n", 1.5*s.size+2, hex(strokeColor))
+		for _, n := range s.nodes {
+			fmt.Fprintf(b, `<text x="%.2f" y="%.2f">%s</text>`+"\n", n.pos.X+1.5*s.size, n.pos.Y+s.size/2, escape(n.name))
+		}
+		b.WriteString("</g>\n")
+	}
+
+	b.WriteString("</svg>\n")
+
+	return b.Flush()
+}
+
+// points returns SVG polygon points attribute of pts.
+func points(pts []point) string {
+	s := make([]string, len(pts))
+	for i, p := range pts {
+		s[i] = fmt.Sprintf("%.2f,%.2f", p.X, p.Y)
+	}
+	return strings.Join(s, " ")
+}
+
+// hex returns hex code of c ignoring its alpha channel.
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// escape returns s escaped for XML character data.
+func escape(s string) string {
+	var b strings.Builder
+	// NOTE: strings.Builder never returns errors
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -1,13 +1,23 @@
 package sigma
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/internal/jsonstream"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/meta"
 	"gonum.org/v1/gonum/graph/formats/sigmajs"
 )
 
+var (
+	// nodeKeys are the keys of node fields reserved by SigmaJS.
+	nodeKeys = []string{"id"}
+	// edgeKeys are the keys of edge fields reserved by SigmaJS.
+	edgeKeys = []string{"id", "source", "target"}
+)
+
 // Marshaler implements graph.Marshaler.
 type Marshaler struct {
 	name   string
@@ -26,39 +36,69 @@ func NewMarshaler(name, prefix, indent string) (*Marshaler, error) {
 
 // Marshal marshals g into format that can be used by
 // SigmaJS. See here for more: http://sigmajs.org/
+// Node UIDs, labels, styles and edge weights are stored in the reserved attribute.
 func (m *Marshaler) Marshal(g graph.Graph) ([]byte, error) {
-	c := sigmajs.Graph{
-		Nodes: make([]sigmajs.Node, g.Nodes().Len()),
-		Edges: make([]sigmajs.Edge, g.Edges().Len()),
+	var b bytes.Buffer
+	if err := m.MarshalTo(&b, g); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// MarshalTo marshals g into w encoding its nodes and edges one by one.
+func (m *Marshaler) MarshalTo(w io.Writer, g graph.Graph) error {
+	enc := jsonstream.NewEncoder(w, m.prefix, m.indent)
+
+	if err := enc.BeginArray("nodes"); err != nil {
+		return err
 	}
 
 	nodes := g.Nodes()
-	i := 0
 	for nodes.Next() {
 		n := nodes.Node().(graph.Node)
 
-		c.Nodes[i] = sigmajs.Node{
+		m := meta.Node(n)
+		a := m.Reserve(n.Attrs(), nodeKeys...)
+
+		if err := enc.Elem(&sigmajs.Node{
 			ID:         fmt.Sprint(n.ID()),
-			Attributes: n.Attrs(),
+			Attributes: meta.Attrs(a, m),
+		}); err != nil {
+			return err
 		}
+	}
 
-		i++
+	if err := enc.EndArray(); err != nil {
+		return err
+	}
+
+	if err := enc.BeginArray("edges"); err != nil {
+		return err
 	}
 
 	edges := g.Edges()
-	i = 0
+	i := 0
 	for edges.Next() {
 		e := edges.Edge().(graph.Edge)
 
-		c.Edges[i] = sigmajs.Edge{
+		m := meta.Edge(e)
+		a := m.Reserve(e.Attrs(), edgeKeys...)
+
+		if err := enc.Elem(&sigmajs.Edge{
 			ID:         fmt.Sprint(i),
 			Source:     fmt.Sprint(e.From().ID()),
 			Target:     fmt.Sprint(e.To().ID()),
-			Attributes: e.Attrs(),
+			Attributes: meta.Attrs(a, m),
+		}); err != nil {
+			return err
 		}
 
 		i++
 	}
 
-	return json.MarshalIndent(c, m.prefix, m.indent)
+	if err := enc.EndArray(); err != nil {
+		return err
+	}
+
+	return enc.Close()
 }
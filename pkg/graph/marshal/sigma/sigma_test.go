@@ -0,0 +1,100 @@
+package sigma
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+func TestUnmarshal(t *testing.T) {
+	// SigmaJS graph
+	data := `{
+  "nodes": [
+    {"id": "a", "label": "A", "x": 1, "y": 2, "size": 3, "color": "#ff0000"},
+    {"id": "b"}
+  ],
+  "edges": [
+    {"id": "e0", "source": "a", "target": "b", "weight": 0.5},
+    {"id": "e1", "source": "a", "target": "b"}
+  ]
+}`
+
+	typ, err := GraphType([]byte(data))
+	if err != nil {
+		t.Fatalf("failed to get graph type: %v", err)
+	}
+
+	if typ != graph.WeightedDirectedMulti {
+		t.Errorf("expected graph type: %s, got: %s", graph.WeightedDirectedMulti, typ)
+	}
+
+	u, err := NewUnmarshaler()
+	if err != nil {
+		t.Fatalf("failed to create unmarshaler: %v", err)
+	}
+
+	g, err := memory.NewGraph(memory.WithType(graph.WeightedDirected))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	if err := u.Unmarshal([]byte(data), g); err != nil {
+		t.Fatalf("failed to unmarshal graph: %v", err)
+	}
+
+	a, b := g.NodeWithUID("a"), g.NodeWithUID("b")
+	if a == nil || b == nil {
+		t.Fatalf("expected nodes a and b")
+	}
+
+	if l := a.Attrs()["label"]; l != "A" {
+		t.Errorf("expected label attribute: A, got: %v", l)
+	}
+
+	if c := a.(*memory.Node).Color(); c != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("expected color: red, got: %v", c)
+	}
+
+	if x, y := a.Attrs()["x"], a.Attrs()["y"]; x != 1.0 || y != 2.0 {
+		t.Errorf("expected position: 1 2, got: %v %v", x, y)
+	}
+
+	if n := g.Edges().Len(); n != 1 {
+		t.Errorf("expected 1 edge, got: %d", n)
+	}
+
+	if w := g.Edge(a.ID(), b.ID()).(graph.Edge).Weight(); w != 0.5 {
+		t.Errorf("expected weight: 0.5, got: %v", w)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{"JSON", `{`},
+		{"Node", `{"nodes": [{"id": "a"}, {"id": "a"}]}`},
+		{"Edge", `{"nodes": [{"id": "a"}], "edges": [{"id": "e", "source": "a", "target": "b"}]}`},
+		{"Meta", `{"nodes": [{"id": "a", "orbnet": 1}]}`},
+		{"Value", `{"nodes": [{"id": "a", "orbnet": {"types": {"n": "int"}}, "n": "foo"}]}`},
+	}
+
+	u, err := NewUnmarshaler()
+	if err != nil {
+		t.Fatalf("failed to create unmarshaler: %v", err)
+	}
+
+	for _, tc := range testCases {
+		g, err := memory.NewGraph()
+		if err != nil {
+			t.Fatalf("failed to create graph: %v", err)
+		}
+
+		if err := u.Unmarshal([]byte(tc.data), g); graph.ErrorCode(err) != graph.EINVALID {
+			t.Errorf("%s: expected error: %s, got: %v", tc.name, graph.EINVALID, err)
+		}
+	}
+}
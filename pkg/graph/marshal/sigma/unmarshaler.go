@@ -0,0 +1,77 @@
+package sigma
+
+import (
+	"encoding/json"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/internal/meta"
+	"gonum.org/v1/gonum/graph/formats/sigmajs"
+)
+
+// Unmarshaler implements graph.Unmarshaler.
+type Unmarshaler struct {
+	opts Options
+}
+
+// NewUnmarshaler creates a new Unmarshaler and returns it.
+func NewUnmarshaler(opts ...Option) (*Unmarshaler, error) {
+	uopts := Options{}
+	for _, apply := range opts {
+		apply(&uopts)
+	}
+
+	return &Unmarshaler{
+		opts: uopts,
+	}, nil
+}
+
+// Unmarshal unmarshals SigmaJS data into g.
+// The attributes of the nodes and edges which were not marshaled by Marshaler
+// are used as graph attributes and their IDs as node UIDs.
+func (u *Unmarshaler) Unmarshal(data []byte, g graph.Graph) error {
+	nodes, edges, err := decode(data)
+	if err != nil {
+		return err
+	}
+
+	return meta.Build(g, nodes, edges, u.opts.Schema)
+}
+
+// GraphType returns the type of graph encoded in SigmaJS data.
+// SigmaJS does not store edge directions so the graph is always directed.
+func GraphType(data []byte) (string, error) {
+	_, edges, err := decode(data)
+	if err != nil {
+		return "", err
+	}
+
+	return meta.GraphType(true, edges), nil
+}
+
+// decode decodes the nodes and edges of SigmaJS data.
+func decode(data []byte) ([]meta.Element, []meta.Element, error) {
+	var c sigmajs.Graph
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, nil, graph.Errorf(graph.EINVALID, "invalid SigmaJS: %v", err)
+	}
+
+	nodes := make([]meta.Element, len(c.Nodes))
+	for i, n := range c.Nodes {
+		nodes[i] = meta.Element{
+			ID:    n.ID,
+			Attrs: n.Attributes,
+		}
+	}
+
+	edges := make([]meta.Element, len(c.Edges))
+	for i, e := range c.Edges {
+		edges[i] = meta.Element{
+			ID:     e.ID,
+			Source: e.Source,
+			Target: e.Target,
+			Attrs:  e.Attributes,
+		}
+	}
+
+	return nodes, edges, nil
+}
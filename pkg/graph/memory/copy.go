@@ -1,12 +1,69 @@
 package memory
 
 import (
-	"sync"
-
+	"github.com/milosgajdos/orbnet/pkg/graph"
 	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
-	"gonum.org/v1/gonum/graph/simple"
+	"github.com/milosgajdos/orbnet/pkg/graph/style"
+	gonum "gonum.org/v1/gonum/graph"
 )
 
+// styleOf returns the style of s if it implements graph.Styler.
+func styleOf(s interface{}, def style.Style) style.Style {
+	if st, ok := s.(graph.Styler); ok {
+		return style.Style{
+			Type:  st.Type(),
+			Shape: st.Shape(),
+			Color: st.Color(),
+		}
+	}
+	return def
+}
+
+// NodeOptions returns the options which copy UID, label, attributes and style of n.
+// Attribute values are deep copied.
+func NodeOptions(n graph.Node) []Option {
+	opts := []Option{
+		WithUID(n.UID()),
+		WithLabel(n.Label()),
+		WithAttrs(attrs.DeepCopy(n.Attrs())),
+		WithStyle(styleOf(n, style.DefaultNode())),
+	}
+
+	if dn, ok := n.(graph.DOTNode); ok {
+		opts = append(opts, WithDotID(dn.DOTID()))
+	}
+
+	return opts
+}
+
+// NewNodeFrom creates a new Node with the given id from an arbitrary graph node n.
+// The new node copies UID, label, attributes and style of n.
+func NewNodeFrom(id int64, n graph.Node) (*Node, error) {
+	return NewNode(id, NodeOptions(n)...)
+}
+
+// NewEdgeFrom creates a new Edge between from and to nodes from an arbitrary graph edge e.
+// The new edge copies UID, label, weight, attributes and style of e
+// as well as its line ID if e is a multigraph line.
+func NewEdgeFrom(from, to gonum.Node, e graph.Edge) (*Edge, error) {
+	edge, err := NewEdge(from, to,
+		WithUID(e.UID()),
+		WithLabel(e.Label()),
+		WithWeight(e.Weight()),
+		WithAttrs(attrs.DeepCopy(e.Attrs())),
+		WithStyle(styleOf(e, style.DefaultEdge())),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if l, ok := e.(gonum.Line); ok {
+		edge.id = l.ID()
+	}
+
+	return edge, nil
+}
+
 // NodeDeepCopy makes a deep copy of Node and returns it.
 func NodeDeepCopy(n *Node) *Node {
 	return &Node{
@@ -22,6 +79,7 @@ func NodeDeepCopy(n *Node) *Node {
 // EdgeDeepCopy makes a deep copy of Edge and returns it
 func EdgeDeepCopy(e *Edge) *Edge {
 	return &Edge{
+		id:     e.id,
 		uid:    e.uid,
 		from:   NodeDeepCopy(e.From().(*Node)),
 		to:     NodeDeepCopy(e.To().(*Node)),
@@ -37,34 +95,23 @@ func GraphDeepCopy(g *Graph) *Graph {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	cg := &Graph{
-		WeightedDirectedGraph: simple.NewWeightedDirectedGraph(DefaultWeight, 0.0),
-		uid:                   g.uid,
-		typ:                   g.typ,
-		label:                 g.label,
-		attrs:                 attrs.CopyFrom(g.attrs),
-		mu:                    &sync.RWMutex{},
-	}
+	cg := newGraph(Options{
+		UID:   g.uid,
+		Type:  g.typ,
+		Label: g.label,
+		Attrs: attrs.CopyFrom(g.attrs),
+	})
 
 	// copy all src nodes.
-	nodes := g.Nodes()
-	for nodes.Next() {
-		n := nodes.Node().(*Node)
-		node := NodeDeepCopy(n)
+	for _, n := range g.nodes {
+		node := NodeDeepCopy(n.(*Node))
 		cg.AddNode(node)
 	}
 
 	// copy all src edges.
-	nodes.Reset()
-	for nodes.Next() {
-		nid := nodes.Node().ID()
-		to := g.From(nid)
-		for to.Next() {
-			vid := to.Node().ID()
-			e := g.WeightedEdge(nid, vid).(*Edge)
-			edge := EdgeDeepCopy(e)
-			cg.SetWeightedEdge(edge)
-		}
+	for _, e := range g.allEdges() {
+		edge := EdgeDeepCopy(e.(*Edge))
+		cg.SetWeightedEdge(edge)
 	}
 
 	return cg
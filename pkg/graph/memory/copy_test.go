@@ -52,3 +52,38 @@ func TestCopy(t *testing.T) {
 		}
 	})
 }
+
+func TestNewFrom(t *testing.T) {
+	g := MustGraph(t)
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n := nodes.Node().(*Node)
+		n2, err := NewNodeFrom(n.ID()+10, n)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+
+		if n2.ID() != n.ID()+10 {
+			t.Errorf("expected ID: %d, got: %d", n.ID()+10, n2.ID())
+		}
+
+		n2.id = n.id
+		if !reflect.DeepEqual(n, n2) {
+			t.Errorf("expected nodes to be equal n: %#v, n2: %#v", n, n2)
+		}
+	}
+
+	edges := g.Edges()
+	for edges.Next() {
+		e := edges.Edge().(*Edge)
+		e2, err := NewEdgeFrom(e.From(), e.To(), e)
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+
+		if !reflect.DeepEqual(e, e2) {
+			t.Errorf("expected edges to be equal e: %#v, e2: %#v", e, e2)
+		}
+	}
+}
@@ -11,7 +11,10 @@ import (
 )
 
 // Edge is a weighted graph edge.
+// Edge is also a weighted multigraph line: its line ID
+// is assigned when the edge is added to a Graph.
 type Edge struct {
+	id     int64
 	uid    string
 	from   gonum.Node
 	to     gonum.Node
@@ -38,6 +41,7 @@ func NewEdge(from, to gonum.Node, opts ...Option) (*Edge, error) {
 	}
 
 	return &Edge{
+		id:     -1,
 		uid:    eopts.UID,
 		from:   from,
 		to:     to,
@@ -48,6 +52,12 @@ func NewEdge(from, to gonum.Node, opts ...Option) (*Edge, error) {
 	}, nil
 }
 
+// ID returns edge line ID.
+// It returns -1 if the edge has not been added to any graph yet.
+func (e Edge) ID() int64 {
+	return e.id
+}
+
 // UID returns edge UID.
 func (e Edge) UID() string {
 	return e.uid
@@ -76,15 +86,22 @@ func (e *Edge) SetWeight(w float64) {
 // ReversedEdge returns a new edge with end points of the pair swapped.
 func (e *Edge) ReversedEdge() gonum.Edge {
 	return &Edge{
-		uid:   e.uid,
-		from:  e.to,
-		to:    e.from,
-		label: e.label,
-		attrs: e.attrs,
-		style: e.style,
+		id:     e.id,
+		uid:    e.uid,
+		from:   e.to,
+		to:     e.from,
+		weight: e.weight,
+		label:  e.label,
+		attrs:  e.attrs,
+		style:  e.style,
 	}
 }
 
+// ReversedLine returns a new line with end points of the pair swapped.
+func (e *Edge) ReversedLine() gonum.Line {
+	return e.ReversedEdge().(*Edge)
+}
+
 // Label returns edge label.
 func (e Edge) Label() string {
 	return e.label
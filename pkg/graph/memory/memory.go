@@ -2,11 +2,15 @@ package memory
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/google/uuid"
+	"github.com/milosgajdos/orbnet/pkg/graph"
 
 	gonum "gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/iterator"
+	"gonum.org/v1/gonum/graph/set/uid"
 	"gonum.org/v1/gonum/graph/simple"
 )
 
@@ -14,19 +18,41 @@ const (
 	// DefaultLabel is memory graph default label.
 	DefaultLabel = "InMemoryGraph"
 	// DefaultType is default graph type.
-	DefaultType = "weighted_directed"
+	DefaultType = graph.WeightedDirected
 	// DefaultWeight is default edge weight.
 	DefaultWeight = 1.0
 )
 
-// Graph is an in-memory graph.
+// pair is a key of the lines between two nodes.
+// Undirected graph pairs are ordered by node IDs.
+type pair struct {
+	from, to int64
+}
+
+// Graph is an in-memory graph safe for concurrent use.
+// Graph can be any of the graph.Weighted* types: undirected graphs
+// return the same neighbours for From and To, multigraphs allow
+// parallel edges (lines) between the same pair of nodes.
+// Nodes and edges which implement graph.Node and graph.Edge
+// are indexed by their UIDs. Their UIDs must not change
+// once they have been added to the graph.
 type Graph struct {
-	*simple.WeightedDirectedGraph
-	uid   string
-	typ   string
-	label string
-	attrs map[string]interface{}
-	mu    *sync.RWMutex
+	uid      string
+	typ      string
+	label    string
+	attrs    map[string]interface{}
+	directed bool
+	multi    bool
+	nodes    map[int64]gonum.Node
+	from     map[int64]map[int64]struct{}
+	to       map[int64]map[int64]struct{}
+	lines    map[pair][]gonum.WeightedEdge
+	nodeIDs  *uid.Set
+	lineIDs  *uid.Set
+	lineSet  map[int64]gonum.WeightedEdge
+	nodeUIDs map[string]graph.Node
+	edgeUIDs map[string]graph.Edge
+	mu       *sync.RWMutex
 }
 
 // NewGraph creates a new graph and returns it.
@@ -42,32 +68,54 @@ func NewGraph(opts ...Option) (*Graph, error) {
 		apply(&gopts)
 	}
 
-	if gopts.Type != DefaultType {
+	if !graph.IsValidType(gopts.Type) {
 		return nil, fmt.Errorf("unsupported graph type: %s", gopts.Type)
 	}
 
-	return &Graph{
-		WeightedDirectedGraph: simple.NewWeightedDirectedGraph(DefaultWeight, 0.0),
-		uid:                   gopts.UID,
-		typ:                   gopts.Type,
-		label:                 gopts.Label,
-		attrs:                 gopts.Attrs,
-		mu:                    &sync.RWMutex{},
-	}, nil
+	return newGraph(gopts), nil
+}
+
+func newGraph(gopts Options) *Graph {
+	g := &Graph{
+		uid:      gopts.UID,
+		typ:      gopts.Type,
+		label:    gopts.Label,
+		attrs:    gopts.Attrs,
+		directed: graph.IsDirected(gopts.Type),
+		multi:    graph.IsMulti(gopts.Type),
+		nodes:    make(map[int64]gonum.Node),
+		from:     make(map[int64]map[int64]struct{}),
+		lines:    make(map[pair][]gonum.WeightedEdge),
+		nodeIDs:  uid.NewSet(),
+		lineIDs:  uid.NewSet(),
+		lineSet:  make(map[int64]gonum.WeightedEdge),
+		nodeUIDs: make(map[string]graph.Node),
+		edgeUIDs: make(map[string]graph.Edge),
+		mu:       &sync.RWMutex{},
+	}
+
+	g.to = g.from
+	if g.directed {
+		g.to = make(map[int64]map[int64]struct{})
+	}
+
+	return g
 }
 
 // UID returns graph UID.
-func (g Graph) UID() string {
+func (g *Graph) UID() string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 	return g.uid
 }
 
 // Type returns the type of graph.
-func (g Graph) Type() string {
+func (g *Graph) Type() string {
 	return g.typ
 }
 
 // Label returns graph label.
-func (g Graph) Label() string {
+func (g *Graph) Label() string {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 	return g.label
@@ -94,16 +142,504 @@ func (g *Graph) Attrs() map[string]interface{} {
 	return g.attrs
 }
 
-// HasEdgeFromTo returns whether an edge exist between two nodoes with the given IDs.
-func (g Graph) HasEdgeFromTo(uid, vid int64) bool {
+// Node returns the node with the given ID if it exists in the graph, and nil otherwise.
+func (g *Graph) Node(id int64) gonum.Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.nodes[id]
+}
+
+// NodeWithUID returns the node with the given UID if it exists in the graph, and nil otherwise.
+func (g *Graph) NodeWithUID(uid string) graph.Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.nodeUIDs[uid]
+}
+
+// Nodes returns a snapshot of all the nodes in the graph ordered by their IDs.
+func (g *Graph) Nodes() gonum.Nodes {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	nodes := make([]gonum.Node, 0, len(g.nodes))
+	for _, n := range g.nodes {
+		nodes = append(nodes, n)
+	}
+
+	return snapshotNodes(nodes)
+}
+
+// NewNode returns a new unique node with an ID which does not exist in the graph.
+func (g *Graph) NewNode() gonum.Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return simple.Node(g.nodeIDs.NewID())
+}
+
+// AddNode adds n to the graph. It panics if the added node ID matches an existing node ID.
+func (g *Graph) AddNode(n gonum.Node) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, exists := g.nodes[n.ID()]; exists {
+		panic(fmt.Sprintf("memory: node ID collision: %d", n.ID()))
+	}
+
+	g.setNode(n)
+}
+
+// AddNewNode creates a new node with an ID which does not exist in the graph and adds it to the graph.
+// Unlike calling NewNode followed by AddNode the node ID is allocated and used atomically.
+func (g *Graph) AddNewNode(opts ...Option) (*Node, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	n, err := NewNode(g.nodeIDs.NewID(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	g.setNode(n)
+
+	return n, nil
+}
+
+// AddNewNode creates a new node and adds it to g.
+// The node is added atomically if g implements AddNewNode, e.g. if g is *Graph.
+func AddNewNode(g graph.Adder, opts ...Option) (*Node, error) {
+	if na, ok := g.(interface {
+		AddNewNode(...Option) (*Node, error)
+	}); ok {
+		return na.AddNewNode(opts...)
+	}
+
+	n, err := NewNode(g.NewNode().ID(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	g.AddNode(n)
+
+	return n, nil
+}
+
+// RemoveNode removes the node with the given ID from the graph, as well as any edges
+// attached to it. If the node is not in the graph it is a no-op.
+func (g *Graph) RemoveNode(id int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	n, ok := g.nodes[id]
+	if !ok {
+		return
+	}
+
+	for vid := range g.from[id] {
+		g.removeLines(id, vid)
+	}
+
+	for uid := range g.to[id] {
+		g.removeLines(uid, id)
+	}
+
+	if gn, ok := n.(graph.Node); ok {
+		delete(g.nodeUIDs, gn.UID())
+	}
+
+	delete(g.nodes, id)
+	delete(g.from, id)
+	delete(g.to, id)
+	g.nodeIDs.Release(id)
+}
+
+// From returns a snapshot of all the nodes that can be reached directly from the node with the given ID.
+func (g *Graph) From(id int64) gonum.Nodes {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.neighbours(g.from[id])
+}
+
+// To returns a snapshot of all the nodes that can reach directly to the node with the given ID.
+// To returns the same nodes as From if the graph is undirected.
+func (g *Graph) To(id int64) gonum.Nodes {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.neighbours(g.to[id])
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes with the given IDs without considering direction.
+func (g *Graph) HasEdgeBetween(xid, yid int64) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if _, ok := g.from[xid][yid]; ok {
+		return true
+	}
+	_, ok := g.to[xid][yid]
+	return ok
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v with the given IDs.
+func (g *Graph) HasEdgeFromTo(uid, vid int64) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	_, ok := g.from[uid][vid]
+	return ok
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// If the graph is a multigraph Edge returns the first line added between u and v.
+func (g *Graph) Edge(uid, vid int64) gonum.Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if e := g.edge(uid, vid); e != nil {
+		return e
+	}
+	return nil
+}
+
+// EdgeWithUID returns the edge with the given UID if it exists in the graph, and nil otherwise.
+func (g *Graph) EdgeWithUID(uid string) graph.Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.edgeUIDs[uid]
+}
+
+// WeightedEdge returns the weighted edge from u to v if such an edge exists and nil otherwise.
+// If the graph is a multigraph WeightedEdge returns the first line added between u and v.
+func (g *Graph) WeightedEdge(uid, vid int64) gonum.WeightedEdge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.edge(uid, vid)
+}
+
+// Lines returns a snapshot of all the lines from u to v.
+func (g *Graph) Lines(uid, vid int64) gonum.Lines {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var lines []gonum.Line
+	for _, e := range g.linesFromTo(uid, vid) {
+		if l, ok := e.(gonum.Line); ok {
+			lines = append(lines, l)
+		}
+	}
+
+	if len(lines) == 0 {
+		return gonum.Empty
+	}
+
+	return iterator.NewOrderedLines(lines)
+}
+
+// WeightedLines returns a snapshot of all the weighted lines from u to v.
+func (g *Graph) WeightedLines(uid, vid int64) gonum.WeightedLines {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var lines []gonum.WeightedLine
+	for _, e := range g.linesFromTo(uid, vid) {
+		if l, ok := e.(gonum.WeightedLine); ok {
+			lines = append(lines, l)
+		}
+	}
+
+	if len(lines) == 0 {
+		return gonum.Empty
+	}
+
+	return iterator.NewOrderedWeightedLines(lines)
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y) returns a non-nil Edge.
+// If the graph is a multigraph the returned weight is the sum of the weights of all the lines
+// between x and y. If x and y are the same node or there is no joining edge between the two
+// nodes the weight value returned is either the graph's absent or self value. Weight returns
+// true if an edge exists between x and y or if x and y have the same ID, false otherwise.
+func (g *Graph) Weight(xid, yid int64) (float64, bool) {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
-	return g.WeightedDirectedGraph.HasEdgeBetween(uid, vid)
+
+	if xid == yid {
+		return DefaultWeight, true
+	}
+
+	lines := g.linesFromTo(xid, yid)
+	if len(lines) == 0 {
+		return 0.0, false
+	}
+
+	var w float64
+	for _, l := range lines {
+		w += l.Weight()
+	}
+
+	return w, true
 }
 
-// To returns all nodes that can reach directly to the node with the given ID.
-func (g Graph) To(id int64) gonum.Nodes {
+// Edges returns a snapshot of all the edges in the graph.
+// Every line of a multigraph is returned as a separate edge
+// and every undirected edge is returned only once.
+func (g *Graph) Edges() gonum.Edges {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
-	return g.WeightedDirectedGraph.To(id)
+
+	weighted := g.allEdges()
+	edges := make([]gonum.Edge, len(weighted))
+	for i, e := range weighted {
+		edges[i] = e
+	}
+
+	return iterator.NewOrderedEdges(edges)
+}
+
+// WeightedEdges returns a snapshot of all the weighted edges in the graph.
+// Every line of a multigraph is returned as a separate edge
+// and every undirected edge is returned only once.
+func (g *Graph) WeightedEdges() gonum.WeightedEdges {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return iterator.NewOrderedWeightedEdges(g.allEdges())
+}
+
+// NewWeightedEdge returns a new weighted edge from the source to the destination node.
+func (g *Graph) NewWeightedEdge(from, to gonum.Node, weight float64) gonum.WeightedEdge {
+	// NOTE: NewEdge never fails when no options are invalid.
+	e, _ := NewEdge(from, to, WithWeight(weight))
+	return e
+}
+
+// SetWeightedEdge adds a weighted edge from one node to another.
+// If the nodes do not exist, they are added and are set to the nodes of the edge otherwise.
+// If the graph is not a multigraph the edge replaces any existing edge between the nodes,
+// otherwise the edge is added as a new line or replaces the existing line with the same ID.
+// Line IDs of *Edge are assigned by the graph if they are unset or used by other lines.
+// It will panic if the IDs of the e.From and e.To are equal.
+func (g *Graph) SetWeightedEdge(e gonum.WeightedEdge) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	from, to := e.From(), e.To()
+	fid, tid := from.ID(), to.ID()
+
+	if fid == tid {
+		panic("memory: adding self edge")
+	}
+
+	g.setNode(from)
+	g.setNode(to)
+
+	if !g.multi {
+		g.removeLines(fid, tid)
+	}
+
+	if me, ok := e.(*Edge); ok {
+		if other, ok := g.lineSet[me.id]; me.id < 0 || (ok && other != e) {
+			me.id = g.lineIDs.NewID()
+		}
+	}
+
+	k := g.key(fid, tid)
+	lines := g.lines[k]
+
+	replaced := false
+	if l, ok := e.(gonum.Line); ok {
+		for i, old := range lines {
+			if ol, ok := old.(gonum.Line); ok && ol.ID() == l.ID() {
+				g.unindexEdge(old)
+				lines[i] = e
+				replaced = true
+				break
+			}
+		}
+	}
+
+	if !replaced {
+		lines = append(lines, e)
+	}
+
+	g.lines[k] = lines
+	g.from[fid][tid] = struct{}{}
+	g.to[tid][fid] = struct{}{}
+
+	if l, ok := e.(gonum.Line); ok {
+		g.lineIDs.Use(l.ID())
+		g.lineSet[l.ID()] = e
+	}
+
+	g.indexEdge(e)
+}
+
+// RemoveEdge removes all the edges with the given end point IDs from the graph, leaving the terminal nodes.
+// If the edge does not exist it is a no-op.
+func (g *Graph) RemoveEdge(fid, tid int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.removeLines(fid, tid)
+}
+
+// RemoveLine removes the line with the given end point and line IDs from the graph, leaving the terminal nodes.
+// If the line does not exist it is a no-op.
+func (g *Graph) RemoveLine(fid, tid, id int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.from[fid][tid]; !ok {
+		return
+	}
+
+	k := g.key(fid, tid)
+	lines := g.lines[k]
+	for i, e := range lines {
+		if l, ok := e.(gonum.Line); ok && l.ID() == id {
+			g.unindexEdge(e)
+			lines = append(lines[:i], lines[i+1:]...)
+			break
+		}
+	}
+
+	if len(lines) > 0 {
+		g.lines[k] = lines
+		return
+	}
+
+	g.removeLines(fid, tid)
+}
+
+// key returns lines key of the given node pair.
+func (g *Graph) key(fid, tid int64) pair {
+	if !g.directed && fid > tid {
+		fid, tid = tid, fid
+	}
+	return pair{from: fid, to: tid}
+}
+
+// linesFromTo returns all the lines from u to v.
+func (g *Graph) linesFromTo(uid, vid int64) []gonum.WeightedEdge {
+	if _, ok := g.from[uid][vid]; !ok {
+		return nil
+	}
+	return g.lines[g.key(uid, vid)]
+}
+
+// edge returns the first line from u to v or nil.
+func (g *Graph) edge(uid, vid int64) gonum.WeightedEdge {
+	lines := g.linesFromTo(uid, vid)
+	if len(lines) == 0 {
+		return nil
+	}
+	return lines[0]
+}
+
+// setNode adds n to the graph or replaces the existing node with the same ID.
+func (g *Graph) setNode(n gonum.Node) {
+	id := n.ID()
+
+	if old, ok := g.nodes[id]; ok {
+		if gn, ok := old.(graph.Node); ok {
+			delete(g.nodeUIDs, gn.UID())
+		}
+	} else {
+		g.from[id] = make(map[int64]struct{})
+		if g.directed {
+			g.to[id] = make(map[int64]struct{})
+		}
+		g.nodeIDs.Use(id)
+	}
+
+	g.nodes[id] = n
+	g.indexNode(n)
+}
+
+// removeLines removes all the lines from u to v.
+func (g *Graph) removeLines(fid, tid int64) {
+	if _, ok := g.from[fid][tid]; !ok {
+		return
+	}
+
+	k := g.key(fid, tid)
+	for _, e := range g.lines[k] {
+		g.unindexEdge(e)
+	}
+	delete(g.lines, k)
+
+	delete(g.from[fid], tid)
+	delete(g.to[tid], fid)
+}
+
+// neighbours returns a snapshot of the nodes with the given IDs.
+func (g *Graph) neighbours(ids map[int64]struct{}) gonum.Nodes {
+	nodes := make([]gonum.Node, 0, len(ids))
+	for id := range ids {
+		nodes = append(nodes, g.nodes[id])
+	}
+
+	return snapshotNodes(nodes)
+}
+
+// allEdges returns all the lines in the graph ordered by their end node IDs and line IDs.
+func (g *Graph) allEdges() []gonum.WeightedEdge {
+	var edges []gonum.WeightedEdge
+	for _, lines := range g.lines {
+		edges = append(edges, lines...)
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		return edgeLess(edges[i], edges[j])
+	})
+
+	return edges
+}
+
+// indexNode indexes n by its UID.
+func (g *Graph) indexNode(n gonum.Node) {
+	if gn, ok := n.(graph.Node); ok {
+		g.nodeUIDs[gn.UID()] = gn
+	}
+}
+
+// indexEdge indexes e by its UID.
+func (g *Graph) indexEdge(e gonum.Edge) {
+	if ge, ok := e.(graph.Edge); ok {
+		g.edgeUIDs[ge.UID()] = ge
+	}
+}
+
+// unindexEdge removes e from the UID and line indices.
+// The UID index is kept if it stores another edge with the same UID.
+func (g *Graph) unindexEdge(e gonum.Edge) {
+	if ge, ok := e.(graph.Edge); ok && g.edgeUIDs[ge.UID()] == ge {
+		delete(g.edgeUIDs, ge.UID())
+	}
+
+	if l, ok := e.(gonum.Line); ok && g.lineSet[l.ID()] == e {
+		delete(g.lineSet, l.ID())
+		g.lineIDs.Release(l.ID())
+	}
+}
+
+// snapshotNodes returns a snapshot of nodes ordered by their IDs.
+func snapshotNodes(nodes []gonum.Node) gonum.Nodes {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID() < nodes[j].ID()
+	})
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// edgeLess orders edges by the IDs of their end nodes and their line IDs.
+func edgeLess(a, b gonum.Edge) bool {
+	if af, bf := a.From().ID(), b.From().ID(); af != bf {
+		return af < bf
+	}
+	if at, bt := a.To().ID(), b.To().ID(); at != bt {
+		return at < bt
+	}
+	al, aok := a.(gonum.Line)
+	bl, bok := b.(gonum.Line)
+	if aok && bok {
+		return al.ID() < bl.ID()
+	}
+	return false
 }
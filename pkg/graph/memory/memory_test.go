@@ -1,7 +1,10 @@
 package memory
 
 import (
+	"sync"
 	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
 )
 
 func TestNewGraph(t *testing.T) {
@@ -61,3 +64,370 @@ func TestNewGraphWithOpts(t *testing.T) {
 	}
 
 }
+
+func TestAddNewNode(t *testing.T) {
+	g, err := NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	n1, err := g.AddNewNode(WithUID("n1"), WithLabel("foo"))
+	if err != nil {
+		t.Fatalf("failed to add node: %v", err)
+	}
+
+	n2, err := g.AddNewNode()
+	if err != nil {
+		t.Fatalf("failed to add node: %v", err)
+	}
+
+	if n1.ID() == n2.ID() {
+		t.Errorf("expected unique node IDs, got: %d", n1.ID())
+	}
+
+	if n := g.Node(n1.ID()); n != n1 {
+		t.Errorf("expected node: %v, got: %v", n1, n)
+	}
+
+	if n := g.NodeWithUID("n1"); n != n1 {
+		t.Errorf("expected node: %v, got: %v", n1, n)
+	}
+
+	if l := n1.Label(); l != "foo" {
+		t.Errorf("expected label: foo, got: %s", l)
+	}
+}
+
+func TestGraphUIDIndex(t *testing.T) {
+	g, err := NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	n1, err := NewNode(g.NewNode().ID(), WithUID("n1"))
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(n1)
+
+	n2, err := NewNode(g.NewNode().ID(), WithUID("n2"))
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(n2)
+
+	if n := g.NodeWithUID("n1"); n != n1 {
+		t.Errorf("expected node: %v, got: %v", n1, n)
+	}
+
+	if n := g.NodeWithUID("foo"); n != nil {
+		t.Errorf("expected nil node, got: %v", n)
+	}
+
+	e1, err := NewEdge(n1, n2, WithUID("e1"))
+	if err != nil {
+		t.Fatalf("failed to create edge: %v", err)
+	}
+	g.SetWeightedEdge(e1)
+
+	if e := g.EdgeWithUID("e1"); e != e1 {
+		t.Errorf("expected edge: %v, got: %v", e1, e)
+	}
+
+	// replacing the edge between the same nodes replaces the indexed edge
+	e2, err := NewEdge(n1, n2, WithUID("e2"))
+	if err != nil {
+		t.Fatalf("failed to create edge: %v", err)
+	}
+	g.SetWeightedEdge(e2)
+
+	if e := g.EdgeWithUID("e1"); e != nil {
+		t.Errorf("expected nil edge, got: %v", e)
+	}
+
+	if e := g.EdgeWithUID("e2"); e != e2 {
+		t.Errorf("expected edge: %v, got: %v", e2, e)
+	}
+
+	g.RemoveEdge(n1.ID(), n2.ID())
+	if e := g.EdgeWithUID("e2"); e != nil {
+		t.Errorf("expected nil edge, got: %v", e)
+	}
+
+	g.SetWeightedEdge(e2)
+	g.RemoveNode(n1.ID())
+
+	if n := g.NodeWithUID("n1"); n != nil {
+		t.Errorf("expected nil node, got: %v", n)
+	}
+
+	if e := g.EdgeWithUID("e2"); e != nil {
+		t.Errorf("expected nil edge, got: %v", e)
+	}
+}
+
+func TestGraphUIDIndexReused(t *testing.T) {
+	g, err := NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	var nodes []*Node
+	for _, uid := range []string{"n1", "n2", "n3"} {
+		n, err := NewNode(g.NewNode().ID(), WithUID(uid))
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(n)
+		nodes = append(nodes, n)
+	}
+
+	e1, err := NewEdge(nodes[0], nodes[1], WithUID("e"))
+	if err != nil {
+		t.Fatalf("failed to create edge: %v", err)
+	}
+	g.SetWeightedEdge(e1)
+
+	// the edge which reuses the UID replaces the indexed edge
+	e2, err := NewEdge(nodes[1], nodes[2], WithUID("e"))
+	if err != nil {
+		t.Fatalf("failed to create edge: %v", err)
+	}
+	g.SetWeightedEdge(e2)
+
+	// removing the replaced edge keeps the indexed edge
+	g.RemoveEdge(nodes[0].ID(), nodes[1].ID())
+	if e := g.EdgeWithUID("e"); e != e2 {
+		t.Errorf("expected edge: %v, got: %v", e2, e)
+	}
+
+	g.RemoveNode(nodes[0].ID())
+	if e := g.EdgeWithUID("e"); e != e2 {
+		t.Errorf("expected edge: %v, got: %v", e2, e)
+	}
+
+	g.RemoveEdge(nodes[1].ID(), nodes[2].ID())
+	if e := g.EdgeWithUID("e"); e != nil {
+		t.Errorf("expected nil edge, got: %v", e)
+	}
+}
+
+func TestGraphSnapshot(t *testing.T) {
+	g := MustGraph(t)
+
+	nodes := g.Nodes()
+	edges := g.Edges()
+
+	g.RemoveNode(1)
+
+	if count := nodes.Len(); count != 2 {
+		t.Errorf("expected nodes: %d, got: %d", 2, count)
+	}
+
+	var prev int64 = -1
+	for nodes.Next() {
+		if id := nodes.Node().ID(); id <= prev {
+			t.Errorf("expected nodes ordered by ID, got: %d after %d", id, prev)
+		} else {
+			prev = id
+		}
+	}
+
+	if count := edges.Len(); count != 1 {
+		t.Errorf("expected edges: %d, got: %d", 1, count)
+	}
+
+	if count := g.Nodes().Len(); count != 1 {
+		t.Errorf("expected nodes: %d, got: %d", 1, count)
+	}
+}
+
+func TestGraphConcurrent(t *testing.T) {
+	g, err := NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	root, err := NewNode(g.NewNode().ID(), WithUID("root"))
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	g.AddNode(root)
+
+	const workers = 8
+	const count = 100
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < count; j++ {
+				n, err := g.AddNewNode()
+				if err != nil {
+					t.Errorf("failed to add node: %v", err)
+					return
+				}
+
+				e, err := NewEdge(root, n)
+				if err != nil {
+					t.Errorf("failed to create edge: %v", err)
+					return
+				}
+				g.SetWeightedEdge(e)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < count; j++ {
+				nodes := g.Nodes()
+				for nodes.Next() {
+					_ = g.From(nodes.Node().ID()).Len()
+				}
+				edges := g.Edges()
+				for edges.Next() {
+					_ = g.EdgeWithUID(edges.Edge().(*Edge).UID())
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if nodes := g.Nodes().Len(); nodes != workers*count+1 {
+		t.Errorf("expected nodes: %d, got: %d", workers*count+1, nodes)
+	}
+
+	if edges := g.Edges().Len(); edges != workers*count {
+		t.Errorf("expected edges: %d, got: %d", workers*count, edges)
+	}
+}
+
+func TestNewGraphInvalidType(t *testing.T) {
+	if _, err := NewGraph(WithType("foo")); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestGraphUndirected(t *testing.T) {
+	g, err := NewGraph(WithType(graph.WeightedUndirected))
+	if err != nil {
+		t.Fatalf("failed to create new graph: %v", err)
+	}
+
+	n1 := MustNode(t, g.NewNode().ID(), "n1", nil)
+	g.AddNode(n1)
+	n2 := MustNode(t, g.NewNode().ID(), "n2", nil)
+	g.AddNode(n2)
+
+	e, err := NewEdge(n1, n2, WithWeight(testWeight))
+	if err != nil {
+		t.Fatalf("failed to create edge: %v", err)
+	}
+	g.SetWeightedEdge(e)
+
+	if !g.HasEdgeFromTo(n2.ID(), n1.ID()) {
+		t.Error("expected edge from n2 to n1")
+	}
+
+	if to := g.To(n1.ID()).Len(); to != 1 {
+		t.Errorf("expected to nodes: 1, got: %d", to)
+	}
+
+	if w, ok := g.Weight(n2.ID(), n1.ID()); !ok || w != testWeight {
+		t.Errorf("expected weight: %f, got: %f", testWeight, w)
+	}
+
+	if count := g.Edges().Len(); count != 1 {
+		t.Errorf("expected edges: 1, got: %d", count)
+	}
+
+	// undirected edges are replaced regardless of their direction
+	re, err := NewEdge(n2, n1)
+	if err != nil {
+		t.Fatalf("failed to create edge: %v", err)
+	}
+	g.SetWeightedEdge(re)
+
+	if count := g.Edges().Len(); count != 1 {
+		t.Errorf("expected edges: 1, got: %d", count)
+	}
+
+	if e := g.EdgeWithUID(e.UID()); e != nil {
+		t.Errorf("expected replaced edge to be unindexed")
+	}
+
+	g.RemoveEdge(n1.ID(), n2.ID())
+	if g.HasEdgeBetween(n1.ID(), n2.ID()) {
+		t.Error("expected no edge between n1 and n2")
+	}
+}
+
+func TestGraphMulti(t *testing.T) {
+	for _, typ := range []string{graph.WeightedDirectedMulti, graph.WeightedUndirectedMulti} {
+		t.Run(typ, func(t *testing.T) {
+			g, err := NewGraph(WithType(typ))
+			if err != nil {
+				t.Fatalf("failed to create new graph: %v", err)
+			}
+
+			n1 := MustNode(t, g.NewNode().ID(), "n1", nil)
+			g.AddNode(n1)
+			n2 := MustNode(t, g.NewNode().ID(), "n2", nil)
+			g.AddNode(n2)
+
+			var lines []*Edge
+			for i := 0; i < 3; i++ {
+				e, err := NewEdge(n1, n2, WithWeight(testWeight))
+				if err != nil {
+					t.Fatalf("failed to create edge: %v", err)
+				}
+				g.SetWeightedEdge(e)
+				lines = append(lines, e)
+			}
+
+			if count := g.Edges().Len(); count != len(lines) {
+				t.Errorf("expected edges: %d, got: %d", len(lines), count)
+			}
+
+			if count := g.Lines(n1.ID(), n2.ID()).Len(); count != len(lines) {
+				t.Errorf("expected lines: %d, got: %d", len(lines), count)
+			}
+
+			if w, _ := g.Weight(n1.ID(), n2.ID()); w != testWeight*float64(len(lines)) {
+				t.Errorf("expected weight: %f, got: %f", testWeight*float64(len(lines)), w)
+			}
+
+			ids := make(map[int64]bool)
+			for _, l := range lines {
+				if l.ID() < 0 || ids[l.ID()] {
+					t.Errorf("invalid line ID: %d", l.ID())
+				}
+				ids[l.ID()] = true
+			}
+
+			g.RemoveLine(n1.ID(), n2.ID(), lines[0].ID())
+			if count := g.Lines(n1.ID(), n2.ID()).Len(); count != len(lines)-1 {
+				t.Errorf("expected lines: %d, got: %d", len(lines)-1, count)
+			}
+
+			if e := g.EdgeWithUID(lines[0].UID()); e != nil {
+				t.Errorf("expected removed line to be unindexed")
+			}
+
+			if e := g.Edge(n1.ID(), n2.ID()); e != lines[1] {
+				t.Errorf("expected edge: %v, got: %v", lines[1], e)
+			}
+
+			if cg := GraphDeepCopy(g); cg.Edges().Len() != len(lines)-1 || cg.Type() != typ {
+				t.Errorf("invalid graph copy")
+			}
+
+			g.RemoveNode(n2.ID())
+			if count := g.Edges().Len(); count != 0 {
+				t.Errorf("expected edges: 0, got: %d", count)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package merge
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+const (
+	// SourcesAttr stores the UIDs of the graphs a node or an edge was merged from.
+	SourcesAttr = "merged_from"
+	// UpdatedAtAttr is the attribute compared by NewestWins policy.
+	UpdatedAtAttr = "updated_at"
+)
+
+// Merger merges graphs.
+type Merger struct {
+	opts Options
+}
+
+// NewMerger creates a new graph merger and returns it.
+func NewMerger(opts ...Option) (*Merger, error) {
+	mopts := Options{
+		Policy:      LeftWins,
+		Aggregation: Sum,
+	}
+
+	for _, apply := range opts {
+		apply(&mopts)
+	}
+
+	switch mopts.Policy {
+	case LeftWins, RightWins, NewestWins:
+	default:
+		return nil, graph.Errorf(graph.EINVALID, "unsupported merge policy: %q", mopts.Policy)
+	}
+
+	switch mopts.Aggregation {
+	case Sum, Max, Mean:
+	default:
+		return nil, graph.Errorf(graph.EINVALID, "unsupported weight aggregation: %q", mopts.Aggregation)
+	}
+
+	return &Merger{
+		opts: mopts,
+	}, nil
+}
+
+// edgeAgg tracks aggregated weights of parallel edges.
+type edgeAgg struct {
+	edge  *memory.Edge
+	sum   float64
+	count int
+}
+
+// merge tracks the state of a single Merge.
+type merge struct {
+	g     *memory.Graph
+	nodes map[string]*memory.Node
+	edges map[string]*edgeAgg
+}
+
+// edgeKey returns the key edge from -> to with the given label is merged by.
+// Edges of undirected graphs are merged regardless of the order of their nodes
+// and parallel edges of multigraphs are merged only if they have the same label.
+func (mg *merge) edgeKey(from, to, label string) string {
+	if !graph.IsDirected(mg.g.Type()) && to < from {
+		from, to = to, from
+	}
+
+	key := from + "\x00" + to
+	if graph.IsMulti(mg.g.Type()) {
+		key += "\x00" + label
+	}
+
+	return key
+}
+
+// Merge merges graphs of the same type into a new in-memory graph of that type and returns it.
+// Nodes are merged by their UIDs; edges are merged by the UIDs of their end nodes and,
+// in multigraphs, by their labels. Conflicting attributes are resolved using the configured
+// policy and the weights of merged edges are aggregated using the configured aggregation.
+// The UIDs of the source graphs are recorded in SourcesAttr attribute of every node and edge.
+func (m *Merger) Merge(graphs ...graph.Graph) (*memory.Graph, error) {
+	if len(graphs) == 0 {
+		return nil, graph.Errorf(graph.EINVALID, "no graphs to merge")
+	}
+
+	typ := graphs[0].Type()
+	for _, src := range graphs[1:] {
+		if src.Type() != typ {
+			return nil, graph.Errorf(graph.EINVALID, "graph %s of type %s can't be merged with %s graph", src.UID(), src.Type(), typ)
+		}
+	}
+
+	label := m.opts.Label
+	if label == "" {
+		label = graphs[0].Label()
+	}
+
+	opts := []memory.Option{
+		memory.WithType(typ),
+		memory.WithLabel(label),
+	}
+
+	if m.opts.UID != "" {
+		opts = append(opts, memory.WithUID(m.opts.UID))
+	}
+
+	g, err := memory.NewGraph(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	mg := &merge{
+		g:     g,
+		nodes: make(map[string]*memory.Node),
+		edges: make(map[string]*edgeAgg),
+	}
+
+	uids := make([]string, 0, len(graphs))
+	for _, src := range graphs {
+		if err := m.mergeGraph(mg, src); err != nil {
+			return nil, err
+		}
+		m.mergeAttrs(g.Attrs(), src.Attrs())
+		uids = append(uids, src.UID())
+	}
+	g.Attrs()[SourcesAttr] = uids
+
+	for _, agg := range mg.edges {
+		if m.opts.Aggregation == Mean {
+			agg.edge.SetWeight(agg.sum / float64(agg.count))
+		}
+	}
+
+	return g, nil
+}
+
+// mergeGraph merges src into the merged graph.
+func (m *Merger) mergeGraph(mg *merge, src graph.Graph) error {
+	nodes := src.Nodes()
+	for nodes.Next() {
+		n, ok := nodes.Node().(graph.Node)
+		if !ok {
+			return graph.Errorf(graph.EINVALID, "invalid node in graph %s", src.UID())
+		}
+
+		if node, ok := mg.nodes[n.UID()]; ok {
+			if m.rightWins(node.Attrs(), n.Attrs()) {
+				node.SetLabel(n.Label())
+			}
+			m.mergeAttrs(node.Attrs(), n.Attrs())
+			addSource(node.Attrs(), n.Attrs(), src.UID())
+			continue
+		}
+
+		node, err := mg.g.AddNewNode(memory.NodeOptions(n)...)
+		if err != nil {
+			return err
+		}
+		addSource(node.Attrs(), nil, src.UID())
+		mg.nodes[node.UID()] = node
+	}
+
+	edges := src.Edges()
+	for edges.Next() {
+		e, ok := edges.Edge().(graph.Edge)
+		if !ok {
+			return graph.Errorf(graph.EINVALID, "invalid edge in graph %s", src.UID())
+		}
+
+		from, ok := e.From().(graph.Node)
+		if !ok {
+			return graph.Errorf(graph.EINVALID, "invalid source node of edge %s", e.UID())
+		}
+
+		to, ok := e.To().(graph.Node)
+		if !ok {
+			return graph.Errorf(graph.EINVALID, "invalid target node of edge %s", e.UID())
+		}
+
+		key := mg.edgeKey(from.UID(), to.UID(), e.Label())
+
+		if agg, ok := mg.edges[key]; ok {
+			if m.rightWins(agg.edge.Attrs(), e.Attrs()) {
+				agg.edge.SetLabel(e.Label())
+			}
+			m.mergeAttrs(agg.edge.Attrs(), e.Attrs())
+			addSource(agg.edge.Attrs(), e.Attrs(), src.UID())
+			m.aggregate(agg, e.Weight())
+			continue
+		}
+
+		f, t := mg.nodes[from.UID()], mg.nodes[to.UID()]
+		if f == nil || t == nil {
+			return graph.Errorf(graph.EINVALID, "edge %s end nodes not found in graph %s", e.UID(), src.UID())
+		}
+
+		edge, err := memory.NewEdgeFrom(f, t, e)
+		if err != nil {
+			return err
+		}
+		addSource(edge.Attrs(), nil, src.UID())
+		mg.g.SetWeightedEdge(edge)
+		mg.edges[key] = &edgeAgg{
+			edge:  edge,
+			sum:   e.Weight(),
+			count: 1,
+		}
+	}
+
+	return nil
+}
+
+// aggregate aggregates weight w into agg.
+func (m *Merger) aggregate(agg *edgeAgg, w float64) {
+	agg.sum += w
+	agg.count++
+
+	switch m.opts.Aggregation {
+	case Sum:
+		agg.edge.SetWeight(agg.sum)
+	case Max:
+		if w > agg.edge.Weight() {
+			agg.edge.SetWeight(w)
+		}
+	}
+}
+
+// rightWins returns true if the right entity takes precedence over the left entity
+// when resolving the conflicts which can't be resolved by ConflictFunc, such as labels.
+func (m *Merger) rightWins(left, right map[string]interface{}) bool {
+	switch m.opts.Policy {
+	case RightWins:
+		return true
+	case NewestWins:
+		return newer(right, left)
+	}
+	return false
+}
+
+// mergeAttrs merges right attributes into left attributes.
+func (m *Merger) mergeAttrs(left, right map[string]interface{}) {
+	// NOTE: conflicts must be resolved against the original left attributes
+	// otherwise NewestWins policy would compare already merged timestamps.
+	orig := attrs.CopyFrom(left)
+
+	for k, rv := range right {
+		if k == SourcesAttr {
+			continue
+		}
+
+		lv, ok := orig[k]
+		if !ok {
+			left[k] = attrs.CopyValue(rv)
+			continue
+		}
+
+		if reflect.DeepEqual(lv, rv) {
+			continue
+		}
+
+		// NOTE: resolved values are copied so that they are not shared with the merged graphs
+		left[k] = attrs.CopyValue(m.resolve(k, orig, right))
+	}
+}
+
+// resolve resolves conflicting attribute k.
+func (m *Merger) resolve(k string, left, right map[string]interface{}) interface{} {
+	if f := m.opts.ConflictFunc; f != nil {
+		return f(k, left, right)
+	}
+
+	if m.rightWins(left, right) {
+		return right[k]
+	}
+
+	return left[k]
+}
+
+// newer returns true if a was updated after b.
+// Entities with no valid UpdatedAtAttr attribute are considered the oldest.
+func newer(a, b map[string]interface{}) bool {
+	at, ok := toTime(a[UpdatedAtAttr])
+	if !ok {
+		return false
+	}
+
+	bt, ok := toTime(b[UpdatedAtAttr])
+	if !ok {
+		return true
+	}
+
+	return at.After(bt)
+}
+
+// toTime attempts to convert v to time.Time.
+func toTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case *time.Time:
+		if t != nil {
+			return *t, true
+		}
+	case string:
+		if pt, err := time.Parse(time.RFC3339, t); err == nil {
+			return pt, true
+		}
+	case interface{ GetTime() *time.Time }:
+		if pt := t.GetTime(); pt != nil {
+			return *pt, true
+		}
+	case fmt.Stringer:
+		return toTime(t.String())
+	}
+	return time.Time{}, false
+}
+
+// addSource records the provenance of a merged entity.
+// It merges the provenance already recorded in src attributes and appends uid.
+func addSource(dst, src map[string]interface{}, uid string) {
+	sources := toStrings(dst[SourcesAttr])
+
+	for _, s := range append(toStrings(src[SourcesAttr]), uid) {
+		found := false
+		for _, ds := range sources {
+			if ds == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			sources = append(sources, s)
+		}
+	}
+
+	dst[SourcesAttr] = sources
+}
+
+// toStrings converts v to a slice of strings.
+func toStrings(v interface{}) []string {
+	switch s := v.(type) {
+	case []string:
+		return append([]string(nil), s...)
+	case []interface{}:
+		res := make([]string, 0, len(s))
+		for _, x := range s {
+			if str, ok := x.(string); ok {
+				res = append(res, str)
+			}
+		}
+		return res
+	}
+	return nil
+}
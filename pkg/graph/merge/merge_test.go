@@ -0,0 +1,334 @@
+package merge
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+type testNode struct {
+	uid   string
+	label string
+	attrs map[string]interface{}
+}
+
+type testEdge struct {
+	from, to string
+	label    string
+	weight   float64
+	attrs    map[string]interface{}
+}
+
+func MustGraph(t *testing.T, uid string, nodes []testNode, edges []testEdge, opts ...memory.Option) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph(append([]memory.Option{memory.WithUID(uid), memory.WithLabel(uid)}, opts...)...)
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	ids := make(map[string]*memory.Node)
+	for _, tn := range nodes {
+		n, err := memory.NewNode(g.NewNode().ID(),
+			memory.WithUID(tn.uid),
+			memory.WithLabel(tn.label),
+			memory.WithAttrs(tn.attrs),
+		)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(n)
+		ids[tn.uid] = n
+	}
+
+	for _, te := range edges {
+		e, err := memory.NewEdge(ids[te.from], ids[te.to],
+			memory.WithLabel(te.label),
+			memory.WithWeight(te.weight),
+			memory.WithAttrs(te.attrs),
+		)
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(e)
+	}
+
+	return g
+}
+
+func MustGraphs(t *testing.T) (*memory.Graph, *memory.Graph) {
+	t.Helper()
+
+	left := MustGraph(t, "left",
+		[]testNode{
+			{uid: "repo", label: "Repo", attrs: map[string]interface{}{"stars": 1, "updated_at": "2021-01-01T00:00:00Z"}},
+			{uid: "topic", label: "Topic", attrs: map[string]interface{}{"name": "go"}},
+		},
+		[]testEdge{
+			{from: "repo", to: "topic", label: "HasTopic", weight: 2.0, attrs: map[string]interface{}{}},
+		},
+	)
+
+	right := MustGraph(t, "right",
+		[]testNode{
+			{uid: "repo", label: "Repository", attrs: map[string]interface{}{"stars": 10, "updated_at": "2022-01-01T00:00:00Z"}},
+			{uid: "topic", label: "Topic", attrs: map[string]interface{}{"name": "go", "updated_at": "2020-01-01T00:00:00Z"}},
+			{uid: "lang", label: "Lang", attrs: map[string]interface{}{"name": "Go"}},
+		},
+		[]testEdge{
+			{from: "repo", to: "topic", label: "HasTopic", weight: 4.0, attrs: map[string]interface{}{}},
+			{from: "repo", to: "lang", label: "IsLanguage", weight: 1.0, attrs: map[string]interface{}{}},
+		},
+	)
+
+	return left, right
+}
+
+func nodeWithUID(t *testing.T, g graph.Graph, uid string) graph.Node {
+	t.Helper()
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		if n := nodes.Node().(graph.Node); n.UID() == uid {
+			return n
+		}
+	}
+
+	t.Fatalf("node %s not found", uid)
+	return nil
+}
+
+func edgeBetween(t *testing.T, g *memory.Graph, from, to string) graph.Edge {
+	t.Helper()
+
+	f, tn := nodeWithUID(t, g, from), nodeWithUID(t, g, to)
+	e := g.WeightedEdge(f.ID(), tn.ID())
+	if e == nil {
+		t.Fatalf("edge %s->%s not found", from, to)
+	}
+
+	return e.(graph.Edge)
+}
+
+func TestNewMerger(t *testing.T) {
+	if _, err := NewMerger(); err != nil {
+		t.Fatalf("failed to create merger: %v", err)
+	}
+
+	if _, err := NewMerger(WithPolicy("foo")); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+
+	if _, err := NewMerger(WithAggregation("foo")); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	testCases := []struct {
+		name   string
+		opts   []Option
+		label  string
+		stars  interface{}
+		weight float64
+	}{
+		{"LeftWins", nil, "Repo", 1, 6.0},
+		{"RightWins", []Option{WithPolicy(RightWins), WithAggregation(Max)}, "Repository", 10, 4.0},
+		{"NewestWins", []Option{WithPolicy(NewestWins), WithAggregation(Mean)}, "Repository", 10, 3.0},
+		{"ConflictFunc", []Option{WithConflictFunc(func(k string, l, r map[string]interface{}) interface{} {
+			if k == "stars" {
+				return l[k].(int) + r[k].(int)
+			}
+			return l[k]
+		})}, "Repo", 11, 6.0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			left, right := MustGraphs(t)
+
+			m, err := NewMerger(append(tc.opts, WithUID("merged"))...)
+			if err != nil {
+				t.Fatalf("failed to create merger: %v", err)
+			}
+
+			g, err := m.Merge(left, right)
+			if err != nil {
+				t.Fatalf("failed to merge graphs: %v", err)
+			}
+
+			if uid := g.UID(); uid != "merged" {
+				t.Errorf("expected uid: merged, got: %s", uid)
+			}
+
+			if label := g.Label(); label != left.Label() {
+				t.Errorf("expected label: %s, got: %s", left.Label(), label)
+			}
+
+			if count := g.Nodes().Len(); count != 3 {
+				t.Errorf("expected nodes: %d, got: %d", 3, count)
+			}
+
+			if count := g.Edges().Len(); count != 2 {
+				t.Errorf("expected edges: %d, got: %d", 2, count)
+			}
+
+			repo := nodeWithUID(t, g, "repo")
+			if label := repo.Label(); label != tc.label {
+				t.Errorf("expected label: %s, got: %s", tc.label, label)
+			}
+
+			if stars := repo.Attrs()["stars"]; stars != tc.stars {
+				t.Errorf("expected stars: %v, got: %v", tc.stars, stars)
+			}
+
+			srcs := []string{"left", "right"}
+			if s := repo.Attrs()[SourcesAttr]; !reflect.DeepEqual(s, srcs) {
+				t.Errorf("expected sources: %v, got: %v", srcs, s)
+			}
+
+			if s := nodeWithUID(t, g, "lang").Attrs()[SourcesAttr]; !reflect.DeepEqual(s, []string{"right"}) {
+				t.Errorf("expected sources: %v, got: %v", []string{"right"}, s)
+			}
+
+			if w := edgeBetween(t, g, "repo", "topic").Weight(); w != tc.weight {
+				t.Errorf("expected weight: %v, got: %v", tc.weight, w)
+			}
+
+			if w := edgeBetween(t, g, "repo", "lang").Weight(); w != 1.0 {
+				t.Errorf("expected weight: %v, got: %v", 1.0, w)
+			}
+		})
+	}
+}
+
+func TestMergeProvenance(t *testing.T) {
+	left, right := MustGraphs(t)
+
+	m, err := NewMerger()
+	if err != nil {
+		t.Fatalf("failed to create merger: %v", err)
+	}
+
+	g, err := m.Merge(left)
+	if err != nil {
+		t.Fatalf("failed to merge graphs: %v", err)
+	}
+
+	g2, err := m.Merge(g, right)
+	if err != nil {
+		t.Fatalf("failed to merge graphs: %v", err)
+	}
+
+	srcs := []string{"left", g.UID(), "right"}
+	if s := nodeWithUID(t, g2, "repo").Attrs()[SourcesAttr]; !reflect.DeepEqual(s, srcs) {
+		t.Errorf("expected sources: %v, got: %v", srcs, s)
+	}
+}
+
+func TestMergeNoGraphs(t *testing.T) {
+	m, err := NewMerger()
+	if err != nil {
+		t.Fatalf("failed to create merger: %v", err)
+	}
+
+	if _, err := m.Merge(); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
+
+func TestMergeMulti(t *testing.T) {
+	nodes := []testNode{
+		{uid: "a", label: "Repo"},
+		{uid: "b", label: "Owner"},
+	}
+
+	edges := []testEdge{
+		{from: "a", to: "b", label: "OwnedBy", weight: 2.0, attrs: map[string]interface{}{}},
+		{from: "a", to: "b", label: "ContributesTo", weight: 1.0, attrs: map[string]interface{}{}},
+	}
+
+	typ := memory.WithType(graph.WeightedUndirectedMulti)
+	left := MustGraph(t, "left", nodes, edges, typ)
+	// NOTE: undirected edges are merged regardless of the order of their nodes
+	right := MustGraph(t, "right", nodes, []testEdge{
+		{from: "b", to: "a", label: "OwnedBy", weight: 2.0, attrs: map[string]interface{}{}},
+		{from: "b", to: "a", label: "ContributesTo", weight: 1.0, attrs: map[string]interface{}{}},
+	}, typ)
+
+	m, err := NewMerger()
+	if err != nil {
+		t.Fatalf("failed to create merger: %v", err)
+	}
+
+	g, err := m.Merge(left, right)
+	if err != nil {
+		t.Fatalf("failed to merge graphs: %v", err)
+	}
+
+	if typ := g.Type(); typ != graph.WeightedUndirectedMulti {
+		t.Errorf("expected type: %s, got: %s", graph.WeightedUndirectedMulti, typ)
+	}
+
+	exp := map[string]float64{
+		"OwnedBy":       4.0,
+		"ContributesTo": 2.0,
+	}
+
+	got := make(map[string]float64)
+	es := g.Edges()
+	for es.Next() {
+		e := es.Edge().(graph.Edge)
+		got[e.Label()] = e.Weight()
+	}
+
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected edges: %v, got: %v", exp, got)
+	}
+}
+
+func TestMergeTypes(t *testing.T) {
+	left, _ := MustGraphs(t)
+	right := MustGraph(t, "right", nil, nil, memory.WithType(graph.WeightedDirectedMulti))
+
+	m, err := NewMerger()
+	if err != nil {
+		t.Fatalf("failed to create merger: %v", err)
+	}
+
+	if _, err := m.Merge(left, right); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
+
+func TestMergeAttrsCopy(t *testing.T) {
+	topics := []string{"go"}
+	owner := map[string]interface{}{"login": "foo"}
+
+	left := MustGraph(t, "left", []testNode{{uid: "repo", label: "Repo", attrs: map[string]interface{}{"topics": topics}}}, nil)
+	right := MustGraph(t, "right", []testNode{{uid: "repo", label: "Repo", attrs: map[string]interface{}{"owner": owner}}}, nil)
+
+	m, err := NewMerger()
+	if err != nil {
+		t.Fatalf("failed to create merger: %v", err)
+	}
+
+	g, err := m.Merge(left, right)
+	if err != nil {
+		t.Fatalf("failed to merge graphs: %v", err)
+	}
+
+	a := nodeWithUID(t, g, "repo").Attrs()
+	a["topics"].([]string)[0] = "rust"
+	a["owner"].(map[string]interface{})["login"] = "bar"
+
+	if topics[0] != "go" {
+		t.Errorf("expected merged topics to be copied")
+	}
+
+	if owner["login"] != "foo" {
+		t.Errorf("expected merged owner to be copied")
+	}
+}
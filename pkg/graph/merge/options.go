@@ -0,0 +1,84 @@
+package merge
+
+// Policy is attribute conflict resolution policy.
+type Policy string
+
+const (
+	// LeftWins keeps the attribute value of the graph merged first.
+	LeftWins Policy = "left"
+	// RightWins keeps the attribute value of the graph merged last.
+	RightWins Policy = "right"
+	// NewestWins keeps the attribute value of the entity which was updated last.
+	// Entities are compared by the value of their UpdatedAtAttr attribute.
+	NewestWins Policy = "newest"
+)
+
+// Aggregation is parallel edge weight aggregation.
+type Aggregation string
+
+const (
+	// Sum sums the weights of parallel edges.
+	Sum Aggregation = "sum"
+	// Max picks the maximum weight of parallel edges.
+	Max Aggregation = "max"
+	// Mean averages the weights of parallel edges.
+	Mean Aggregation = "mean"
+)
+
+// ConflictFunc resolves conflicting attribute values of two merged entities.
+// It receives the attribute key and the attributes of the left and right entity.
+// The returned value is stored in the merged entity.
+type ConflictFunc func(key string, left, right map[string]interface{}) interface{}
+
+// Options configure merger.
+type Options struct {
+	// UID configures merged graph UID.
+	UID string
+	// Label configures merged graph label.
+	Label string
+	// Policy configures attribute conflict resolution policy.
+	Policy Policy
+	// ConflictFunc configures custom attribute conflict resolution.
+	// It takes precedence over Policy.
+	ConflictFunc ConflictFunc
+	// Aggregation configures parallel edge weight aggregation.
+	Aggregation Aggregation
+}
+
+// Option is functional merger option.
+type Option func(*Options)
+
+// WithUID sets UID option.
+func WithUID(u string) Option {
+	return func(o *Options) {
+		o.UID = u
+	}
+}
+
+// WithLabel sets Label option.
+func WithLabel(l string) Option {
+	return func(o *Options) {
+		o.Label = l
+	}
+}
+
+// WithPolicy sets Policy option.
+func WithPolicy(p Policy) Option {
+	return func(o *Options) {
+		o.Policy = p
+	}
+}
+
+// WithConflictFunc sets ConflictFunc option.
+func WithConflictFunc(f ConflictFunc) Option {
+	return func(o *Options) {
+		o.ConflictFunc = f
+	}
+}
+
+// WithAggregation sets Aggregation option.
+func WithAggregation(a Aggregation) Option {
+	return func(o *Options) {
+		o.Aggregation = a
+	}
+}
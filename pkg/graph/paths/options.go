@@ -0,0 +1,97 @@
+package paths
+
+import "github.com/milosgajdos/orbnet/pkg/graph"
+
+const (
+	// DefaultK is the default number of the shortest paths found by Yen.
+	DefaultK = 3
+	// DefaultMaxDepth is the default maximum number of edges of the paths found by AllSimple.
+	DefaultMaxDepth = 4
+)
+
+// Heuristic returns the estimated cost of the path between x and y.
+// A* finds the shortest path if the heuristic never overestimates the cost
+// and it does not decrease by more than the edge weight between neighbouring nodes.
+type Heuristic func(x, y graph.Node) float64
+
+// Options configure path finding.
+type Options struct {
+	// Algorithm is path finding algorithm.
+	Algorithm Algorithm
+	// K is the number of the shortest paths found by Yen.
+	K int
+	// MaxDepth is the maximum number of edges of the paths found by AllSimple.
+	MaxDepth int
+	// Limit is the maximum number of paths found by AllSimple.
+	// If it's not positive all the paths are returned.
+	Limit int
+	// Heuristic is A* heuristic.
+	// If it's nil the minimum edge weight is used for all the nodes but the target.
+	Heuristic Heuristic
+}
+
+// Option is functional path finding option.
+type Option func(*Options)
+
+// WithAlgorithm sets Algorithm option.
+func WithAlgorithm(a Algorithm) Option {
+	return func(o *Options) {
+		o.Algorithm = a
+	}
+}
+
+// WithK sets K option.
+func WithK(k int) Option {
+	return func(o *Options) {
+		o.K = k
+	}
+}
+
+// WithMaxDepth sets MaxDepth option.
+func WithMaxDepth(d int) Option {
+	return func(o *Options) {
+		o.MaxDepth = d
+	}
+}
+
+// WithLimit sets Limit option.
+func WithLimit(n int) Option {
+	return func(o *Options) {
+		o.Limit = n
+	}
+}
+
+// WithHeuristic sets Heuristic option.
+func WithHeuristic(h Heuristic) Option {
+	return func(o *Options) {
+		o.Heuristic = h
+	}
+}
+
+func newOptions(opts ...Option) (Options, error) {
+	popts := Options{
+		Algorithm: Dijkstra,
+		K:         DefaultK,
+		MaxDepth:  DefaultMaxDepth,
+	}
+
+	for _, apply := range opts {
+		apply(&popts)
+	}
+
+	switch popts.Algorithm {
+	case Dijkstra, AStar, Yen, AllSimple:
+	default:
+		return Options{}, graph.Errorf(graph.EINVALID, "unsupported algorithm: %q", popts.Algorithm)
+	}
+
+	if popts.K <= 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid number of paths: %d", popts.K)
+	}
+
+	if popts.MaxDepth <= 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid max depth: %d", popts.MaxDepth)
+	}
+
+	return popts, nil
+}
@@ -0,0 +1,356 @@
+package paths
+
+import (
+	"math"
+	"sort"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	gonum "gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/iterator"
+	"gonum.org/v1/gonum/graph/path"
+)
+
+// Algorithm is path finding algorithm.
+type Algorithm string
+
+const (
+	// Dijkstra finds the shortest path using Dijkstra's algorithm.
+	Dijkstra Algorithm = "dijkstra"
+	// AStar finds the shortest path using A* search.
+	AStar Algorithm = "astar"
+	// Yen finds K shortest loopless paths using Yen's algorithm.
+	Yen Algorithm = "yen"
+	// AllSimple finds all simple paths up to MaxDepth edges.
+	AllSimple Algorithm = "all"
+)
+
+// Path is a path between two graph nodes.
+type Path struct {
+	// Nodes are the path nodes ordered from the source to the target node.
+	Nodes []graph.Node
+	// Edges are the path edges ordered from the source to the target node.
+	Edges []graph.Edge
+	// Weight is the sum of the path edge weights.
+	Weight float64
+}
+
+// Find finds the paths from the source to the target node in g using the Algorithm option.
+// Dijkstra and AStar return at most one path, Yen returns at most K shortest paths and
+// AllSimple returns the simple paths with at most MaxDepth edges. The paths are ordered by
+// their weight and the paths of the same weight by their length.
+// Edges of undirected graphs are traversed in both directions. If there are multiple edges
+// between two nodes, such as the lines of multigraphs, the edge with the smallest weight is used.
+// It returns error if either of the nodes is not found or if g contains a negative edge weight.
+func Find(g graph.Graph, source, target int64, opts ...Option) ([]Path, error) {
+	popts, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := newView(g)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range []int64{source, target} {
+		if _, ok := v.nodes[id]; !ok {
+			return nil, graph.Errorf(graph.ENOTFOUND, "node %d not found", id)
+		}
+	}
+
+	s, t := v.nodes[source], v.nodes[target]
+
+	var found [][]gonum.Node
+	switch {
+	case source == target:
+		found = [][]gonum.Node{{s}}
+	case popts.Algorithm == Dijkstra:
+		if p, _ := path.DijkstraFrom(s, v.graph()).To(target); len(p) > 0 {
+			found = append(found, p)
+		}
+	case popts.Algorithm == AStar:
+		h := popts.Heuristic
+		if h == nil {
+			h = v.minWeightHeuristic
+		}
+		heuristic := func(x, y gonum.Node) float64 {
+			return h(v.nodes[x.ID()], v.nodes[y.ID()])
+		}
+		sp, _ := path.AStar(s, t, v.graph(), heuristic)
+		if p, _ := sp.To(target); len(p) > 0 {
+			found = append(found, p)
+		}
+	case popts.Algorithm == Yen:
+		found = path.YenKShortestPaths(v.graph(), popts.K, math.Inf(1), s, t)
+	case popts.Algorithm == AllSimple:
+		found = v.allSimple(source, target, popts.MaxDepth, popts.Limit)
+	}
+
+	paths := make([]Path, len(found))
+	for i, p := range found {
+		paths[i] = v.path(p)
+	}
+
+	sort.SliceStable(paths, func(i, j int) bool {
+		if paths[i].Weight != paths[j].Weight {
+			return paths[i].Weight < paths[j].Weight
+		}
+		return len(paths[i].Edges) < len(paths[j].Edges)
+	})
+
+	return paths, nil
+}
+
+// view is a traversable view of graph.
+// Graph nodes are connected by at most one edge which has the smallest weight
+// of all the edges between them.
+type view struct {
+	// nodes indexes graph nodes by their IDs.
+	nodes map[int64]graph.Node
+	// adj stores ID sorted nodes reachable from graph nodes by a single edge.
+	adj map[int64][]int64
+	// in stores ID sorted nodes from which graph nodes are reachable by a single edge.
+	in map[int64][]int64
+	// edges stores the edges with the smallest weight between graph nodes.
+	edges map[int64]map[int64]graph.Edge
+	// minWeight is the smallest edge weight.
+	minWeight float64
+	// directed is true if graph edges are directed.
+	directed bool
+}
+
+func newView(g graph.Graph) (*view, error) {
+	v := &view{
+		nodes:     make(map[int64]graph.Node),
+		adj:       make(map[int64][]int64),
+		in:        make(map[int64][]int64),
+		edges:     make(map[int64]map[int64]graph.Edge),
+		minWeight: math.Inf(1),
+		directed:  graph.IsDirected(g.Type()),
+	}
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n, ok := nodes.Node().(graph.Node)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid node in graph %s", g.UID())
+		}
+		v.nodes[n.ID()] = n
+	}
+
+	edges := g.Edges()
+	for edges.Next() {
+		e, ok := edges.Edge().(graph.Edge)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid edge in graph %s", g.UID())
+		}
+
+		if e.Weight() < 0 {
+			return nil, graph.Errorf(graph.EINVALID, "negative edge weight: %f", e.Weight())
+		}
+
+		if e.Weight() < v.minWeight {
+			v.minWeight = e.Weight()
+		}
+
+		from, to := e.From().ID(), e.To().ID()
+		v.link(from, to, e)
+		if !v.directed {
+			v.link(to, from, e)
+		}
+	}
+
+	for _, adj := range []map[int64][]int64{v.adj, v.in} {
+		for id := range adj {
+			ids := adj[id]
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		}
+	}
+
+	return v, nil
+}
+
+func (v *view) link(from, to int64, e graph.Edge) {
+	if _, ok := v.edges[from]; !ok {
+		v.edges[from] = make(map[int64]graph.Edge)
+	}
+
+	cur, ok := v.edges[from][to]
+	if !ok {
+		v.adj[from] = append(v.adj[from], to)
+		v.in[to] = append(v.in[to], from)
+	}
+
+	if !ok || e.Weight() < cur.Weight() {
+		v.edges[from][to] = e
+	}
+}
+
+// graph returns v as a directed graph if its edges are directed.
+// Gonum path finding traverses the edges of the graphs which are
+// not directed in both directions.
+func (v *view) graph() gonum.Graph {
+	if v.directed {
+		return directedView{view: v}
+	}
+	return v
+}
+
+// Node returns the node with the given ID if it exists in the graph.
+func (v *view) Node(id int64) gonum.Node {
+	n, ok := v.nodes[id]
+	if !ok {
+		return nil
+	}
+	return n
+}
+
+// Nodes returns all the nodes in the graph ordered by their IDs.
+func (v *view) Nodes() gonum.Nodes {
+	ids := make([]int64, 0, len(v.nodes))
+	for id := range v.nodes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return v.nodesOf(ids)
+}
+
+// From returns all nodes that can be reached directly from the node with the given ID.
+func (v *view) From(id int64) gonum.Nodes {
+	return v.nodesOf(v.adj[id])
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y without considering direction.
+func (v *view) HasEdgeBetween(xid, yid int64) bool {
+	_, ok := v.edges[xid][yid]
+	if !ok {
+		_, ok = v.edges[yid][xid]
+	}
+	return ok
+}
+
+// Edge returns the edge with the smallest weight from u to v if such an edge exists.
+func (v *view) Edge(uid, vid int64) gonum.Edge {
+	e, ok := v.edges[uid][vid]
+	if !ok {
+		return nil
+	}
+	return e
+}
+
+// Weight returns the weight of the edge from x to y.
+// The weight of the path from a node to itself is 0.
+func (v *view) Weight(xid, yid int64) (float64, bool) {
+	if xid == yid {
+		return 0, true
+	}
+
+	if e, ok := v.edges[xid][yid]; ok {
+		return e.Weight(), true
+	}
+
+	return math.Inf(1), false
+}
+
+func (v *view) nodesOf(ids []int64) gonum.Nodes {
+	if len(ids) == 0 {
+		return gonum.Empty
+	}
+
+	nodes := make([]gonum.Node, len(ids))
+	for i, id := range ids {
+		nodes[i] = v.nodes[id]
+	}
+
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// minWeightHeuristic returns the smallest edge weight unless x and y are the same node.
+// Every path between different nodes has at least one edge so the heuristic never overestimates.
+func (v *view) minWeightHeuristic(x, y graph.Node) float64 {
+	if x.ID() == y.ID() {
+		return 0
+	}
+	return v.minWeight
+}
+
+// path returns the path through the given nodes.
+func (v *view) path(nodes []gonum.Node) Path {
+	p := Path{
+		Nodes: make([]graph.Node, len(nodes)),
+		Edges: make([]graph.Edge, 0, len(nodes)-1),
+	}
+
+	for i, n := range nodes {
+		p.Nodes[i] = v.nodes[n.ID()]
+		if i > 0 {
+			e := v.edges[nodes[i-1].ID()][n.ID()]
+			p.Edges = append(p.Edges, e)
+			p.Weight += e.Weight()
+		}
+	}
+
+	return p
+}
+
+// allSimple returns at most limit simple paths with at most maxDepth edges
+// from the source to the target node. If limit is not positive all the paths are returned.
+func (v *view) allSimple(source, target int64, maxDepth, limit int) [][]gonum.Node {
+	var paths [][]gonum.Node
+
+	onPath := map[int64]bool{source: true}
+	p := []gonum.Node{v.nodes[source]}
+
+	var visit func(u int64) bool
+	visit = func(u int64) bool {
+		if u == target {
+			paths = append(paths, append([]gonum.Node(nil), p...))
+			return limit > 0 && len(paths) >= limit
+		}
+
+		if len(p) > maxDepth {
+			return false
+		}
+
+		for _, nid := range v.adj[u] {
+			if onPath[nid] {
+				continue
+			}
+
+			onPath[nid] = true
+			p = append(p, v.nodes[nid])
+
+			stop := visit(nid)
+
+			p = p[:len(p)-1]
+			onPath[nid] = false
+
+			if stop {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	visit(source)
+
+	return paths
+}
+
+// directedView is a view of graph with directed edges.
+type directedView struct {
+	*view
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (d directedView) HasEdgeFromTo(uid, vid int64) bool {
+	_, ok := d.edges[uid][vid]
+	return ok
+}
+
+// To returns all nodes that can reach directly to the node with the given ID.
+func (d directedView) To(id int64) gonum.Nodes {
+	return d.nodesOf(d.in[id])
+}
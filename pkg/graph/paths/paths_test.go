@@ -0,0 +1,206 @@
+package paths
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+type testEdge struct {
+	from   string
+	to     string
+	weight float64
+}
+
+// MustGraph returns the graph of the given type with the following edges:
+//
+//	a -> b (1), b -> d (1), a -> c (2), c -> d (1), a -> d (5), b -> c (1)
+func MustGraph(t *testing.T, typ string, extra ...testEdge) (*memory.Graph, map[string]int64) {
+	t.Helper()
+
+	g, err := memory.NewGraph(memory.WithType(typ))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	nodes := make(map[string]*memory.Node)
+	ids := make(map[string]int64)
+	for _, uid := range []string{"a", "b", "c", "d", "e"} {
+		n, err := memory.NewNode(g.NewNode().ID(), memory.WithUID(uid))
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(n)
+		nodes[uid] = n
+		ids[uid] = n.ID()
+	}
+
+	edges := []testEdge{
+		{"a", "b", 1}, {"b", "d", 1}, {"a", "c", 2},
+		{"c", "d", 1}, {"a", "d", 5}, {"b", "c", 1},
+	}
+
+	for _, te := range append(edges, extra...) {
+		e, err := memory.NewEdge(nodes[te.from], nodes[te.to], memory.WithWeight(te.weight))
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(e)
+	}
+
+	return g, ids
+}
+
+func uids(p Path) []string {
+	out := make([]string, len(p.Nodes))
+	for i, n := range p.Nodes {
+		out[i] = n.UID()
+	}
+	return out
+}
+
+func assertPaths(t *testing.T, paths []Path, exp [][]string, weights []float64) {
+	t.Helper()
+
+	if len(paths) != len(exp) {
+		t.Fatalf("expected %d paths, got: %d", len(exp), len(paths))
+	}
+
+	for i, p := range paths {
+		if got := uids(p); !reflect.DeepEqual(got, exp[i]) {
+			t.Errorf("path %d: expected: %v, got: %v", i, exp[i], got)
+		}
+
+		if p.Weight != weights[i] {
+			t.Errorf("path %d: expected weight: %f, got: %f", i, weights[i], p.Weight)
+		}
+
+		if len(p.Edges) != len(p.Nodes)-1 {
+			t.Fatalf("path %d: expected %d edges, got: %d", i, len(p.Nodes)-1, len(p.Edges))
+		}
+
+		for j, e := range p.Edges {
+			from, to := e.From().ID(), e.To().ID()
+			u, v := p.Nodes[j].ID(), p.Nodes[j+1].ID()
+			if (from != u || to != v) && (from != v || to != u) {
+				t.Errorf("path %d: edge %d does not join %d and %d", i, j, u, v)
+			}
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	testCases := []struct {
+		name    string
+		opts    []Option
+		exp     [][]string
+		weights []float64
+	}{
+		{"Dijkstra", nil, [][]string{{"a", "b", "d"}}, []float64{2}},
+		{"AStar", []Option{WithAlgorithm(AStar)}, [][]string{{"a", "b", "d"}}, []float64{2}},
+		{"AStarHeuristic", []Option{
+			WithAlgorithm(AStar),
+			WithHeuristic(func(x, y graph.Node) float64 { return 0 }),
+		}, [][]string{{"a", "b", "d"}}, []float64{2}},
+		{"Yen", []Option{WithAlgorithm(Yen)},
+			[][]string{{"a", "b", "d"}, {"a", "c", "d"}, {"a", "b", "c", "d"}},
+			[]float64{2, 3, 3}},
+		{"YenAll", []Option{WithAlgorithm(Yen), WithK(10)},
+			[][]string{{"a", "b", "d"}, {"a", "c", "d"}, {"a", "b", "c", "d"}, {"a", "d"}},
+			[]float64{2, 3, 3, 5}},
+		{"AllSimple", []Option{WithAlgorithm(AllSimple), WithMaxDepth(2)},
+			[][]string{{"a", "b", "d"}, {"a", "c", "d"}, {"a", "d"}},
+			[]float64{2, 3, 5}},
+		{"AllSimpleLimit", []Option{WithAlgorithm(AllSimple), WithLimit(1)},
+			[][]string{{"a", "b", "c", "d"}},
+			[]float64{3}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g, ids := MustGraph(t, graph.WeightedDirected)
+
+			paths, err := Find(g, ids["a"], ids["d"], tc.opts...)
+			if err != nil {
+				t.Fatalf("failed to find paths: %v", err)
+			}
+			assertPaths(t, paths, tc.exp, tc.weights)
+		})
+	}
+}
+
+func TestFindDirection(t *testing.T) {
+	g, ids := MustGraph(t, graph.WeightedDirected)
+
+	paths, err := Find(g, ids["d"], ids["a"])
+	if err != nil {
+		t.Fatalf("failed to find paths: %v", err)
+	}
+
+	if len(paths) != 0 {
+		t.Errorf("expected no paths, got: %d", len(paths))
+	}
+
+	u, ids := MustGraph(t, graph.WeightedUndirected)
+
+	paths, err = Find(u, ids["d"], ids["a"], WithAlgorithm(Yen), WithK(2))
+	if err != nil {
+		t.Fatalf("failed to find paths: %v", err)
+	}
+	assertPaths(t, paths, [][]string{{"d", "b", "a"}, {"d", "c", "a"}}, []float64{2, 3})
+}
+
+func TestFindMulti(t *testing.T) {
+	g, ids := MustGraph(t, graph.WeightedDirectedMulti, testEdge{"a", "d", 0.5})
+
+	paths, err := Find(g, ids["a"], ids["d"])
+	if err != nil {
+		t.Fatalf("failed to find paths: %v", err)
+	}
+	assertPaths(t, paths, [][]string{{"a", "d"}}, []float64{0.5})
+}
+
+func TestFindSame(t *testing.T) {
+	g, ids := MustGraph(t, graph.WeightedDirected)
+
+	for _, algo := range []Algorithm{Dijkstra, AStar, Yen, AllSimple} {
+		paths, err := Find(g, ids["e"], ids["e"], WithAlgorithm(algo))
+		if err != nil {
+			t.Fatalf("%s: failed to find paths: %v", algo, err)
+		}
+		assertPaths(t, paths, [][]string{{"e"}}, []float64{0})
+	}
+}
+
+func TestFindErrors(t *testing.T) {
+	g, ids := MustGraph(t, graph.WeightedDirected)
+
+	testCases := []struct {
+		name   string
+		target int64
+		opts   []Option
+		code   string
+	}{
+		{"NotFound", 100, nil, graph.ENOTFOUND},
+		{"Algorithm", ids["d"], []Option{WithAlgorithm("foo")}, graph.EINVALID},
+		{"K", ids["d"], []Option{WithAlgorithm(Yen), WithK(0)}, graph.EINVALID},
+		{"MaxDepth", ids["d"], []Option{WithAlgorithm(AllSimple), WithMaxDepth(0)}, graph.EINVALID},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Find(g, ids["a"], tc.target, tc.opts...); graph.ErrorCode(err) != tc.code {
+				t.Errorf("expected error: %s, got: %v", tc.code, err)
+			}
+		})
+	}
+
+	n, ids := MustGraph(t, graph.WeightedDirected, testEdge{"d", "e", -1})
+	if _, err := Find(n, ids["a"], ids["e"]); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
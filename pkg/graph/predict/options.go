@@ -0,0 +1,106 @@
+package predict
+
+import (
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/builder/stars"
+)
+
+const (
+	// DefaultLimit is the default number of suggested topics of each repo.
+	DefaultLimit = 3
+	// DefaultMinConfidence is the default minimum confidence of suggested topics.
+	DefaultMinConfidence = 0.1
+	// DefaultKeywordConfidence is the default confidence of topics matched by keywords.
+	DefaultKeywordConfidence = 0.5
+)
+
+// Options configure topic suggestions.
+type Options struct {
+	// MaxTopics is the maximum number of topics of the repos which get suggestions.
+	MaxTopics int
+	// Limit is the maximum number of suggested topics of each repo.
+	// If it's not positive all the suggested topics are returned.
+	Limit int
+	// MinConfidence is the minimum confidence of suggested topics.
+	MinConfidence float64
+	// KeywordConfidence is the confidence of topics matched by keywords.
+	// If it's zero topics are suggested by neighbourhood similarity only.
+	KeywordConfidence float64
+	// Via are the labels of the nodes which link similar repos.
+	Via []string
+	// Text are the repo attributes matched against topic names.
+	Text []string
+}
+
+// Option is functional topic suggestion option.
+type Option func(*Options)
+
+// WithMaxTopics sets MaxTopics option.
+func WithMaxTopics(n int) Option {
+	return func(o *Options) {
+		o.MaxTopics = n
+	}
+}
+
+// WithLimit sets Limit option.
+func WithLimit(n int) Option {
+	return func(o *Options) {
+		o.Limit = n
+	}
+}
+
+// WithMinConfidence sets MinConfidence option.
+func WithMinConfidence(c float64) Option {
+	return func(o *Options) {
+		o.MinConfidence = c
+	}
+}
+
+// WithKeywordConfidence sets KeywordConfidence option.
+func WithKeywordConfidence(c float64) Option {
+	return func(o *Options) {
+		o.KeywordConfidence = c
+	}
+}
+
+// WithVia sets Via option.
+func WithVia(labels ...string) Option {
+	return func(o *Options) {
+		o.Via = labels
+	}
+}
+
+// WithText sets Text option.
+func WithText(keys ...string) Option {
+	return func(o *Options) {
+		o.Text = keys
+	}
+}
+
+func newOptions(opts ...Option) (Options, error) {
+	popts := Options{
+		Limit:             DefaultLimit,
+		MinConfidence:     DefaultMinConfidence,
+		KeywordConfidence: DefaultKeywordConfidence,
+		Via:               []string{stars.OwnerEntity.String(), stars.LangEntity.String()},
+		Text:              []string{"name", "description", "readme"},
+	}
+
+	for _, apply := range opts {
+		apply(&popts)
+	}
+
+	if popts.MaxTopics < 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid max topics: %d", popts.MaxTopics)
+	}
+
+	if popts.MinConfidence < 0 || popts.MinConfidence > 1 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid min confidence: %f", popts.MinConfidence)
+	}
+
+	if popts.KeywordConfidence < 0 || popts.KeywordConfidence >= 1 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid keyword confidence: %f", popts.KeywordConfidence)
+	}
+
+	return popts, nil
+}
@@ -0,0 +1,331 @@
+package predict
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/builder/stars"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+const (
+	// ConfidenceAttr is the confidence attribute of suggested topic edges.
+	ConfidenceAttr = "confidence"
+)
+
+// Suggestion is a suggested topic of a repo.
+type Suggestion struct {
+	// RepoUID is repo node UID.
+	RepoUID string `json:"repo_uid"`
+	// Repo is repo name.
+	Repo string `json:"repo"`
+	// TopicUID is topic node UID.
+	TopicUID string `json:"topic_uid"`
+	// Topic is topic name.
+	Topic string `json:"topic"`
+	// Confidence is the confidence of the suggestion in [0, 1].
+	Confidence float64 `json:"confidence"`
+	// Reasons explain the suggestion.
+	Reasons []string `json:"reasons"`
+}
+
+// Topics suggests likely topics of the repos in g which have at most MaxTopics topics.
+// Topics are suggested by neighbourhood similarity and keyword matching:
+//   - the topics of the repos which share Via option neighbours, such as owner or language,
+//     with the repo are scored by the weighted share of those repos having them; neighbours
+//     shared by fewer repos weigh more
+//   - topic names found in the repo Text option attributes get KeywordConfidence
+//
+// The two confidences are combined as independent evidence.
+// Edge directions are ignored and existing SuggestedTopic edges are not treated as repo topics.
+// Suggestions are ordered by repo name and their confidence.
+func Topics(g graph.Graph, opts ...Option) ([]Suggestion, error) {
+	popts, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := newView(g, popts)
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestions []Suggestion
+	for _, rid := range v.repos {
+		if len(v.topics[rid]) > popts.MaxTopics {
+			continue
+		}
+
+		suggestions = append(suggestions, v.suggest(rid, popts)...)
+	}
+
+	return suggestions, nil
+}
+
+// Materialise adds the suggestions to g as SuggestedTopic edges from repos to topics
+// weighted by their confidence. Suggestions of already linked repos and topics are skipped.
+func Materialise(g *memory.Graph, suggestions []Suggestion) error {
+	for _, s := range suggestions {
+		repo := g.NodeWithUID(s.RepoUID)
+		if repo == nil {
+			return graph.Errorf(graph.ENOTFOUND, "repo %s not found", s.RepoUID)
+		}
+
+		topic := g.NodeWithUID(s.TopicUID)
+		if topic == nil {
+			return graph.Errorf(graph.ENOTFOUND, "topic %s not found", s.TopicUID)
+		}
+
+		if g.HasEdgeBetween(repo.ID(), topic.ID()) {
+			continue
+		}
+
+		style := stars.LinkEntity.DefaultStyle()
+		attrs := map[string]interface{}{
+			"relation":     stars.SuggestedTopicEdgeLabel,
+			"weight":       s.Confidence,
+			ConfidenceAttr: s.Confidence,
+			"style":        style.Type,
+			"shape":        style.Shape,
+			"color":        style.Color,
+		}
+
+		e, err := memory.NewEdge(repo, topic,
+			memory.WithLabel(stars.SuggestedTopicEdgeLabel),
+			memory.WithWeight(s.Confidence),
+			memory.WithAttrs(attrs),
+			memory.WithStyle(style),
+		)
+		if err != nil {
+			return err
+		}
+		g.SetWeightedEdge(e)
+	}
+
+	return nil
+}
+
+// view is an undirected view of repos linked to their topics and via nodes.
+type view struct {
+	// nodes indexes graph nodes by their IDs.
+	nodes map[int64]graph.Node
+	// repos stores repo IDs sorted by repo names.
+	repos []int64
+	// topics stores ID sorted topics of the repos.
+	topics map[int64][]int64
+	// via stores ID sorted via neighbours of the repos.
+	via map[int64][]int64
+	// members stores ID sorted repos linked to via nodes.
+	members map[int64][]int64
+	// keywords stores normalised topic names by topic IDs.
+	keywords map[int64]string
+}
+
+func newView(g graph.Graph, opts Options) (*view, error) {
+	v := &view{
+		nodes:    make(map[int64]graph.Node),
+		topics:   make(map[int64][]int64),
+		via:      make(map[int64][]int64),
+		members:  make(map[int64][]int64),
+		keywords: make(map[int64]string),
+	}
+
+	viaLabels := make(map[string]bool, len(opts.Via))
+	for _, l := range opts.Via {
+		viaLabels[l] = true
+	}
+
+	repoLabel := stars.RepoEntity.String()
+	topicLabel := stars.TopicEntity.String()
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n, ok := nodes.Node().(graph.Node)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid node in graph %s", g.UID())
+		}
+		v.nodes[n.ID()] = n
+
+		switch n.Label() {
+		case repoLabel:
+			v.repos = append(v.repos, n.ID())
+		case topicLabel:
+			if kw := normalise(graph.NodeName(n)); len(kw) > 1 {
+				v.keywords[n.ID()] = kw
+			}
+		}
+	}
+
+	topics := make(map[int64]map[int64]bool)
+	via := make(map[int64]map[int64]bool)
+
+	edges := g.Edges()
+	for edges.Next() {
+		e := edges.Edge()
+
+		from, to := v.nodes[e.From().ID()], v.nodes[e.To().ID()]
+		if to.Label() == repoLabel {
+			from, to = to, from
+		}
+
+		if from.Label() != repoLabel {
+			continue
+		}
+
+		switch {
+		case to.Label() == topicLabel:
+			if ge, ok := e.(graph.Edge); ok && ge.Label() == stars.SuggestedTopicEdgeLabel {
+				continue
+			}
+			link(topics, from.ID(), to.ID())
+		case viaLabels[to.Label()]:
+			link(via, from.ID(), to.ID())
+			link(via, to.ID(), from.ID())
+		}
+	}
+
+	for rid, tids := range topics {
+		v.topics[rid] = graph.SortedIDs(tids)
+	}
+
+	for id, ids := range via {
+		if v.nodes[id].Label() == repoLabel {
+			v.via[id] = graph.SortedIDs(ids)
+			continue
+		}
+		v.members[id] = graph.SortedIDs(ids)
+	}
+
+	sort.Slice(v.repos, func(i, j int) bool {
+		ni, nj := graph.NodeName(v.nodes[v.repos[i]]), graph.NodeName(v.nodes[v.repos[j]])
+		if ni != nj {
+			return ni < nj
+		}
+		return v.repos[i] < v.repos[j]
+	})
+
+	return v, nil
+}
+
+// suggest returns the suggested topics of the repo with the given id.
+func (v *view) suggest(rid int64, opts Options) []Suggestion {
+	repo := v.nodes[rid]
+
+	has := make(map[int64]bool, len(v.topics[rid]))
+	for _, tid := range v.topics[rid] {
+		has[tid] = true
+	}
+
+	scores := make(map[int64]float64)
+	reasons := make(map[int64][]string)
+
+	var total float64
+	for _, vid := range v.via[rid] {
+		var similar []int64
+		for _, id := range v.members[vid] {
+			if id != rid && len(v.topics[id]) > 0 {
+				similar = append(similar, id)
+			}
+		}
+
+		if len(similar) == 0 {
+			continue
+		}
+
+		// NOTE: via node links at least two repos: rid and the similar repo
+		w := 1 / math.Log(float64(len(v.members[vid])))
+		total += w
+
+		counts := make(map[int64]int)
+		for _, id := range similar {
+			for _, tid := range v.topics[id] {
+				counts[tid]++
+			}
+		}
+
+		via := v.nodes[vid]
+		for tid, count := range counts {
+			if has[tid] {
+				continue
+			}
+			scores[tid] += w * float64(count) / float64(len(similar))
+			reasons[tid] = append(reasons[tid],
+				fmt.Sprintf("%s %s: %d/%d repos", via.Label(), graph.NodeName(via), count, len(similar)))
+		}
+	}
+
+	for tid := range scores {
+		scores[tid] /= total
+	}
+
+	if opts.KeywordConfidence > 0 {
+		text := v.text(repo, opts.Text)
+		for tid, kw := range v.keywords {
+			if has[tid] || !strings.Contains(text, " "+kw+" ") {
+				continue
+			}
+			scores[tid] = 1 - (1-scores[tid])*(1-opts.KeywordConfidence)
+			reasons[tid] = append(reasons[tid], fmt.Sprintf("keyword: %s", graph.NodeName(v.nodes[tid])))
+		}
+	}
+
+	var suggestions []Suggestion
+	for tid, score := range scores {
+		if score <= 0 || score < opts.MinConfidence {
+			continue
+		}
+
+		topic := v.nodes[tid]
+		suggestions = append(suggestions, Suggestion{
+			RepoUID:    repo.UID(),
+			Repo:       graph.NodeName(repo),
+			TopicUID:   topic.UID(),
+			Topic:      graph.NodeName(topic),
+			Confidence: score,
+			Reasons:    reasons[tid],
+		})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Confidence != suggestions[j].Confidence {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		}
+		return suggestions[i].Topic < suggestions[j].Topic
+	})
+
+	if opts.Limit > 0 && len(suggestions) > opts.Limit {
+		suggestions = suggestions[:opts.Limit]
+	}
+
+	return suggestions
+}
+
+// text returns the normalised text of the node attributes with the given keys
+// padded with spaces so that whole keywords can be matched in it.
+func (v *view) text(n graph.Node, keys []string) string {
+	var parts []string
+	for _, k := range keys {
+		if s, ok := n.Attrs()[k].(string); ok && s != "" {
+			parts = append(parts, normalise(s))
+		}
+	}
+	return " " + strings.Join(parts, " ") + " "
+}
+
+func link(adj map[int64]map[int64]bool, from, to int64) {
+	if _, ok := adj[from]; !ok {
+		adj[from] = make(map[int64]bool)
+	}
+	adj[from][to] = true
+}
+
+// normalise lower cases s and replaces all runs of non-alphanumeric characters with a single space.
+func normalise(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
@@ -0,0 +1,177 @@
+package predict
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/builder/stars"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+const tol = 1e-6
+
+// MustGraph returns the following graph of repos linked to their topics, languages and owners:
+//
+//	r1 -> k8s, cloud, go, o1
+//	r2 -> k8s, o1
+//	r3 -> go, o1 with "A Kubernetes cloud operator" description
+//	r4 -> cli, go
+func MustGraph(t *testing.T) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	nodes := []struct {
+		uid   string
+		label string
+		attrs map[string]interface{}
+	}{
+		{"r1", "Repo", map[string]interface{}{"name": "r1"}},
+		{"r2", "Repo", map[string]interface{}{"name": "r2"}},
+		{"r3", "Repo", map[string]interface{}{"name": "r3", "description": "A Kubernetes cloud operator"}},
+		{"r4", "Repo", map[string]interface{}{"name": "r4"}},
+		{"k8s-Topic", "Topic", map[string]interface{}{"name": "k8s"}},
+		{"cloud-Topic", "Topic", map[string]interface{}{"name": "cloud"}},
+		{"cli-Topic", "Topic", map[string]interface{}{"name": "cli"}},
+		{"go-Lang", "Lang", map[string]interface{}{"name": "go"}},
+		{"o1", "Owner", map[string]interface{}{"name": "o1"}},
+	}
+
+	ns := make(map[string]*memory.Node)
+	for _, tn := range nodes {
+		n, err := memory.NewNode(g.NewNode().ID(),
+			memory.WithUID(tn.uid),
+			memory.WithLabel(tn.label),
+			memory.WithAttrs(tn.attrs),
+		)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(n)
+		ns[tn.uid] = n
+	}
+
+	for _, te := range [][2]string{
+		{"r1", "k8s-Topic"}, {"r1", "cloud-Topic"}, {"r1", "go-Lang"}, {"r1", "o1"},
+		{"r2", "k8s-Topic"}, {"r2", "o1"},
+		{"r3", "go-Lang"}, {"r3", "o1"},
+		{"r4", "cli-Topic"}, {"r4", "go-Lang"},
+	} {
+		e, err := memory.NewEdge(ns[te[0]], ns[te[1]])
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(e)
+	}
+
+	return g
+}
+
+func assertSuggestions(t *testing.T, suggestions []Suggestion, repos, topics []string, confidences []float64) {
+	t.Helper()
+
+	if len(suggestions) != len(topics) {
+		t.Fatalf("expected %d suggestions, got: %d: %v", len(topics), len(suggestions), suggestions)
+	}
+
+	for i, s := range suggestions {
+		if s.RepoUID != repos[i] || s.Topic != topics[i] {
+			t.Errorf("suggestion %d: expected: %s %s, got: %s %s", i, repos[i], topics[i], s.RepoUID, s.Topic)
+		}
+		if math.Abs(s.Confidence-confidences[i]) > tol {
+			t.Errorf("suggestion %d: expected confidence: %f, got: %f", i, confidences[i], s.Confidence)
+		}
+	}
+}
+
+func TestTopics(t *testing.T) {
+	g := MustGraph(t)
+
+	suggestions, err := Topics(g, WithKeywordConfidence(0.6))
+	if err != nil {
+		t.Fatalf("failed to suggest topics: %v", err)
+	}
+
+	repos := []string{"r3", "r3", "r3"}
+	assertSuggestions(t, suggestions, repos, []string{"cloud", "k8s", "cli"}, []float64{0.8, 0.75, 0.25})
+
+	if exp := []string{"Lang go: 1/2 repos", "Owner o1: 1/2 repos", "keyword: cloud"}; !reflect.DeepEqual(suggestions[0].Reasons, exp) {
+		t.Errorf("expected reasons: %v, got: %v", exp, suggestions[0].Reasons)
+	}
+
+	suggestions, err = Topics(g, WithKeywordConfidence(0), WithMinConfidence(0.3), WithLimit(1))
+	if err != nil {
+		t.Fatalf("failed to suggest topics: %v", err)
+	}
+	assertSuggestions(t, suggestions, []string{"r3"}, []string{"k8s"}, []float64{0.75})
+
+	suggestions, err = Topics(g, WithMaxTopics(1), WithKeywordConfidence(0), WithVia(stars.OwnerEntity.String()))
+	if err != nil {
+		t.Fatalf("failed to suggest topics: %v", err)
+	}
+	assertSuggestions(t, suggestions, []string{"r2", "r3", "r3"}, []string{"cloud", "k8s", "cloud"}, []float64{1, 1, 0.5})
+}
+
+func TestMaterialise(t *testing.T) {
+	g := MustGraph(t)
+
+	suggestions, err := Topics(g)
+	if err != nil {
+		t.Fatalf("failed to suggest topics: %v", err)
+	}
+
+	if err := Materialise(g, suggestions); err != nil {
+		t.Fatalf("failed to materialise suggestions: %v", err)
+	}
+
+	for _, s := range suggestions {
+		e := g.Edge(g.NodeWithUID(s.RepoUID).ID(), g.NodeWithUID(s.TopicUID).ID())
+		if e == nil {
+			t.Fatalf("missing suggested edge: %s -> %s", s.RepoUID, s.TopicUID)
+		}
+
+		me := e.(*memory.Edge)
+		if me.Label() != stars.SuggestedTopicEdgeLabel || me.Weight() != s.Confidence || me.Attrs()[ConfidenceAttr] != s.Confidence {
+			t.Errorf("unexpected suggested edge: %s %f %v", me.Label(), me.Weight(), me.Attrs())
+		}
+	}
+
+	// suggested topics are not treated as repo topics
+	again, err := Topics(g)
+	if err != nil {
+		t.Fatalf("failed to suggest topics: %v", err)
+	}
+
+	if !reflect.DeepEqual(again, suggestions) {
+		t.Errorf("expected suggestions: %v, got: %v", suggestions, again)
+	}
+
+	err = Materialise(g, []Suggestion{{RepoUID: "foo", TopicUID: "k8s-Topic"}})
+	if code := graph.ErrorCode(err); code != graph.ENOTFOUND {
+		t.Errorf("expected error: %s, got: %v", graph.ENOTFOUND, err)
+	}
+}
+
+func TestTopicsErrors(t *testing.T) {
+	g := MustGraph(t)
+
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{"MaxTopics", []Option{WithMaxTopics(-1)}},
+		{"MinConfidence", []Option{WithMinConfidence(2)}},
+		{"KeywordConfidence", []Option{WithKeywordConfidence(1)}},
+	}
+
+	for _, tc := range testCases {
+		if _, err := Topics(g, tc.opts...); graph.ErrorCode(err) != graph.EINVALID {
+			t.Errorf("%s: expected error: %s, got: %v", tc.name, graph.EINVALID, err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package projection
+
+// Weighting is projected edge weighting.
+type Weighting string
+
+const (
+	// Count weights edges by the number of shared neighbours.
+	Count Weighting = "count"
+	// Jaccard weights edges by Jaccard similarity of the node neighbourhoods.
+	Jaccard Weighting = "jaccard"
+	// Newman weights edges by Newman's collaboration weighting:
+	// every shared neighbour with degree k contributes 1/(k-1).
+	Newman Weighting = "newman"
+)
+
+// IsValid returns true if w is a supported weighting.
+func (w Weighting) IsValid() bool {
+	switch w {
+	case Count, Jaccard, Newman:
+		return true
+	}
+	return false
+}
+
+// Options configure projection.
+type Options struct {
+	// Weighting is projected edge weighting.
+	Weighting Weighting
+	// Label is projected graph label.
+	Label string
+}
+
+// Option is functional projection option.
+type Option func(*Options)
+
+// WithWeighting sets Weighting option.
+func WithWeighting(w Weighting) Option {
+	return func(o *Options) {
+		o.Weighting = w
+	}
+}
+
+// WithLabel sets Label option.
+func WithLabel(l string) Option {
+	return func(o *Options) {
+		o.Label = l
+	}
+}
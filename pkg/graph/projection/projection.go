@@ -0,0 +1,187 @@
+package projection
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	gonum "gonum.org/v1/gonum/graph"
+)
+
+const (
+	// CountAttr is the name of projected edge attribute which stores the number of shared neighbours.
+	CountAttr = "count"
+	// WeightAttr is the name of projected edge attribute which stores the edge weight.
+	WeightAttr = "weight"
+	// RelationAttr is the name of projected edge attribute which stores the via label.
+	RelationAttr = "relation"
+)
+
+// Parse parses projection specification in the form of "target:via", e.g. Topic:Repo.
+func Parse(spec string) (target, via string, err error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", graph.Errorf(graph.EINVALID, "invalid projection: %q", spec)
+	}
+	return parts[0], parts[1], nil
+}
+
+// ParseWeighting parses projected edge weighting.
+func ParseWeighting(s string) (Weighting, error) {
+	w := Weighting(strings.TrimSpace(s))
+	if !w.IsValid() {
+		return "", graph.Errorf(graph.EINVALID, "unsupported weighting: %q", s)
+	}
+	return w, nil
+}
+
+// Project returns one-mode projection of the nodes of g with target label via the nodes with via label.
+// Two target nodes are connected by an undirected edge if they share at least one via neighbour.
+// Edge directions in g are ignored. Projected edges are labeled with via label, their weights
+// are set according to the Weighting option and stored in their weight attribute along with
+// the number of shared neighbours stored in their count attribute. Projected nodes are copies of the target nodes of g with the same IDs.
+func Project(g graph.Graph, target, via string, opts ...Option) (*memory.Graph, error) {
+	popts := Options{
+		Weighting: Count,
+		Label:     g.Label(),
+	}
+
+	for _, apply := range opts {
+		apply(&popts)
+	}
+
+	if !popts.Weighting.IsValid() {
+		return nil, graph.Errorf(graph.EINVALID, "unsupported weighting: %q", popts.Weighting)
+	}
+
+	if target == via {
+		return nil, graph.Errorf(graph.EINVALID, "target and via labels must differ: %q", target)
+	}
+
+	pg, err := memory.NewGraph(
+		memory.WithType(graph.WeightedUndirected),
+		memory.WithLabel(popts.Label),
+		memory.WithAttrs(attrs.CopyFrom(g.Attrs())),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// neighbours maps via nodes to their target neighbours
+	neighbours := make(map[int64][]int64)
+	// degrees stores the number of via neighbours of target nodes
+	degrees := make(map[int64]int)
+
+	dg, isDirected := g.(gonum.Directed)
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n, ok := nodes.Node().(graph.Node)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid node in graph %s", g.UID())
+		}
+
+		if n.Label() != target {
+			continue
+		}
+
+		node, err := memory.NewNodeFrom(n.ID(), n)
+		if err != nil {
+			return nil, err
+		}
+		pg.AddNode(node)
+
+		seen := make(map[int64]bool)
+		visit := func(it gonum.Nodes) {
+			for it.Next() {
+				v, ok := it.Node().(graph.Node)
+				if !ok || v.Label() != via || seen[v.ID()] {
+					continue
+				}
+				seen[v.ID()] = true
+				neighbours[v.ID()] = append(neighbours[v.ID()], n.ID())
+			}
+		}
+
+		visit(g.From(n.ID()))
+		if isDirected {
+			visit(dg.To(n.ID()))
+		}
+		degrees[n.ID()] = len(seen)
+	}
+
+	type pair [2]int64
+
+	counts := make(map[pair]int)
+	newman := make(map[pair]float64)
+
+	for _, ids := range neighbours {
+		if len(ids) < 2 {
+			continue
+		}
+
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+		w := 1 / float64(len(ids)-1)
+		for i := range ids {
+			for j := i + 1; j < len(ids); j++ {
+				p := pair{ids[i], ids[j]}
+				counts[p]++
+				newman[p] += w
+			}
+		}
+	}
+
+	pairs := make([]pair, 0, len(counts))
+	for p := range counts {
+		pairs = append(pairs, p)
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+
+	for _, p := range pairs {
+		count := counts[p]
+
+		var weight float64
+		switch popts.Weighting {
+		case Count:
+			weight = float64(count)
+		case Jaccard:
+			weight = float64(count) / float64(degrees[p[0]]+degrees[p[1]]-count)
+		case Newman:
+			weight = newman[p]
+		}
+
+		edge, err := memory.NewEdge(pg.Node(p[0]), pg.Node(p[1]),
+			memory.WithLabel(via),
+			memory.WithWeight(weight),
+			memory.WithAttrs(map[string]interface{}{
+				RelationAttr: via,
+				WeightAttr:   weight,
+				CountAttr:    int64(count),
+			}),
+		)
+		if err != nil {
+			return nil, err
+		}
+		pg.SetWeightedEdge(edge)
+	}
+
+	return pg, nil
+}
+
+// Schema returns the attribute schema of projected edges.
+func Schema() attrs.Schema {
+	return attrs.Schema{
+		RelationAttr: attrs.String,
+		WeightAttr:   attrs.Float,
+		CountAttr:    attrs.Int,
+	}
+}
@@ -0,0 +1,182 @@
+package projection
+
+import (
+	"math"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/api/memory/marshal/json"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/cytoscape"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/dot"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/gexf"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/networkx"
+	"github.com/milosgajdos/orbnet/pkg/graph/marshal/sigma"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+// MustGraph returns the following graph:
+//
+//	r1 -> a, r1 -> b, r2 -> a, r2 -> b, r2 -> c, r3 -> c, r1 -> go
+func MustGraph(t *testing.T) (*memory.Graph, map[string]int64) {
+	t.Helper()
+
+	g, err := memory.NewGraph(memory.WithLabel("test"))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	nodes := []struct {
+		uid   string
+		label string
+	}{
+		{"r1", "Repo"}, {"r2", "Repo"}, {"r3", "Repo"},
+		{"a", "Topic"}, {"b", "Topic"}, {"c", "Topic"},
+		{"go", "Lang"},
+	}
+
+	ns := make(map[string]*memory.Node)
+	ids := make(map[string]int64)
+	for _, tn := range nodes {
+		n, err := memory.NewNode(g.NewNode().ID(),
+			memory.WithUID(tn.uid),
+			memory.WithLabel(tn.label),
+			memory.WithAttrs(map[string]interface{}{"name": tn.uid}),
+		)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(n)
+		ns[tn.uid] = n
+		ids[tn.uid] = n.ID()
+	}
+
+	for _, te := range [][2]string{
+		{"r1", "a"}, {"r1", "b"},
+		{"r2", "a"}, {"r2", "b"}, {"r2", "c"},
+		{"r3", "c"}, {"r1", "go"},
+	} {
+		e, err := memory.NewEdge(ns[te[0]], ns[te[1]])
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(e)
+	}
+
+	return g, ids
+}
+
+func TestProject(t *testing.T) {
+	testCases := []struct {
+		weighting Weighting
+		exp       map[[2]string]float64
+	}{
+		{Count, map[[2]string]float64{{"a", "b"}: 2, {"a", "c"}: 1, {"b", "c"}: 1}},
+		{Jaccard, map[[2]string]float64{{"a", "b"}: 1, {"a", "c"}: 1.0 / 3.0, {"b", "c"}: 1.0 / 3.0}},
+		{Newman, map[[2]string]float64{{"a", "b"}: 1.5, {"a", "c"}: 0.5, {"b", "c"}: 0.5}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(string(tc.weighting), func(t *testing.T) {
+			g, ids := MustGraph(t)
+
+			pg, err := Project(g, "Topic", "Repo", WithWeighting(tc.weighting))
+			if err != nil {
+				t.Fatalf("failed to project graph: %v", err)
+			}
+
+			if typ := pg.Type(); typ != graph.WeightedUndirected {
+				t.Errorf("expected graph type: %s, got: %s", graph.WeightedUndirected, typ)
+			}
+
+			if n := pg.Nodes().Len(); n != 3 {
+				t.Errorf("expected nodes: %d, got: %d", 3, n)
+			}
+
+			if n := pg.Edges().Len(); n != len(tc.exp) {
+				t.Errorf("expected edges: %d, got: %d", len(tc.exp), n)
+			}
+
+			for p, want := range tc.exp {
+				e, ok := pg.Edge(ids[p[1]], ids[p[0]]).(graph.Edge)
+				if !ok {
+					t.Fatalf("missing edge %s-%s", p[0], p[1])
+				}
+				if got := e.Weight(); math.Abs(got-want) > 1e-9 {
+					t.Errorf("edge %s-%s: expected weight: %f, got: %f", p[0], p[1], want, got)
+				}
+				if got := e.Attrs()[WeightAttr]; got != e.Weight() {
+					t.Errorf("edge %s-%s: expected weight attribute: %f, got: %v", p[0], p[1], e.Weight(), got)
+				}
+				if e.Label() != "Repo" {
+					t.Errorf("edge %s-%s: expected label: %s, got: %s", p[0], p[1], "Repo", e.Label())
+				}
+			}
+
+			count := pg.Edge(ids["a"], ids["b"]).(graph.Edge).Attrs()[CountAttr]
+			if count != int64(2) {
+				t.Errorf("expected count: %d, got: %v", 2, count)
+			}
+		})
+	}
+}
+
+func TestProjectErrors(t *testing.T) {
+	g, _ := MustGraph(t)
+
+	if _, err := Project(g, "Topic", "Repo", WithWeighting("foo")); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+
+	if _, err := Project(g, "Topic", "Topic"); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+
+	for _, spec := range []string{"Topic", "Topic:", ":Repo", "Topic:Repo:Lang"} {
+		if _, _, err := Parse(spec); graph.ErrorCode(err) != graph.EINVALID {
+			t.Errorf("%s: expected error: %s, got: %v", spec, graph.EINVALID, err)
+		}
+	}
+
+	target, via, err := Parse("Topic:Repo")
+	if err != nil || target != "Topic" || via != "Repo" {
+		t.Errorf("failed to parse projection: %s, %s, %v", target, via, err)
+	}
+
+	if _, err := ParseWeighting("foo"); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+
+	if w, err := ParseWeighting("jaccard"); err != nil || w != Jaccard {
+		t.Errorf("failed to parse weighting: %s, %v", w, err)
+	}
+}
+
+func TestProjectMarshal(t *testing.T) {
+	g, _ := MustGraph(t)
+
+	pg, err := Project(g, "Topic", "Repo", WithWeighting(Jaccard))
+	if err != nil {
+		t.Fatalf("failed to project graph: %v", err)
+	}
+
+	newMarshalers := map[string]func(name, prefix, indent string) (graph.Marshaler, error){
+		"dot":       func(n, p, i string) (graph.Marshaler, error) { return dot.NewMarshaler(n, p, i) },
+		"gexf":      func(n, p, i string) (graph.Marshaler, error) { return gexf.NewMarshaler(n, p, i) },
+		"cytoscape": func(n, p, i string) (graph.Marshaler, error) { return cytoscape.NewMarshaler(n, p, i) },
+		"sigma":     func(n, p, i string) (graph.Marshaler, error) { return sigma.NewMarshaler(n, p, i) },
+		"networkx":  func(n, p, i string) (graph.Marshaler, error) { return networkx.NewMarshaler(n, p, i) },
+		"jsonapi":   func(n, p, i string) (graph.Marshaler, error) { return json.NewMarshaler(n, p, i) },
+	}
+
+	for format, newMarshaler := range newMarshalers {
+		m, err := newMarshaler("test", "", "")
+		if err != nil {
+			t.Fatalf("failed to create %s marshaler: %v", format, err)
+		}
+
+		if _, err := m.Marshal(pg); err != nil {
+			t.Errorf("failed to marshal graph to %s: %v", format, err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package recommend
+
+import (
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/builder/stars"
+)
+
+const (
+	// DefaultLimit is the default number of recommendations.
+	DefaultLimit = 10
+	// DefaultPaths is the default number of explanation paths of each recommendation.
+	DefaultPaths = 3
+	// DefaultDamping is the default personalised PageRank damping factor.
+	DefaultDamping = 0.85
+	// DefaultTolerance is the default tolerance of personalised PageRank iterations.
+	DefaultTolerance = 1e-8
+)
+
+// Options configure recommendations.
+type Options struct {
+	// Method is scoring method.
+	Method Method
+	// Label is the label of recommended nodes.
+	Label string
+	// Via are the labels of the nodes which link recommended nodes.
+	// If empty, the nodes with any label link recommended nodes.
+	Via []string
+	// Limit is the maximum number of recommendations.
+	// If it's not positive all the candidates are returned.
+	Limit int
+	// Paths is the maximum number of explanation paths of each recommendation.
+	Paths int
+	// Damping is personalised PageRank damping factor.
+	Damping float64
+	// Tolerance terminates personalised PageRank iterations.
+	Tolerance float64
+}
+
+// Option is functional recommendation option.
+type Option func(*Options)
+
+// WithMethod sets Method option.
+func WithMethod(m Method) Option {
+	return func(o *Options) {
+		o.Method = m
+	}
+}
+
+// WithLabel sets Label option.
+func WithLabel(l string) Option {
+	return func(o *Options) {
+		o.Label = l
+	}
+}
+
+// WithVia sets Via option.
+func WithVia(labels ...string) Option {
+	return func(o *Options) {
+		o.Via = labels
+	}
+}
+
+// WithLimit sets Limit option.
+func WithLimit(n int) Option {
+	return func(o *Options) {
+		o.Limit = n
+	}
+}
+
+// WithPaths sets Paths option.
+func WithPaths(n int) Option {
+	return func(o *Options) {
+		o.Paths = n
+	}
+}
+
+// WithDamping sets Damping option.
+func WithDamping(d float64) Option {
+	return func(o *Options) {
+		o.Damping = d
+	}
+}
+
+// WithTolerance sets Tolerance option.
+func WithTolerance(t float64) Option {
+	return func(o *Options) {
+		o.Tolerance = t
+	}
+}
+
+func newOptions(opts ...Option) (Options, error) {
+	ropts := Options{
+		Method:    PageRank,
+		Label:     stars.RepoEntity.String(),
+		Limit:     DefaultLimit,
+		Paths:     DefaultPaths,
+		Damping:   DefaultDamping,
+		Tolerance: DefaultTolerance,
+	}
+
+	for _, apply := range opts {
+		apply(&ropts)
+	}
+
+	switch ropts.Method {
+	case PageRank, AdamicAdar, CommonNeighbours:
+	default:
+		return Options{}, graph.Errorf(graph.EINVALID, "unsupported method: %q", ropts.Method)
+	}
+
+	if ropts.Label == "" {
+		return Options{}, graph.Errorf(graph.EINVALID, "empty label")
+	}
+
+	if ropts.Paths < 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid number of paths: %d", ropts.Paths)
+	}
+
+	if ropts.Damping <= 0 || ropts.Damping >= 1 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid damping factor: %f", ropts.Damping)
+	}
+
+	if ropts.Tolerance <= 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid tolerance: %f", ropts.Tolerance)
+	}
+
+	return ropts, nil
+}
@@ -0,0 +1,415 @@
+package recommend
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	gonum "gonum.org/v1/gonum/graph"
+)
+
+// Method is recommendation scoring method.
+type Method string
+
+const (
+	// PageRank scores candidates by personalised PageRank seeded by the seed nodes.
+	PageRank Method = "pagerank"
+	// AdamicAdar scores candidates by Adamic-Adar index of their shared neighbours with the seed nodes.
+	AdamicAdar Method = "adamic-adar"
+	// CommonNeighbours scores candidates by the number of their shared neighbours with the seed nodes.
+	CommonNeighbours Method = "common"
+)
+
+const (
+	// maxIterations limits the number of personalised PageRank iterations.
+	maxIterations = 1000
+)
+
+// Hop is a node on explanation path.
+type Hop struct {
+	// UID is node UID.
+	UID string `json:"uid"`
+	// Label is node label.
+	Label string `json:"label"`
+	// Name is node name.
+	Name string `json:"name"`
+}
+
+// Path explains recommendation by a path from a seed node
+// to the recommended node through their shared neighbour.
+type Path struct {
+	// Hops are the nodes on the path.
+	Hops []Hop `json:"hops"`
+	// Score is the contribution of the path to Adamic-Adar score.
+	Score float64 `json:"score"`
+}
+
+// String returns the path formatted as node names followed by their labels.
+func (p Path) String() string {
+	hops := make([]string, len(p.Hops))
+	for i, h := range p.Hops {
+		hops[i] = h.Name + " (" + h.Label + ")"
+	}
+	return strings.Join(hops, " - ")
+}
+
+// Recommendation is a recommended node.
+type Recommendation struct {
+	// UID is node UID.
+	UID string `json:"uid"`
+	// Label is node label.
+	Label string `json:"label"`
+	// Name is node name.
+	Name string `json:"name"`
+	// Score is recommendation score.
+	Score float64 `json:"score"`
+	// Paths explain the recommendation.
+	Paths []Path `json:"paths"`
+}
+
+// Recommend ranks the nodes of g with the Label option which are related to the seed nodes with the given UIDs.
+// Seed nodes which are not found by their UIDs are looked up by their full_name or name attributes.
+// Seed nodes with a different label, such as owners or users, are replaced by their neighbours with the Label option,
+// which makes it possible to recommend repos to the users linked to their starred repos in multi-user graphs.
+// Candidates are linked to the seeds via their shared neighbours with Via option labels; in multi-user graphs
+// this includes the users who starred both the seed and the candidate repos. Edge directions are ignored.
+// Recommendations are ordered by their score and each of them is explained by the paths
+// from the seed nodes through the shared neighbours with the highest Adamic-Adar contributions.
+func Recommend(g graph.Graph, uids []string, opts ...Option) ([]Recommendation, error) {
+	ropts, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(uids) == 0 {
+		return nil, graph.Errorf(graph.EINVALID, "no seed nodes")
+	}
+
+	v, err := newView(g, ropts)
+	if err != nil {
+		return nil, err
+	}
+
+	seeds, err := v.seeds(uids)
+	if err != nil {
+		return nil, err
+	}
+
+	scores, paths := v.neighbourScores(seeds, ropts.Method)
+
+	if ropts.Method == PageRank {
+		scores = v.pageRank(seeds, ropts)
+	}
+
+	var recs []Recommendation
+	for id, score := range scores {
+		if seeds[id] || score <= 0 {
+			continue
+		}
+
+		n := v.nodes[id]
+		if n.Label() != ropts.Label {
+			continue
+		}
+
+		ps := paths[id]
+		if ps == nil {
+			ps = []Path{}
+		}
+		sort.SliceStable(ps, func(i, j int) bool {
+			return ps[i].Score > ps[j].Score
+		})
+		if len(ps) > ropts.Paths {
+			ps = ps[:ropts.Paths]
+		}
+
+		recs = append(recs, Recommendation{
+			UID:   n.UID(),
+			Label: n.Label(),
+			Name:  graph.NodeName(n),
+			Score: score,
+			Paths: ps,
+		})
+	}
+
+	sort.Slice(recs, func(i, j int) bool {
+		if recs[i].Score != recs[j].Score {
+			return recs[i].Score > recs[j].Score
+		}
+		if recs[i].Name != recs[j].Name {
+			return recs[i].Name < recs[j].Name
+		}
+		return recs[i].UID < recs[j].UID
+	})
+
+	if ropts.Limit > 0 && len(recs) > ropts.Limit {
+		recs = recs[:ropts.Limit]
+	}
+
+	return recs, nil
+}
+
+// view is an undirected view of graph restricted to candidate and via nodes.
+type view struct {
+	// nodes indexes graph nodes by their IDs.
+	nodes map[int64]graph.Node
+	// uids indexes graph nodes by their UIDs.
+	uids map[string]graph.Node
+	// names indexes graph nodes by their names.
+	names map[string][]graph.Node
+	// adj stores ID sorted neighbours of all graph nodes.
+	adj map[int64][]int64
+	// weights stores summed weights of the edges between graph nodes.
+	weights map[int64]map[int64]float64
+	// label is candidate label.
+	label string
+	// via are via labels.
+	via map[string]bool
+}
+
+func newView(g graph.Graph, opts Options) (*view, error) {
+	v := &view{
+		nodes:   make(map[int64]graph.Node),
+		uids:    make(map[string]graph.Node),
+		names:   make(map[string][]graph.Node),
+		adj:     make(map[int64][]int64),
+		weights: make(map[int64]map[int64]float64),
+		label:   opts.Label,
+		via:     make(map[string]bool, len(opts.Via)),
+	}
+
+	for _, l := range opts.Via {
+		v.via[l] = true
+	}
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n, ok := nodes.Node().(graph.Node)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid node in graph %s", g.UID())
+		}
+		v.nodes[n.ID()] = n
+		v.uids[n.UID()] = n
+		v.names[graph.NodeName(n)] = append(v.names[graph.NodeName(n)], n)
+	}
+
+	edges := g.Edges()
+	for edges.Next() {
+		e := edges.Edge()
+		from, to := e.From().ID(), e.To().ID()
+
+		w := 1.0
+		if we, ok := e.(gonum.WeightedEdge); ok {
+			w = we.Weight()
+		}
+
+		if opts.Method == PageRank && w < 0 {
+			return nil, graph.Errorf(graph.EINVALID, "negative edge weight: %f", w)
+		}
+
+		v.link(from, to, w)
+		v.link(to, from, w)
+	}
+
+	for id := range v.adj {
+		ids := v.adj[id]
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	}
+
+	return v, nil
+}
+
+func (v *view) link(from, to int64, w float64) {
+	if _, ok := v.weights[from]; !ok {
+		v.weights[from] = make(map[int64]float64)
+	}
+
+	if _, ok := v.weights[from][to]; !ok {
+		v.adj[from] = append(v.adj[from], to)
+	}
+	v.weights[from][to] += w
+}
+
+// isVia returns true if the node with the given id links candidates.
+func (v *view) isVia(id int64) bool {
+	l := v.nodes[id].Label()
+	return l != v.label && (len(v.via) == 0 || v.via[l])
+}
+
+// allowed returns true if the node with the given id is either a candidate or via node.
+func (v *view) allowed(id int64) bool {
+	return v.nodes[id].Label() == v.label || v.isVia(id)
+}
+
+// degree returns the number of allowed neighbours of the node with the given id.
+func (v *view) degree(id int64) int {
+	var d int
+	for _, nid := range v.adj[id] {
+		if v.allowed(nid) {
+			d++
+		}
+	}
+	return d
+}
+
+// node returns the node with the given UID or the only node with the given name.
+func (v *view) node(uid string) (graph.Node, error) {
+	if n, ok := v.uids[uid]; ok {
+		return n, nil
+	}
+
+	switch nodes := v.names[uid]; len(nodes) {
+	case 0:
+		return nil, graph.Errorf(graph.ENOTFOUND, "node %s not found", uid)
+	case 1:
+		return nodes[0], nil
+	default:
+		return nil, graph.Errorf(graph.EINVALID, "ambiguous node name: %s", uid)
+	}
+}
+
+// seeds returns IDs of the seed nodes with the given UIDs or names.
+// Seed nodes without candidate label are replaced by their neighbours with candidate label.
+func (v *view) seeds(uids []string) (map[int64]bool, error) {
+	seeds := make(map[int64]bool)
+
+	for _, uid := range uids {
+		n, err := v.node(uid)
+		if err != nil {
+			return nil, err
+		}
+
+		if n.Label() == v.label {
+			seeds[n.ID()] = true
+			continue
+		}
+
+		for _, id := range v.adj[n.ID()] {
+			if v.nodes[id].Label() == v.label {
+				seeds[id] = true
+			}
+		}
+	}
+
+	if len(seeds) == 0 {
+		return nil, graph.Errorf(graph.EINVALID, "no seed nodes with label %s", v.label)
+	}
+
+	return seeds, nil
+}
+
+// neighbourScores scores candidates by their shared via neighbours with the seeds.
+// It returns Adamic-Adar scores unless method is CommonNeighbours and the paths
+// through the shared neighbours scored by their Adamic-Adar contributions.
+func (v *view) neighbourScores(seeds map[int64]bool, method Method) (map[int64]float64, map[int64][]Path) {
+	scores := make(map[int64]float64)
+	paths := make(map[int64][]Path)
+
+	for _, sid := range graph.SortedIDs(seeds) {
+		for _, vid := range v.adj[sid] {
+			if !v.isVia(vid) {
+				continue
+			}
+
+			// NOTE: via node has at least two neighbours: the seed and the candidate
+			w := 1 / math.Log(float64(v.degree(vid)))
+
+			for _, cid := range v.adj[vid] {
+				if seeds[cid] || v.nodes[cid].Label() != v.label {
+					continue
+				}
+
+				if method == CommonNeighbours {
+					scores[cid]++
+				} else {
+					scores[cid] += w
+				}
+
+				paths[cid] = append(paths[cid], Path{
+					Hops:  []Hop{hop(v.nodes[sid]), hop(v.nodes[vid]), hop(v.nodes[cid])},
+					Score: w,
+				})
+			}
+		}
+	}
+
+	return scores, paths
+}
+
+// pageRank computes personalised PageRank of candidate and via nodes
+// with the teleportation distributed uniformly among the seeds.
+func (v *view) pageRank(seeds map[int64]bool, opts Options) map[int64]float64 {
+	var ids []int64
+	for id := range v.nodes {
+		if v.allowed(id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	// out stores the summed weights of outgoing edges of the nodes
+	out := make(map[int64]float64, len(ids))
+	for _, id := range ids {
+		for _, nid := range v.adj[id] {
+			if v.allowed(nid) {
+				out[id] += v.weights[id][nid]
+			}
+		}
+	}
+
+	p := 1 / float64(len(seeds))
+
+	rank := make(map[int64]float64, len(ids))
+	for id := range seeds {
+		rank[id] = p
+	}
+
+	for i := 0; i < maxIterations; i++ {
+		next := make(map[int64]float64, len(ids))
+
+		var dangling float64
+		for _, id := range ids {
+			r := rank[id]
+			if r == 0 {
+				continue
+			}
+
+			if out[id] == 0 {
+				dangling += r
+				continue
+			}
+
+			for _, nid := range v.adj[id] {
+				if v.allowed(nid) {
+					next[nid] += opts.Damping * r * v.weights[id][nid] / out[id]
+				}
+			}
+		}
+
+		for id := range seeds {
+			next[id] += ((1 - opts.Damping) + opts.Damping*dangling) * p
+		}
+
+		var delta float64
+		for _, id := range ids {
+			delta += math.Abs(next[id] - rank[id])
+		}
+
+		rank = next
+
+		if delta < opts.Tolerance {
+			break
+		}
+	}
+
+	return rank
+}
+
+func hop(n graph.Node) Hop {
+	return Hop{
+		UID:   n.UID(),
+		Label: n.Label(),
+		Name:  graph.NodeName(n),
+	}
+}
@@ -0,0 +1,193 @@
+package recommend
+
+import (
+	"math"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+const tol = 1e-6
+
+// MustGraph returns the following graph of repos linked to their topics, languages and owners:
+//
+//	r1 -> k8s, cloud, go, o1
+//	r2 -> k8s, cloud, go
+//	r3 -> cloud, python
+//	r4 -> rust, o1
+//	r5 -> python, go topic
+func MustGraph(t *testing.T) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	nodes := []struct {
+		uid   string
+		name  string
+		label string
+	}{
+		{"r1", "r1", "Repo"}, {"r2", "r2", "Repo"}, {"r3", "r3", "Repo"},
+		{"r4", "r4", "Repo"}, {"r5", "r5", "Repo"},
+		{"k8s-Topic", "k8s", "Topic"}, {"cloud-Topic", "cloud", "Topic"}, {"go-Topic", "go", "Topic"},
+		{"go-Lang", "go", "Lang"}, {"python-Lang", "python", "Lang"}, {"rust-Lang", "rust", "Lang"},
+		{"o1", "o1", "Owner"},
+	}
+
+	ns := make(map[string]*memory.Node)
+	for _, tn := range nodes {
+		n, err := memory.NewNode(g.NewNode().ID(),
+			memory.WithUID(tn.uid),
+			memory.WithLabel(tn.label),
+			memory.WithAttrs(map[string]interface{}{"name": tn.name}),
+		)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(n)
+		ns[tn.uid] = n
+	}
+
+	for _, te := range [][2]string{
+		{"r1", "k8s-Topic"}, {"r1", "cloud-Topic"}, {"r1", "go-Lang"}, {"r1", "o1"},
+		{"r2", "k8s-Topic"}, {"r2", "cloud-Topic"}, {"r2", "go-Lang"},
+		{"r3", "cloud-Topic"}, {"r3", "python-Lang"},
+		{"r4", "rust-Lang"}, {"r4", "o1"},
+		{"r5", "python-Lang"}, {"r5", "go-Topic"},
+	} {
+		e, err := memory.NewEdge(ns[te[0]], ns[te[1]])
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(e)
+	}
+
+	return g
+}
+
+func assertRecs(t *testing.T, recs []Recommendation, exp []string, scores []float64) {
+	t.Helper()
+
+	if len(recs) != len(exp) {
+		t.Fatalf("expected %d recommendations, got: %d: %v", len(exp), len(recs), recs)
+	}
+
+	for i, uid := range exp {
+		if recs[i].UID != uid {
+			t.Errorf("recommendation %d: expected: %s, got: %s", i, uid, recs[i].UID)
+		}
+		if scores != nil && math.Abs(recs[i].Score-scores[i]) > tol {
+			t.Errorf("recommendation %s: expected score: %f, got: %f", uid, scores[i], recs[i].Score)
+		}
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	ln2, ln3 := math.Log(2), math.Log(3)
+
+	t.Run("Common", func(t *testing.T) {
+		recs, err := Recommend(MustGraph(t), []string{"r1"}, WithMethod(CommonNeighbours))
+		if err != nil {
+			t.Fatalf("failed to recommend: %v", err)
+		}
+		assertRecs(t, recs, []string{"r2", "r3", "r4"}, []float64{3, 1, 1})
+	})
+
+	t.Run("AdamicAdar", func(t *testing.T) {
+		recs, err := Recommend(MustGraph(t), []string{"r1"}, WithMethod(AdamicAdar), WithPaths(2))
+		if err != nil {
+			t.Fatalf("failed to recommend: %v", err)
+		}
+		assertRecs(t, recs, []string{"r2", "r4", "r3"}, []float64{2/ln2 + 1/ln3, 1 / ln2, 1 / ln3})
+
+		paths := recs[0].Paths
+		if len(paths) != 2 {
+			t.Fatalf("expected %d paths, got: %d", 2, len(paths))
+		}
+
+		for _, p := range paths {
+			if math.Abs(p.Score-1/ln2) > tol {
+				t.Errorf("expected path score: %f, got: %f", 1/ln2, p.Score)
+			}
+			if len(p.Hops) != 3 || p.Hops[0].UID != "r1" || p.Hops[2].UID != "r2" {
+				t.Errorf("unexpected path: %s", p)
+			}
+		}
+
+		if s := paths[0].String(); s != "r1 (Repo) - k8s (Topic) - r2 (Repo)" {
+			t.Errorf("unexpected path: %s", s)
+		}
+	})
+
+	t.Run("PageRank", func(t *testing.T) {
+		recs, err := Recommend(MustGraph(t), []string{"r1"}, WithLimit(0))
+		if err != nil {
+			t.Fatalf("failed to recommend: %v", err)
+		}
+
+		if len(recs) != 4 {
+			t.Fatalf("expected %d recommendations, got: %d", 4, len(recs))
+		}
+
+		if recs[0].UID != "r2" || recs[3].UID != "r5" {
+			t.Errorf("unexpected recommendations: %v", recs)
+		}
+
+		if len(recs[3].Paths) != 0 {
+			t.Errorf("expected no paths, got: %v", recs[3].Paths)
+		}
+
+		limited, err := Recommend(MustGraph(t), []string{"r1"}, WithLimit(1))
+		if err != nil {
+			t.Fatalf("failed to recommend: %v", err)
+		}
+		assertRecs(t, limited, []string{"r2"}, []float64{recs[0].Score})
+	})
+
+	t.Run("Via", func(t *testing.T) {
+		recs, err := Recommend(MustGraph(t), []string{"r1"}, WithMethod(AdamicAdar), WithVia("Topic"))
+		if err != nil {
+			t.Fatalf("failed to recommend: %v", err)
+		}
+		assertRecs(t, recs, []string{"r2", "r3"}, []float64{1/ln2 + 1/ln3, 1 / ln3})
+	})
+
+	t.Run("Owner", func(t *testing.T) {
+		recs, err := Recommend(MustGraph(t), []string{"o1"}, WithMethod(CommonNeighbours))
+		if err != nil {
+			t.Fatalf("failed to recommend: %v", err)
+		}
+		assertRecs(t, recs, []string{"r2", "r3"}, []float64{3, 1})
+	})
+}
+
+func TestRecommendErrors(t *testing.T) {
+	g := MustGraph(t)
+
+	testCases := []struct {
+		name string
+		uids []string
+		opts []Option
+		code string
+	}{
+		{"NoSeeds", nil, nil, graph.EINVALID},
+		{"NotFound", []string{"foo"}, nil, graph.ENOTFOUND},
+		{"Ambiguous", []string{"go"}, nil, graph.EINVALID},
+		{"Method", []string{"r1"}, []Option{WithMethod("foo")}, graph.EINVALID},
+		{"Damping", []string{"r1"}, []Option{WithDamping(1)}, graph.EINVALID},
+		{"Paths", []string{"r1"}, []Option{WithPaths(-1)}, graph.EINVALID},
+		{"NoCandidateSeeds", []string{"rust-Lang"}, []Option{WithLabel("Owner")}, graph.EINVALID},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Recommend(g, tc.uids, tc.opts...); graph.ErrorCode(err) != tc.code {
+				t.Errorf("expected error: %s, got: %v", tc.code, err)
+			}
+		})
+	}
+}
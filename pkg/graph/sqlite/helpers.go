@@ -49,6 +49,45 @@ func (n *NullTime) Value() (driver.Value, error) {
 	return (*time.Time)(n).UTC().Format(time.RFC3339), nil
 }
 
+// placeholders returns a comma separated list of n SQL placeholders.
+func placeholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat("?, ", n-1) + "?"
+}
+
+// appendStrings appends strings in s to args and returns the result.
+func appendStrings(args []interface{}, s []string) []interface{} {
+	for _, v := range s {
+		args = append(args, v)
+	}
+	return args
+}
+
+// chunk splits s into chunks of at most size items.
+func chunk(s []string, size int) [][]string {
+	var chunks [][]string
+	for size < len(s) {
+		s, chunks = s[size:], append(chunks, s[:size])
+	}
+	if len(s) > 0 {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
+
+// contains returns true if s contains v.
+func contains(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// AttrsFromString decodes JSON encoded attributes.
 func AttrsFromString(attrString string) (map[string]interface{}, error) {
 	attrs := map[string]interface{}{}
 	if len(attrString) > 0 {
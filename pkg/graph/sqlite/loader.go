@@ -2,29 +2,67 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
-	"time"
+	"strings"
 
 	"github.com/milosgajdos/orbnet/pkg/graph"
 	"github.com/milosgajdos/orbnet/pkg/graph/memory"
 )
 
+const (
+	// nodesStage is progress stage reported while loading nodes.
+	nodesStage = "nodes"
+	// edgesStage is progress stage reported while loading edges.
+	edgesStage = "edges"
+)
+
 // Loader loads graph from sqlite DB.
 type Loader struct {
-	db *DB
+	db   *DB
+	opts Options
 }
 
 // NewLoader creates a new loader and returns it.
-func NewLoader(db *DB) (*Loader, error) {
+// By default the loader loads the whole graph.
+// Loaded nodes and edges can be restricted with the given options
+// in which case the loader loads the induced subgraph of the selected nodes.
+func NewLoader(db *DB, opts ...Option) (*Loader, error) {
+	lopts := Options{
+		BatchSize: DefaultBatchSize,
+	}
+
+	for _, apply := range opts {
+		apply(&lopts)
+	}
+
+	if lopts.BatchSize <= 0 {
+		return nil, fmt.Errorf("invalid batch size: %d", lopts.BatchSize)
+	}
+
+	if lopts.Hops < 0 {
+		return nil, fmt.Errorf("invalid hops: %d", lopts.Hops)
+	}
+
 	return &Loader{
-		db: db,
+		db:   db,
+		opts: lopts,
 	}, nil
 }
 
+// loadState tracks the state of a single Load.
+type loadState struct {
+	g     *memory.Graph
+	guid  string
+	nodes map[string]*memory.Node
+	edges int
+}
+
 // Load loads the graph from sqlite DB and returns it.
+// Rows are read in batches; ctx is checked for cancellation between the batches.
 func (l *Loader) Load(ctx context.Context, uid string) (graph.Graph, error) {
-	tx, err := l.db.db.BeginTx(ctx, nil)
+	tx, err := l.db.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
 		return nil, err
 	}
@@ -32,21 +70,19 @@ func (l *Loader) Load(ctx context.Context, uid string) (graph.Graph, error) {
 	defer tx.Rollback()
 
 	var (
+		typ       string
 		label     string
 		attrsJSON string
-		createdAt time.Time
-		updatedAt time.Time
 	)
 
 	err = tx.QueryRowContext(ctx, `
 		SELECT
+			type,
 			label,
-			attrs,
-			created_at,
-			updated_at
+			attrs
 		FROM graphs
 		WHERE uid = ?
-	`, uid).Scan(&label, &attrsJSON, (*NullTime)(&createdAt), (*NullTime)(&updatedAt))
+	`, uid).Scan(&typ, &label, &attrsJSON)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve graph: %w", err)
 	}
@@ -59,6 +95,7 @@ func (l *Loader) Load(ctx context.Context, uid string) (graph.Graph, error) {
 	// Create the in-memory graph
 	g, err := memory.NewGraph(
 		memory.WithUID(uid),
+		memory.WithType(typ),
 		memory.WithLabel(label),
 		memory.WithAttrs(attrs),
 	)
@@ -66,126 +103,474 @@ func (l *Loader) Load(ctx context.Context, uid string) (graph.Graph, error) {
 		return nil, fmt.Errorf("failed to create in-memory graph: %w", err)
 	}
 
-	// Retrieve nodes
-	rows, err := tx.QueryContext(ctx, `
+	s := &loadState{
+		g:     g,
+		guid:  uid,
+		nodes: make(map[string]*memory.Node),
+	}
+
+	if len(l.opts.Seeds) > 0 {
+		if err := l.loadNeighbourhood(ctx, tx, s); err != nil {
+			return nil, err
+		}
+		if err := l.loadNodeEdges(ctx, tx, s); err != nil {
+			return nil, err
+		}
+		return g, nil
+	}
+
+	if err := l.loadNodes(ctx, tx, s); err != nil {
+		return nil, err
+	}
+
+	if err := l.loadEdges(ctx, tx, s); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// filtersNodes returns true if the loader loads a subset of graph nodes.
+func (l *Loader) filtersNodes() bool {
+	return len(l.opts.NodeLabels) > 0 || l.opts.NodePredicate != nil || len(l.opts.Seeds) > 0
+}
+
+// progress reports progress if the progress callback has been configured.
+func (l *Loader) progress(stage string, s *loadState) {
+	if l.opts.Progress != nil {
+		l.opts.Progress(Progress{
+			Stage: stage,
+			Nodes: len(s.nodes),
+			Edges: s.edges,
+		})
+	}
+}
+
+// addNode adds the node to the graph if it matches loader filters.
+// It returns true if the node has been added.
+func (l *Loader) addNode(s *loadState, id int64, uid, label, attrsJSON string) (bool, error) {
+	if _, ok := s.nodes[uid]; ok {
+		return true, nil
+	}
+
+	if len(l.opts.NodeLabels) > 0 && !contains(l.opts.NodeLabels, label) {
+		return false, nil
+	}
+
+	attrs, err := AttrsFromString(attrsJSON)
+	if err != nil {
+		return false, err
+	}
+
+	if err := l.opts.Schema.CoerceNode(label, attrs); err != nil {
+		return false, fmt.Errorf("node %s: %w", uid, err)
+	}
+
+	if p := l.opts.NodePredicate; p != nil && !p(label, attrs) {
+		return false, nil
+	}
+
+	node, err := memory.NewNode(id,
+		memory.WithUID(uid),
+		memory.WithLabel(label),
+		memory.WithAttrs(attrs),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create node: %w", err)
+	}
+	s.g.AddNode(node)
+	s.nodes[uid] = node
+
+	return true, nil
+}
+
+// addEdge adds the edge to the graph if it matches loader filters
+// and both of its end nodes have been loaded.
+func (l *Loader) addEdge(s *loadState, uid, source, target, label string, weight float64, attrsJSON string) error {
+	sourceNode, sourceExists := s.nodes[source]
+	targetNode, targetExists := s.nodes[target]
+
+	if !sourceExists || !targetExists {
+		if l.filtersNodes() {
+			return nil
+		}
+		return errors.New("source or target node does not exist")
+	}
+
+	attrs, err := AttrsFromString(attrsJSON)
+	if err != nil {
+		return err
+	}
+
+	if err := l.opts.Schema.CoerceEdge(label, attrs); err != nil {
+		return fmt.Errorf("edge %s: %w", uid, err)
+	}
+
+	if p := l.opts.EdgePredicate; p != nil && !p(label, attrs) {
+		return nil
+	}
+
+	edge, err := memory.NewEdge(sourceNode, targetNode,
+		memory.WithUID(uid),
+		memory.WithLabel(label),
+		memory.WithWeight(weight),
+		memory.WithAttrs(attrs),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create edge: %w", err)
+	}
+	s.g.SetWeightedEdge(edge)
+	s.edges++
+
+	return nil
+}
+
+// loadNodes streams all graph nodes matching the loader filters in batches.
+func (l *Loader) loadNodes(ctx context.Context, tx *sql.Tx, s *loadState) error {
+	where, args := []string{"graph = ?"}, []interface{}{s.guid}
+	if labels := l.opts.NodeLabels; len(labels) > 0 {
+		where, args = append(where, "label IN ("+placeholders(len(labels))+")"), appendStrings(args, labels)
+	}
+
+	query := `
 		SELECT
 			id,
 			uid,
 			label,
-			attrs,
-			created_at,
-			updated_at
+			attrs
 		FROM nodes
-		WHERE graph = ?
-	`, uid)
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve nodes: %w", err)
+		WHERE ` + strings.Join(where, " AND ") + ` AND id > ?
+		ORDER BY id
+		LIMIT ?`
+
+	var lastID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, query, append(args, lastID, l.opts.BatchSize)...)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve nodes: %w", err)
+		}
+
+		n := 0
+		err = scanNodes(rows, func(id int64, uid, label, attrs string) error {
+			n++
+			lastID = id
+			_, err := l.addNode(s, id, uid, label, attrs)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		l.progress(nodesStage, s)
+
+		if n < l.opts.BatchSize {
+			return nil
+		}
 	}
-	defer rows.Close()
+}
 
-	nodeMap := make(map[string]*memory.Node) // Map to store nodes by their UID
+// loadEdges streams all graph edges matching the loader filters in batches.
+func (l *Loader) loadEdges(ctx context.Context, tx *sql.Tx, s *loadState) error {
+	where, args := []string{"graph = ?"}, []interface{}{s.guid}
+	if labels := l.opts.EdgeLabels; len(labels) > 0 {
+		where, args = append(where, "label IN ("+placeholders(len(labels))+")"), appendStrings(args, labels)
+	}
 
-	for rows.Next() {
-		var (
-			id            int64
-			nodeUID       string
-			nodeLabel     string
-			nodeAttrsJSON string
-			createdAt     time.Time
-			updatedAt     time.Time
-		)
+	query := `
+		SELECT
+			rowid,
+			uid,
+			source,
+			target,
+			label,
+			weight,
+			attrs
+		FROM edges
+		WHERE ` + strings.Join(where, " AND ") + ` AND rowid > ?
+		ORDER BY rowid
+		LIMIT ?`
 
-		if err := rows.Scan(&id, &nodeUID, &nodeLabel, &nodeAttrsJSON, (*NullTime)(&createdAt), (*NullTime)(&updatedAt)); err != nil {
-			return nil, fmt.Errorf("failed to scan node: %w", err)
+	var lastID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
 
-		nodeAttrs, err := AttrsFromString(nodeAttrsJSON)
+		rows, err := tx.QueryContext(ctx, query, append(args, lastID, l.opts.BatchSize)...)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("failed to retrieve edges: %w", err)
 		}
 
-		// Create node and add it to the graph
-		node, err := memory.NewNode(id,
-			memory.WithUID(nodeUID),
-			memory.WithLabel(nodeLabel),
-			memory.WithAttrs(nodeAttrs),
-		)
+		n := 0
+		err = scanEdges(rows, func(rowID int64, uid, source, target, label string, weight float64, attrs string) error {
+			n++
+			lastID = rowID
+			return l.addEdge(s, uid, source, target, label, weight, attrs)
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to create node: %w", err)
+			return err
+		}
+
+		l.progress(edgesStage, s)
+
+		if n < l.opts.BatchSize {
+			return nil
 		}
-		g.AddNode(node)
-		nodeMap[nodeUID] = node
 	}
+}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("row error: %w", err)
+// loadNodeEdges loads the edges outgoing from the already loaded nodes in batches.
+func (l *Loader) loadNodeEdges(ctx context.Context, tx *sql.Tx, s *loadState) error {
+	uids := make([]string, 0, len(s.nodes))
+	for uid := range s.nodes {
+		uids = append(uids, uid)
 	}
 
-	// Retrieve edges
-	edgeRows, err := tx.QueryContext(ctx, `
+	for _, batch := range chunk(uids, l.opts.BatchSize) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		where, args := []string{"graph = ?"}, []interface{}{s.guid}
+		where, args = append(where, "source IN ("+placeholders(len(batch))+")"), appendStrings(args, batch)
+		if labels := l.opts.EdgeLabels; len(labels) > 0 {
+			where, args = append(where, "label IN ("+placeholders(len(labels))+")"), appendStrings(args, labels)
+		}
+
+		rows, err := tx.QueryContext(ctx, `
+			SELECT
+				rowid,
+				uid,
+				source,
+				target,
+				label,
+				weight,
+				attrs
+			FROM edges
+			WHERE `+strings.Join(where, " AND "), args...)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve edges: %w", err)
+		}
+
+		err = scanEdges(rows, func(_ int64, uid, source, target, label string, weight float64, attrs string) error {
+			return l.addEdge(s, uid, source, target, label, weight, attrs)
+		})
+		if err != nil {
+			return err
+		}
+
+		l.progress(edgesStage, s)
+	}
+
+	return nil
+}
+
+// loadNeighbourhood loads all nodes at most Hops edges away from the seed nodes.
+// Edge direction is ignored when traversing the graph.
+// Nodes which do not match the loader filters are not traversed.
+func (l *Loader) loadNeighbourhood(ctx context.Context, tx *sql.Tx, s *loadState) error {
+	seen := make(map[string]struct{})
+	for _, uid := range l.opts.Seeds {
+		seen[uid] = struct{}{}
+	}
+
+	frontier, err := l.loadNodesByUID(ctx, tx, s, l.opts.Seeds)
+	if err != nil {
+		return err
+	}
+
+	for hop := 0; hop < l.opts.Hops && len(frontier) > 0; hop++ {
+		var next []string
+
+		for _, batch := range chunk(frontier, l.opts.BatchSize) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			neighbours, err := l.neighbours(ctx, tx, s.guid, batch)
+			if err != nil {
+				return err
+			}
+
+			for _, uid := range neighbours {
+				if _, ok := seen[uid]; ok {
+					continue
+				}
+				seen[uid] = struct{}{}
+				next = append(next, uid)
+			}
+		}
+
+		frontier, err = l.loadNodesByUID(ctx, tx, s, next)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// neighbours returns the UIDs of the nodes adjacent to the nodes with the given uids.
+func (l *Loader) neighbours(ctx context.Context, tx *sql.Tx, guid string, uids []string) ([]string, error) {
+	where, args := []string{"graph = ?"}, []interface{}{guid}
+	in := placeholders(len(uids))
+	where, args = append(where, "(source IN ("+in+") OR target IN ("+in+"))"), appendStrings(appendStrings(args, uids), uids)
+	if labels := l.opts.EdgeLabels; len(labels) > 0 {
+		where, args = append(where, "label IN ("+placeholders(len(labels))+")"), appendStrings(args, labels)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
 		SELECT
+			rowid,
 			uid,
 			source,
 			target,
 			label,
 			weight,
-			attrs,
-			created_at,
-			updated_at
+			attrs
 		FROM edges
-		WHERE graph = ?
-	`, uid)
+		WHERE `+strings.Join(where, " AND "), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve edges: %w", err)
 	}
-	defer edgeRows.Close()
 
-	for edgeRows.Next() {
-		var (
-			edgeUID       string
-			sourceUID     string
-			targetUID     string
-			edgeLabel     string
-			weight        float64
-			edgeAttrsJSON string
-			createdAt     time.Time
-			updatedAt     time.Time
-		)
+	batch := make(map[string]struct{}, len(uids))
+	for _, uid := range uids {
+		batch[uid] = struct{}{}
+	}
 
-		err := edgeRows.Scan(&edgeUID, &sourceUID, &targetUID, &edgeLabel,
-			&weight, &edgeAttrsJSON, (*NullTime)(&createdAt), (*NullTime)(&updatedAt))
+	var neighbours []string
+	err = scanEdges(rows, func(_ int64, _, source, target, label string, _ float64, attrsJSON string) error {
+		if p := l.opts.EdgePredicate; p != nil {
+			attrs, err := AttrsFromString(attrsJSON)
+			if err != nil {
+				return err
+			}
+			if err := l.opts.Schema.CoerceEdge(label, attrs); err != nil {
+				return err
+			}
+			if !p(label, attrs) {
+				return nil
+			}
+		}
+		if _, ok := batch[source]; ok {
+			neighbours = append(neighbours, target)
+		}
+		if _, ok := batch[target]; ok {
+			neighbours = append(neighbours, source)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return neighbours, nil
+}
+
+// loadNodesByUID loads the nodes with the given uids in batches.
+// It returns the UIDs of the nodes which matched loader filters.
+func (l *Loader) loadNodesByUID(ctx context.Context, tx *sql.Tx, s *loadState, uids []string) ([]string, error) {
+	var loaded []string
+
+	for _, batch := range chunk(uids, l.opts.BatchSize) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		args := appendStrings([]interface{}{s.guid}, batch)
+		rows, err := tx.QueryContext(ctx, `
+			SELECT
+				id,
+				uid,
+				label,
+				attrs
+			FROM nodes
+			WHERE graph = ? AND uid IN (`+placeholders(len(batch))+`)
+			ORDER BY id`, args...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan edge: %w", err)
+			return nil, fmt.Errorf("failed to retrieve nodes: %w", err)
 		}
 
-		edgeAttrs, err := AttrsFromString(edgeAttrsJSON)
+		err = scanNodes(rows, func(id int64, uid, label, attrs string) error {
+			ok, err := l.addNode(s, id, uid, label, attrs)
+			if err != nil {
+				return err
+			}
+			if ok {
+				loaded = append(loaded, uid)
+			}
+			return nil
+		})
 		if err != nil {
 			return nil, err
 		}
 
-		// Create edge and add it to the graph
-		sourceNode, sourceExists := nodeMap[sourceUID]
-		targetNode, targetExists := nodeMap[targetUID]
+		l.progress(nodesStage, s)
+	}
+
+	return loaded, nil
+}
+
+// scanNodes calls fn for every node row and closes rows.
+func scanNodes(rows *sql.Rows, fn func(id int64, uid, label, attrs string) error) error {
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id        int64
+			uid       string
+			label     string
+			attrsJSON string
+		)
+
+		if err := rows.Scan(&id, &uid, &label, &attrsJSON); err != nil {
+			return fmt.Errorf("failed to scan node: %w", err)
+		}
 
-		if !sourceExists || !targetExists {
-			return nil, errors.New("source or target node does not exist")
+		if err := fn(id, uid, label, attrsJSON); err != nil {
+			return err
 		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row error: %w", err)
+	}
 
-		edge, err := memory.NewEdge(sourceNode, targetNode,
-			memory.WithUID(edgeUID),
-			memory.WithLabel(edgeLabel),
-			memory.WithWeight(weight),
-			memory.WithAttrs(edgeAttrs),
+	return nil
+}
+
+// scanEdges calls fn for every edge row and closes rows.
+func scanEdges(rows *sql.Rows, fn func(rowID int64, uid, source, target, label string, weight float64, attrs string) error) error {
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			rowID     int64
+			uid       string
+			source    string
+			target    string
+			label     string
+			weight    float64
+			attrsJSON string
 		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create edge: %w", err)
+
+		if err := rows.Scan(&rowID, &uid, &source, &target, &label, &weight, &attrsJSON); err != nil {
+			return fmt.Errorf("failed to scan edge: %w", err)
+		}
+
+		if err := fn(rowID, uid, source, target, label, weight, attrsJSON); err != nil {
+			return err
 		}
-		g.SetWeightedEdge(edge)
 	}
 
-	if err := edgeRows.Err(); err != nil {
-		return nil, fmt.Errorf("row error: %w", err)
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row error: %w", err)
 	}
 
-	return g, nil
+	return nil
 }
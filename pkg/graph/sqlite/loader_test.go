@@ -3,8 +3,10 @@ package sqlite
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
 	"github.com/milosgajdos/orbnet/pkg/graph/memory"
 )
 
@@ -163,3 +165,356 @@ func TestLoader_Load(t *testing.T) {
 		}
 	}
 }
+
+// MustSyncedGraph syncs a small test graph into db and returns it.
+// The graph contains the following edges:
+// repo1 -HasTopic-> topic, repo1 -IsLanguage-> lang, repo2 -HasTopic-> topic
+func MustSyncedGraph(t *testing.T, db *DB) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create new graph: %v", err)
+	}
+
+	nodes := []struct {
+		uid   string
+		label string
+		attrs map[string]interface{}
+	}{
+		{"repo1", "Repo", map[string]interface{}{"stars": 10}},
+		{"repo2", "Repo", map[string]interface{}{"stars": 1000}},
+		{"topic", "Topic", map[string]interface{}{"name": "go"}},
+		{"lang", "Lang", map[string]interface{}{"name": "go"}},
+	}
+
+	nodeMap := make(map[string]*memory.Node)
+	for i, n := range nodes {
+		node, err := memory.NewNode(int64(i), memory.WithUID(n.uid), memory.WithLabel(n.label), memory.WithAttrs(n.attrs))
+		if err != nil {
+			t.Fatalf("failed to create new node: %v", err)
+		}
+		g.AddNode(node)
+		nodeMap[n.uid] = node
+	}
+
+	edges := []struct {
+		uid    string
+		source string
+		target string
+		label  string
+	}{
+		{"edge1", "repo1", "topic", "HasTopic"},
+		{"edge2", "repo1", "lang", "IsLanguage"},
+		{"edge3", "repo2", "topic", "HasTopic"},
+	}
+
+	for _, e := range edges {
+		edge, err := memory.NewEdge(nodeMap[e.source], nodeMap[e.target], memory.WithUID(e.uid), memory.WithLabel(e.label))
+		if err != nil {
+			t.Fatalf("failed to create new edge: %v", err)
+		}
+		g.SetWeightedEdge(edge)
+	}
+
+	if err := MustSyncer(t, db).Sync(context.Background(), g); err != nil {
+		t.Fatalf("failed to sync graph: %v", err)
+	}
+
+	return g
+}
+
+func graphUIDs(g graph.Graph) (map[string]bool, map[string]bool) {
+	nodes, edges := make(map[string]bool), make(map[string]bool)
+	for it := g.Nodes(); it.Next(); {
+		nodes[it.Node().(graph.Node).UID()] = true
+	}
+	for it := g.Edges(); it.Next(); {
+		edges[it.Edge().(graph.Edge).UID()] = true
+	}
+	return nodes, edges
+}
+
+func TestLoader_LoadWithOptions(t *testing.T) {
+	testCases := []struct {
+		name  string
+		opts  []Option
+		nodes []string
+		edges []string
+	}{
+		{
+			name:  "NodeLabels",
+			opts:  []Option{WithNodeLabels("Repo", "Topic")},
+			nodes: []string{"repo1", "repo2", "topic"},
+			edges: []string{"edge1", "edge3"},
+		},
+		{
+			name:  "EdgeLabels",
+			opts:  []Option{WithEdgeLabels("IsLanguage")},
+			nodes: []string{"repo1", "repo2", "topic", "lang"},
+			edges: []string{"edge2"},
+		},
+		{
+			name: "NodePredicate",
+			opts: []Option{WithNodePredicate(func(label string, attrs map[string]interface{}) bool {
+				stars, ok := attrs["stars"].(int64)
+				return label != "Repo" || (ok && stars > 100)
+			})},
+			nodes: []string{"repo2", "topic", "lang"},
+			edges: []string{"edge3"},
+		},
+		{
+			name:  "NeighbourhoodOneHop",
+			opts:  []Option{WithNeighbourhood(1, "lang")},
+			nodes: []string{"lang", "repo1"},
+			edges: []string{"edge2"},
+		},
+		{
+			name:  "NeighbourhoodTwoHops",
+			opts:  []Option{WithNeighbourhood(2, "lang")},
+			nodes: []string{"lang", "repo1", "topic"},
+			edges: []string{"edge1", "edge2"},
+		},
+		{
+			name:  "NeighbourhoodEdgeLabels",
+			opts:  []Option{WithNeighbourhood(3, "lang"), WithEdgeLabels("IsLanguage")},
+			nodes: []string{"lang", "repo1"},
+			edges: []string{"edge2"},
+		},
+		{
+			name:  "SmallBatch",
+			opts:  []Option{WithBatchSize(1)},
+			nodes: []string{"repo1", "repo2", "topic", "lang"},
+			edges: []string{"edge1", "edge2", "edge3"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := MustOpenDB(t)
+			defer db.Close()
+			g := MustSyncedGraph(t, db)
+
+			l, err := NewLoader(db, tc.opts...)
+			if err != nil {
+				t.Fatalf("failed to create loader: %v", err)
+			}
+
+			lg, err := l.Load(context.Background(), g.UID())
+			if err != nil {
+				t.Fatalf("failed to load graph: %v", err)
+			}
+
+			nodes, edges := graphUIDs(lg)
+			if len(nodes) != len(tc.nodes) {
+				t.Errorf("expected nodes %v, got %v", tc.nodes, nodes)
+			}
+			for _, uid := range tc.nodes {
+				if !nodes[uid] {
+					t.Errorf("expected node %s in loaded graph", uid)
+				}
+			}
+			if len(edges) != len(tc.edges) {
+				t.Errorf("expected edges %v, got %v", tc.edges, edges)
+			}
+			for _, uid := range tc.edges {
+				if !edges[uid] {
+					t.Errorf("expected edge %s in loaded graph", uid)
+				}
+			}
+		})
+	}
+}
+
+func TestLoader_LoadProgress(t *testing.T) {
+	db := MustOpenDB(t)
+	defer db.Close()
+	g := MustSyncedGraph(t, db)
+
+	var last Progress
+	batches := 0
+	l, err := NewLoader(db, WithBatchSize(2), WithProgress(func(p Progress) {
+		batches++
+		last = p
+	}))
+	if err != nil {
+		t.Fatalf("failed to create loader: %v", err)
+	}
+
+	if _, err := l.Load(context.Background(), g.UID()); err != nil {
+		t.Fatalf("failed to load graph: %v", err)
+	}
+
+	// 4 nodes and 3 edges in batches of 2: 3 node batches and 2 edge batches
+	if exp := 5; batches != exp {
+		t.Errorf("expected %d batches, got %d", exp, batches)
+	}
+
+	if last.Stage != edgesStage || last.Nodes != 4 || last.Edges != 3 {
+		t.Errorf("unexpected final progress: %+v", last)
+	}
+}
+
+func TestLoader_LoadCanceled(t *testing.T) {
+	db := MustOpenDB(t)
+	defer db.Close()
+	g := MustSyncedGraph(t, db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l, err := NewLoader(db, WithBatchSize(1), WithProgress(func(Progress) { cancel() }))
+	if err != nil {
+		t.Fatalf("failed to create loader: %v", err)
+	}
+
+	if _, err := l.Load(ctx, g.UID()); err == nil {
+		t.Fatal("expected error loading graph with canceled context")
+	}
+}
+
+func TestNewLoaderInvalidOptions(t *testing.T) {
+	db := MustOpenDB(t)
+	defer db.Close()
+
+	if _, err := NewLoader(db, WithBatchSize(0)); err == nil {
+		t.Error("expected error for invalid batch size")
+	}
+
+	if _, err := NewLoader(db, WithNeighbourhood(-1, "foo")); err == nil {
+		t.Error("expected error for invalid hops")
+	}
+}
+
+func TestLoader_LoadTypes(t *testing.T) {
+	for _, typ := range []string{
+		graph.WeightedDirected,
+		graph.WeightedUndirected,
+		graph.WeightedDirectedMulti,
+		graph.WeightedUndirectedMulti,
+	} {
+		t.Run(typ, func(t *testing.T) {
+			db := MustOpenDB(t)
+			defer db.Close()
+			l := MustLoader(t, db)
+			s := MustSyncer(t, db)
+
+			ctx := context.Background()
+
+			g, err := memory.NewGraph(memory.WithType(typ))
+			if err != nil {
+				t.Fatalf("failed to create new graph: %v", err)
+			}
+
+			node1, err := memory.NewNode(1, memory.WithUID("node1"))
+			if err != nil {
+				t.Fatalf("failed to create new node: %v", err)
+			}
+			g.AddNode(node1)
+
+			node2, err := memory.NewNode(2, memory.WithUID("node2"))
+			if err != nil {
+				t.Fatalf("failed to create new node: %v", err)
+			}
+			g.AddNode(node2)
+
+			for _, uid := range []string{"edge1", "edge2"} {
+				edge, err := memory.NewEdge(node1, node2, memory.WithUID(uid))
+				if err != nil {
+					t.Fatalf("failed to create new edge: %v", err)
+				}
+				g.SetWeightedEdge(edge)
+			}
+
+			if err := s.Sync(ctx, g); err != nil {
+				t.Fatalf("failed to sync graph: %v", err)
+			}
+
+			lg, err := l.Load(ctx, g.UID())
+			if err != nil {
+				t.Fatalf("failed to load graph: %v", err)
+			}
+
+			if lt := lg.Type(); lt != typ {
+				t.Errorf("expected type: %s, got: %s", typ, lt)
+			}
+
+			if want, got := g.Edges().Len(), lg.Edges().Len(); want != got {
+				t.Errorf("expected edges: %d, got: %d", want, got)
+			}
+		})
+	}
+}
+
+func TestLoader_LoadSchema(t *testing.T) {
+	db := MustOpenDB(t)
+	defer db.Close()
+	s := MustSyncer(t, db)
+
+	ctx := context.Background()
+
+	g, err := memory.NewGraph()
+	if err != nil {
+		t.Fatalf("failed to create new graph: %v", err)
+	}
+
+	node1, err := memory.NewNode(1, memory.WithUID("node1"), memory.WithLabel("Repo"),
+		memory.WithAttrs(map[string]interface{}{"stars": 10, "created_at": "2023-01-02T15:04:05Z"}))
+	if err != nil {
+		t.Fatalf("failed to create new node: %v", err)
+	}
+	g.AddNode(node1)
+
+	node2, err := memory.NewNode(2, memory.WithUID("node2"), memory.WithLabel("Topic"))
+	if err != nil {
+		t.Fatalf("failed to create new node: %v", err)
+	}
+	g.AddNode(node2)
+
+	edge, err := memory.NewEdge(node1, node2, memory.WithUID("edge1"), memory.WithLabel("HasTopic"),
+		memory.WithAttrs(map[string]interface{}{"weight": 1}))
+	if err != nil {
+		t.Fatalf("failed to create new edge: %v", err)
+	}
+	g.SetWeightedEdge(edge)
+
+	if err := s.Sync(ctx, g); err != nil {
+		t.Fatalf("failed to sync graph: %v", err)
+	}
+
+	r := attrs.NewRegistry()
+	if err := r.SetNodeSchema("Repo", attrs.Schema{"stars": attrs.Int, "created_at": attrs.Time}); err != nil {
+		t.Fatalf("failed to set node schema: %v", err)
+	}
+	if err := r.SetEdgeSchema("HasTopic", attrs.Schema{"weight": attrs.Float}); err != nil {
+		t.Fatalf("failed to set edge schema: %v", err)
+	}
+
+	l, err := NewLoader(db, WithSchema(r))
+	if err != nil {
+		t.Fatalf("failed to create loader: %v", err)
+	}
+
+	lg, err := l.Load(ctx, g.UID())
+	if err != nil {
+		t.Fatalf("failed to load graph: %v", err)
+	}
+
+	mg, ok := lg.(*memory.Graph)
+	if !ok {
+		t.Fatalf("unexpected graph type: %T", lg)
+	}
+
+	a := mg.NodeWithUID("node1").(graph.Node).Attrs()
+	if _, ok := a["stars"].(int64); !ok {
+		t.Errorf("expected stars to be int64, got: %T", a["stars"])
+	}
+	if _, ok := a["created_at"].(time.Time); !ok {
+		t.Errorf("expected created_at to be time.Time, got: %T", a["created_at"])
+	}
+
+	ea := mg.EdgeWithUID("edge1").(graph.Edge).Attrs()
+	if _, ok := ea["weight"].(float64); !ok {
+		t.Errorf("expected weight to be float64, got: %T", ea["weight"])
+	}
+}
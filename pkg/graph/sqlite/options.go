@@ -0,0 +1,109 @@
+package sqlite
+
+import "github.com/milosgajdos/orbnet/pkg/graph/attrs"
+
+const (
+	// DefaultBatchSize is the default number of rows read per batch.
+	DefaultBatchSize = 1000
+)
+
+// Predicate reports whether an entity with the given label and attributes should be loaded.
+type Predicate func(label string, attrs map[string]interface{}) bool
+
+// Progress reports loading progress.
+type Progress struct {
+	// Stage is the loading stage: either "nodes" or "edges".
+	Stage string
+	// Nodes is the number of nodes loaded so far.
+	Nodes int
+	// Edges is the number of edges loaded so far.
+	Edges int
+}
+
+// ProgressFunc is called after every loaded batch.
+type ProgressFunc func(Progress)
+
+// Options configure loader.
+type Options struct {
+	// NodeLabels restricts loaded nodes to the given labels.
+	NodeLabels []string
+	// EdgeLabels restricts loaded edges to the given labels.
+	EdgeLabels []string
+	// NodePredicate filters loaded nodes.
+	NodePredicate Predicate
+	// EdgePredicate filters loaded edges.
+	EdgePredicate Predicate
+	// Seeds are UIDs of the nodes the loaded neighbourhood is centered on.
+	Seeds []string
+	// Hops is the radius of the neighbourhood around Seeds.
+	Hops int
+	// BatchSize is the number of rows read per batch.
+	BatchSize int
+	// Progress is called after every loaded batch.
+	Progress ProgressFunc
+	// Schema coerces loaded node and edge attributes.
+	Schema *attrs.Registry
+}
+
+// Option is functional loader option.
+type Option func(*Options)
+
+// WithNodeLabels sets NodeLabels option.
+func WithNodeLabels(labels ...string) Option {
+	return func(o *Options) {
+		o.NodeLabels = labels
+	}
+}
+
+// WithEdgeLabels sets EdgeLabels option.
+func WithEdgeLabels(labels ...string) Option {
+	return func(o *Options) {
+		o.EdgeLabels = labels
+	}
+}
+
+// WithNodePredicate sets NodePredicate option.
+func WithNodePredicate(p Predicate) Option {
+	return func(o *Options) {
+		o.NodePredicate = p
+	}
+}
+
+// WithEdgePredicate sets EdgePredicate option.
+func WithEdgePredicate(p Predicate) Option {
+	return func(o *Options) {
+		o.EdgePredicate = p
+	}
+}
+
+// WithNeighbourhood sets Seeds and Hops options.
+// Only the nodes at most hops edges away from any of the seeds are loaded.
+func WithNeighbourhood(hops int, seeds ...string) Option {
+	return func(o *Options) {
+		o.Hops = hops
+		o.Seeds = seeds
+	}
+}
+
+// WithBatchSize sets BatchSize option.
+func WithBatchSize(n int) Option {
+	return func(o *Options) {
+		o.BatchSize = n
+	}
+}
+
+// WithProgress sets Progress option.
+func WithProgress(p ProgressFunc) Option {
+	return func(o *Options) {
+		o.Progress = p
+	}
+}
+
+// WithSchema sets Schema option.
+// Loaded attributes are coerced to the types declared in the schema
+// before they are evaluated by predicates.
+func WithSchema(r *attrs.Registry) Option {
+	return func(o *Options) {
+		o.Schema = r
+	}
+}
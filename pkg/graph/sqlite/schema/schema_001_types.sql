@@ -0,0 +1,2 @@
+-- Add graph type; existing graphs are weighted directed graphs
+ALTER TABLE graphs ADD COLUMN type TEXT NOT NULL DEFAULT 'weighted_directed';
@@ -78,14 +78,38 @@ func NewDB(dsn string) (*DB, error) {
 	return s, nil
 }
 
-// migrate database with the attached migration files.
+// migrate sets up migration tracking and executes pending migration files.
+//
+// Migration files are embedded in the sqlite/schema folder and are executed
+// in lexigraphical order.
+//
+// Once a migration is run, its name is stored in the 'migrations' table so it
+// is not re-executed. Migrations run in a transaction to prevent partial
+// migrations.
 func (s *DB) migrate() error {
+	// Ensure the 'migrations' table exists so we don't duplicate migrations.
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS migrations (name TEXT PRIMARY KEY);`); err != nil {
+		return fmt.Errorf("cannot create migrations table: %w", err)
+	}
+
 	names, err := fs.Glob(migrationFS, Migrations)
 	if err != nil {
 		return err
 	}
 	sort.Strings(names)
 
+	for _, name := range names {
+		if err := s.migrateFile(name); err != nil {
+			return fmt.Errorf("migration error: name=%q err=%w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// migrateFile runs a single migration file within a transaction. On success, the
+// migration file name is saved to the "migrations" table to prevent re-running.
+func (s *DB) migrateFile(name string) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
@@ -93,14 +117,24 @@ func (s *DB) migrate() error {
 	// nolint:errcheck
 	defer tx.Rollback()
 
-	for _, name := range names {
-		buf, err := fs.ReadFile(migrationFS, name)
-		if err != nil {
-			return err
-		}
-		if _, err := tx.Exec(string(buf)); err != nil {
-			return err
-		}
+	var n int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM migrations WHERE name = ?`, name).Scan(&n); err != nil {
+		return err
+	}
+	if n != 0 {
+		return nil // already run migration, skip
+	}
+
+	buf, err := fs.ReadFile(migrationFS, name)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(string(buf)); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`INSERT INTO migrations (name) VALUES (?)`, name); err != nil {
+		return err
 	}
 
 	return tx.Commit()
@@ -72,14 +72,16 @@ func (s *Syncer) syncGraph(ctx context.Context, tx *sql.Tx, g graph.Graph) error
 	if _, err := tx.ExecContext(ctx, `
 		INSERT INTO graphs (
 			uid,
+			type,
 			label,
 			attrs,
 			created_at,
 			updated_at
 		)
-		VALUES (?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?)
 	`,
 		g.UID(),
+		g.Type(),
 		g.Label(),
 		attrs,
 		(*NullTime)(&createdAt),
@@ -0,0 +1,53 @@
+package stats
+
+import "github.com/milosgajdos/orbnet/pkg/graph"
+
+const (
+	// DefaultTop is the default number of top nodes by degree of each label.
+	DefaultTop = 10
+	// DefaultFrequencies is the default number of rows of language and topic frequency tables.
+	DefaultFrequencies = 20
+)
+
+// Options configure graph statistics.
+type Options struct {
+	// Top is the number of top nodes by degree of each label.
+	Top int
+	// Frequencies is the number of rows of language and topic frequency tables.
+	// If it's not positive the tables contain all languages and topics.
+	Frequencies int
+}
+
+// Option is functional statistics option.
+type Option func(*Options)
+
+// WithTop sets Top option.
+func WithTop(n int) Option {
+	return func(o *Options) {
+		o.Top = n
+	}
+}
+
+// WithFrequencies sets Frequencies option.
+func WithFrequencies(n int) Option {
+	return func(o *Options) {
+		o.Frequencies = n
+	}
+}
+
+func newOptions(opts ...Option) (Options, error) {
+	sopts := Options{
+		Top:         DefaultTop,
+		Frequencies: DefaultFrequencies,
+	}
+
+	for _, apply := range opts {
+		apply(&sopts)
+	}
+
+	if sopts.Top < 0 {
+		return Options{}, graph.Errorf(graph.EINVALID, "invalid number of top nodes: %d", sopts.Top)
+	}
+
+	return sopts, nil
+}
@@ -0,0 +1,145 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteText writes human readable statistics to w.
+func (s *Stats) WriteText(w io.Writer) error {
+	ew := &errWriter{w: w}
+
+	ew.printf("graph: %s (%s, %s)\n", s.Label, s.UID, s.Type)
+	ew.printf("nodes: %d\n", s.Nodes)
+	ew.printf("edges: %d\n", s.Edges)
+	ew.printf("density: %.6f\n", s.Density)
+	ew.printf("components: %d (largest: %d, isolated nodes: %d)\n",
+		s.Components.Count, s.Components.Largest, s.Components.Isolated)
+
+	writeTextCounts(ew, "node labels", s.NodeLabels)
+	writeTextCounts(ew, "edge labels", s.EdgeLabels)
+
+	ew.printf("\ndegrees:\n")
+	for _, d := range append([]Distribution{s.Degrees}, s.LabelDegrees...) {
+		label := d.Label
+		if label == "" {
+			label = "all"
+		}
+		ew.printf("    %s: nodes %d, min %d, max %d, mean %.2f, median %.1f\n",
+			label, d.Nodes, d.Min, d.Max, d.Mean, d.Median)
+		for _, b := range d.Histogram {
+			ew.printf("        %s: %d\n", binRange(b), b.Count)
+		}
+	}
+
+	ew.printf("\ncomponent sizes:\n")
+	for _, b := range s.Components.Sizes {
+		ew.printf("    %d: %d\n", b.Min, b.Count)
+	}
+
+	for _, t := range s.Top {
+		ew.printf("\ntop %s nodes by degree:\n", t.Label)
+		for i, n := range t.Nodes {
+			ew.printf("    %d. %s (%d)\n", i+1, n.Name, n.Degree)
+		}
+	}
+
+	writeTextCounts(ew, "starred by month", s.Starred)
+	writeTextCounts(ew, "languages", s.Languages)
+	writeTextCounts(ew, "topics", s.Topics)
+
+	return ew.err
+}
+
+// writeTextCounts writes titled counts to ew.
+func writeTextCounts(ew *errWriter, title string, counts []Count) {
+	ew.printf("\n%s:\n", title)
+	for _, c := range counts {
+		ew.printf("    %s: %d\n", c.Name, c.Count)
+	}
+}
+
+// WriteMarkdown writes statistics to w as Markdown document.
+func (s *Stats) WriteMarkdown(w io.Writer) error {
+	ew := &errWriter{w: w}
+
+	ew.printf("# %s\n\n", s.Label)
+	ew.printf("| Metric | Value |\n|---|---|\n")
+	ew.printf("| UID | %s |\n", s.UID)
+	ew.printf("| Type | %s |\n", s.Type)
+	ew.printf("| Nodes | %d |\n", s.Nodes)
+	ew.printf("| Edges | %d |\n", s.Edges)
+	ew.printf("| Density | %.6f |\n", s.Density)
+	ew.printf("| Components | %d |\n", s.Components.Count)
+	ew.printf("| Largest component | %d |\n", s.Components.Largest)
+	ew.printf("| Isolated nodes | %d |\n", s.Components.Isolated)
+
+	writeMarkdownCounts(ew, "Node labels", "Label", s.NodeLabels)
+	writeMarkdownCounts(ew, "Edge labels", "Label", s.EdgeLabels)
+
+	ew.printf("\n## Degrees\n\n")
+	ew.printf("| Label | Nodes | Min | Max | Mean | Median |\n|---|---|---|---|---|---|\n")
+	for _, d := range append([]Distribution{s.Degrees}, s.LabelDegrees...) {
+		label := d.Label
+		if label == "" {
+			label = "all"
+		}
+		ew.printf("| %s | %d | %d | %d | %.2f | %.1f |\n", label, d.Nodes, d.Min, d.Max, d.Mean, d.Median)
+	}
+
+	ew.printf("\n### Degree distribution\n\n")
+	ew.printf("| Degree | Nodes |\n|---|---|\n")
+	for _, b := range s.Degrees.Histogram {
+		ew.printf("| %s | %d |\n", binRange(b), b.Count)
+	}
+
+	ew.printf("\n## Component sizes\n\n")
+	ew.printf("| Size | Components |\n|---|---|\n")
+	for _, b := range s.Components.Sizes {
+		ew.printf("| %d | %d |\n", b.Min, b.Count)
+	}
+
+	for _, t := range s.Top {
+		ew.printf("\n## Top %s nodes\n\n", t.Label)
+		ew.printf("| # | Name | Degree |\n|---|---|---|\n")
+		for i, n := range t.Nodes {
+			ew.printf("| %d | %s | %d |\n", i+1, n.Name, n.Degree)
+		}
+	}
+
+	writeMarkdownCounts(ew, "Starred by month", "Month", s.Starred)
+	writeMarkdownCounts(ew, "Languages", "Language", s.Languages)
+	writeMarkdownCounts(ew, "Topics", "Topic", s.Topics)
+
+	return ew.err
+}
+
+// writeMarkdownCounts writes counts to ew as Markdown table.
+func writeMarkdownCounts(ew *errWriter, title, column string, counts []Count) {
+	ew.printf("\n## %s\n\n", title)
+	ew.printf("| %s | Count |\n|---|---|\n", column)
+	for _, c := range counts {
+		ew.printf("| %s | %d |\n", c.Name, c.Count)
+	}
+}
+
+// binRange formats the range of values of histogram bin b.
+func binRange(b Bin) string {
+	if b.Min == b.Max {
+		return fmt.Sprintf("%d", b.Min)
+	}
+	return fmt.Sprintf("%d-%d", b.Min, b.Max)
+}
+
+// errWriter remembers the first write error.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
@@ -0,0 +1,480 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/builder/stars"
+)
+
+const (
+	// StarredAtAttr is the attribute which stores the time the repo was starred at.
+	StarredAtAttr = "starred_at"
+	// monthLayout formats the months of starred_at histogram.
+	monthLayout = "2006-01"
+)
+
+// Count is a named count.
+type Count struct {
+	// Name is counted item name.
+	Name string `json:"name"`
+	// Count is the number of items.
+	Count int `json:"count"`
+}
+
+// Bin is a histogram bin of the values in [Min, Max].
+type Bin struct {
+	// Min is the smallest value in the bin.
+	Min int `json:"min"`
+	// Max is the largest value in the bin.
+	Max int `json:"max"`
+	// Count is the number of values in the bin.
+	Count int `json:"count"`
+}
+
+// Distribution summarises the degrees of the nodes with the given label.
+type Distribution struct {
+	// Label is node label; it's empty for all graph nodes.
+	Label string `json:"label,omitempty"`
+	// Nodes is the number of nodes.
+	Nodes int `json:"nodes"`
+	// Min is the minimum degree.
+	Min int `json:"min"`
+	// Max is the maximum degree.
+	Max int `json:"max"`
+	// Mean is the mean degree.
+	Mean float64 `json:"mean"`
+	// Median is the median degree.
+	Median float64 `json:"median"`
+	// Histogram counts the nodes in power of two degree bins: 0, 1, 2-3, 4-7 etc.
+	Histogram []Bin `json:"histogram"`
+}
+
+// Components summarises weakly connected components.
+type Components struct {
+	// Count is the number of components.
+	Count int `json:"count"`
+	// Largest is the number of nodes in the largest component.
+	Largest int `json:"largest"`
+	// Isolated is the number of nodes with no edges.
+	Isolated int `json:"isolated"`
+	// Sizes counts the components of each size ordered by size in descending order.
+	Sizes []Bin `json:"sizes"`
+}
+
+// Degree is a node degree.
+type Degree struct {
+	// UID is node UID.
+	UID string `json:"uid"`
+	// Name is node name.
+	Name string `json:"name"`
+	// Degree is node degree.
+	Degree int `json:"degree"`
+}
+
+// Top are the top nodes with the given label by their degree.
+type Top struct {
+	// Label is node label.
+	Label string `json:"label"`
+	// Nodes are the top nodes ordered by their degree.
+	Nodes []Degree `json:"nodes"`
+}
+
+// Stats are graph statistics.
+type Stats struct {
+	// UID is graph UID.
+	UID string `json:"uid"`
+	// Label is graph label.
+	Label string `json:"label"`
+	// Type is graph type.
+	Type string `json:"type"`
+	// Nodes is the number of nodes.
+	Nodes int `json:"nodes"`
+	// Edges is the number of edges.
+	Edges int `json:"edges"`
+	// Density is the ratio of the edges to all possible edges.
+	Density float64 `json:"density"`
+	// NodeLabels counts the nodes of each label.
+	NodeLabels []Count `json:"node_labels"`
+	// EdgeLabels counts the edges of each label.
+	EdgeLabels []Count `json:"edge_labels"`
+	// Degrees is the degree distribution of all nodes.
+	Degrees Distribution `json:"degrees"`
+	// LabelDegrees are the degree distributions of the nodes of each label.
+	LabelDegrees []Distribution `json:"label_degrees"`
+	// Components summarises weakly connected components.
+	Components Components `json:"components"`
+	// Top are the top nodes by degree of each label.
+	Top []Top `json:"top"`
+	// Starred counts the repos starred in each month.
+	Starred []Count `json:"starred"`
+	// Languages counts the repos of each language.
+	Languages []Count `json:"languages"`
+	// Topics counts the repos of each topic.
+	Topics []Count `json:"topics"`
+}
+
+// Compute computes the statistics of g.
+// Degrees count the edges incident to the nodes regardless of their direction.
+// Count tables are ordered by count in descending order and then by name.
+func Compute(g graph.Graph, opts ...Option) (*Stats, error) {
+	sopts, err := newOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Stats{
+		UID:   g.UID(),
+		Label: g.Label(),
+		Type:  g.Type(),
+	}
+
+	var nodes []graph.Node
+
+	iter := g.Nodes()
+	for iter.Next() {
+		n, ok := iter.Node().(graph.Node)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid node in graph %s", g.UID())
+		}
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].UID() < nodes[j].UID() })
+
+	index := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		index[n.ID()] = i
+	}
+
+	degrees := make([]int, len(nodes))
+	// repos stores the distinct repo neighbours of each node
+	repos := make([]map[int]bool, len(nodes))
+	comps := newUnionFind(len(nodes))
+
+	nodeLabels := make(map[string]int)
+	edgeLabels := make(map[string]int)
+
+	repoLabel := stars.RepoEntity.String()
+
+	edges := g.Edges()
+	for edges.Next() {
+		e := edges.Edge()
+		from, to := index[e.From().ID()], index[e.To().ID()]
+
+		degrees[from]++
+		degrees[to]++
+		comps.union(from, to)
+
+		if ge, ok := e.(graph.Edge); ok {
+			edgeLabels[ge.Label()]++
+		}
+
+		if nodes[to].Label() == repoLabel {
+			addRepo(repos, from, to)
+		}
+		if nodes[from].Label() == repoLabel {
+			addRepo(repos, to, from)
+		}
+
+		s.Edges++
+	}
+
+	s.Nodes = len(nodes)
+	s.Density = density(s.Nodes, s.Edges, graph.IsDirected(g.Type()))
+
+	byLabel := make(map[string][]int)
+	for i, n := range nodes {
+		nodeLabels[n.Label()]++
+		byLabel[n.Label()] = append(byLabel[n.Label()], i)
+	}
+
+	s.NodeLabels = counts(nodeLabels, 0)
+	s.EdgeLabels = counts(edgeLabels, 0)
+
+	s.Degrees = distribution("", degrees)
+	s.LabelDegrees = []Distribution{}
+	s.Top = []Top{}
+
+	for _, label := range sortedLabels(byLabel) {
+		ids := byLabel[label]
+
+		ds := make([]int, len(ids))
+		for i, id := range ids {
+			ds[i] = degrees[id]
+		}
+		s.LabelDegrees = append(s.LabelDegrees, distribution(label, ds))
+
+		if sopts.Top > 0 {
+			s.Top = append(s.Top, top(label, ids, nodes, degrees, sopts.Top))
+		}
+	}
+
+	s.Components = components(comps, degrees)
+
+	s.Starred, err = starred(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Languages = frequencies(nodes, repos, byLabel[stars.LangEntity.String()], sopts.Frequencies)
+	s.Topics = frequencies(nodes, repos, byLabel[stars.TopicEntity.String()], sopts.Frequencies)
+
+	return s, nil
+}
+
+func addRepo(repos []map[int]bool, id, repo int) {
+	if repos[id] == nil {
+		repos[id] = make(map[int]bool)
+	}
+	repos[id][repo] = true
+}
+
+// density returns the ratio of e edges to all possible edges between n nodes.
+func density(n, e int, directed bool) float64 {
+	if n < 2 {
+		return 0
+	}
+
+	pairs := float64(n) * float64(n-1)
+	if !directed {
+		pairs /= 2
+	}
+
+	return float64(e) / pairs
+}
+
+// counts returns the counts ordered by count and name, limited to limit items if limit is positive.
+func counts(m map[string]int, limit int) []Count {
+	cx := make([]Count, 0, len(m))
+	for name, c := range m {
+		cx = append(cx, Count{Name: name, Count: c})
+	}
+
+	sort.Slice(cx, func(i, j int) bool {
+		if cx[i].Count != cx[j].Count {
+			return cx[i].Count > cx[j].Count
+		}
+		return cx[i].Name < cx[j].Name
+	})
+
+	if limit > 0 && len(cx) > limit {
+		cx = cx[:limit]
+	}
+
+	return cx
+}
+
+// distribution returns the distribution of the given degrees.
+func distribution(label string, degrees []int) Distribution {
+	d := Distribution{
+		Label:     label,
+		Nodes:     len(degrees),
+		Histogram: []Bin{},
+	}
+
+	if len(degrees) == 0 {
+		return d
+	}
+
+	sorted := make([]int, len(degrees))
+	copy(sorted, degrees)
+	sort.Ints(sorted)
+
+	d.Min, d.Max = sorted[0], sorted[len(sorted)-1]
+
+	var sum int
+	for _, deg := range sorted {
+		sum += deg
+	}
+	d.Mean = float64(sum) / float64(len(sorted))
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		d.Median = float64(sorted[mid-1]+sorted[mid]) / 2
+	} else {
+		d.Median = float64(sorted[mid])
+	}
+
+	for _, deg := range sorted {
+		lo, hi := bin(deg)
+		if last := len(d.Histogram) - 1; last >= 0 && d.Histogram[last].Min == lo {
+			d.Histogram[last].Count++
+			continue
+		}
+		d.Histogram = append(d.Histogram, Bin{Min: lo, Max: hi, Count: 1})
+	}
+
+	return d
+}
+
+// bin returns the bounds of the power of two bin of degree d.
+func bin(d int) (int, int) {
+	if d == 0 {
+		return 0, 0
+	}
+
+	lo := 1
+	for lo*2 <= d {
+		lo *= 2
+	}
+
+	return lo, 2*lo - 1
+}
+
+// top returns the top n nodes with the given ids by their degree.
+func top(label string, ids []int, nodes []graph.Node, degrees []int, n int) Top {
+	t := Top{
+		Label: label,
+		Nodes: make([]Degree, 0, len(ids)),
+	}
+
+	for _, id := range ids {
+		t.Nodes = append(t.Nodes, Degree{
+			UID:    nodes[id].UID(),
+			Name:   graph.NodeName(nodes[id]),
+			Degree: degrees[id],
+		})
+	}
+
+	sort.SliceStable(t.Nodes, func(i, j int) bool {
+		if t.Nodes[i].Degree != t.Nodes[j].Degree {
+			return t.Nodes[i].Degree > t.Nodes[j].Degree
+		}
+		return t.Nodes[i].Name < t.Nodes[j].Name
+	})
+
+	if len(t.Nodes) > n {
+		t.Nodes = t.Nodes[:n]
+	}
+
+	return t
+}
+
+// components summarises the components of the union-find forest.
+func components(uf *unionFind, degrees []int) Components {
+	c := Components{
+		Sizes: []Bin{},
+	}
+
+	sizes := make(map[int]int)
+	for i := range uf.parent {
+		if degrees[i] == 0 {
+			c.Isolated++
+		}
+
+		if uf.find(i) != i {
+			continue
+		}
+
+		c.Count++
+		sizes[uf.size[i]]++
+		if uf.size[i] > c.Largest {
+			c.Largest = uf.size[i]
+		}
+	}
+
+	for size, count := range sizes {
+		c.Sizes = append(c.Sizes, Bin{Min: size, Max: size, Count: count})
+	}
+	sort.Slice(c.Sizes, func(i, j int) bool { return c.Sizes[i].Min > c.Sizes[j].Min })
+
+	return c
+}
+
+// starred returns the number of the nodes starred in each month between the first and the last starred month.
+func starred(nodes []graph.Node) ([]Count, error) {
+	months := make(map[string]int)
+
+	var first, last time.Time
+	for _, n := range nodes {
+		v, ok := n.Attrs()[StarredAtAttr]
+		if !ok || v == nil {
+			continue
+		}
+
+		tv, err := attrs.Coerce(attrs.Time, v)
+		if err != nil {
+			return nil, graph.Errorf(graph.EINVALID, "invalid %s of node %s: %v", StarredAtAttr, n.UID(), err)
+		}
+
+		t := tv.(time.Time).UTC()
+		month := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if first.IsZero() || month.Before(first) {
+			first = month
+		}
+		if month.After(last) {
+			last = month
+		}
+		months[month.Format(monthLayout)]++
+	}
+
+	cx := []Count{}
+	if first.IsZero() {
+		return cx, nil
+	}
+
+	for m := first; !m.After(last); m = m.AddDate(0, 1, 0) {
+		key := m.Format(monthLayout)
+		cx = append(cx, Count{Name: key, Count: months[key]})
+	}
+
+	return cx, nil
+}
+
+// frequencies counts the repos linked to the nodes with the given ids.
+func frequencies(nodes []graph.Node, repos []map[int]bool, ids []int, limit int) []Count {
+	m := make(map[string]int, len(ids))
+	for _, id := range ids {
+		m[graph.NodeName(nodes[id])] += len(repos[id])
+	}
+	return counts(m, limit)
+}
+
+func sortedLabels(m map[string][]int) []string {
+	labels := make([]string, 0, len(m))
+	for l := range m {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// unionFind is a disjoint set forest of node indices.
+type unionFind struct {
+	parent []int
+	size   []int
+}
+
+func newUnionFind(n int) *unionFind {
+	uf := &unionFind{
+		parent: make([]int, n),
+		size:   make([]int, n),
+	}
+	for i := range uf.parent {
+		uf.parent[i] = i
+		uf.size[i] = 1
+	}
+	return uf
+}
+
+func (uf *unionFind) find(i int) int {
+	for uf.parent[i] != i {
+		uf.parent[i] = uf.parent[uf.parent[i]]
+		i = uf.parent[i]
+	}
+	return i
+}
+
+func (uf *unionFind) union(i, j int) {
+	ri, rj := uf.find(i), uf.find(j)
+	if ri == rj {
+		return
+	}
+	if uf.size[ri] < uf.size[rj] {
+		ri, rj = rj, ri
+	}
+	uf.parent[rj] = ri
+	uf.size[ri] += uf.size[rj]
+}
@@ -0,0 +1,191 @@
+package stats
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+// MustGraph returns the following graph of repos linked to their topics, languages and owners
+// and an isolated topic:
+//
+//	r1 -> k8s, cloud, go, o1 starred in 2023-01
+//	r2 -> k8s, go, o1 starred in 2023-03
+//	r3 -> python, o2 starred in 2023-03 as RFC3339 string
+//	lonely
+func MustGraph(t *testing.T) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph(memory.WithUID("g1"), memory.WithLabel("stars"))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	nodes := []struct {
+		uid   string
+		label string
+		attrs map[string]interface{}
+	}{
+		{"r1", "Repo", map[string]interface{}{"full_name": "o1/r1", "starred_at": time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)}},
+		{"r2", "Repo", map[string]interface{}{"full_name": "o1/r2", "starred_at": time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)}},
+		{"r3", "Repo", map[string]interface{}{"full_name": "o2/r3", "starred_at": "2023-03-31T23:00:00Z"}},
+		{"k8s-Topic", "Topic", map[string]interface{}{"name": "k8s"}},
+		{"cloud-Topic", "Topic", map[string]interface{}{"name": "cloud"}},
+		{"lonely-Topic", "Topic", map[string]interface{}{"name": "lonely"}},
+		{"go-Lang", "Lang", map[string]interface{}{"name": "go"}},
+		{"python-Lang", "Lang", map[string]interface{}{"name": "python"}},
+		{"o1", "Owner", map[string]interface{}{"name": "o1"}},
+		{"o2", "Owner", map[string]interface{}{"name": "o2"}},
+	}
+
+	ns := make(map[string]*memory.Node)
+	for _, tn := range nodes {
+		n, err := memory.NewNode(g.NewNode().ID(),
+			memory.WithUID(tn.uid),
+			memory.WithLabel(tn.label),
+			memory.WithAttrs(tn.attrs),
+		)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(n)
+		ns[tn.uid] = n
+	}
+
+	for _, te := range [][3]string{
+		{"r1", "k8s-Topic", "HasTopic"}, {"r1", "cloud-Topic", "HasTopic"}, {"r1", "go-Lang", "IsLanguage"}, {"r1", "o1", "OwnedBy"},
+		{"r2", "k8s-Topic", "HasTopic"}, {"r2", "go-Lang", "IsLanguage"}, {"r2", "o1", "OwnedBy"},
+		{"r3", "python-Lang", "IsLanguage"}, {"r3", "o2", "OwnedBy"},
+	} {
+		e, err := memory.NewEdge(ns[te[0]], ns[te[1]], memory.WithLabel(te[2]))
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(e)
+	}
+
+	return g
+}
+
+func TestCompute(t *testing.T) {
+	g := MustGraph(t)
+
+	s, err := Compute(g, WithTop(2), WithFrequencies(1))
+	if err != nil {
+		t.Fatalf("failed to compute stats: %v", err)
+	}
+
+	if s.Nodes != 10 || s.Edges != 9 {
+		t.Errorf("expected 10 nodes and 9 edges, got: %d, %d", s.Nodes, s.Edges)
+	}
+
+	if exp := 9.0 / 90; s.Density != exp {
+		t.Errorf("expected density: %f, got: %f", exp, s.Density)
+	}
+
+	expLabels := []Count{{"Repo", 3}, {"Topic", 3}, {"Lang", 2}, {"Owner", 2}}
+	if !reflect.DeepEqual(s.NodeLabels, expLabels) {
+		t.Errorf("expected node labels: %v, got: %v", expLabels, s.NodeLabels)
+	}
+
+	expLabels = []Count{{"HasTopic", 3}, {"IsLanguage", 3}, {"OwnedBy", 3}}
+	if !reflect.DeepEqual(s.EdgeLabels, expLabels) {
+		t.Errorf("expected edge labels: %v, got: %v", expLabels, s.EdgeLabels)
+	}
+
+	expDegrees := Distribution{
+		Nodes:  10,
+		Min:    0,
+		Max:    4,
+		Mean:   1.8,
+		Median: 2,
+		Histogram: []Bin{
+			{Min: 0, Max: 0, Count: 1},
+			{Min: 1, Max: 1, Count: 3},
+			{Min: 2, Max: 3, Count: 5},
+			{Min: 4, Max: 7, Count: 1},
+		},
+	}
+	if !reflect.DeepEqual(s.Degrees, expDegrees) {
+		t.Errorf("expected degrees: %+v, got: %+v", expDegrees, s.Degrees)
+	}
+
+	if len(s.LabelDegrees) != 4 || s.LabelDegrees[2].Label != "Repo" || s.LabelDegrees[2].Mean != 3 {
+		t.Errorf("unexpected label degrees: %+v", s.LabelDegrees)
+	}
+
+	expComps := Components{
+		Count:    3,
+		Largest:  6,
+		Isolated: 1,
+		Sizes:    []Bin{{Min: 6, Max: 6, Count: 1}, {Min: 3, Max: 3, Count: 1}, {Min: 1, Max: 1, Count: 1}},
+	}
+	if !reflect.DeepEqual(s.Components, expComps) {
+		t.Errorf("expected components: %+v, got: %+v", expComps, s.Components)
+	}
+
+	expTop := Top{Label: "Repo", Nodes: []Degree{{"r1", "o1/r1", 4}, {"r2", "o1/r2", 3}}}
+	if len(s.Top) != 4 || !reflect.DeepEqual(s.Top[2], expTop) {
+		t.Errorf("expected top: %+v, got: %+v", expTop, s.Top)
+	}
+
+	expStarred := []Count{{"2023-01", 1}, {"2023-02", 0}, {"2023-03", 2}}
+	if !reflect.DeepEqual(s.Starred, expStarred) {
+		t.Errorf("expected starred: %v, got: %v", expStarred, s.Starred)
+	}
+
+	if exp := []Count{{"go", 2}}; !reflect.DeepEqual(s.Languages, exp) {
+		t.Errorf("expected languages: %v, got: %v", exp, s.Languages)
+	}
+
+	if exp := []Count{{"k8s", 2}}; !reflect.DeepEqual(s.Topics, exp) {
+		t.Errorf("expected topics: %v, got: %v", exp, s.Topics)
+	}
+}
+
+func TestComputeErrors(t *testing.T) {
+	g := MustGraph(t)
+
+	if _, err := Compute(g, WithTop(-1)); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+
+	g.NodeWithUID("r1").Attrs()[StarredAtAttr] = "yesterday"
+	if _, err := Compute(g); graph.ErrorCode(err) != graph.EINVALID {
+		t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	s, err := Compute(MustGraph(t))
+	if err != nil {
+		t.Fatalf("failed to compute stats: %v", err)
+	}
+
+	var text bytes.Buffer
+	if err := s.WriteText(&text); err != nil {
+		t.Fatalf("failed to write text: %v", err)
+	}
+
+	for _, exp := range []string{"nodes: 10\n", "components: 3 (largest: 6, isolated nodes: 1)\n", "    1. o1/r1 (4)\n", "    2023-02: 0\n"} {
+		if !strings.Contains(text.String(), exp) {
+			t.Errorf("expected text to contain %q:\n%s", exp, text.String())
+		}
+	}
+
+	var md bytes.Buffer
+	if err := s.WriteMarkdown(&md); err != nil {
+		t.Fatalf("failed to write markdown: %v", err)
+	}
+
+	for _, exp := range []string{"# stars\n", "| Nodes | 10 |\n", "## Top Repo nodes\n", "| 1 | o1/r1 | 4 |\n", "| k8s | 2 |\n"} {
+		if !strings.Contains(md.String(), exp) {
+			t.Errorf("expected markdown to contain %q:\n%s", exp, md.String())
+		}
+	}
+}
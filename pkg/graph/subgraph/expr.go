@@ -0,0 +1,504 @@
+package subgraph
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+)
+
+// Expr is a compiled node predicate expression.
+//
+// Expressions compare node attributes with literals, e.g.
+//
+//	stargazers_count > 1000 && language == "go"
+//
+// The following operators are supported: ==, !=, <, <=, >, >=, &&, || and !.
+// Literals are either numbers, double quoted strings or booleans true and false.
+// Identifiers resolve to node attributes; nested attributes are addressed
+// using dots, e.g. owner.login. If the node has no such attribute, identifiers
+// label and uid resolve to node label and UID, respectively.
+// Strings are compared case-insensitively. Time attributes can be compared
+// with RFC3339 formatted strings.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Compile compiles expression s and returns it.
+func Compile(s string) (*Expr, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, graph.Errorf(graph.EINVALID, "unexpected token %q at %d", t.val, t.pos)
+	}
+
+	return &Expr{
+		src:  s,
+		root: root,
+	}, nil
+}
+
+// String returns expression source.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Match returns true if node n satisfies the expression.
+func (e *Expr) Match(n graph.Node) bool {
+	return truthy(e.root.eval(n))
+}
+
+// node is expression AST node.
+type node interface {
+	eval(n graph.Node) interface{}
+}
+
+type literal struct {
+	val interface{}
+}
+
+func (l literal) eval(graph.Node) interface{} {
+	return l.val
+}
+
+type ident struct {
+	path []string
+}
+
+func (i ident) eval(n graph.Node) interface{} {
+	var v interface{} = n.Attrs()
+	for _, k := range i.path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			v = nil
+			break
+		}
+		if v, ok = m[k]; !ok {
+			break
+		}
+	}
+
+	if v == nil && len(i.path) == 1 {
+		switch i.path[0] {
+		case "label":
+			return n.Label()
+		case "uid":
+			return n.UID()
+		}
+	}
+
+	return v
+}
+
+type not struct {
+	x node
+}
+
+func (u not) eval(n graph.Node) interface{} {
+	return !truthy(u.x.eval(n))
+}
+
+type logical struct {
+	op   string
+	l, r node
+}
+
+func (b logical) eval(n graph.Node) interface{} {
+	if b.op == "&&" {
+		return truthy(b.l.eval(n)) && truthy(b.r.eval(n))
+	}
+	return truthy(b.l.eval(n)) || truthy(b.r.eval(n))
+}
+
+type compare struct {
+	op   string
+	l, r node
+}
+
+func (c compare) eval(n graph.Node) interface{} {
+	l, r := c.l.eval(n), c.r.eval(n)
+
+	cmp, ok := compareValues(l, r)
+	if !ok {
+		// incomparable values are only ever different
+		return c.op == "!="
+	}
+
+	switch c.op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+
+	return false
+}
+
+// compareValues compares a and b and returns -1, 0 or 1 if a is less, equal or greater than b.
+// It returns false if a and b are not comparable.
+func compareValues(a, b interface{}) (int, bool) {
+	if a == nil || b == nil {
+		return 0, false
+	}
+
+	if af, ok := toFloat(a); ok {
+		bf, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case af < bf:
+			return -1, true
+		case af > bf:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	at, aok := toTime(a)
+	bt, bok := toTime(b)
+	if aok && bok {
+		return at.Compare(bt), true
+	}
+
+	if ab, ok := a.(bool); ok {
+		bb, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		if ab == bb {
+			return 0, true
+		}
+		if !ab {
+			return -1, true
+		}
+		return 1, true
+	}
+
+	as, ok := toString(a)
+	if !ok {
+		return 0, false
+	}
+
+	bs, ok := toString(b)
+	if !ok {
+		return 0, false
+	}
+
+	return strings.Compare(strings.ToLower(as), strings.ToLower(bs)), true
+}
+
+// toFloat converts numeric v to float64.
+func toFloat(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// toTime converts v to time.Time.
+func toTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case *time.Time:
+		if t != nil {
+			return *t, true
+		}
+	case interface{ GetTime() *time.Time }:
+		if pt := t.GetTime(); pt != nil {
+			return *pt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// toString converts v to string.
+func toString(v interface{}) (string, bool) {
+	switch s := v.(type) {
+	case string:
+		return s, true
+	case fmt.Stringer:
+		return s.String(), true
+	}
+	return "", false
+}
+
+// truthy returns the boolean value of v.
+func truthy(v interface{}) bool {
+	switch b := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return b
+	case string:
+		return b != ""
+	}
+
+	if f, ok := toFloat(v); ok {
+		return f != 0
+	}
+
+	return true
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokKind
+	val  string
+	pos  int
+}
+
+// lex splits s into tokens.
+func lex(s string) ([]token, error) {
+	var tokens []token
+
+	rs := []rune(s)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, val: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, val: ")", pos: i})
+			i++
+		case r == '"':
+			j := i + 1
+			var sb strings.Builder
+			for ; j < len(rs) && rs[j] != '"'; j++ {
+				if rs[j] == '\\' && j+1 < len(rs) {
+					j++
+				}
+				sb.WriteRune(rs[j])
+			}
+			if j >= len(rs) {
+				return nil, graph.Errorf(graph.EINVALID, "unterminated string at %d", i)
+			}
+			tokens = append(tokens, token{kind: tokString, val: sb.String(), pos: i})
+			i = j + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			j := i + 1
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokNumber, val: string(rs[i:j]), pos: i})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_' || rs[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, val: string(rs[i:j]), pos: i})
+			i = j
+		default:
+			op := ""
+			for _, o := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!"} {
+				if strings.HasPrefix(string(rs[i:]), o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, graph.Errorf(graph.EINVALID, "unexpected character %q at %d", r, i)
+			}
+			tokens = append(tokens, token{kind: tokOp, val: op, pos: i})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, pos: len(rs)}), nil
+}
+
+// parser is a recursive descent expression parser.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for t := p.peek(); t.kind == tokOp && t.val == "||"; t = p.peek() {
+		p.next()
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = logical{op: "||", l: l, r: r}
+	}
+
+	return l, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for t := p.peek(); t.kind == tokOp && t.val == "&&"; t = p.peek() {
+		p.next()
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = logical{op: "&&", l: l, r: r}
+	}
+
+	return l, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if t := p.peek(); t.kind == tokOp && t.val == "!" {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return not{x: x}, nil
+	}
+
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	l, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	if t.kind != tokOp {
+		return l, nil
+	}
+
+	switch t.val {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.next()
+		r, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return compare{op: t.val, l: l, r: r}, nil
+	}
+
+	return l, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokLParen:
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, graph.Errorf(graph.EINVALID, "expected ) at %d", t.pos)
+		}
+		return x, nil
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.val, 64)
+		if err != nil {
+			return nil, graph.Errorf(graph.EINVALID, "invalid number %q at %d", t.val, t.pos)
+		}
+		return literal{val: f}, nil
+	case tokString:
+		if ts, err := time.Parse(time.RFC3339, t.val); err == nil {
+			return timeLiteral{s: t.val, t: ts}, nil
+		}
+		return literal{val: t.val}, nil
+	case tokIdent:
+		switch t.val {
+		case "true":
+			return literal{val: true}, nil
+		case "false":
+			return literal{val: false}, nil
+		}
+		return ident{path: strings.Split(t.val, ".")}, nil
+	case tokEOF:
+		return nil, graph.Errorf(graph.EINVALID, "unexpected end of expression")
+	}
+
+	return nil, graph.Errorf(graph.EINVALID, "unexpected token %q at %d", t.val, t.pos)
+}
+
+// timeLiteral is an RFC3339 formatted string literal.
+// It compares as time with time values and as string with anything else.
+type timeLiteral struct {
+	s string
+	t time.Time
+}
+
+func (l timeLiteral) eval(graph.Node) interface{} {
+	return l
+}
+
+// GetTime returns literal time.
+func (l timeLiteral) GetTime() *time.Time {
+	return &l.t
+}
+
+// String returns literal string.
+func (l timeLiteral) String() string {
+	return l.s
+}
@@ -0,0 +1,78 @@
+package subgraph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+func TestCompile(t *testing.T) {
+	pushed := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	n, err := memory.NewNode(1,
+		memory.WithUID("repo"),
+		memory.WithLabel("Repo"),
+		memory.WithAttrs(map[string]interface{}{
+			"stargazers_count": 1500,
+			"language":         "Go",
+			"fork":             false,
+			"pushed_at":        pushed,
+			"owner":            map[string]interface{}{"login": "foo"},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+
+	testCases := []struct {
+		expr  string
+		match bool
+	}{
+		{`stargazers_count > 1000 && language == "go"`, true},
+		{`stargazers_count > 1000 && language == "rust"`, false},
+		{`stargazers_count >= 1500 && stargazers_count <= 1500`, true},
+		{`stargazers_count < 1000 || label == "Repo"`, true},
+		{`!fork`, true},
+		{`fork == false && !(language != "Go")`, true},
+		{`owner.login == "foo"`, true},
+		{`uid == "repo"`, true},
+		{`missing == 1`, false},
+		{`missing != 1`, true},
+		{`pushed_at > "2022-06-01T00:00:00Z"`, true},
+		{`pushed_at < "2022-06-01T00:00:00Z"`, false},
+		{`language > 1`, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			e, err := Compile(tc.expr)
+			if err != nil {
+				t.Fatalf("failed to compile %q: %v", tc.expr, err)
+			}
+
+			if match := e.Match(n); match != tc.match {
+				t.Errorf("expected match: %v, got: %v", tc.match, match)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`stars >`,
+		`(stars > 1`,
+		`stars > 1)`,
+		`name == "foo`,
+		`stars # 1`,
+		`stars 1`,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := Compile(expr); graph.ErrorCode(err) != graph.EINVALID {
+				t.Errorf("expected error: %s, got: %v", graph.EINVALID, err)
+			}
+		})
+	}
+}
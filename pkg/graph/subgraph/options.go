@@ -0,0 +1,60 @@
+package subgraph
+
+import "github.com/milosgajdos/orbnet/pkg/graph"
+
+// Predicate reports whether node n should be selected.
+type Predicate func(n graph.Node) bool
+
+// Options configure subgraph extraction.
+type Options struct {
+	// Labels selects nodes with the given labels.
+	Labels []string
+	// Predicate selects nodes which satisfy it.
+	Predicate Predicate
+	// EgoUIDs selects the ego networks centered on the nodes with the given UIDs.
+	EgoUIDs []string
+	// EgoRadius is the radius of the ego networks.
+	EgoRadius int
+	// TopN selects N nodes with the highest degree.
+	TopN int
+}
+
+// Option is functional subgraph option.
+type Option func(*Options)
+
+// WithLabels sets Labels option.
+func WithLabels(labels ...string) Option {
+	return func(o *Options) {
+		o.Labels = labels
+	}
+}
+
+// WithPredicate sets Predicate option.
+func WithPredicate(p Predicate) Option {
+	return func(o *Options) {
+		o.Predicate = p
+	}
+}
+
+// WithExpr sets Predicate option to the compiled expression.
+func WithExpr(e *Expr) Option {
+	return func(o *Options) {
+		o.Predicate = e.Match
+	}
+}
+
+// WithEgo sets EgoRadius and EgoUIDs options.
+// Only the nodes at most k edges away from any of the given nodes are selected.
+func WithEgo(k int, uids ...string) Option {
+	return func(o *Options) {
+		o.EgoRadius = k
+		o.EgoUIDs = uids
+	}
+}
+
+// WithTopN sets TopN option.
+func WithTopN(n int) Option {
+	return func(o *Options) {
+		o.TopN = n
+	}
+}
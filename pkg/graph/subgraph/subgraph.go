@@ -0,0 +1,200 @@
+package subgraph
+
+import (
+	"sort"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/attrs"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+	gonum "gonum.org/v1/gonum/graph"
+)
+
+// Extract extracts the subgraph of g induced by the selected nodes and returns it.
+// Nodes are selected by all the configured selectors: labels, predicate and ego
+// networks are evaluated on g and the intersection of their selections is
+// then narrowed down to the top N nodes by their degree in g.
+// If no selector is configured all the nodes of g are selected.
+func Extract(g graph.Graph, opts ...Option) (*memory.Graph, error) {
+	sopts := Options{}
+	for _, apply := range opts {
+		apply(&sopts)
+	}
+
+	if sopts.EgoRadius < 0 {
+		return nil, graph.Errorf(graph.EINVALID, "invalid ego radius: %d", sopts.EgoRadius)
+	}
+
+	if sopts.TopN < 0 {
+		return nil, graph.Errorf(graph.EINVALID, "invalid top N: %d", sopts.TopN)
+	}
+
+	labels := make(map[string]bool, len(sopts.Labels))
+	for _, l := range sopts.Labels {
+		labels[l] = true
+	}
+
+	var ego map[int64]bool
+	if len(sopts.EgoUIDs) > 0 {
+		var err error
+		ego, err = egoNodes(g, sopts.EgoRadius, sopts.EgoUIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var selected []graph.Node
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n, ok := nodes.Node().(graph.Node)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid node in graph %s", g.UID())
+		}
+
+		if len(labels) > 0 && !labels[n.Label()] {
+			continue
+		}
+
+		if sopts.Predicate != nil && !sopts.Predicate(n) {
+			continue
+		}
+
+		if ego != nil && !ego[n.ID()] {
+			continue
+		}
+
+		selected = append(selected, n)
+	}
+
+	if sopts.TopN > 0 && len(selected) > sopts.TopN {
+		degrees := make(map[int64]int, len(selected))
+		for _, n := range selected {
+			degrees[n.ID()] = degree(g, n.ID())
+		}
+
+		sort.SliceStable(selected, func(i, j int) bool {
+			di, dj := degrees[selected[i].ID()], degrees[selected[j].ID()]
+			if di != dj {
+				return di > dj
+			}
+			return selected[i].UID() < selected[j].UID()
+		})
+
+		selected = selected[:sopts.TopN]
+	}
+
+	return induce(g, selected)
+}
+
+// egoNodes returns IDs of the nodes at most k edges away from the nodes with the given UIDs.
+// Edge directions are ignored.
+func egoNodes(g graph.Graph, k int, uids []string) (map[int64]bool, error) {
+	seeds := make(map[string]bool, len(uids))
+	for _, uid := range uids {
+		seeds[uid] = true
+	}
+
+	visited := make(map[int64]bool)
+	var frontier []int64
+
+	nodes := g.Nodes()
+	for nodes.Next() {
+		n, ok := nodes.Node().(graph.Node)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid node in graph %s", g.UID())
+		}
+		if seeds[n.UID()] {
+			visited[n.ID()] = true
+			frontier = append(frontier, n.ID())
+			delete(seeds, n.UID())
+		}
+	}
+
+	for uid := range seeds {
+		return nil, graph.Errorf(graph.ENOTFOUND, "node %s not found", uid)
+	}
+
+	dg, directed := g.(gonum.Directed)
+
+	for hop := 0; hop < k && len(frontier) > 0; hop++ {
+		var next []int64
+		visit := func(it gonum.Nodes) {
+			for it.Next() {
+				id := it.Node().ID()
+				if !visited[id] {
+					visited[id] = true
+					next = append(next, id)
+				}
+			}
+		}
+		for _, id := range frontier {
+			visit(g.From(id))
+			if directed {
+				visit(dg.To(id))
+			}
+		}
+		frontier = next
+	}
+
+	return visited, nil
+}
+
+// degree returns the degree of node id.
+// The degree of a directed graph node is the sum of its in and out degree.
+// Undirected graphs may implement gonum.Directed so the graph type is checked.
+func degree(g graph.Graph, id int64) int {
+	d := g.From(id).Len()
+	if dg, ok := g.(gonum.Directed); ok && graph.IsDirected(g.Type()) {
+		d += dg.To(id).Len()
+	}
+	return d
+}
+
+// induce returns the subgraph of g induced by nodes.
+// The subgraph has the same type as g.
+func induce(g graph.Graph, nodes []graph.Node) (*memory.Graph, error) {
+	sg, err := memory.NewGraph(
+		memory.WithType(g.Type()),
+		memory.WithLabel(g.Label()),
+		memory.WithAttrs(attrs.CopyFrom(g.Attrs())),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	copies := make(map[int64]*memory.Node, len(nodes))
+	for _, n := range nodes {
+		node, err := memory.NewNodeFrom(n.ID(), n)
+		if err != nil {
+			return nil, err
+		}
+		sg.AddNode(node)
+		copies[n.ID()] = node
+	}
+
+	edges := g.Edges()
+	for edges.Next() {
+		e, ok := edges.Edge().(graph.Edge)
+		if !ok {
+			return nil, graph.Errorf(graph.EINVALID, "invalid edge in graph %s", g.UID())
+		}
+
+		from, ok := copies[e.From().ID()]
+		if !ok {
+			continue
+		}
+
+		to, ok := copies[e.To().ID()]
+		if !ok {
+			continue
+		}
+
+		edge, err := memory.NewEdgeFrom(from, to, e)
+		if err != nil {
+			return nil, err
+		}
+		sg.SetWeightedEdge(edge)
+	}
+
+	return sg, nil
+}
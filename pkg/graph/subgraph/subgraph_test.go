@@ -0,0 +1,178 @@
+package subgraph
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/milosgajdos/orbnet/pkg/graph"
+	"github.com/milosgajdos/orbnet/pkg/graph/memory"
+)
+
+// MustGraph returns the following graph:
+//
+//	repo1 -> topic1, repo1 -> go, repo2 -> topic1, repo2 -> go, repo3 -> rust
+func MustGraph(t *testing.T) *memory.Graph {
+	t.Helper()
+
+	g, err := memory.NewGraph(memory.WithLabel("test"))
+	if err != nil {
+		t.Fatalf("failed to create graph: %v", err)
+	}
+
+	nodes := []struct {
+		uid   string
+		label string
+		attrs map[string]interface{}
+	}{
+		{"repo1", "Repo", map[string]interface{}{"stargazers_count": 2000, "language": "Go"}},
+		{"repo2", "Repo", map[string]interface{}{"stargazers_count": 10, "language": "Go"}},
+		{"repo3", "Repo", map[string]interface{}{"stargazers_count": 5000, "language": "Rust"}},
+		{"topic1", "Topic", map[string]interface{}{"name": "graph"}},
+		{"go", "Lang", map[string]interface{}{"name": "go"}},
+		{"rust", "Lang", map[string]interface{}{"name": "rust"}},
+	}
+
+	ids := make(map[string]*memory.Node)
+	for _, tn := range nodes {
+		n, err := memory.NewNode(g.NewNode().ID(),
+			memory.WithUID(tn.uid),
+			memory.WithLabel(tn.label),
+			memory.WithAttrs(tn.attrs),
+		)
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		g.AddNode(n)
+		ids[tn.uid] = n
+	}
+
+	for _, te := range [][2]string{
+		{"repo1", "topic1"},
+		{"repo1", "go"},
+		{"repo2", "topic1"},
+		{"repo2", "go"},
+		{"repo3", "rust"},
+	} {
+		e, err := memory.NewEdge(ids[te[0]], ids[te[1]])
+		if err != nil {
+			t.Fatalf("failed to create edge: %v", err)
+		}
+		g.SetWeightedEdge(e)
+	}
+
+	return g
+}
+
+func nodeUIDs(g graph.Graph) []string {
+	var uids []string
+	nodes := g.Nodes()
+	for nodes.Next() {
+		uids = append(uids, nodes.Node().(graph.Node).UID())
+	}
+	sort.Strings(uids)
+	return uids
+}
+
+func TestExtract(t *testing.T) {
+	expr, err := Compile(`stargazers_count > 1000 || label != "Repo"`)
+	if err != nil {
+		t.Fatalf("failed to compile expression: %v", err)
+	}
+
+	testCases := []struct {
+		name  string
+		opts  []Option
+		nodes []string
+		edges int
+	}{
+		{"All", nil, []string{"go", "repo1", "repo2", "repo3", "rust", "topic1"}, 5},
+		{"Labels", []Option{WithLabels("Repo", "Lang")}, []string{"go", "repo1", "repo2", "repo3", "rust"}, 3},
+		{"Expr", []Option{WithExpr(expr)}, []string{"go", "repo1", "repo3", "rust", "topic1"}, 3},
+		{"Ego0", []Option{WithEgo(0, "go")}, []string{"go"}, 0},
+		{"Ego1", []Option{WithEgo(1, "go")}, []string{"go", "repo1", "repo2"}, 2},
+		{"Ego2", []Option{WithEgo(2, "go")}, []string{"go", "repo1", "repo2", "topic1"}, 4},
+		{"TopN", []Option{WithTopN(3)}, []string{"go", "repo1", "repo2"}, 2},
+		{"Combined", []Option{WithLabels("Repo"), WithEgo(2, "go"), WithTopN(1)}, []string{"repo1"}, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := MustGraph(t)
+
+			sg, err := Extract(g, tc.opts...)
+			if err != nil {
+				t.Fatalf("failed to extract subgraph: %v", err)
+			}
+
+			if uids := nodeUIDs(sg); !reflect.DeepEqual(uids, tc.nodes) {
+				t.Errorf("expected nodes: %v, got: %v", tc.nodes, uids)
+			}
+
+			if count := sg.Edges().Len(); count != tc.edges {
+				t.Errorf("expected edges: %d, got: %d", tc.edges, count)
+			}
+		})
+	}
+}
+
+func TestDegree(t *testing.T) {
+	testCases := []struct {
+		typ string
+		exp int
+	}{
+		{graph.WeightedDirected, 2},
+		{graph.WeightedUndirected, 2},
+	}
+
+	for _, tc := range testCases {
+		g, err := memory.NewGraph(memory.WithType(tc.typ))
+		if err != nil {
+			t.Fatalf("failed to create graph: %v", err)
+		}
+
+		var nodes []*memory.Node
+		for _, uid := range []string{"a", "b", "c"} {
+			n, err := memory.AddNewNode(g, memory.WithUID(uid))
+			if err != nil {
+				t.Fatalf("failed to add node: %v", err)
+			}
+			nodes = append(nodes, n)
+		}
+
+		// a -> b, c -> a
+		for _, te := range [][2]*memory.Node{{nodes[0], nodes[1]}, {nodes[2], nodes[0]}} {
+			e, err := memory.NewEdge(te[0], te[1])
+			if err != nil {
+				t.Fatalf("failed to create edge: %v", err)
+			}
+			g.SetWeightedEdge(e)
+		}
+
+		if d := degree(g, nodes[0].ID()); d != tc.exp {
+			t.Errorf("%s: expected degree: %d, got: %d", tc.typ, tc.exp, d)
+		}
+	}
+}
+
+func TestExtractErrors(t *testing.T) {
+	g := MustGraph(t)
+
+	testCases := []struct {
+		name string
+		opts []Option
+		code string
+	}{
+		{"UnknownEgo", []Option{WithEgo(1, "foo")}, graph.ENOTFOUND},
+		{"NegativeRadius", []Option{WithEgo(-1, "go")}, graph.EINVALID},
+		{"NegativeTopN", []Option{WithTopN(-1)}, graph.EINVALID},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Extract(g, tc.opts...); graph.ErrorCode(err) != tc.code {
+				t.Errorf("expected error: %s, got: %v", tc.code, err)
+			}
+		})
+	}
+}